@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,9 +11,13 @@ import (
 	"syscall"
 	"time"
 
+	"craftsbite-backend/internal/authz"
 	"craftsbite-backend/internal/config"
 	"craftsbite-backend/internal/database"
+	"craftsbite-backend/internal/discord"
 	"craftsbite-backend/internal/handlers"
+	"craftsbite-backend/internal/jobs"
+	"craftsbite-backend/internal/mailer"
 	"craftsbite-backend/internal/middleware"
 	"craftsbite-backend/internal/repository"
 	"craftsbite-backend/internal/routes"
@@ -54,6 +59,7 @@ func main() {
 			cfg.Database.Name,
 		)
 		fmt.Printf("JWT Expiration: %s\n", cfg.JWT.Expiration)
+		fmt.Printf("Refresh Token Expiration: %s\n", cfg.JWT.RefreshExpiration)
 		fmt.Printf("CORS Allowed Origins: %v\n", cfg.CORS.AllowedOrigins)
 		fmt.Printf("Log Level: %s\n", cfg.Logging.Level)
 		fmt.Println("=================================")
@@ -76,35 +82,125 @@ func main() {
 	workLocationRepo := repository.NewWorkLocationRepository(db)
 	workLocationHistoryRepo := repository.NewWorkLocationHistoryRepository(db)
 	wfhPeriodRepo := repository.NewWFHPeriodRepository(db)
+	discordLinkRepo := repository.NewDiscordLinkRepository(db)
+	snapshotRepo := repository.NewHeadcountSnapshotRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	teamHistoryRepo := repository.NewTeamHistoryRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	scheduleRuleRepo := repository.NewScheduleRuleRepository(db)
+	lateChangeRepo := repository.NewLateChangeRepository(db)
+	mealPriceRepo := repository.NewMealPriceRepository(db)
+	guestBookingRepo := repository.NewGuestBookingRepository(db)
+	participationRuleRepo := repository.NewParticipationRuleRepository(db)
+	cutoffPolicyRepo := repository.NewCutoffPolicyRepository(db)
+	mealTypeRepo := repository.NewMealTypeRepository(db)
+	menuRepo := repository.NewMenuRepository(db)
+	capacityRepo := repository.NewCapacityRepository(db)
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	consumptionRepo := repository.NewConsumptionRepository(db)
+	vendorRepo := repository.NewVendorRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	authAuditRepo := repository.NewAuthAuditRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
 
 	sseHub := sse.NewHub()
 
+	authorizer := authz.NewAuthorizer(roleRepo, teamRepo)
+
 	// Initialize services
-	authService := services.NewAuthService(userRepo, cfg)
-	userService := services.NewUserService(userRepo, teamRepo)
-	participationResolver := services.NewParticipationResolver(mealRepo, scheduleRepo, bulkOptOutRepo, userRepo, cfg)
-	mealService := services.NewMealService(mealRepo, scheduleRepo, historyRepo, userRepo, teamRepo, workLocationRepo, participationResolver, cfg)
-	scheduleService := services.NewScheduleService(scheduleRepo)
-	headcountService := services.NewHeadcountService(userRepo, scheduleRepo, participationResolver, teamRepo, workLocationRepo, wfhPeriodRepo, cfg)
-	workLocationService := services.NewWorkLocationService(workLocationRepo, userRepo, teamRepo, wfhPeriodRepo, workLocationHistoryRepo, cfg)
+	mealCatalog := services.NewMealCatalog(mealTypeRepo)
+	scheduleCalendar := services.NewScheduleCalendar(scheduleRepo, scheduleRuleRepo, cfg)
+	sessionService := services.NewSessionService(sessionRepo, cfg)
+	loginProtectionService := services.NewLoginProtectionService(loginAttemptRepo, authAuditRepo, userRepo, cfg)
+	twoFactorService := services.NewTwoFactorService(twoFactorRepo, userRepo, authAuditRepo, loginProtectionService, cfg)
+	authService := services.NewAuthService(userRepo, sessionService, loginProtectionService, twoFactorService, cfg)
+	userService := services.NewUserService(userRepo, teamRepo, sessionRepo, roleRepo)
+	mail := mailer.New(cfg.Mail)
+	registrationService := services.NewRegistrationService(invitationRepo, userRepo, roleRepo, teamRepo, teamHistoryRepo, authAuditRepo, userService, mail, cfg)
+	passwordService := services.NewPasswordService(userRepo, passwordResetRepo, authAuditRepo, sessionService, mail, cfg)
+	cutoffPolicyService := services.NewCutoffPolicyService(cutoffPolicyRepo, scheduleRepo, mealCatalog, cfg)
+	participationResolver := services.NewParticipationResolver(mealRepo, scheduleCalendar, bulkOptOutRepo, participationRuleRepo, userRepo, mealCatalog, cfg)
+	notificationService := services.NewNotificationService(notificationRepo)
+	capacityService := services.NewCapacityService(db, capacityRepo, waitlistRepo, scheduleRepo, userRepo, participationResolver, mealCatalog, notificationService)
+	mealService := services.NewMealService(mealRepo, scheduleCalendar, historyRepo, userRepo, teamRepo, workLocationRepo, menuRepo, participationResolver, cutoffPolicyService, capacityService, mealCatalog, authorizer, cfg)
+	scheduleService := services.NewScheduleService(db, scheduleRepo, scheduleRuleRepo, scheduleCalendar, mealCatalog)
+	headcountService := services.NewHeadcountService(userRepo, scheduleCalendar, participationResolver, teamRepo, workLocationRepo, wfhPeriodRepo, guestBookingRepo, menuRepo, mealCatalog, cfg)
+	workLocationService := services.NewWorkLocationService(workLocationRepo, userRepo, teamRepo, wfhPeriodRepo, workLocationHistoryRepo, authorizer, cfg)
 	wfhPeriodService := services.NewWFHPeriodService(wfhPeriodRepo)
+	teamService := services.NewTeamService(teamRepo, userRepo, teamHistoryRepo)
+	roleService := services.NewRoleService(roleRepo, authorizer)
+	lateChangeService := services.NewLateChangeService(lateChangeRepo, userRepo, teamRepo, mealService, cutoffPolicyService, authorizer, cfg)
+	guestBookingService := services.NewGuestBookingService(guestBookingRepo, mealService, cfg)
+	menuService := services.NewMenuService(menuRepo, mealCatalog, authorizer)
+	consumptionService := services.NewConsumptionService(consumptionRepo, userRepo, teamRepo, snapshotRepo, scheduleCalendar, participationResolver, mealCatalog, cfg)
+	vendorOrderDeliverer := services.NewVendorOrderDeliverer(mail, cfg)
+	vendorService := services.NewVendorService(vendorRepo, headcountService, mealCatalog, vendorOrderDeliverer, cfg)
+	billingService := services.NewBillingService(mealPriceRepo, snapshotRepo, userRepo, teamRepo, scheduleCalendar, participationResolver, mealCatalog, authorizer, cfg)
 
 	// Phase 4: Initialize advanced feature services
-	preferenceService := services.NewPreferenceService(userRepo, historyRepo)
-	bulkOptOutService := services.NewBulkOptOutService(db, bulkOptOutRepo, historyRepo, teamRepo)
+	preferenceService := services.NewPreferenceService(userRepo, historyRepo, participationRuleRepo, mealCatalog)
+	bulkOptOutService := services.NewBulkOptOutService(db, bulkOptOutRepo, historyRepo, teamRepo, mealCatalog, authorizer)
 	historyService := services.NewHistoryService(historyRepo)
+	forecastService := services.NewForecastService(headcountService, snapshotRepo, historyRepo, consumptionRepo, teamRepo, cfg)
+	snapshotService := services.NewHeadcountSnapshotService(snapshotRepo, headcountService, mealCatalog, scheduleCalendar, cutoffPolicyService, cfg)
+	discordService := services.NewDiscordService(discordLinkRepo, userRepo, teamRepo, mealService, workLocationService, headcountService, authorizer)
+
+	var discordPublicKey ed25519.PublicKey
+	if cfg.Discord.PublicKey != "" {
+		discordPublicKey, err = discord.ParsePublicKey(cfg.Discord.PublicKey)
+		if err != nil {
+			log.Fatalf("Failed to load Discord public key: %v", err)
+		}
+	} else {
+		logger.Warn("DISCORD_PUBLIC_KEY is not set, Discord interactions will be rejected")
+	}
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService, userService)
-	userHandler := handlers.NewUserHandler(userService)
+	authHandler := handlers.NewAuthHandler(authService, registrationService, passwordService)
+	userHandler := handlers.NewUserHandler(userService, authorizer)
 	mealHandler := handlers.NewMealHandler(mealService, teamRepo, headcountService, sseHub)
 	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
-	headcountHandler := handlers.NewHeadcountHandler(headcountService, sseHub)
+	headcountHandler := handlers.NewHeadcountHandler(headcountService, forecastService, sseHub)
 	preferenceHandler := handlers.NewPreferenceHandler(preferenceService)
 	bulkOptOutHandler := handlers.NewBulkOptOutHandler(bulkOptOutService)
 	historyHandler := handlers.NewHistoryHandler(historyService)
 	workLocationHandler := handlers.NewWorkLocationHandler(workLocationService)
 	wfhPeriodHandler := handlers.NewWFHPeriodHandler(wfhPeriodService)
+	discordHandler := handlers.NewDiscordHandler(discordService, headcountService, sseHub, discordPublicKey)
+	snapshotHandler := handlers.NewHeadcountSnapshotHandler(snapshotService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	loginProtectionHandler := handlers.NewLoginProtectionHandler(loginProtectionService)
+	invitationHandler := handlers.NewInvitationHandler(registrationService, authorizer)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
+	teamHandler := handlers.NewTeamHandler(teamService)
+	roleHandler := handlers.NewRoleHandler(roleService)
+	lateChangeHandler := handlers.NewLateChangeHandler(lateChangeService, headcountService, sseHub)
+	billingHandler := handlers.NewBillingHandler(billingService)
+	guestBookingHandler := handlers.NewGuestBookingHandler(guestBookingService, headcountService, sseHub)
+	cutoffPolicyHandler := handlers.NewCutoffPolicyHandler(cutoffPolicyService)
+	mealTypeHandler := handlers.NewMealTypeHandler(mealCatalog)
+	menuHandler := handlers.NewMenuHandler(menuService)
+	capacityHandler := handlers.NewCapacityHandler(capacityService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	consumptionHandler := handlers.NewConsumptionHandler(consumptionService)
+	vendorHandler := handlers.NewVendorHandler(vendorService)
+
+	// Freeze each meal's headcount at its cutoff
+	snapshotScheduler, err := jobs.NewHeadcountSnapshotJob(snapshotService).StartScheduler(cfg.Headcount.SnapshotCron)
+	if err != nil {
+		log.Fatalf("Failed to start headcount snapshot scheduler: %v", err)
+	}
+	defer jobs.StopScheduler(snapshotScheduler)
+
+	// Send vendor orders at their deadlines and amend them when the headcount changes
+	vendorOrderScheduler, err := jobs.NewVendorOrderJob(vendorService).StartScheduler(cfg.Vendor.OrderCron)
+	if err != nil {
+		log.Fatalf("Failed to start vendor order scheduler: %v", err)
+	}
+	defer jobs.StopScheduler(vendorOrderScheduler)
 
 	// Phase 4: Initialize cleanup job
 	// cleanupJob := jobs.NewCleanupJob(historyRepo, cfg.Cleanup.RetentionMonths)
@@ -122,6 +218,11 @@ func main() {
 	// Initialize router without default middleware
 	router := gin.New()
 
+	// Only believe X-Forwarded-For from configured proxies, so clients cannot pick the IP they are rate limited by
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Apply global middleware in order
 	router.Use(middleware.RecoveryMiddleware())
 	router.Use(middleware.RequestIDMiddleware())
@@ -139,7 +240,25 @@ func main() {
         History:    historyHandler,
 		WorkLocation: workLocationHandler,
 		WFHPeriod:    wfhPeriodHandler,
-    }, cfg)
+		Discord:      discordHandler,
+		Snapshot:     snapshotHandler,
+		Session:      sessionHandler,
+		Team:         teamHandler,
+		Role:         roleHandler,
+		LateChange:   lateChangeHandler,
+		Billing:      billingHandler,
+		Guest:        guestBookingHandler,
+		Cutoff:       cutoffPolicyHandler,
+		MealType:     mealTypeHandler,
+		Menu:         menuHandler,
+		Capacity:     capacityHandler,
+		Notification: notificationHandler,
+		Consumption:  consumptionHandler,
+		Vendor:       vendorHandler,
+		LoginProtection: loginProtectionHandler,
+		Invitation:      invitationHandler,
+		TwoFactor:       twoFactorHandler,
+    }, cfg, sessionService, authorizer, middleware.NewMemoryRateLimitStore())
 
 	// Create HTTP server
 	srv := &http.Server{
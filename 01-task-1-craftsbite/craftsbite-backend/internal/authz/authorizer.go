@@ -0,0 +1,128 @@
+package authz
+
+import (
+	"craftsbite-backend/internal/repository"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrForbidden is returned when an actor lacks a permission for a target user
+var ErrForbidden = errors.New("permission denied")
+
+// cacheTTL bounds how stale role grants can be when roles are edited by another instance
+const cacheTTL = time.Minute
+
+// Authorizer answers permission questions for roles and resource scopes.
+// Route-level checks go through middleware.RequirePermission; services use Authorize and ScopeFor.
+type Authorizer interface {
+	// ScopeFor returns the widest scope at which role holds permission, or "" if it does not
+	ScopeFor(role string, permission Permission) (Scope, error)
+	// HasPermission reports whether role holds permission at least at the required scope
+	HasPermission(role string, permission Permission, required Scope) (bool, error)
+	// Authorize checks that the actor may exercise permission on the target user
+	Authorize(role, actorID string, permission Permission, targetUserID string) error
+	// Invalidate drops cached grants so role edits take effect immediately
+	Invalidate()
+}
+
+type authorizer struct {
+	roleRepo repository.RoleRepository
+	teamRepo repository.TeamRepository
+
+	mu       sync.RWMutex
+	grants   map[string]map[Permission]Scope
+	loadedAt time.Time
+}
+
+// NewAuthorizer creates an authorizer backed by the roles stored in the database
+func NewAuthorizer(roleRepo repository.RoleRepository, teamRepo repository.TeamRepository) Authorizer {
+	return &authorizer{
+		roleRepo: roleRepo,
+		teamRepo: teamRepo,
+	}
+}
+
+func (a *authorizer) ScopeFor(role string, permission Permission) (Scope, error) {
+	grants, err := a.loadGrants()
+	if err != nil {
+		return "", err
+	}
+	return grants[role][permission], nil
+}
+
+func (a *authorizer) HasPermission(role string, permission Permission, required Scope) (bool, error) {
+	scope, err := a.ScopeFor(role, permission)
+	if err != nil {
+		return false, err
+	}
+	return scope.Covers(required), nil
+}
+
+func (a *authorizer) Authorize(role, actorID string, permission Permission, targetUserID string) error {
+	scope, err := a.ScopeFor(role, permission)
+	if err != nil {
+		return err
+	}
+
+	switch scope {
+	case ScopeAll:
+		return nil
+	case ScopeTeam:
+		if targetUserID == actorID {
+			return nil
+		}
+		isMember, err := a.teamRepo.IsUserInAnyTeamLedBy(actorID, targetUserID)
+		if err != nil {
+			return fmt.Errorf("failed to check team membership: %w", err)
+		}
+		if isMember {
+			return nil
+		}
+		return fmt.Errorf("%w: %s is limited to members of your own team", ErrForbidden, permission)
+	case ScopeOwn:
+		if targetUserID == actorID {
+			return nil
+		}
+		return fmt.Errorf("%w: %s is limited to your own account", ErrForbidden, permission)
+	}
+	return fmt.Errorf("%w: %s", ErrForbidden, permission)
+}
+
+func (a *authorizer) Invalidate() {
+	a.mu.Lock()
+	a.grants = nil
+	a.mu.Unlock()
+}
+
+// loadGrants returns role -> permission -> scope, reloading from the database when stale
+func (a *authorizer) loadGrants() (map[string]map[Permission]Scope, error) {
+	a.mu.RLock()
+	grants, loadedAt := a.grants, a.loadedAt
+	a.mu.RUnlock()
+	if grants != nil && time.Since(loadedAt) < cacheTTL {
+		return grants, nil
+	}
+
+	roles, err := a.roleRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	grants = make(map[string]map[Permission]Scope, len(roles))
+	for _, role := range roles {
+		perms := make(map[Permission]Scope, len(role.Permissions))
+		for _, p := range role.Permissions {
+			perms[Permission(p.Permission)] = Scope(p.Scope)
+		}
+		grants[role.Name] = perms
+	}
+
+	a.mu.Lock()
+	a.grants = grants
+	a.loadedAt = time.Now()
+	a.mu.Unlock()
+
+	return grants, nil
+}
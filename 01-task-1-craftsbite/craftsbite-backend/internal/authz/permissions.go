@@ -0,0 +1,140 @@
+package authz
+
+// Permission is a named capability that a role can be granted
+type Permission string
+
+// Scope limits which users a permission applies to. Scopes are ordered:
+// a grant at ScopeAll also covers ScopeTeam, which also covers ScopeOwn.
+type Scope string
+
+const (
+	// ScopeOwn applies to the acting user only
+	ScopeOwn Scope = "own"
+	// ScopeTeam applies to the acting user and members of the teams they lead
+	ScopeTeam Scope = "team"
+	// ScopeAll applies to everyone
+	ScopeAll Scope = "all"
+)
+
+// rank orders scopes from narrowest to widest; unknown scopes rank 0
+func (s Scope) rank() int {
+	switch s {
+	case ScopeOwn:
+		return 1
+	case ScopeTeam:
+		return 2
+	case ScopeAll:
+		return 3
+	}
+	return 0
+}
+
+// Covers reports whether a grant at scope s satisfies a requirement of scope required
+func (s Scope) Covers(required Scope) bool {
+	return s.rank() > 0 && s.rank() >= required.rank()
+}
+
+const (
+	PermUserRead       Permission = "user:read"
+	PermUserWrite      Permission = "user:write"
+	PermUserAssignRole Permission = "user:assign_role"
+
+	PermTeamRead   Permission = "team:read"
+	PermTeamManage Permission = "team:manage"
+
+	PermParticipationRead     Permission = "participation:read"
+	PermParticipationOverride Permission = "participation:override"
+	PermBulkOptOutManage      Permission = "bulk_optout:manage"
+	PermHistoryRead           Permission = "history:read"
+	PermLateChangeReview      Permission = "late_change:review"
+
+	PermScheduleWrite  Permission = "schedule:write"
+	PermMealTypeManage Permission = "meal_type:manage"
+	PermMenuManage     Permission = "menu:manage"
+
+	PermHeadcountRead     Permission = "headcount:read"
+	PermHeadcountSnapshot Permission = "headcount:snapshot"
+
+	PermCheckInRecord   Permission = "checkin:record"
+	PermConsumptionRead Permission = "consumption:read"
+	PermVendorManage    Permission = "vendor:manage"
+
+	PermWorkLocationRead     Permission = "work_location:read"
+	PermWorkLocationReport   Permission = "work_location:report"
+	PermWorkLocationOverride Permission = "work_location:override"
+	PermWFHPeriodManage      Permission = "wfh_period:manage"
+
+	PermBillingRead   Permission = "billing:read"
+	PermBillingManage Permission = "billing:manage"
+
+	PermDiscordManage      Permission = "discord:manage"
+	PermSessionManage      Permission = "session:manage"
+	PermRoleManage         Permission = "role:manage"
+	PermAuthSecurityManage Permission = "auth_security:manage"
+)
+
+// PermissionInfo describes a permission and the scopes it can be granted at
+type PermissionInfo struct {
+	Name        Permission `json:"name"`
+	Description string     `json:"description"`
+	Scopes      []Scope    `json:"scopes"`
+}
+
+var (
+	ownOrAll     = []Scope{ScopeOwn, ScopeAll}
+	teamOrAll    = []Scope{ScopeTeam, ScopeAll}
+	anyScope     = []Scope{ScopeOwn, ScopeTeam, ScopeAll}
+	everyoneOnly = []Scope{ScopeAll}
+)
+
+// Catalog lists every permission the application checks
+var Catalog = []PermissionInfo{
+	{PermUserRead, "View user profiles", ownOrAll},
+	{PermUserWrite, "Create, update and deactivate users", ownOrAll},
+	{PermUserAssignRole, "Change a user's role", everyoneOnly},
+	{PermTeamRead, "View team membership", anyScope},
+	{PermTeamManage, "Create, update and deactivate teams and their members", everyoneOnly},
+	{PermParticipationRead, "View other users' meal participation", teamOrAll},
+	{PermParticipationOverride, "Override other users' meal participation", teamOrAll},
+	{PermBulkOptOutManage, "Create bulk opt-outs on behalf of other users", teamOrAll},
+	{PermHistoryRead, "View participation history", ownOrAll},
+	{PermLateChangeReview, "Approve or reject late meal change requests", teamOrAll},
+	{PermScheduleWrite, "Create, update and delete day schedules", everyoneOnly},
+	{PermMealTypeManage, "Add, edit and retire meal types", everyoneOnly},
+	{PermMenuManage, "Plan, publish and clone daily menus", everyoneOnly},
+	{PermHeadcountRead, "View headcount reports, forecasts and snapshots", everyoneOnly},
+	{PermHeadcountSnapshot, "Capture headcount snapshots manually", everyoneOnly},
+	{PermCheckInRecord, "Check users in for meals at the canteen", everyoneOnly},
+	{PermConsumptionRead, "View consumption and no-show reports", everyoneOnly},
+	{PermVendorManage, "Manage vendors and send, amend and acknowledge their meal orders", everyoneOnly},
+	{PermWorkLocationRead, "List other users' work locations", teamOrAll},
+	{PermWorkLocationReport, "View monthly WFH reports", teamOrAll},
+	{PermWorkLocationOverride, "Set other users' work location", teamOrAll},
+	{PermWFHPeriodManage, "Manage company-wide WFH periods", everyoneOnly},
+	{PermBillingRead, "View monthly meal billing statements", anyScope},
+	{PermBillingManage, "Manage meal prices and subsidies", everyoneOnly},
+	{PermDiscordManage, "Link and unlink Discord accounts", everyoneOnly},
+	{PermSessionManage, "View and revoke other users' sessions", everyoneOnly},
+	{PermRoleManage, "Define roles and their permissions", everyoneOnly},
+	{PermAuthSecurityManage, "Lift login lockouts and view the auth audit log", everyoneOnly},
+}
+
+// LookupPermission returns the catalog entry of a permission
+func LookupPermission(name Permission) (PermissionInfo, bool) {
+	for _, info := range Catalog {
+		if info.Name == name {
+			return info, true
+		}
+	}
+	return PermissionInfo{}, false
+}
+
+// AllowsScope reports whether the permission can be granted at the given scope
+func (p PermissionInfo) AllowsScope(scope Scope) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
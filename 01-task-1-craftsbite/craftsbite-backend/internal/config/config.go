@@ -17,14 +17,27 @@ type Config struct {
     Meal         MealConfig
     Cleanup      CleanupConfig
     RateLimit    RateLimitConfig
+    Login        LoginConfig
+    Registration RegistrationConfig
+    Password     PasswordConfig
+    TwoFactor    TwoFactorConfig
     WorkLocation WorkLocationConfig
     Headcount HeadcountConfig
+    Discord      DiscordConfig
+    Billing      BillingConfig
+    Mail         MailConfig
+    Vendor       VendorConfig
 }
 
 type ServerConfig struct {
     Host string
     Port int
     Env  string
+    // TrustedProxies are the proxy addresses or CIDRs whose X-Forwarded-For header is
+    // believed when resolving the client IP; empty trusts none
+    TrustedProxies []string
+    // AppURL is the public address of the web app, used for links in email
+    AppURL string
 }
 
 type DatabaseConfig struct {
@@ -40,8 +53,9 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-    Secret     string
-    Expiration time.Duration
+    Secret            string
+    Expiration        time.Duration
+    RefreshExpiration time.Duration
 }
 
 type CORSConfig struct {
@@ -54,10 +68,18 @@ type LoggingConfig struct {
 }
 
 type MealConfig struct {
+    // CutoffTime is the HH:MM on the previous day used for meals without a cutoff policy
     CutoffTime     string
     CutoffTimezone string
     WeekendDays    []string
     ForwardWindowDays int
+    // LateChangeDeadlines maps a meal type to the HH:MM on the meal day until which a
+    // post-cutoff change can still be requested. Meals not listed accept no late requests.
+    LateChangeDeadlines map[string]string
+    // GuestDailyCap is the most guests that can be booked for a single meal on one day
+    GuestDailyCap int
+    // CheckInTokenTTL is how long a QR check-in token stays valid after it is issued
+    CheckInTokenTTL time.Duration
 }
 
 type CleanupConfig struct {
@@ -67,7 +89,53 @@ type CleanupConfig struct {
 
 type RateLimitConfig struct {
     Enabled           bool
+    // RequestsPerMinute limits each user's write requests
     RequestsPerMinute int
+    // ReadRequestsPerMinute limits each user's GET requests
+    ReadRequestsPerMinute int
+    // AuthRequestsPerMinute limits login, registration and token refresh per client IP
+    AuthRequestsPerMinute int
+}
+
+type LoginConfig struct {
+    // MaxAccountFailures consecutive failed logins lock an account; MaxIPFailures lock a client IP
+    MaxAccountFailures int
+    MaxIPFailures      int
+    // FailureWindow is how long a failed login counts towards a lockout
+    FailureWindow   time.Duration
+    LockoutDuration time.Duration
+    // RetryDelay is how long an account must wait after a failed login; it doubles with every further failure
+    RetryDelay time.Duration
+}
+
+// Registration modes
+const (
+    RegistrationInviteOnly = "invite_only"
+    RegistrationOpen       = "open"
+)
+
+type RegistrationConfig struct {
+    // Mode is invite_only, where accounts are created from admin invitations only, or open,
+    // which also lets people with an allowed email domain sign up as employees
+    Mode           string
+    AllowedDomains []string
+    InviteTTL      time.Duration
+}
+
+type PasswordConfig struct {
+    // ResetTokenTTL is how long a password reset link stays valid
+    ResetTokenTTL time.Duration
+}
+
+type TwoFactorConfig struct {
+    // RequiredRoles must use two-factor authentication; users with these roles enrol at their next login
+    RequiredRoles []string
+    // Issuer names the account in authenticator apps
+    Issuer string
+    // ChallengeTTL is how long the second login step may take after the password was accepted
+    ChallengeTTL time.Duration
+    // EncryptionKey encrypts TOTP secrets at rest; when empty a key is derived from the JWT secret
+    EncryptionKey string
 }
 
 type WorkLocationConfig struct {
@@ -76,6 +144,34 @@ type WorkLocationConfig struct {
 
 type HeadcountConfig struct {
     MaxForecastDays int
+    // SnapshotCron is how often meals whose cutoff has passed are checked for a snapshot
+    SnapshotCron string
+}
+
+type DiscordConfig struct {
+    PublicKey string
+}
+
+type BillingConfig struct {
+    // Currency is the ISO 4217 code prices are recorded in; amounts are stored in minor units
+    Currency string
+}
+
+type MailConfig struct {
+    // SMTPHost is the outgoing mail server; when empty, mail is written to FileDir or the log instead
+    SMTPHost     string
+    SMTPPort     int
+    SMTPUsername string
+    SMTPPassword string
+    From         string
+    // FileDir, when set and SMTPHost is not, receives each message as an .eml file
+    FileDir string
+}
+
+type VendorConfig struct {
+    // OrderCron is how often due vendor orders are generated and amendments delivered
+    OrderCron      string
+    WebhookTimeout time.Duration
 }
 
 func LoadConfig() (*Config, error) {
@@ -98,6 +194,8 @@ func LoadConfig() (*Config, error) {
             Host: viper.GetString("SERVER_HOST"),
             Port: viper.GetInt("SERVER_PORT"),
             Env:  viper.GetString("ENV"),
+            TrustedProxies: parseCommaSeparated(viper.GetString("TRUSTED_PROXIES")),
+            AppURL:         strings.TrimRight(viper.GetString("APP_URL"), "/"),
         },
         Database: DatabaseConfig{
             Host:            viper.GetString("DB_HOST"),
@@ -111,8 +209,9 @@ func LoadConfig() (*Config, error) {
             ConnMaxLifetime: viper.GetDuration("DB_CONN_MAX_LIFETIME"),
         },
         JWT: JWTConfig{
-            Secret:     viper.GetString("JWT_SECRET"),
-            Expiration: viper.GetDuration("JWT_EXPIRATION"),
+            Secret:            viper.GetString("JWT_SECRET"),
+            Expiration:        viper.GetDuration("JWT_EXPIRATION"),
+            RefreshExpiration: viper.GetDuration("JWT_REFRESH_EXPIRATION"),
         },
         CORS: CORSConfig{
             AllowedOrigins: parseCommaSeparated(viper.GetString("CORS_ALLOWED_ORIGINS")),
@@ -126,6 +225,9 @@ func LoadConfig() (*Config, error) {
             CutoffTimezone: viper.GetString("MEAL_CUTOFF_TIMEZONE"),
             WeekendDays:    parseCommaSeparated(viper.GetString("MEAL_WEEKEND_DAYS")),
             ForwardWindowDays: viper.GetInt("MEAL_FORWARD_WINDOW_DAYS"),
+            LateChangeDeadlines: parseKeyValueList(viper.GetString("MEAL_LATE_CHANGE_DEADLINES")),
+            GuestDailyCap: viper.GetInt("MEAL_GUEST_DAILY_CAP"),
+            CheckInTokenTTL: viper.GetDuration("MEAL_CHECKIN_TOKEN_TTL"),
         },
         Cleanup: CleanupConfig{
             RetentionMonths: viper.GetInt("HISTORY_RETENTION_MONTHS"),
@@ -134,12 +236,54 @@ func LoadConfig() (*Config, error) {
         RateLimit: RateLimitConfig{
             Enabled:           viper.GetBool("RATE_LIMIT_ENABLED"),
             RequestsPerMinute: viper.GetInt("RATE_LIMIT_REQUESTS_PER_MINUTE"),
+            ReadRequestsPerMinute: viper.GetInt("RATE_LIMIT_READ_REQUESTS_PER_MINUTE"),
+            AuthRequestsPerMinute: viper.GetInt("RATE_LIMIT_AUTH_REQUESTS_PER_MINUTE"),
+        },
+        Login: LoginConfig{
+            MaxAccountFailures: viper.GetInt("LOGIN_MAX_ACCOUNT_FAILURES"),
+            MaxIPFailures:      viper.GetInt("LOGIN_MAX_IP_FAILURES"),
+            FailureWindow:      viper.GetDuration("LOGIN_FAILURE_WINDOW"),
+            LockoutDuration:    viper.GetDuration("LOGIN_LOCKOUT_DURATION"),
+            RetryDelay:         viper.GetDuration("LOGIN_RETRY_DELAY"),
+        },
+        Registration: RegistrationConfig{
+            Mode:           viper.GetString("REGISTRATION_MODE"),
+            AllowedDomains: parseCommaSeparated(strings.ToLower(viper.GetString("REGISTRATION_ALLOWED_DOMAINS"))),
+            InviteTTL:      viper.GetDuration("REGISTRATION_INVITE_TTL"),
+        },
+        Password: PasswordConfig{
+            ResetTokenTTL: viper.GetDuration("PASSWORD_RESET_TOKEN_TTL"),
+        },
+        TwoFactor: TwoFactorConfig{
+            RequiredRoles: parseCommaSeparated(viper.GetString("TWO_FACTOR_REQUIRED_ROLES")),
+            Issuer:        viper.GetString("TWO_FACTOR_ISSUER"),
+            ChallengeTTL:  viper.GetDuration("TWO_FACTOR_CHALLENGE_TTL"),
+            EncryptionKey: viper.GetString("TWO_FACTOR_ENCRYPTION_KEY"),
         },
         WorkLocation: WorkLocationConfig{
             MonthlyWFHAllowance: viper.GetInt("WORK_LOCATION_MONTHLY_WFH_ALLOWANCE"),
         },
         Headcount: HeadcountConfig{
             MaxForecastDays: viper.GetInt("HEADCOUNT_MAX_FORECAST_DAYS"),
+            SnapshotCron:    viper.GetString("HEADCOUNT_SNAPSHOT_CRON"),
+        },
+        Discord: DiscordConfig{
+            PublicKey: viper.GetString("DISCORD_PUBLIC_KEY"),
+        },
+        Billing: BillingConfig{
+            Currency: strings.ToUpper(viper.GetString("BILLING_CURRENCY")),
+        },
+        Mail: MailConfig{
+            SMTPHost:     viper.GetString("SMTP_HOST"),
+            SMTPPort:     viper.GetInt("SMTP_PORT"),
+            SMTPUsername: viper.GetString("SMTP_USERNAME"),
+            SMTPPassword: viper.GetString("SMTP_PASSWORD"),
+            From:         viper.GetString("MAIL_FROM"),
+            FileDir:      viper.GetString("MAIL_FILE_DIR"),
+        },
+        Vendor: VendorConfig{
+            OrderCron:      viper.GetString("VENDOR_ORDER_CRON"),
+            WebhookTimeout: viper.GetDuration("VENDOR_WEBHOOK_TIMEOUT"),
         },
     }
 
@@ -154,6 +298,7 @@ func setDefaults() {
     viper.SetDefault("SERVER_HOST", "localhost")
     viper.SetDefault("SERVER_PORT", 8080)
     viper.SetDefault("ENV", "development")
+    viper.SetDefault("APP_URL", "http://localhost:3000")
 
     viper.SetDefault("DB_HOST", "localhost")
     viper.SetDefault("DB_PORT", "5432")
@@ -164,7 +309,8 @@ func setDefaults() {
     viper.SetDefault("DB_MAX_IDLE_CONNS", 5)
     viper.SetDefault("DB_CONN_MAX_LIFETIME", "5m")
 
-    viper.SetDefault("JWT_EXPIRATION", "24h")
+    viper.SetDefault("JWT_EXPIRATION", "15m")
+    viper.SetDefault("JWT_REFRESH_EXPIRATION", "720h")
 
     viper.SetDefault("CORS_ALLOWED_ORIGINS", "http://localhost:3000")
 
@@ -175,15 +321,44 @@ func setDefaults() {
     viper.SetDefault("MEAL_CUTOFF_TIMEZONE", "Asia/Dhaka")
     viper.SetDefault("MEAL_WEEKEND_DAYS", "Saturday,Sunday")
     viper.SetDefault("MEAL_FORWARD_WINDOW_DAYS", 7)
+    viper.SetDefault("MEAL_LATE_CHANGE_DEADLINES", "lunch=10:00,snacks=15:00")
+    viper.SetDefault("MEAL_GUEST_DAILY_CAP", 20)
+    viper.SetDefault("MEAL_CHECKIN_TOKEN_TTL", "12h")
 
     viper.SetDefault("HISTORY_RETENTION_MONTHS", 3)
     viper.SetDefault("CLEANUP_CRON", "0 2 * * *")
 
     viper.SetDefault("RATE_LIMIT_ENABLED", true)
     viper.SetDefault("RATE_LIMIT_REQUESTS_PER_MINUTE", 100)
+    viper.SetDefault("RATE_LIMIT_READ_REQUESTS_PER_MINUTE", 300)
+    viper.SetDefault("RATE_LIMIT_AUTH_REQUESTS_PER_MINUTE", 10)
+
+    viper.SetDefault("LOGIN_MAX_ACCOUNT_FAILURES", 5)
+    viper.SetDefault("LOGIN_MAX_IP_FAILURES", 20)
+    viper.SetDefault("LOGIN_FAILURE_WINDOW", "15m")
+    viper.SetDefault("LOGIN_LOCKOUT_DURATION", "15m")
+    viper.SetDefault("LOGIN_RETRY_DELAY", "1s")
+
+    viper.SetDefault("REGISTRATION_MODE", RegistrationInviteOnly)
+    viper.SetDefault("REGISTRATION_INVITE_TTL", "168h")
+
+    viper.SetDefault("PASSWORD_RESET_TOKEN_TTL", "1h")
+
+    viper.SetDefault("TWO_FACTOR_REQUIRED_ROLES", "admin,logistics")
+    viper.SetDefault("TWO_FACTOR_ISSUER", "CraftsBite")
+    viper.SetDefault("TWO_FACTOR_CHALLENGE_TTL", "5m")
 
     viper.SetDefault("WORK_LOCATION_MONTHLY_WFH_ALLOWANCE", 5)
     viper.SetDefault("HEADCOUNT_MAX_FORECAST_DAYS", 14)
+    viper.SetDefault("HEADCOUNT_SNAPSHOT_CRON", "* * * * *")
+
+    viper.SetDefault("BILLING_CURRENCY", "BDT")
+
+    viper.SetDefault("SMTP_PORT", 587)
+    viper.SetDefault("MAIL_FROM", "CraftsBite <no-reply@craftsbite.local>")
+
+    viper.SetDefault("VENDOR_ORDER_CRON", "*/5 * * * *")
+    viper.SetDefault("VENDOR_WEBHOOK_TIMEOUT", "10s")
 }   
 
 func (c *Config) Validate() error {
@@ -207,6 +382,72 @@ func (c *Config) Validate() error {
         return fmt.Errorf("ENV must be one of: development, staging, production, test")
     }
 
+    for mealType, deadline := range c.Meal.LateChangeDeadlines {
+        if _, err := time.Parse("15:04", deadline); err != nil {
+            return fmt.Errorf("MEAL_LATE_CHANGE_DEADLINES: invalid time %q for %s, expected HH:MM", deadline, mealType)
+        }
+    }
+
+    if c.Meal.GuestDailyCap < 0 {
+        return fmt.Errorf("MEAL_GUEST_DAILY_CAP cannot be negative")
+    }
+
+    if c.Meal.CheckInTokenTTL <= 0 {
+        return fmt.Errorf("MEAL_CHECKIN_TOKEN_TTL must be positive")
+    }
+
+    if c.RateLimit.Enabled && (c.RateLimit.RequestsPerMinute <= 0 || c.RateLimit.ReadRequestsPerMinute <= 0 || c.RateLimit.AuthRequestsPerMinute <= 0) {
+        return fmt.Errorf("RATE_LIMIT_*_REQUESTS_PER_MINUTE must be positive when rate limiting is enabled")
+    }
+
+    if c.Login.MaxAccountFailures <= 0 || c.Login.MaxIPFailures <= 0 {
+        return fmt.Errorf("LOGIN_MAX_ACCOUNT_FAILURES and LOGIN_MAX_IP_FAILURES must be positive")
+    }
+    if c.Login.FailureWindow <= 0 || c.Login.LockoutDuration <= 0 || c.Login.RetryDelay < 0 {
+        return fmt.Errorf("LOGIN_FAILURE_WINDOW and LOGIN_LOCKOUT_DURATION must be positive and LOGIN_RETRY_DELAY not negative")
+    }
+
+    switch c.Registration.Mode {
+    case RegistrationInviteOnly:
+    case RegistrationOpen:
+        if len(c.Registration.AllowedDomains) == 0 {
+            return fmt.Errorf("REGISTRATION_ALLOWED_DOMAINS is required when REGISTRATION_MODE is open")
+        }
+    default:
+        return fmt.Errorf("REGISTRATION_MODE must be one of: invite_only, open")
+    }
+    if c.Registration.InviteTTL <= 0 {
+        return fmt.Errorf("REGISTRATION_INVITE_TTL must be positive")
+    }
+    if c.Password.ResetTokenTTL <= 0 {
+        return fmt.Errorf("PASSWORD_RESET_TOKEN_TTL must be positive")
+    }
+
+    if c.TwoFactor.Issuer == "" || strings.Contains(c.TwoFactor.Issuer, ":") {
+        return fmt.Errorf("TWO_FACTOR_ISSUER is required and cannot contain ':'")
+    }
+    if c.TwoFactor.ChallengeTTL <= 0 {
+        return fmt.Errorf("TWO_FACTOR_CHALLENGE_TTL must be positive")
+    }
+    if c.TwoFactor.EncryptionKey != "" && len(c.TwoFactor.EncryptionKey) < 32 {
+        return fmt.Errorf("TWO_FACTOR_ENCRYPTION_KEY must be at least 32 characters long")
+    }
+
+    if len(c.Billing.Currency) != 3 {
+        return fmt.Errorf("BILLING_CURRENCY must be a 3-letter ISO 4217 code")
+    }
+
+    if c.Mail.SMTPHost != "" && c.Mail.From == "" {
+        return fmt.Errorf("MAIL_FROM is required when SMTP_HOST is set")
+    }
+    if c.IsProduction() && c.Mail.SMTPHost == "" && c.Mail.FileDir == "" {
+        return fmt.Errorf("SMTP_HOST or MAIL_FILE_DIR is required in production")
+    }
+
+    if c.Vendor.WebhookTimeout <= 0 {
+        return fmt.Errorf("VENDOR_WEBHOOK_TIMEOUT must be positive")
+    }
+
     return nil
 }
 
@@ -224,6 +465,21 @@ func (c *ServerConfig) GetServerAddress() string {
     return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// parseKeyValueList parses "lunch=10:00,snacks=15:00" into a map; entries without "=" are ignored
+func parseKeyValueList(s string) map[string]string {
+    result := make(map[string]string)
+    for _, part := range parseCommaSeparated(s) {
+        key, value, ok := strings.Cut(part, "=")
+        if !ok {
+            continue
+        }
+        if key, value = strings.TrimSpace(key), strings.TrimSpace(value); key != "" && value != "" {
+            result[key] = value
+        }
+    }
+    return result
+}
+
 func parseCommaSeparated(s string) []string {
     if s == "" {
         return []string{}
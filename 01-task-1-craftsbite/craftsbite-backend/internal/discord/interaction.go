@@ -0,0 +1,171 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// InteractionType is the type of an incoming Discord interaction
+type InteractionType int
+
+const (
+	InteractionTypePing               InteractionType = 1
+	InteractionTypeApplicationCommand InteractionType = 2
+)
+
+// ResponseType is the type of an interaction response
+type ResponseType int
+
+const (
+	ResponseTypePong                     ResponseType = 1
+	ResponseTypeChannelMessageWithSource ResponseType = 4
+)
+
+// MessageFlagEphemeral makes a reply visible only to the invoking user
+const MessageFlagEphemeral = 1 << 6
+
+// maxSignatureAge is how far the signed timestamp may be from now, so that a captured
+// interaction cannot be replayed later
+const maxSignatureAge = 5 * time.Minute
+
+// Interaction is the payload Discord POSTs to the interactions endpoint
+type Interaction struct {
+	ID     string          `json:"id"`
+	Type   InteractionType `json:"type"`
+	Data   CommandData     `json:"data"`
+	Member *Member         `json:"member,omitempty"`
+	User   *User           `json:"user,omitempty"`
+}
+
+// CommandData holds the invoked slash command and its options
+type CommandData struct {
+	Name    string          `json:"name"`
+	Options []CommandOption `json:"options,omitempty"`
+}
+
+// CommandOption is a single slash command option
+type CommandOption struct {
+	Name  string          `json:"name"`
+	Type  int             `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Member is the guild member that invoked the interaction
+type Member struct {
+	User User `json:"user"`
+}
+
+// User is a Discord user
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// InteractionResponse is the reply sent back to Discord
+type InteractionResponse struct {
+	Type ResponseType     `json:"type"`
+	Data *ResponseMessage `json:"data,omitempty"`
+}
+
+// ResponseMessage is the message content of an interaction response
+type ResponseMessage struct {
+	Content string `json:"content"`
+	Flags   int    `json:"flags,omitempty"`
+}
+
+// CallerID returns the Discord user ID of whoever invoked the interaction.
+// Guild interactions carry the user under member, DMs carry it directly.
+func (i *Interaction) CallerID() string {
+	if i.Member != nil && i.Member.User.ID != "" {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// StringOption returns the value of a string option, or "" if it was not provided
+func (d CommandData) StringOption(name string) string {
+	for _, opt := range d.Options {
+		if opt.Name != name {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(opt.Value, &value); err == nil {
+			return value
+		}
+		return string(opt.Value)
+	}
+	return ""
+}
+
+// BoolOption returns the value of a boolean option and whether it was provided
+func (d CommandData) BoolOption(name string) (bool, bool) {
+	for _, opt := range d.Options {
+		if opt.Name != name {
+			continue
+		}
+		var value bool
+		if err := json.Unmarshal(opt.Value, &value); err == nil {
+			return value, true
+		}
+		var raw string
+		if err := json.Unmarshal(opt.Value, &raw); err == nil {
+			if parsed, err := strconv.ParseBool(raw); err == nil {
+				return parsed, true
+			}
+		}
+		return false, false
+	}
+	return false, false
+}
+
+// EphemeralMessage builds a message response only the caller can see
+func EphemeralMessage(content string) InteractionResponse {
+	return InteractionResponse{
+		Type: ResponseTypeChannelMessageWithSource,
+		Data: &ResponseMessage{Content: content, Flags: MessageFlagEphemeral},
+	}
+}
+
+// ParsePublicKey decodes the hex-encoded application public key from the Discord developer portal
+func ParsePublicKey(hexKey string) (ed25519.PublicKey, error) {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid discord public key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid discord public key: expected %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// VerifySignature checks the X-Signature-Ed25519 header against timestamp + body and
+// rejects a X-Signature-Timestamp more than maxSignatureAge away from now
+func VerifySignature(publicKey ed25519.PublicKey, signatureHex, timestamp string, body []byte) bool {
+	if len(publicKey) != ed25519.PublicKeySize || signatureHex == "" || timestamp == "" {
+		return false
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(seconds, 0))
+	if age > maxSignatureAge || age < -maxSignatureAge {
+		return false
+	}
+	signature, err := hex.DecodeString(signatureHex)
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+
+	message := make([]byte, 0, len(timestamp)+len(body))
+	message = append(message, timestamp...)
+	message = append(message, body...)
+	return ed25519.Verify(publicKey, message, signature)
+}
@@ -0,0 +1,131 @@
+// Package export writes report tables as CSV or as multi-sheet XLSX workbooks.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Content types of the export formats
+const (
+	ContentTypeCSV  = "text/csv; charset=utf-8"
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// Sheet is a table with a header row. Cells may be strings, integers, floats, booleans,
+// times or nil; numbers and booleans keep their type in XLSX.
+type Sheet struct {
+	Name    string
+	Columns []string
+	Rows    [][]any
+}
+
+// AddRow appends a row of cells
+func (s *Sheet) AddRow(cells ...any) {
+	s.Rows = append(s.Rows, cells)
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return ContentTypeXLSX
+	}
+	return ContentTypeCSV
+}
+
+// Write writes sheets in the given format. CSV holds a single table, so only the first
+// sheet is written; reports put their most detailed, flattened table first.
+func Write(w io.Writer, format string, sheets []Sheet) error {
+	switch format {
+	case FormatCSV:
+		if len(sheets) == 0 {
+			return nil
+		}
+		return WriteCSV(w, sheets[0])
+	case FormatXLSX:
+		return WriteXLSX(w, sheets)
+	default:
+		return fmt.Errorf("unsupported export format '%s'", format)
+	}
+}
+
+// WriteCSV writes a sheet as CSV
+func WriteCSV(w io.Writer, sheet Sheet) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(sheet.Columns); err != nil {
+		return err
+	}
+	record := make([]string, len(sheet.Columns))
+	for _, row := range sheet.Rows {
+		record = record[:0]
+		for _, cell := range row {
+			record = append(record, csvCell(cell))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvCell formats a cell for CSV. Text that a spreadsheet would evaluate as a formula is
+// prefixed with a quote so user-entered names cannot inject formulas.
+func csvCell(cell any) string {
+	text := formatCell(cell)
+	if _, isText := cell.(string); !isText {
+		if _, isText := cell.(*string); !isText {
+			return text
+		}
+	}
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return "'" + text
+		}
+	}
+	return text
+}
+
+// formatCell renders any cell as text
+func formatCell(cell any) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,219 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxSheetNameLength is the longest sheet name spreadsheet applications accept
+const maxSheetNameLength = 31
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const (
+	spreadsheetNS   = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	relationshipsNS = "http://schemas.openxmlformats.org/package/2006/relationships"
+	officeDocRelNS  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+// stylesXML defines two cell formats: 0 is the default and 1 is bold, used for header rows
+const stylesXML = xmlHeader + `<styleSheet xmlns="` + spreadsheetNS + `">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
+
+// WriteXLSX writes sheets as an Office Open XML workbook, one worksheet per sheet with a
+// bold, frozen header row. Strings are written inline so rows can be streamed.
+func WriteXLSX(w io.Writer, sheets []Sheet) error {
+	if len(sheets) == 0 {
+		sheets = []Sheet{{Name: "Sheet1"}}
+	}
+	names := sheetNames(sheets)
+
+	zw := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML(len(sheets))},
+		{"_rels/.rels", xmlHeader + `<Relationships xmlns="` + relationshipsNS + `">` +
+			`<Relationship Id="rId1" Type="` + officeDocRelNS + `/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", workbookXML(names)},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML(len(sheets))},
+		{"xl/styles.xml", stylesXML},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	for i, sheet := range sheets {
+		f, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err := writeWorksheet(f, sheet); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func contentTypesXML(sheetCount int) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheetCount; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func workbookXML(names []string) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<workbook xmlns="` + spreadsheetNS + `" xmlns:r="` + officeDocRelNS + `"><sheets>`)
+	for i, name := range names {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func workbookRelsXML(sheetCount int) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Relationships xmlns="` + relationshipsNS + `">`)
+	for i := 1; i <= sheetCount; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="%s/worksheet" Target="worksheets/sheet%d.xml"/>`, i, officeDocRelNS, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="%s/styles" Target="styles.xml"/>`, sheetCount+1, officeDocRelNS)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+func writeWorksheet(w io.Writer, sheet Sheet) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xmlHeader)
+	bw.WriteString(`<worksheet xmlns="` + spreadsheetNS + `">`)
+	bw.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	bw.WriteString(`<sheetData>`)
+
+	header := make([]any, len(sheet.Columns))
+	for i, column := range sheet.Columns {
+		header[i] = column
+	}
+	writeRow(bw, 1, header, true)
+	for i, row := range sheet.Rows {
+		writeRow(bw, i+2, row, false)
+	}
+
+	bw.WriteString(`</sheetData></worksheet>`)
+	return bw.Flush()
+}
+
+func writeRow(w *bufio.Writer, rowNumber int, cells []any, header bool) {
+	fmt.Fprintf(w, `<row r="%d">`, rowNumber)
+	for i, cell := range cells {
+		ref := columnName(i) + strconv.Itoa(rowNumber)
+		style := ""
+		if header {
+			style = ` s="1"`
+		}
+
+		switch v := cell.(type) {
+		case nil:
+			continue
+		case int, int64, float64:
+			fmt.Fprintf(w, `<c r="%s"%s><v>%s</v></c>`, ref, style, formatCell(v))
+		case bool:
+			value := "0"
+			if v {
+				value = "1"
+			}
+			fmt.Fprintf(w, `<c r="%s"%s t="b"><v>%s</v></c>`, ref, style, value)
+		default:
+			text := formatCell(v)
+			if text == "" {
+				continue
+			}
+			space := ""
+			if strings.TrimSpace(text) != text {
+				space = ` xml:space="preserve"`
+			}
+			fmt.Fprintf(w, `<c r="%s"%s t="inlineStr"><is><t%s>%s</t></is></c>`, ref, style, space, escapeXML(text))
+		}
+	}
+	w.WriteString(`</row>`)
+}
+
+// columnName converts a zero-based column index to its letters: 0 is A, 25 is Z, 26 is AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// sheetNames makes sheet names valid and unique: no []:*?/\ and at most 31 characters
+func sheetNames(sheets []Sheet) []string {
+	used := make(map[string]bool, len(sheets))
+	names := make([]string, len(sheets))
+	for i, sheet := range sheets {
+		name := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\`, r) {
+				return '_'
+			}
+			return r
+		}, strings.TrimSpace(sheet.Name))
+		if name == "" {
+			name = fmt.Sprintf("Sheet%d", i+1)
+		}
+		name = truncateRunes(name, maxSheetNameLength)
+
+		candidate := name
+		for n := 2; used[strings.ToLower(candidate)]; n++ {
+			suffix := fmt.Sprintf(" (%d)", n)
+			candidate = truncateRunes(name, maxSheetNameLength-len(suffix)) + suffix
+		}
+		used[strings.ToLower(candidate)] = true
+		names[i] = candidate
+	}
+	return names
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) > max {
+		return string(runes[:max])
+	}
+	return s
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -4,8 +4,11 @@ import (
 	"craftsbite-backend/internal/models"
 	"craftsbite-backend/internal/services"
 	"craftsbite-backend/internal/utils"
+	"errors"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,25 +20,52 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// RegisterRequest represents registration request body
+// RegisterRequest represents self-registration request body. Self-registered accounts are
+// employees; other roles are only given through invitations.
 type RegisterRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
-	Role     string `json:"role" binding:"required"`
+	Role     string `json:"role"`
+}
+
+// ForgotPasswordRequest represents forgot password request body
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest represents reset password request body
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// ChangePasswordRequest represents change password request body
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
+// VerifyTwoFactorRequest represents the second login step request body. Code is a TOTP
+// code or a recovery code.
+type VerifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
 }
 
 // AuthHandler handles authentication endpoints
 type AuthHandler struct {
-	authService services.AuthService
-	userService services.UserService
+	authService         services.AuthService
+	registrationService services.RegistrationService
+	passwordService     services.PasswordService
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService services.AuthService, userService services.UserService) *AuthHandler {
+func NewAuthHandler(authService services.AuthService, registrationService services.RegistrationService, passwordService services.PasswordService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		userService: userService,
+		authService:         authService,
+		registrationService: registrationService,
+		passwordService:     passwordService,
 	}
 }
 
@@ -47,24 +77,123 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(req.Email, req.Password)
+	response, err := h.authService.Login(req.Email, req.Password, clientInfo(c))
+	if loginThrottled(c, err) {
+		return
+	}
 	if err != nil {
 		utils.ErrorResponse(c, 401, "INVALID_CREDENTIALS", err.Error())
 		return
 	}
 
-	setAuthCookie(c, response.Token, response.ExpiresAt)
+	// The password was right but no session is started until the second step
+	if response.TwoFactor != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data": gin.H{
+				"two_factor": response.TwoFactor,
+			},
+		})
+		return
+	}
+
+	setSessionCookies(c, response)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"user":               response.User,
+			"expires_at":         response.ExpiresAt,
+			"refresh_expires_at": response.RefreshExpiresAt,
+		},
+	})
+}
+
+// VerifyTwoFactor completes a login with the challenge from Login and a TOTP or recovery
+// code. When the challenge enrolled the user, the new recovery codes are returned once.
+// POST /api/v1/auth/login/2fa
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req VerifyTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	response, err := h.authService.VerifyTwoFactor(req.ChallengeToken, req.Code, clientInfo(c))
+	if loginThrottled(c, err) {
+		return
+	}
+	if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+		utils.ErrorResponse(c, 401, "INVALID_TWO_FACTOR_CODE", err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrInvalidLoginChallenge) {
+		utils.ErrorResponse(c, 401, "INVALID_LOGIN_CHALLENGE", err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 400, "TWO_FACTOR_FAILED", err.Error())
+		return
+	}
+
+	setSessionCookies(c, response)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"user":               response.User,
+			"expires_at":         response.ExpiresAt,
+			"refresh_expires_at": response.RefreshExpiresAt,
+			"recovery_codes":     response.RecoveryCodes,
+		},
+	})
+}
+
+// loginThrottled answers 429 with Retry-After and returns true when a login was refused by
+// the login protection
+func loginThrottled(c *gin.Context, err error) bool {
+	var throttled *services.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+	code := "LOGIN_THROTTLED"
+	if throttled.Locked {
+		code = "LOGIN_LOCKED"
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	utils.ErrorResponse(c, 429, code, err.Error())
+	return true
+}
+
+// Refresh exchanges the refresh token cookie for a new access token and rotated refresh token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	refreshToken, err := c.Cookie(refreshCookieName)
+	if err != nil {
+		utils.ErrorResponse(c, 401, "UNAUTHORIZED", "Refresh token required")
+		return
+	}
+
+	response, err := h.authService.Refresh(refreshToken)
+	if err != nil {
+		expireSessionCookies(c)
+		utils.ErrorResponse(c, 401, "INVALID_REFRESH_TOKEN", err.Error())
+		return
+	}
+
+	setSessionCookies(c, response)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"user":       response.User,
-			"expires_at": response.ExpiresAt,
+			"user":               response.User,
+			"expires_at":         response.ExpiresAt,
+			"refresh_expires_at": response.RefreshExpiresAt,
 		},
 	})
 }
 
-// Register handles user registration
+// Register handles self-registration, which is only available when registration is open
+// and is limited to employees with an allowed email domain
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -72,47 +201,163 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Validate role
-	validRoles := map[string]bool{
-		"employee":  true,
-		"team_lead": true,
-		"admin":     true,
-		"logistics": true,
+	if req.Role != "" && req.Role != models.RoleEmployee.String() {
+		utils.ErrorResponse(c, 400, "INVALID_ROLE", "Self-registration is limited to the employee role, other roles need an invitation")
+		return
 	}
-	if !validRoles[req.Role] {
-		utils.ErrorResponse(c, 400, "INVALID_ROLE", "Role must be one of: employee, team_lead, admin, logistics")
+
+	user, err := h.registrationService.Register(req.Name, req.Email, req.Password, clientInfo(c))
+	switch {
+	case errors.Is(err, services.ErrRegistrationClosed):
+		utils.ErrorResponse(c, 403, "REGISTRATION_CLOSED", "Registration is by invitation only")
+		return
+	case errors.Is(err, services.ErrEmailDomainNotAllowed):
+		utils.ErrorResponse(c, 403, "EMAIL_DOMAIN_NOT_ALLOWED", "Registration is not open to this email domain")
+		return
+	case err != nil:
+		utils.ErrorResponse(c, 400, "REGISTRATION_FAILED", err.Error())
 		return
 	}
 
-	// Create user using UserService
-	userInput := services.CreateUserInput{
-		Email:                 req.Email,
-		Name:                  req.Name,
-		Password:              req.Password,
-		Role:                  models.Role(req.Role),
-		DefaultMealPreference: "opt_in",
+	h.loginNewUser(c, user, req.Password, "User registered")
+}
+
+// GetInvitation shows an invitee what they are invited to before they accept
+// GET /api/v1/auth/accept-invite?token=
+func (h *AuthHandler) GetInvitation(c *gin.Context) {
+	invitation, err := h.registrationService.GetInvitation(c.Query("token"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "INVALID_INVITATION", err.Error())
+		return
+	}
+
+	data := gin.H{
+		"email":      invitation.Email,
+		"role":       invitation.Role,
+		"expires_at": invitation.ExpiresAt,
+	}
+	if invitation.Team != nil {
+		data["team_name"] = invitation.Team.Name
+	}
+	utils.SuccessResponse(c, 200, data, "Invitation retrieved successfully")
+}
+
+// AcceptInvite creates the invitee's account from an invitation token and logs them in
+// POST /api/v1/auth/accept-invite
+func (h *AuthHandler) AcceptInvite(c *gin.Context) {
+	var input services.AcceptInvitationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
 	}
 
-	user, err := h.userService.CreateUser(userInput)
+	user, err := h.registrationService.AcceptInvitation(input, clientInfo(c))
 	if err != nil {
-		utils.ErrorResponse(c, 400, "REGISTRATION_FAILED", err.Error())
+		utils.ErrorResponse(c, 400, "INVALID_INVITATION", err.Error())
 		return
 	}
 
-	// Auto-login: generate token for the newly created user
-	loginResponse, err := h.authService.Login(req.Email, req.Password)
+	h.loginNewUser(c, user, input.Password, "Invitation accepted")
+}
+
+// loginNewUser starts a session for an account that was just created
+func (h *AuthHandler) loginNewUser(c *gin.Context, user *models.User, password, message string) {
+	loginResponse, err := h.authService.Login(user.Email, password, clientInfo(c))
 	if err != nil {
 		// User created but login failed - still return success with user data
-		utils.SuccessResponse(c, 201, user, "User registered successfully. Please login.")
+		utils.SuccessResponse(c, 201, user, message+" successfully. Please login.")
+		return
+	}
+	// The tokens only travel in cookies, as in Login
+	if loginResponse.TwoFactor != nil {
+		utils.SuccessResponse(c, 201, gin.H{
+			"two_factor": loginResponse.TwoFactor,
+		}, message+" successfully. Set up two-factor authentication to finish logging in.")
+		return
+	}
+
+	setSessionCookies(c, loginResponse)
+
+	utils.SuccessResponse(c, 201, gin.H{
+		"user":               loginResponse.User,
+		"expires_at":         loginResponse.ExpiresAt,
+		"refresh_expires_at": loginResponse.RefreshExpiresAt,
+	}, message+" and logged in successfully")
+}
+
+// ForgotPassword emails a password reset link. The response is the same whether or not
+// the email belongs to an account.
+// POST /api/v1/auth/forgot-password
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	if err := h.passwordService.ForgotPassword(req.Email, clientInfo(c)); err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", "Failed to process the request")
 		return
 	}
 
-	utils.SuccessResponse(c, 201, loginResponse, "User registered and logged in successfully")
+	utils.SuccessResponse(c, 200, nil, "If an account exists for this email, a password reset link has been sent")
 }
 
-// Logout handles user logout (placeholder)
+// ResetPassword sets a new password with a reset token and signs the user out everywhere
+// POST /api/v1/auth/reset-password
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	err := h.passwordService.ResetPassword(req.Token, req.Password, clientInfo(c))
+	if errors.Is(err, services.ErrInvalidResetToken) {
+		utils.ErrorResponse(c, 400, "INVALID_RESET_TOKEN", err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 400, "RESET_FAILED", err.Error())
+		return
+	}
+
+	expireSessionCookies(c)
+	utils.SuccessResponse(c, 200, nil, "Password reset successfully. Please login with your new password.")
+}
+
+// ChangePassword replaces the current user's password and signs out their other sessions
+// POST /api/v1/auth/change-password
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	err := h.passwordService.ChangePassword(c.GetString("user_id"), c.GetString("session_id"), req.CurrentPassword, req.NewPassword, clientInfo(c))
+	if errors.Is(err, services.ErrIncorrectPassword) {
+		utils.ErrorResponse(c, 400, "INCORRECT_PASSWORD", err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 400, "CHANGE_PASSWORD_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Password changed successfully")
+}
+
+// Logout revokes the current session and clears the session cookies
 func (h *AuthHandler) Logout(c *gin.Context) {
-	expireAuthCookie(c)
+	if sessionID, exists := c.Get("session_id"); exists {
+		if err := h.authService.Logout(sessionID.(string)); err != nil {
+			utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+			return
+		}
+	}
+
+	expireSessionCookies(c)
 	utils.SuccessResponse(c, 200, nil, "Logout successful")
 }
 
@@ -133,13 +378,37 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	utils.SuccessResponse(c, 200, user, "User retrieved successfully")
 }
 
-func setAuthCookie(c *gin.Context, token string, expiresAt time.Time) {
+const (
+	authCookieName    = "auth_token"
+	refreshCookieName = "refresh_token"
+	// refreshCookiePath keeps the refresh token off every request except the auth endpoints
+	refreshCookiePath = "/api/v1/auth"
+)
+
+func clientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+func setSessionCookies(c *gin.Context, response *services.LoginResponse) {
+	setCookie(c, authCookieName, response.Token, "/", response.ExpiresAt)
+	setCookie(c, refreshCookieName, response.RefreshToken, refreshCookiePath, response.RefreshExpiresAt)
+}
+
+func expireSessionCookies(c *gin.Context) {
+	expireCookie(c, authCookieName, "/")
+	expireCookie(c, refreshCookieName, refreshCookiePath)
+}
+
+func setCookie(c *gin.Context, name, value, path string, expiresAt time.Time) {
 	isProd := os.Getenv("ENV") == "production"
 
 	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "auth_token",
-		Value:    token,
-		Path:     "/",
+		Name:     name,
+		Value:    value,
+		Path:     path,
 		Expires:  expiresAt,
 		MaxAge:   int(time.Until(expiresAt).Seconds()),
 		HttpOnly: true, // JS cannot read this
@@ -148,12 +417,12 @@ func setAuthCookie(c *gin.Context, token string, expiresAt time.Time) {
 	})
 }
 
-func expireAuthCookie(c *gin.Context) {
+func expireCookie(c *gin.Context, name, path string) {
 	isProd := os.Getenv("ENV") == "production"
 
 	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "auth_token",
-		Path:     "/",
+		Name:     name,
+		Path:     path,
 		Expires:  time.Unix(0, 0),
 		MaxAge:   -1, // browser deletes it immediately
 		HttpOnly: true,
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/authz"
+	"craftsbite-backend/internal/export"
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BillingHandler handles meal pricing and monthly statement endpoints
+type BillingHandler struct {
+	billingService services.BillingService
+}
+
+// NewBillingHandler creates a new billing handler
+func NewBillingHandler(billingService services.BillingService) *BillingHandler {
+	return &BillingHandler{billingService: billingService}
+}
+
+// ListPrices returns the meal price list
+// GET /api/v1/billing/prices
+func (h *BillingHandler) ListPrices(c *gin.Context) {
+	prices, err := h.billingService.ListPrices()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, prices, "Meal prices retrieved successfully")
+}
+
+// CreatePrice adds an effective-dated meal price
+// POST /api/v1/billing/prices
+func (h *BillingHandler) CreatePrice(c *gin.Context) {
+	var input services.CreateMealPriceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	price, err := h.billingService.CreatePrice(c.GetString("user_id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, price, "Meal price created successfully")
+}
+
+// DeletePrice removes a price that has not taken effect yet
+// DELETE /api/v1/billing/prices/:id
+func (h *BillingHandler) DeletePrice(c *gin.Context) {
+	if err := h.billingService.DeletePrice(c.Param("id")); err != nil {
+		utils.ErrorResponse(c, 400, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Meal price deleted successfully")
+}
+
+// GetMyStatement returns the current user's statement for ?month=YYYY-MM (default: current month)
+// GET /api/v1/billing/statements/me
+func (h *BillingHandler) GetMyStatement(c *gin.Context) {
+	h.userStatement(c, c.GetString("user_id"))
+}
+
+// GetUserStatement returns a user's statement
+// GET /api/v1/billing/statements/users/:user_id
+func (h *BillingHandler) GetUserStatement(c *gin.Context) {
+	h.userStatement(c, c.Param("user_id"))
+}
+
+func (h *BillingHandler) userStatement(c *gin.Context, userID string) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	statement, err := h.billingService.GetUserStatement(c.GetString("user_id"), c.GetString("role"), userID, statementMonth(c))
+	if err != nil {
+		statementError(c, err)
+		return
+	}
+
+	if format != "" {
+		sendExport(c, format, fmt.Sprintf("statement-%s-%s", statement.Month, statement.UserID), userStatementSheet(statement))
+		return
+	}
+	utils.SuccessResponse(c, 200, statement, "Statement retrieved successfully")
+}
+
+// GetTeamStatement returns the statement of every member of a team
+// GET /api/v1/billing/statements/teams/:team_id
+func (h *BillingHandler) GetTeamStatement(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	statement, err := h.billingService.GetTeamStatement(c.GetString("user_id"), c.GetString("role"), c.Param("team_id"), statementMonth(c))
+	if err != nil {
+		statementError(c, err)
+		return
+	}
+
+	if format != "" {
+		sendExport(c, format, fmt.Sprintf("statement-%s-team-%s", statement.Month, statement.TeamID), groupStatementSheet(statement))
+		return
+	}
+	utils.SuccessResponse(c, 200, statement, "Team statement retrieved successfully")
+}
+
+// GetOrganizationStatement returns the statement of every active user
+// GET /api/v1/billing/statements
+func (h *BillingHandler) GetOrganizationStatement(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	statement, err := h.billingService.GetOrganizationStatement(statementMonth(c))
+	if err != nil {
+		statementError(c, err)
+		return
+	}
+
+	if format != "" {
+		sendExport(c, format, fmt.Sprintf("statement-%s", statement.Month), groupStatementSheet(statement))
+		return
+	}
+	utils.SuccessResponse(c, 200, statement, "Statement retrieved successfully")
+}
+
+func statementMonth(c *gin.Context) string {
+	return c.DefaultQuery("month", time.Now().Format("2006-01"))
+}
+
+func statementError(c *gin.Context, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ErrorResponse(c, 403, "FORBIDDEN", err.Error())
+		return
+	}
+	utils.ErrorResponse(c, 400, "STATEMENT_FAILED", err.Error())
+}
+
+// userStatementSheet renders one row per billed meal followed by a total row
+func userStatementSheet(statement *services.MonthlyStatement) export.Sheet {
+	sheet := export.Sheet{
+		Name:    "statement",
+		Columns: []string{"date", "meal_type", "unit_price", "company_share", "employee_share", "currency", "source"},
+	}
+	for _, line := range statement.Lines {
+		unitPrice := formatMinorUnits(line.UnitPrice)
+		if !line.Priced {
+			unitPrice = ""
+		}
+		sheet.AddRow(
+			line.Date,
+			string(line.MealType),
+			unitPrice,
+			formatMinorUnits(line.CompanyShare),
+			formatMinorUnits(line.EmployeeShare),
+			statement.Currency,
+			line.Source,
+		)
+	}
+	sheet.AddRow(
+		"total",
+		statement.Totals.Meals,
+		formatMinorUnits(statement.Totals.Total),
+		formatMinorUnits(statement.Totals.CompanyShare),
+		formatMinorUnits(statement.Totals.EmployeeShare),
+		statement.Currency,
+		"",
+	)
+	return sheet
+}
+
+// groupStatementSheet renders one row per user
+func groupStatementSheet(statement *services.GroupStatement) export.Sheet {
+	sheet := export.Sheet{
+		Name:    "users",
+		Columns: []string{"user_id", "name", "email", "month", "meals", "unpriced_meals", "total", "company_share", "employee_share", "currency"},
+	}
+	for _, user := range statement.Users {
+		sheet.AddRow(
+			user.UserID,
+			user.Name,
+			user.Email,
+			user.Month,
+			user.Totals.Meals,
+			user.Totals.UnpricedMeals,
+			formatMinorUnits(user.Totals.Total),
+			formatMinorUnits(user.Totals.CompanyShare),
+			formatMinorUnits(user.Totals.EmployeeShare),
+			user.Currency,
+		)
+	}
+	return sheet
+}
+
+// formatMinorUnits renders an amount in minor units as a decimal, e.g. 12050 as "120.50"
+func formatMinorUnits(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
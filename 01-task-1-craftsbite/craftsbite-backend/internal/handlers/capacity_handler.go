@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CapacityHandler handles meal capacity and waitlist endpoints
+type CapacityHandler struct {
+	capacityService services.CapacityService
+}
+
+// NewCapacityHandler creates a new capacity handler
+func NewCapacityHandler(capacityService services.CapacityService) *CapacityHandler {
+	return &CapacityHandler{capacityService: capacityService}
+}
+
+// GetDayCapacities returns the seat usage of every meal with a capacity on a date
+// GET /api/v1/schedules/:date/capacities
+func (h *CapacityHandler) GetDayCapacities(c *gin.Context) {
+	capacities, err := h.capacityService.GetDayCapacities(c.Param("date"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, capacities, "Capacities retrieved successfully")
+}
+
+// SetCapacity limits the seats of a meal on a scheduled date
+// PUT /api/v1/schedules/:date/capacities/:meal_type
+func (h *CapacityHandler) SetCapacity(c *gin.Context) {
+	var input services.MealCapacityInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	status, err := h.capacityService.SetCapacity(c.GetString("user_id"), c.Param("date"), c.Param("meal_type"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, status, "Capacity saved successfully")
+}
+
+// DeleteCapacity makes a meal unlimited again on a date
+// DELETE /api/v1/schedules/:date/capacities/:meal_type
+func (h *CapacityHandler) DeleteCapacity(c *gin.Context) {
+	if err := h.capacityService.DeleteCapacity(c.Param("date"), c.Param("meal_type")); err != nil {
+		utils.ErrorResponse(c, 400, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Capacity removed successfully")
+}
+
+// GetWaitlist returns a meal's waitlist in promotion order
+// GET /api/v1/schedules/:date/capacities/:meal_type/waitlist
+func (h *CapacityHandler) GetWaitlist(c *gin.Context) {
+	waitlist, err := h.capacityService.GetWaitlist(c.Param("date"), c.Param("meal_type"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, waitlist, "Waitlist retrieved successfully")
+}
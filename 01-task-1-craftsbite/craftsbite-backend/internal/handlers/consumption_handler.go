@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConsumptionHandler handles canteen check-ins and consumption reports
+type ConsumptionHandler struct {
+	consumptionService services.ConsumptionService
+}
+
+// NewConsumptionHandler creates a new consumption handler
+func NewConsumptionHandler(consumptionService services.ConsumptionService) *ConsumptionHandler {
+	return &ConsumptionHandler{consumptionService: consumptionService}
+}
+
+// GetMyCheckInToken issues the token the current user shows as a QR code at the canteen
+// GET /api/v1/users/me/check-in-token
+func (h *ConsumptionHandler) GetMyCheckInToken(c *gin.Context) {
+	token, err := h.consumptionService.IssueCheckInToken(c.GetString("user_id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "TOKEN_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, token, "Check-in token issued successfully")
+}
+
+// CheckIn records that a user was served a meal, identified by QR token, user ID or email
+// POST /api/v1/check-ins
+func (h *ConsumptionHandler) CheckIn(c *gin.Context) {
+	var input services.CheckInInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := h.consumptionService.CheckIn(c.GetString("user_id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "CHECK_IN_FAILED", err.Error())
+		return
+	}
+
+	message := "Checked in successfully"
+	if !result.OptedIn {
+		message = "Checked in as a walk-in (not opted in)"
+	}
+	utils.SuccessResponse(c, 201, result, message)
+}
+
+// ListCheckIns returns the check-ins of a meal for ?date= (default: today) and ?meal_type=
+// GET /api/v1/check-ins
+func (h *ConsumptionHandler) ListCheckIns(c *gin.Context) {
+	mealType := c.Query("meal_type")
+	if mealType == "" {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "meal_type query parameter is required")
+		return
+	}
+
+	checkIns, err := h.consumptionService.ListCheckIns(c.DefaultQuery("date", time.Now().Format("2006-01-02")), mealType)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, checkIns, "Check-ins retrieved successfully")
+}
+
+// UndoCheckIn removes a check-in recorded by mistake
+// DELETE /api/v1/check-ins/:id
+func (h *ConsumptionHandler) UndoCheckIn(c *gin.Context) {
+	if err := h.consumptionService.UndoCheckIn(c.Param("id")); err != nil {
+		utils.ErrorResponse(c, 400, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Check-in removed successfully")
+}
+
+// GetReport compares opted-in with checked-in meals between ?start= and ?end=, optionally for ?team_id=
+// GET /api/v1/check-ins/report
+func (h *ConsumptionHandler) GetReport(c *gin.Context) {
+	startDate := c.Query("start")
+	endDate := c.Query("end")
+	if startDate == "" || endDate == "" {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "start and end query parameters are required")
+		return
+	}
+
+	report, err := h.consumptionService.GetReport(startDate, endDate, c.Query("team_id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "REPORT_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, report, "Consumption report retrieved successfully")
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CutoffPolicyHandler handles per-meal cutoff policy endpoints
+type CutoffPolicyHandler struct {
+	cutoffService services.CutoffPolicyService
+}
+
+// NewCutoffPolicyHandler creates a new cutoff policy handler
+func NewCutoffPolicyHandler(cutoffService services.CutoffPolicyService) *CutoffPolicyHandler {
+	return &CutoffPolicyHandler{cutoffService: cutoffService}
+}
+
+// ListPolicies returns the default cutoff of every meal type
+// GET /api/v1/meals/cutoff-policies
+func (h *CutoffPolicyHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.cutoffService.ListPolicies()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, policies, "Cutoff policies retrieved successfully")
+}
+
+// SetPolicy creates or replaces a meal type's cutoff policy
+// PUT /api/v1/meals/cutoff-policies/:meal_type
+func (h *CutoffPolicyHandler) SetPolicy(c *gin.Context) {
+	var input services.CutoffPolicyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	policy, err := h.cutoffService.SetPolicy(c.GetString("user_id"), c.Param("meal_type"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, policy, "Cutoff policy saved successfully")
+}
+
+// DeletePolicy reverts a meal type to the global cutoff
+// DELETE /api/v1/meals/cutoff-policies/:meal_type
+func (h *CutoffPolicyHandler) DeletePolicy(c *gin.Context) {
+	if err := h.cutoffService.DeletePolicy(c.Param("meal_type")); err != nil {
+		utils.ErrorResponse(c, 400, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Cutoff policy deleted successfully")
+}
+
+// GetDayCutoffs returns the effective cutoff and deadline of every meal type on a date
+// GET /api/v1/schedules/:date/cutoffs
+func (h *CutoffPolicyHandler) GetDayCutoffs(c *gin.Context) {
+	cutoffs, err := h.cutoffService.GetDayCutoffs(c.Param("date"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, cutoffs, "Cutoffs retrieved successfully")
+}
+
+// SetDayOverride overrides a meal type's cutoff on a scheduled date
+// PUT /api/v1/schedules/:date/cutoffs/:meal_type
+func (h *CutoffPolicyHandler) SetDayOverride(c *gin.Context) {
+	var input services.CutoffPolicyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	policy, err := h.cutoffService.SetDayOverride(c.GetString("user_id"), c.Param("date"), c.Param("meal_type"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, policy, "Cutoff override saved successfully")
+}
+
+// DeleteDayOverride removes a date's cutoff override
+// DELETE /api/v1/schedules/:date/cutoffs/:meal_type
+func (h *CutoffPolicyHandler) DeleteDayOverride(c *gin.Context) {
+	if err := h.cutoffService.DeleteDayOverride(c.Param("date"), c.Param("meal_type")); err != nil {
+		utils.ErrorResponse(c, 400, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Cutoff override deleted successfully")
+}
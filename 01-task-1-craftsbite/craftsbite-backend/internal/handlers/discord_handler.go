@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/discord"
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/sse"
+	"craftsbite-backend/internal/utils"
+	"craftsbite-backend/pkg/logger"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DiscordHandler handles Discord interactions and account links
+type DiscordHandler struct {
+	discordService   services.DiscordService
+	headcountService services.HeadcountService
+	hub              *sse.Hub
+	publicKey        ed25519.PublicKey
+}
+
+// NewDiscordHandler creates a new Discord handler.
+// A nil public key rejects every interaction.
+func NewDiscordHandler(discordService services.DiscordService, headcountService services.HeadcountService, hub *sse.Hub, publicKey ed25519.PublicKey) *DiscordHandler {
+	return &DiscordHandler{
+		discordService:   discordService,
+		headcountService: headcountService,
+		hub:              hub,
+		publicKey:        publicKey,
+	}
+}
+
+// Interactions receives slash commands from Discord
+// POST /interactions
+func (h *DiscordHandler) Interactions(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.String(http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	signature := c.GetHeader("X-Signature-Ed25519")
+	timestamp := c.GetHeader("X-Signature-Timestamp")
+	if !discord.VerifySignature(h.publicKey, signature, timestamp, body) {
+		c.String(http.StatusUnauthorized, "invalid request signature")
+		return
+	}
+
+	var interaction discord.Interaction
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		c.String(http.StatusBadRequest, "invalid interaction payload")
+		return
+	}
+
+	switch interaction.Type {
+	case discord.InteractionTypePing:
+		c.JSON(http.StatusOK, discord.InteractionResponse{Type: discord.ResponseTypePong})
+		return
+	case discord.InteractionTypeApplicationCommand:
+	default:
+		c.String(http.StatusBadRequest, "unsupported interaction type")
+		return
+	}
+
+	result, err := h.discordService.HandleCommand(interaction.CallerID(), interaction.Data)
+	if err != nil {
+		logger.Error("Discord command failed",
+			zap.String("caller_id", interaction.CallerID()),
+			zap.String("command", interaction.Data.Name),
+			zap.Error(err),
+		)
+		c.JSON(http.StatusOK, discord.EphemeralMessage("⚠️ Something went wrong. Please try again later."))
+		return
+	}
+
+	if result.UpdatedDate != "" {
+		if summary, broadcastErr := h.headcountService.GetHeadcountByDate(result.UpdatedDate); broadcastErr == nil {
+			if payload, marshalErr := json.Marshal(summary); marshalErr == nil {
+				h.hub.Broadcast(result.UpdatedDate, string(payload))
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, discord.EphemeralMessage(result.Content))
+}
+
+type linkDiscordRequest struct {
+	DiscordUserID string `json:"discord_user_id" binding:"required"`
+	UserID        string `json:"user_id" binding:"required"`
+}
+
+// GET /api/v1/admin/discord-links
+func (h *DiscordHandler) ListLinks(c *gin.Context) {
+	links, err := h.discordService.ListLinks()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, links, "Discord links retrieved")
+}
+
+// POST /api/v1/admin/discord-links
+func (h *DiscordHandler) LinkUser(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, 401, "UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	var req linkDiscordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	link, err := h.discordService.LinkUser(adminID.(string), req.DiscordUserID, req.UserID)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "LINK_DISCORD_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, link, "Discord account linked successfully")
+}
+
+// DELETE /api/v1/admin/discord-links/:discord_user_id
+func (h *DiscordHandler) UnlinkUser(c *gin.Context) {
+	discordUserID := c.Param("discord_user_id")
+	if discordUserID == "" {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Discord user ID is required")
+		return
+	}
+
+	if err := h.discordService.UnlinkUser(discordUserID); err != nil {
+		utils.ErrorResponse(c, 400, "UNLINK_DISCORD_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Discord account unlinked successfully")
+}
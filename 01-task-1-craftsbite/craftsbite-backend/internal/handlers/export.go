@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/export"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+	"craftsbite-backend/pkg/logger"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// exportFormat returns the format requested with ?format=csv|xlsx or, failing that, with
+// the Accept header. It returns "" for the default JSON response and responds with 400
+// and false to an unknown format.
+func exportFormat(c *gin.Context) (string, bool) {
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		accept := c.GetHeader("Accept")
+		switch {
+		case strings.Contains(accept, export.ContentTypeXLSX):
+			format = export.FormatXLSX
+		case strings.Contains(accept, "text/csv"):
+			format = export.FormatCSV
+		}
+	}
+
+	switch format {
+	case "", "json":
+		return "", true
+	case export.FormatCSV, export.FormatXLSX:
+		return format, true
+	default:
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "format must be json, csv or xlsx")
+		return "", false
+	}
+}
+
+// sendExport streams sheets as a CSV or XLSX attachment. CSV carries the first sheet only.
+func sendExport(c *gin.Context, format, basename string, sheets ...export.Sheet) {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, basename, format))
+	c.Header("Content-Type", export.ContentType(format))
+	c.Status(200)
+
+	// The status is already sent, so a failed write can only be logged
+	if err := export.Write(c.Writer, format, sheets); err != nil {
+		logger.Warn("Failed to write export", zap.String("file", basename), zap.Error(err))
+	}
+}
+
+// exportName names an export after a report and the dates it covers
+func exportName(report, start, end string) string {
+	if end == "" || end == start {
+		return fmt.Sprintf("%s-%s", report, start)
+	}
+	return fmt.Sprintf("%s-%s_%s", report, start, end)
+}
+
+// sortedMealKeys returns the meal types of a per-meal map in a stable order
+func sortedMealKeys[V any](meals map[string]V) []string {
+	keys := make([]string, 0, len(meals))
+	for key := range meals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dietaryColumns are the dietary breakdown columns shared by headcount exports
+func dietaryColumns() []string {
+	columns := make([]string, 0, len(models.DietaryRestrictions)+1)
+	for _, restriction := range models.DietaryRestrictions {
+		columns = append(columns, "dietary_"+restriction.String())
+	}
+	return append(columns, "with_allergens")
+}
+
+// dietaryCells renders a dietary breakdown in the order of dietaryColumns
+func dietaryCells(dietary *services.DietaryCounts) []any {
+	cells := make([]any, 0, len(models.DietaryRestrictions)+1)
+	for _, restriction := range models.DietaryRestrictions {
+		count := 0
+		if dietary != nil {
+			count = dietary.Restrictions[restriction]
+		}
+		cells = append(cells, count)
+	}
+	withAllergens := 0
+	if dietary != nil {
+		withAllergens = dietary.WithAllergens
+	}
+	return append(cells, withAllergens)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/sse"
+	"craftsbite-backend/internal/utils"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GuestBookingHandler handles guest meal booking endpoints
+type GuestBookingHandler struct {
+	guestService     services.GuestBookingService
+	headcountService services.HeadcountService
+	hub              *sse.Hub
+}
+
+// NewGuestBookingHandler creates a new guest booking handler
+func NewGuestBookingHandler(guestService services.GuestBookingService, headcountService services.HeadcountService, hub *sse.Hub) *GuestBookingHandler {
+	return &GuestBookingHandler{
+		guestService:     guestService,
+		headcountService: headcountService,
+		hub:              hub,
+	}
+}
+
+// ListMyBookings lists the guest bookings made by the current user
+// GET /api/v1/meals/guests
+func (h *GuestBookingHandler) ListMyBookings(c *gin.Context) {
+	bookings, err := h.guestService.ListMyBookings(c.GetString("user_id"))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, bookings, "Guest bookings retrieved successfully")
+}
+
+// CreateBooking books meals for guests hosted by the current user
+// POST /api/v1/meals/guests
+func (h *GuestBookingHandler) CreateBooking(c *gin.Context) {
+	var input services.CreateGuestBookingInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	booking, err := h.guestService.CreateBooking(c.GetString("user_id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "GUEST_BOOKING_FAILED", err.Error())
+		return
+	}
+
+	h.broadcast(booking.Date)
+	utils.SuccessResponse(c, 201, booking, "Guest booking created successfully")
+}
+
+// CancelBooking cancels one of the current user's guest bookings
+// DELETE /api/v1/meals/guests/:id
+func (h *GuestBookingHandler) CancelBooking(c *gin.Context) {
+	booking, err := h.guestService.CancelBooking(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "CANCEL_FAILED", err.Error())
+		return
+	}
+
+	h.broadcast(booking.Date)
+	utils.SuccessResponse(c, 200, nil, "Guest booking cancelled successfully")
+}
+
+// ListByDate lists every active guest booking of a date
+// GET /api/v1/headcount/:date/guests
+func (h *GuestBookingHandler) ListByDate(c *gin.Context) {
+	bookings, err := h.guestService.ListByDate(c.Param("date"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, bookings, "Guest bookings retrieved successfully")
+}
+
+// broadcast pushes the updated headcount of a date to live subscribers
+func (h *GuestBookingHandler) broadcast(date string) {
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	if summary, err := h.headcountService.GetHeadcountByDate(date); err == nil {
+		if payload, marshalErr := json.Marshal(summary); marshalErr == nil {
+			h.hub.Broadcast(date, string(payload))
+		}
+	}
+}
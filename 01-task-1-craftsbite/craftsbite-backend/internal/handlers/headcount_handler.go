@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"craftsbite-backend/internal/export"
 	"craftsbite-backend/internal/services"
 	"craftsbite-backend/internal/sse"
 	"craftsbite-backend/internal/utils"
@@ -8,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,13 +17,15 @@ import (
 // HeadcountHandler handles headcount reporting endpoints
 type HeadcountHandler struct {
 	headcountService services.HeadcountService
+	forecastService  services.ForecastService
 	hub              *sse.Hub
 }
 
 // NewHeadcountHandler creates a new headcount handler
-func NewHeadcountHandler(headcountService services.HeadcountService, hub *sse.Hub) *HeadcountHandler {
+func NewHeadcountHandler(headcountService services.HeadcountService, forecastService services.ForecastService, hub *sse.Hub) *HeadcountHandler {
 	return &HeadcountHandler{
 		headcountService: headcountService,
+		forecastService:  forecastService,
 		hub:              hub,
 	}
 }
@@ -38,7 +42,8 @@ func (h *HeadcountHandler) GetTodayHeadcount(c *gin.Context) {
 	utils.SuccessResponse(c, 200, summary, "Today's and tomorrow's headcount retrieved successfully")
 }
 
-// GetHeadcountByDate returns headcount summary for a specific date
+// GetHeadcountByDate returns headcount summary for a specific date. With ?end= it returns
+// the summary of every scheduled date up to end; ?format=csv|xlsx exports the summaries.
 // GET /api/headcount/:date
 func (h *HeadcountHandler) GetHeadcountByDate(c *gin.Context) {
 	// Get date from URL parameter
@@ -48,13 +53,37 @@ func (h *HeadcountHandler) GetHeadcountByDate(c *gin.Context) {
 		return
 	}
 
-	summary, err := h.headcountService.GetHeadcountByDate(date)
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	end := c.Query("end")
+	if end == "" && format == "" {
+		summary, err := h.headcountService.GetHeadcountByDate(date)
+		if err != nil {
+			utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+			return
+		}
+
+		utils.SuccessResponse(c, 200, summary, "Headcount retrieved successfully")
+		return
+	}
+	if end == "" {
+		end = date
+	}
+
+	summaries, err := h.headcountService.GetHeadcountRange(date, end)
 	if err != nil {
 		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, 200, summary, "Headcount retrieved successfully")
+	if format != "" {
+		sendExport(c, format, exportName("headcount", date, end), headcountSheets(summaries)...)
+		return
+	}
+	utils.SuccessResponse(c, 200, summaries, "Headcount retrieved successfully")
 }
 
 // GetDetailedHeadcount returns detailed headcount for a specific date and meal
@@ -69,12 +98,21 @@ func (h *HeadcountHandler) GetDetailedHeadcount(c *gin.Context) {
 		return
 	}
 
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
 	details, err := h.headcountService.GetDetailedHeadcount(date, mealType)
 	if err != nil {
 		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
 		return
 	}
 
+	if format != "" {
+		sendExport(c, format, exportName("headcount-"+mealType, date, ""), detailedHeadcountSheets(details)...)
+		return
+	}
 	utils.SuccessResponse(c, 200, details, "Detailed headcount retrieved successfully")
 }
 
@@ -132,6 +170,10 @@ func (h *HeadcountHandler) StreamHeadcount(c *gin.Context) {
 	})
 }
 
+// GetForecast returns the headcount forecast of the coming ?days=. With
+// ?mode=statistical each meal also gets an estimate learned from past weeks.
+// ?format=csv|xlsx exports the forecast.
+// GET /api/headcount/forecast
 func (h *HeadcountHandler) GetForecast(c *gin.Context) {
     days := 7
     if daysStr := c.Query("days"); daysStr != "" {
@@ -140,12 +182,195 @@ func (h *HeadcountHandler) GetForecast(c *gin.Context) {
         }
     }
 
+    format, ok := exportFormat(c)
+    if !ok {
+        return
+    }
+
+    mode := c.DefaultQuery("mode", services.ForecastMethodRuleBased)
+    if mode == services.ForecastMethodStatistical {
+        forecasts, err := h.forecastService.GetForecast(days)
+        if err != nil {
+            utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+            return
+        }
+        if format != "" {
+            sendExport(c, format, forecastExportName(mode, forecastDates(forecasts)), forecastSheets(forecasts)...)
+            return
+        }
+        utils.SuccessResponse(c, 200, forecasts, "Forecast retrieved successfully")
+        return
+    }
+    if mode != services.ForecastMethodRuleBased {
+        utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "mode must be rule_based or statistical")
+        return
+    }
+
     summaries, err := h.headcountService.GetForecast(days)
     if err != nil {
         utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
         return
     }
 
+    if format != "" {
+        sendExport(c, format, forecastExportName(mode, summaryDates(summaries)), headcountSheets(summaries)...)
+        return
+    }
+
     utils.SuccessResponse(c, 200, summaries, "Forecast retrieved successfully")
 }
 
+
+// GetForecastBacktest replays the statistical forecast over the past ?weeks=,
+// made ?horizon= days before each meal's snapshot, and reports its error
+// GET /api/headcount/forecast/backtest
+func (h *HeadcountHandler) GetForecastBacktest(c *gin.Context) {
+	weeks, err := strconv.Atoi(c.DefaultQuery("weeks", "0"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "weeks must be a number")
+		return
+	}
+	horizon, err := strconv.Atoi(c.DefaultQuery("horizon", "1"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "horizon must be a number")
+		return
+	}
+
+	backtest, err := h.forecastService.Backtest(weeks, horizon)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "INVALID_RANGE", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, backtest, "Forecast backtest retrieved successfully")
+}
+
+// headcountSheets renders daily summaries as a meals sheet, a sheet of the day totals
+// and a sheet of every team's meals
+func headcountSheets(summaries []*services.DailyHeadcountSummary) []export.Sheet {
+	meals := export.Sheet{
+		Name:    "meals",
+		Columns: append([]string{"date", "day_status", "meal_type", "total_active_users", "participating", "opted_out", "guests"}, dietaryColumns()...),
+	}
+	days := export.Sheet{
+		Name:    "days",
+		Columns: []string{"date", "day_status", "total_active_users", "office", "wfh", "not_set"},
+	}
+	teams := export.Sheet{
+		Name:    "teams",
+		Columns: append([]string{"date", "team_id", "team_name", "total_members", "office", "wfh", "not_set", "meal_type", "participating", "opted_out", "guests"}, dietaryColumns()...),
+	}
+
+	for _, summary := range summaries {
+		days.AddRow(summary.Date, summary.DayStatus.String(), summary.TotalActiveUsers,
+			summary.LocationSplit.Office, summary.LocationSplit.WFH, summary.LocationSplit.NotSet)
+
+		for _, mealType := range sortedMealKeys(summary.Meals) {
+			meal := summary.Meals[mealType]
+			row := []any{summary.Date, summary.DayStatus.String(), mealType, summary.TotalActiveUsers, meal.Participating, meal.OptedOut, meal.Guests}
+			meals.AddRow(append(row, dietaryCells(meal.Dietary)...)...)
+		}
+
+		for _, team := range summary.Teams {
+			for _, mealType := range sortedMealKeys(team.Meals) {
+				meal := team.Meals[mealType]
+				row := []any{summary.Date, team.TeamID, team.TeamName, team.TotalMembers,
+					team.LocationSplit.Office, team.LocationSplit.WFH, team.LocationSplit.NotSet,
+					mealType, meal.Participating, meal.OptedOut, meal.Guests}
+				teams.AddRow(append(row, dietaryCells(meal.Dietary)...)...)
+			}
+		}
+	}
+
+	return []export.Sheet{meals, days, teams}
+}
+
+// detailedHeadcountSheets renders a meal's headcount as a sheet of every user, a sheet
+// of the guest bookings and a one-row summary
+func detailedHeadcountSheets(details *services.DetailedHeadcount) []export.Sheet {
+	users := export.Sheet{
+		Name:    "participants",
+		Columns: []string{"date", "meal_type", "user_id", "name", "email", "is_participating", "source", "dietary_restrictions", "allergens"},
+	}
+	for _, group := range [][]services.ParticipantInfo{details.Participants, details.NonParticipants} {
+		for _, p := range group {
+			restrictions := make([]string, len(p.DietaryRestrictions))
+			for i, restriction := range p.DietaryRestrictions {
+				restrictions[i] = restriction.String()
+			}
+			users.AddRow(details.Date, details.MealType, p.UserID, p.Name, p.Email, p.IsParticipating,
+				p.Source, strings.Join(restrictions, ","), p.Allergens)
+		}
+	}
+
+	guests := export.Sheet{
+		Name:    "guests",
+		Columns: []string{"date", "meal_type", "booking_id", "host_user_id", "host_name", "guest_count", "guest_names", "dietary_notes", "purpose"},
+	}
+	for _, booking := range details.GuestBookings {
+		guests.AddRow(details.Date, details.MealType, booking.ID.String(), booking.HostUserID.String(), booking.Host.Name,
+			booking.GuestCount, booking.GuestNames, booking.DietaryNotes, booking.Purpose)
+	}
+
+	summary := export.Sheet{
+		Name:    "summary",
+		Columns: append([]string{"date", "meal_type", "participating", "opted_out", "guests"}, dietaryColumns()...),
+	}
+	row := []any{details.Date, details.MealType, details.TotalCount, len(details.NonParticipants), details.Guests}
+	summary.AddRow(append(row, dietaryCells(details.Dietary)...)...)
+
+	return []export.Sheet{users, guests, summary}
+}
+
+// forecastSheets renders statistical forecasts as a meals sheet and a sheet of every team's meals
+func forecastSheets(forecasts []services.DailyForecast) []export.Sheet {
+	meals := export.Sheet{
+		Name:    "meals",
+		Columns: []string{"date", "day_status", "meal_type", "rule_based", "estimate", "low", "high", "method", "observations", "confidence"},
+	}
+	teams := export.Sheet{
+		Name:    "teams",
+		Columns: []string{"date", "team_id", "team_name", "meal_type", "rule_based", "estimate", "low", "high", "method", "observations"},
+	}
+
+	for _, day := range forecasts {
+		for _, mealType := range sortedMealKeys(day.Meals) {
+			meal := day.Meals[mealType]
+			meals.AddRow(day.Date, day.DayStatus.String(), mealType, meal.RuleBased, meal.Estimate,
+				meal.Low, meal.High, meal.Method, meal.Observations, day.Confidence)
+		}
+		for _, team := range day.Teams {
+			for _, mealType := range sortedMealKeys(team.Meals) {
+				meal := team.Meals[mealType]
+				teams.AddRow(day.Date, team.TeamID, team.TeamName, mealType, meal.RuleBased, meal.Estimate,
+					meal.Low, meal.High, meal.Method, meal.Observations)
+			}
+		}
+	}
+
+	return []export.Sheet{meals, teams}
+}
+
+// forecastExportName names a forecast export after its mode and the dates it covers
+func forecastExportName(mode string, dates []string) string {
+	if len(dates) == 0 {
+		return "forecast-" + mode
+	}
+	return exportName("forecast-"+mode, dates[0], dates[len(dates)-1])
+}
+
+func summaryDates(summaries []*services.DailyHeadcountSummary) []string {
+	dates := make([]string, len(summaries))
+	for i, summary := range summaries {
+		dates[i] = summary.Date
+	}
+	return dates
+}
+
+func forecastDates(forecasts []services.DailyForecast) []string {
+	dates := make([]string, len(forecasts))
+	for i, forecast := range forecasts {
+		dates[i] = forecast.Date
+	}
+	return dates
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeadcountSnapshotHandler handles headcount snapshot endpoints
+type HeadcountSnapshotHandler struct {
+	snapshotService services.HeadcountSnapshotService
+}
+
+// NewHeadcountSnapshotHandler creates a new headcount snapshot handler
+func NewHeadcountSnapshotHandler(snapshotService services.HeadcountSnapshotService) *HeadcountSnapshotHandler {
+	return &HeadcountSnapshotHandler{
+		snapshotService: snapshotService,
+	}
+}
+
+// GetSnapshots returns the frozen headcount of every meal on a date
+// GET /api/v1/headcount/:date/snapshot
+func (h *HeadcountSnapshotHandler) GetSnapshots(c *gin.Context) {
+	date := c.Param("date")
+
+	snapshots, err := h.snapshotService.GetSnapshots(date)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, snapshots, "Headcount snapshots retrieved successfully")
+}
+
+// GetSnapshot returns the frozen headcount of a single meal with per-user breakdown
+// GET /api/v1/headcount/:date/snapshot/:meal_type
+func (h *HeadcountSnapshotHandler) GetSnapshot(c *gin.Context) {
+	date := c.Param("date")
+	mealType := c.Param("meal_type")
+
+	snapshot, err := h.snapshotService.GetSnapshot(date, mealType)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "SNAPSHOT_NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, snapshot, "Headcount snapshot retrieved successfully")
+}
+
+// CaptureSnapshot freezes the headcount of any meals on a date that have no snapshot yet
+// POST /api/v1/headcount/:date/snapshot
+func (h *HeadcountSnapshotHandler) CaptureSnapshot(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, 401, "UNAUTHORIZED", "User not authenticated")
+		return
+	}
+	capturedBy := userID.(string)
+
+	snapshots, err := h.snapshotService.CaptureSnapshot(c.Param("date"), &capturedBy)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "SNAPSHOT_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, snapshots, "Headcount snapshot captured successfully")
+}
+
+// DiffSnapshot compares the frozen headcount of a date against the live headcount
+// GET /api/v1/headcount/:date/snapshot/diff
+func (h *HeadcountSnapshotHandler) DiffSnapshot(c *gin.Context) {
+	diff, err := h.snapshotService.DiffSnapshot(c.Param("date"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, diff, "Headcount snapshot diff retrieved successfully")
+}
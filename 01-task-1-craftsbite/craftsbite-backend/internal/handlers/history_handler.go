@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"craftsbite-backend/internal/models"
 	"craftsbite-backend/internal/services"
 	"craftsbite-backend/internal/utils"
 	"strconv"
@@ -65,23 +64,10 @@ func (h *HistoryHandler) GetAuditTrail(c *gin.Context) {
 	utils.SuccessResponse(c, 200, history, "Audit trail retrieved successfully")
 }
 
-// GetUserHistoryAdmin returns the participation history for a specific user (admin/logistics only)
+// GetUserHistoryAdmin returns the participation history for a specific user
+// The route requires history:read at "all" scope
 // GET /api/v1/admin/meals/history/:user_id
 func (h *HistoryHandler) GetUserHistoryAdmin(c *gin.Context) {
-	// Get requester role from context
-	role, exists := c.Get("role")
-	if !exists {
-		utils.ErrorResponse(c, 401, "UNAUTHORIZED", "User not authenticated")
-		return
-	}
-
-	// Verify requester has permission (admin or logistics)
-	userRole := models.Role(role.(string))
-	if userRole != models.RoleAdmin && userRole != models.RoleLogistics {
-		utils.ErrorResponse(c, 403, "FORBIDDEN", "Only admins and logistics can view other users' history")
-		return
-	}
-
 	// Get target user ID from URL parameter
 	targetUserID := c.Param("user_id")
 	if targetUserID == "" {
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/authz"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvitationHandler handles the administration of invitations
+type InvitationHandler struct {
+	registrationService services.RegistrationService
+	authorizer          authz.Authorizer
+}
+
+// NewInvitationHandler creates a new invitation handler
+func NewInvitationHandler(registrationService services.RegistrationService, authorizer authz.Authorizer) *InvitationHandler {
+	return &InvitationHandler{
+		registrationService: registrationService,
+		authorizer:          authorizer,
+	}
+}
+
+// CreateInvitation invites someone by email with a role and optional team
+// POST /api/v1/admin/invitations
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var input services.CreateInvitationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	// Inviting with any role but employee is assigning a role, which needs its own permission
+	if input.Role != "" && input.Role != models.RoleEmployee.String() {
+		canAssign, err := h.authorizer.HasPermission(c.GetString("role"), authz.PermUserAssignRole, authz.ScopeAll)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "INTERNAL_ERROR", "Failed to check permissions")
+			return
+		}
+		if !canAssign {
+			utils.ErrorResponse(c, 403, "FORBIDDEN", "You cannot invite users with this role")
+			return
+		}
+	}
+
+	sent, err := h.registrationService.CreateInvitation(c.GetString("user_id"), input, clientInfo(c))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "INVITATION_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, sent, "Invitation created successfully")
+}
+
+// ListInvitations lists invitations, optionally filtered by ?status=pending|accepted|revoked|expired
+// GET /api/v1/admin/invitations
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.registrationService.ListInvitations(c.Query("status"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, invitations, "Invitations retrieved successfully")
+}
+
+// ResendInvitation issues a new link for an invitation and emails it again
+// POST /api/v1/admin/invitations/:id/resend
+func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
+	sent, err := h.registrationService.ResendInvitation(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "RESEND_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, sent, "Invitation resent successfully")
+}
+
+// RevokeInvitation withdraws a pending invitation
+// DELETE /api/v1/admin/invitations/:id
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	invitation, err := h.registrationService.RevokeInvitation(c.GetString("user_id"), c.Param("id"), clientInfo(c))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "REVOKE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, invitation, "Invitation revoked successfully")
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/authz"
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/sse"
+	"craftsbite-backend/internal/utils"
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LateChangeHandler handles post-cutoff change requests and their review
+type LateChangeHandler struct {
+	lateChangeService services.LateChangeService
+	headcountService  services.HeadcountService
+	hub               *sse.Hub
+}
+
+// NewLateChangeHandler creates a new late change handler
+func NewLateChangeHandler(lateChangeService services.LateChangeService, headcountService services.HeadcountService, hub *sse.Hub) *LateChangeHandler {
+	return &LateChangeHandler{
+		lateChangeService: lateChangeService,
+		headcountService:  headcountService,
+		hub:               hub,
+	}
+}
+
+// ReviewLateChangeRequest represents the optional body when approving or rejecting a request
+type ReviewLateChangeRequest struct {
+	Note string `json:"note"`
+}
+
+// CreateRequest files a late change request for the current user
+// POST /api/v1/meals/late-requests
+func (h *LateChangeHandler) CreateRequest(c *gin.Context) {
+	var input services.CreateLateChangeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	request, err := h.lateChangeService.CreateRequest(c.GetString("user_id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "LATE_REQUEST_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, request, "Late change request submitted successfully")
+}
+
+// ListMyRequests lists the current user's late change requests
+// GET /api/v1/meals/late-requests
+func (h *LateChangeHandler) ListMyRequests(c *gin.Context) {
+	requests, err := h.lateChangeService.ListMyRequests(c.GetString("user_id"))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, requests, "Late change requests retrieved successfully")
+}
+
+// GetRules lists the per-meal deadlines for late change requests
+// GET /api/v1/meals/late-requests/rules
+func (h *LateChangeHandler) GetRules(c *gin.Context) {
+	utils.SuccessResponse(c, 200, h.lateChangeService.Rules(), "Late change rules retrieved successfully")
+}
+
+// CancelRequest withdraws one of the current user's pending requests
+// DELETE /api/v1/meals/late-requests/:id
+func (h *LateChangeHandler) CancelRequest(c *gin.Context) {
+	if err := h.lateChangeService.CancelRequest(c.GetString("user_id"), c.Param("id")); err != nil {
+		utils.ErrorResponse(c, 400, "CANCEL_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Late change request cancelled successfully")
+}
+
+// ListPending lists the pending requests the current user can review
+// GET /api/v1/meals/late-requests/pending
+func (h *LateChangeHandler) ListPending(c *gin.Context) {
+	requests, err := h.lateChangeService.ListPending(c.GetString("user_id"))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, requests, "Pending late change requests retrieved successfully")
+}
+
+// Approve approves a request and applies the change
+// POST /api/v1/meals/late-requests/:id/approve
+func (h *LateChangeHandler) Approve(c *gin.Context) {
+	var input ReviewLateChangeRequest
+	if !bindOptionalJSON(c, &input) {
+		return
+	}
+
+	request, err := h.lateChangeService.Approve(c.GetString("user_id"), c.Param("id"), input.Note)
+	if err != nil {
+		h.reviewError(c, "APPROVE_FAILED", err)
+		return
+	}
+
+	date := request.Date
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	if summary, broadcastErr := h.headcountService.GetHeadcountByDate(date); broadcastErr == nil {
+		if payload, marshalErr := json.Marshal(summary); marshalErr == nil {
+			h.hub.Broadcast(date, string(payload))
+		}
+	}
+
+	utils.SuccessResponse(c, 200, request, "Late change request approved successfully")
+}
+
+// Reject rejects a request
+// POST /api/v1/meals/late-requests/:id/reject
+func (h *LateChangeHandler) Reject(c *gin.Context) {
+	var input ReviewLateChangeRequest
+	if !bindOptionalJSON(c, &input) {
+		return
+	}
+
+	request, err := h.lateChangeService.Reject(c.GetString("user_id"), c.Param("id"), input.Note)
+	if err != nil {
+		h.reviewError(c, "REJECT_FAILED", err)
+		return
+	}
+
+	utils.SuccessResponse(c, 200, request, "Late change request rejected successfully")
+}
+
+func (h *LateChangeHandler) reviewError(c *gin.Context, code string, err error) {
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ErrorResponse(c, 403, "FORBIDDEN", err.Error())
+		return
+	}
+	utils.ErrorResponse(c, 400, code, err.Error())
+}
+
+// bindOptionalJSON binds a JSON body if one was sent, writing a 400 response on malformed input
+func bindOptionalJSON(c *gin.Context, obj interface{}) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(obj); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
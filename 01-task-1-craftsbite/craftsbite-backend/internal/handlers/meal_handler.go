@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"craftsbite-backend/internal/export"
 	"craftsbite-backend/internal/repository"
 	"craftsbite-backend/internal/services"
 	"craftsbite-backend/internal/sse"
 	"craftsbite-backend/internal/utils"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -111,7 +113,21 @@ func (h *MealHandler) SetParticipation(c *gin.Context) {
 		}
 	}
 
-	utils.SuccessResponse(c, 200, nil, "Participation updated successfully")
+	// A full meal queues opt-ins on its waitlist, so report where the user ended up
+	var result *services.ParticipationStatus
+	if statuses, err := h.mealService.GetParticipation(userID.(string), req.Date); err == nil {
+		for i := range statuses {
+			if string(statuses[i].MealType) == req.MealType {
+				result = &statuses[i]
+			}
+		}
+	}
+
+	message := "Participation updated successfully"
+	if result != nil && result.Waitlisted {
+		message = fmt.Sprintf("Meal is full, you are number %d on the waitlist", result.WaitlistPosition)
+	}
+	utils.SuccessResponse(c, 200, result, message)
 }
 
 // OverrideParticipationRequest represents the request body for admin override
@@ -182,14 +198,99 @@ func (h *MealHandler) GetTeamParticipation(c *gin.Context) {
 	utils.SuccessResponse(c, 200, response, "Team participation retrieved successfully")
 }
 
+// GetAllTeamsParticipation returns every team's participation for today. ?start= and ?end=
+// select a range of dates instead; ?format=csv|xlsx exports teams and members.
+// GET /api/v1/meals/all-teams-participation
 func (h *MealHandler) GetAllTeamsParticipation(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
 	today := time.Now().Format("2006-01-02")
+	start, end := c.Query("start"), c.Query("end")
 
-	response, err := h.mealService.GetAllTeamsParticipation(today)
+	if start == "" && end == "" && format == "" {
+		response, err := h.mealService.GetAllTeamsParticipation(today)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+			return
+		}
+
+		utils.SuccessResponse(c, 200, response, "All teams participation retrieved successfully")
+		return
+	}
+	if start == "" {
+		start = today
+	}
+	if end == "" {
+		end = start
+	}
+
+	responses, err := h.mealService.GetAllTeamsParticipationRange(start, end)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	if format != "" {
+		sendExport(c, format, exportName("team-participation", start, end), participationSheets(responses)...)
 		return
 	}
+	utils.SuccessResponse(c, 200, responses, "All teams participation retrieved successfully")
+}
+
+// participationSheets renders team participation as a sheet of every member with one column
+// per meal type and a sheet counting each team's participants per meal
+func participationSheets(responses []*services.TeamParticipationResponse) []export.Sheet {
+	mealSet := make(map[string]bool)
+	for _, response := range responses {
+		for _, team := range response.Teams {
+			for _, member := range team.Members {
+				for mealType := range member.Meals {
+					mealSet[mealType] = true
+				}
+			}
+		}
+	}
+	mealTypes := sortedMealKeys(mealSet)
+
+	members := export.Sheet{
+		Name:    "members",
+		Columns: append([]string{"date", "team_id", "team_name", "user_id", "name", "email", "is_team_lead", "is_over_wfh_limit"}, mealTypes...),
+	}
+	teams := export.Sheet{
+		Name:    "teams",
+		Columns: append([]string{"date", "team_id", "team_name", "team_lead_user_id", "members"}, mealTypes...),
+	}
+
+	for _, response := range responses {
+		for _, team := range response.Teams {
+			participating := make(map[string]int, len(mealTypes))
+			for _, member := range team.Members {
+				row := []any{response.Date, team.TeamID, team.TeamName, member.UserID, member.Name, member.Email,
+					member.UserID == team.TeamLeadUserID, member.IsOverWFHLimit}
+				for _, mealType := range mealTypes {
+					eating, scheduled := member.Meals[mealType]
+					if !scheduled {
+						row = append(row, nil)
+						continue
+					}
+					row = append(row, eating)
+					if eating {
+						participating[mealType]++
+					}
+				}
+				members.AddRow(row...)
+			}
+
+			row := []any{response.Date, team.TeamID, team.TeamName, team.TeamLeadUserID, len(team.Members)}
+			for _, mealType := range mealTypes {
+				row = append(row, participating[mealType])
+			}
+			teams.AddRow(row...)
+		}
+	}
 
-	utils.SuccessResponse(c, 200, response, "All teams participation retrieved successfully")
+	return []export.Sheet{members, teams}
 }
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MealTypeHandler handles meal type catalogue endpoints
+type MealTypeHandler struct {
+	catalog services.MealCatalog
+}
+
+// NewMealTypeHandler creates a new meal type handler
+func NewMealTypeHandler(catalog services.MealCatalog) *MealTypeHandler {
+	return &MealTypeHandler{catalog: catalog}
+}
+
+// ListMealTypes returns the meal type catalogue in display order; ?include_inactive=true adds retired types
+// GET /api/v1/meals/types
+func (h *MealTypeHandler) ListMealTypes(c *gin.Context) {
+	mealTypes, err := h.catalog.List(c.Query("include_inactive") == "true")
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, mealTypes, "Meal types retrieved successfully")
+}
+
+// CreateMealType adds a meal type to the catalogue
+// POST /api/v1/meals/types
+func (h *MealTypeHandler) CreateMealType(c *gin.Context) {
+	var input services.CreateMealTypeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	mealType, err := h.catalog.Create(input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, mealType, "Meal type created successfully")
+}
+
+// UpdateMealType edits a meal type's display and default settings
+// PUT /api/v1/meals/types/:code
+func (h *MealTypeHandler) UpdateMealType(c *gin.Context) {
+	var input services.UpdateMealTypeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	mealType, err := h.catalog.Update(c.Param("code"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, mealType, "Meal type updated successfully")
+}
+
+// DeactivateMealType retires a meal type
+// DELETE /api/v1/meals/types/:code
+func (h *MealTypeHandler) DeactivateMealType(c *gin.Context) {
+	if err := h.catalog.Deactivate(c.Param("code")); err != nil {
+		utils.ErrorResponse(c, 400, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Meal type deactivated successfully")
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MenuHandler handles menu planning endpoints
+type MenuHandler struct {
+	menuService services.MenuService
+}
+
+// NewMenuHandler creates a new menu handler
+func NewMenuHandler(menuService services.MenuService) *MenuHandler {
+	return &MenuHandler{menuService: menuService}
+}
+
+// GetMenus returns the menus between ?start= and ?end= (YYYY-MM-DD)
+// GET /api/v1/menus
+func (h *MenuHandler) GetMenus(c *gin.Context) {
+	startDate := c.Query("start")
+	endDate := c.Query("end")
+	if startDate == "" || endDate == "" {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "start and end query parameters are required")
+		return
+	}
+
+	menus, err := h.menuService.GetMenus(c.GetString("role"), startDate, endDate)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "INVALID_RANGE", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, menus, "Menus retrieved successfully")
+}
+
+// SaveMenu creates or replaces the menu of a meal on a date
+// PUT /api/v1/menus
+func (h *MenuHandler) SaveMenu(c *gin.Context) {
+	var input services.SaveMenuInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	menu, err := h.menuService.SaveMenu(c.GetString("user_id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "SAVE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, menu, "Menu saved successfully")
+}
+
+// CloneMenus copies a block of days' menus as drafts
+// POST /api/v1/menus/clone
+func (h *MenuHandler) CloneMenus(c *gin.Context) {
+	var input services.CloneMenusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := h.menuService.CloneMenus(c.GetString("user_id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "CLONE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, result, "Menus cloned successfully")
+}
+
+// PublishMenu makes a draft menu visible to everyone
+// POST /api/v1/menus/:id/publish
+func (h *MenuHandler) PublishMenu(c *gin.Context) {
+	menu, err := h.menuService.PublishMenu(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "PUBLISH_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, menu, "Menu published successfully")
+}
+
+// UnpublishMenu returns a published menu to draft
+// POST /api/v1/menus/:id/unpublish
+func (h *MenuHandler) UnpublishMenu(c *gin.Context) {
+	menu, err := h.menuService.UnpublishMenu(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "UNPUBLISH_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, menu, "Menu unpublished successfully")
+}
+
+// DeleteMenu removes a menu and its dishes
+// DELETE /api/v1/menus/:id
+func (h *MenuHandler) DeleteMenu(c *gin.Context) {
+	if err := h.menuService.DeleteMenu(c.Param("id")); err != nil {
+		utils.ErrorResponse(c, 400, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Menu deleted successfully")
+}
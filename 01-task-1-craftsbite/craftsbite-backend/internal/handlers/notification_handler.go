@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler handles the current user's in-app notifications
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// ListNotifications returns the current user's notifications, newest first; ?unread=true hides read ones
+// GET /api/v1/users/me/notifications
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	notifications, err := h.notificationService.List(c.GetString("user_id"), c.Query("unread") == "true")
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, notifications, "Notifications retrieved successfully")
+}
+
+// MarkRead marks one notification as read
+// POST /api/v1/users/me/notifications/:id/read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	if err := h.notificationService.MarkRead(c.GetString("user_id"), c.Param("id")); err != nil {
+		utils.ErrorResponse(c, 400, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Notification marked as read")
+}
+
+// MarkAllRead marks all of the current user's notifications as read
+// POST /api/v1/users/me/notifications/read
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	if err := h.notificationService.MarkAllRead(c.GetString("user_id")); err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Notifications marked as read")
+}
@@ -38,9 +38,12 @@ func (h *PreferenceHandler) GetPreferences(c *gin.Context) {
 	utils.SuccessResponse(c, 200, preferences, "Preferences retrieved successfully")
 }
 
-// UpdatePreferencesRequest represents the request body for updating preferences
+// UpdatePreferencesRequest represents the request body for updating preferences.
+// Omitted fields are left unchanged; dietary_restrictions replaces the whole list.
 type UpdatePreferencesRequest struct {
-	DefaultMealPreference string `json:"default_meal_preference" binding:"required"`
+	DefaultMealPreference *string   `json:"default_meal_preference"`
+	DietaryRestrictions   *[]string `json:"dietary_restrictions"`
+	Allergens             *string   `json:"allergens"`
 }
 
 // UpdatePreferences updates the current user's meal preferences
@@ -60,12 +63,87 @@ func (h *PreferenceHandler) UpdatePreferences(c *gin.Context) {
 		return
 	}
 
+	if req.DefaultMealPreference == nil && req.DietaryRestrictions == nil && req.Allergens == nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "At least one preference must be provided")
+		return
+	}
+
 	// Update preference
-	err := h.preferenceService.UpdateDefaultPreference(userID.(string), req.DefaultMealPreference)
+	if req.DefaultMealPreference != nil {
+		err := h.preferenceService.UpdateDefaultPreference(userID.(string), *req.DefaultMealPreference)
+		if err != nil {
+			utils.ErrorResponse(c, 400, "UPDATE_PREFERENCE_ERROR", err.Error())
+			return
+		}
+	}
+
+	// Update dietary profile
+	if req.DietaryRestrictions != nil || req.Allergens != nil {
+		err := h.preferenceService.UpdateDietaryProfile(userID.(string), req.DietaryRestrictions, req.Allergens)
+		if err != nil {
+			utils.ErrorResponse(c, 400, "UPDATE_PREFERENCE_ERROR", err.Error())
+			return
+		}
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Preferences updated successfully")
+}
+
+// ListRules returns the current user's recurring participation rules
+// GET /api/v1/users/me/preferences/rules
+func (h *PreferenceHandler) ListRules(c *gin.Context) {
+	rules, err := h.preferenceService.ListRules(c.GetString("user_id"))
 	if err != nil {
-		utils.ErrorResponse(c, 400, "UPDATE_PREFERENCE_ERROR", err.Error())
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, 200, nil, "Preferences updated successfully")
+	utils.SuccessResponse(c, 200, rules, "Participation rules retrieved successfully")
+}
+
+// CreateRule adds a recurring participation rule for the current user
+// POST /api/v1/users/me/preferences/rules
+func (h *PreferenceHandler) CreateRule(c *gin.Context) {
+	var input services.ParticipationRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	rule, err := h.preferenceService.CreateRule(c.GetString("user_id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "CREATE_RULE_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, rule, "Participation rule created successfully")
+}
+
+// UpdateRule replaces one of the current user's recurring participation rules
+// PUT /api/v1/users/me/preferences/rules/:id
+func (h *PreferenceHandler) UpdateRule(c *gin.Context) {
+	var input services.ParticipationRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	rule, err := h.preferenceService.UpdateRule(c.GetString("user_id"), c.Param("id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "UPDATE_RULE_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, rule, "Participation rule updated successfully")
+}
+
+// DeleteRule removes one of the current user's recurring participation rules
+// DELETE /api/v1/users/me/preferences/rules/:id
+func (h *PreferenceHandler) DeleteRule(c *gin.Context) {
+	if err := h.preferenceService.DeleteRule(c.GetString("user_id"), c.Param("id")); err != nil {
+		utils.ErrorResponse(c, 400, "DELETE_RULE_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Participation rule deleted successfully")
 }
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHandler handles role and permission management endpoints
+type RoleHandler struct {
+	roleService services.RoleService
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(roleService services.RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+// ListRoles lists every role with its permissions
+// GET /api/v1/roles
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, roles, "Roles retrieved successfully")
+}
+
+// ListPermissions lists the permissions that can be granted and their allowed scopes
+// GET /api/v1/roles/permissions
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	utils.SuccessResponse(c, 200, h.roleService.ListPermissions(), "Permissions retrieved successfully")
+}
+
+// GetRole returns a role with its permissions
+// GET /api/v1/roles/:name
+func (h *RoleHandler) GetRole(c *gin.Context) {
+	role, err := h.roleService.GetRole(c.Param("name"))
+	if err != nil {
+		utils.ErrorResponse(c, 404, "ROLE_NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, role, "Role retrieved successfully")
+}
+
+// CreateRole defines a custom role
+// POST /api/v1/roles
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var input services.CreateRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	role, err := h.roleService.CreateRole(input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, role, "Role created successfully")
+}
+
+// UpdateRole changes a role's description and permissions
+// PUT /api/v1/roles/:name
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	var input services.UpdateRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(c.Param("name"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, role, "Role updated successfully")
+}
+
+// DeleteRole removes a custom role
+// DELETE /api/v1/roles/:name
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	if err := h.roleService.DeleteRole(c.Param("name")); err != nil {
+		utils.ErrorResponse(c, 400, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Role deleted successfully")
+}
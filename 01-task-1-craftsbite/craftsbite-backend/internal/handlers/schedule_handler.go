@@ -4,6 +4,9 @@ import (
 	"craftsbite-backend/internal/models"
 	"craftsbite-backend/internal/services"
 	"craftsbite-backend/internal/utils"
+	"io"
+	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -182,3 +185,137 @@ func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
 
 	utils.SuccessResponse(c, 200, nil, "Schedule deleted successfully")
 }
+
+// maxCalendarUploadBytes bounds the size of an imported iCalendar file
+const maxCalendarUploadBytes = 1 << 20
+
+// ListRules returns every recurring schedule rule
+// GET /api/v1/schedules/rules
+func (h *ScheduleHandler) ListRules(c *gin.Context) {
+	rules, err := h.scheduleService.ListRules()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, rules, "Schedule rules retrieved successfully")
+}
+
+// CreateRule creates a recurring schedule rule (Admin and Logistics only)
+// POST /api/v1/schedules/rules
+func (h *ScheduleHandler) CreateRule(c *gin.Context) {
+	var input services.ScheduleRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	rule, err := h.scheduleService.CreateRule(c.GetString("user_id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "CREATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, rule, "Schedule rule created successfully")
+}
+
+// UpdateRule updates a recurring schedule rule (Admin and Logistics only)
+// PUT /api/v1/schedules/rules/:id
+func (h *ScheduleHandler) UpdateRule(c *gin.Context) {
+	var input services.UpdateScheduleRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	rule, err := h.scheduleService.UpdateRule(c.Param("id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "UPDATE_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, rule, "Schedule rule updated successfully")
+}
+
+// DeleteRule deletes a recurring schedule rule (Admin and Logistics only)
+// DELETE /api/v1/schedules/rules/:id
+func (h *ScheduleHandler) DeleteRule(c *gin.Context) {
+	if err := h.scheduleService.DeleteRule(c.Param("id")); err != nil {
+		utils.ErrorResponse(c, 400, "DELETE_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Schedule rule deleted successfully")
+}
+
+// GetEffectiveSchedule lists every day in a range with the schedule that applies and its source
+// GET /api/v1/schedules/effective?start_date=YYYY-MM-DD&end_date=YYYY-MM-DD
+func (h *ScheduleHandler) GetEffectiveSchedule(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "start_date and end_date query parameters are required")
+		return
+	}
+
+	days, err := h.scheduleService.GetEffectiveSchedule(startDate, endDate)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, days, "Effective schedule retrieved successfully")
+}
+
+// PreviewSchedule shows the effective schedule of a range with a draft rule applied, without saving it
+// POST /api/v1/schedules/preview
+func (h *ScheduleHandler) PreviewSchedule(c *gin.Context) {
+	var input services.PreviewScheduleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	preview, err := h.scheduleService.PreviewSchedule(input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, preview, "Schedule preview generated successfully")
+}
+
+// ImportHolidays imports government holidays from an iCalendar file, sent either as the
+// multipart form field "file" or as the raw request body. ?dry_run=true reports without saving.
+// POST /api/v1/schedules/import/ics
+func (h *ScheduleHandler) ImportHolidays(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCalendarUploadBytes)
+
+	var calendar io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "file is required")
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "failed to read uploaded file")
+			return
+		}
+		defer file.Close()
+		calendar = file
+	}
+
+	result, err := h.scheduleService.ImportHolidays(c.GetString("user_id"), calendar, c.Query("dry_run") == "true")
+	if err != nil {
+		utils.ErrorResponse(c, 400, "IMPORT_ERROR", err.Error())
+		return
+	}
+
+	status, message := 201, "Holidays imported successfully"
+	if result.DryRun {
+		status, message = 200, "Holiday import preview generated successfully"
+	}
+	utils.SuccessResponse(c, status, result, message)
+}
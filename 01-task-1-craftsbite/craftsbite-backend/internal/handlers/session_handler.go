@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler handles listing and revoking login sessions
+type SessionHandler struct {
+	sessionService services.SessionService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(sessionService services.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// ListMySessions returns the caller's active sessions
+// GET /api/v1/auth/sessions
+func (h *SessionHandler) ListMySessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, 401, "UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	sessions, err := h.sessionService.ListSessions(userID.(string), c.GetString("session_id"))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, sessions, "Sessions retrieved successfully")
+}
+
+// RevokeMySession revokes one of the caller's sessions
+// DELETE /api/v1/auth/sessions/:id
+func (h *SessionHandler) RevokeMySession(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, 401, "UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	if err := h.sessionService.RevokeOwnSession(userID.(string), c.Param("id")); err != nil {
+		utils.ErrorResponse(c, 404, "SESSION_NOT_FOUND", err.Error())
+		return
+	}
+
+	if c.Param("id") == c.GetString("session_id") {
+		expireSessionCookies(c)
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Session revoked successfully")
+}
+
+// RevokeMyOtherSessions revokes every session of the caller except the current one
+// DELETE /api/v1/auth/sessions
+func (h *SessionHandler) RevokeMyOtherSessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, 401, "UNAUTHORIZED", "User not authenticated")
+		return
+	}
+
+	revoked, err := h.sessionService.RevokeUserSessions(userID.(string), models.SessionRevokeUser, c.GetString("session_id"))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{"revoked": revoked}, "Other sessions revoked successfully")
+}
+
+// ListUserSessions returns the active sessions of any user
+// GET /api/v1/admin/users/:user_id/sessions
+func (h *SessionHandler) ListUserSessions(c *gin.Context) {
+	sessions, err := h.sessionService.ListSessions(c.Param("user_id"), c.GetString("session_id"))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, sessions, "Sessions retrieved successfully")
+}
+
+// RevokeUserSessions revokes every session of a user, signing them out everywhere
+// DELETE /api/v1/admin/users/:user_id/sessions
+func (h *SessionHandler) RevokeUserSessions(c *gin.Context) {
+	revoked, err := h.sessionService.RevokeUserSessions(c.Param("user_id"), models.SessionRevokeAdmin, "")
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{"revoked": revoked}, "User sessions revoked successfully")
+}
+
+// RevokeSession revokes a single session of any user
+// DELETE /api/v1/admin/sessions/:id
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	if err := h.sessionService.RevokeSession(c.Param("id"), models.SessionRevokeAdmin); err != nil {
+		utils.ErrorResponse(c, 404, "SESSION_NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Session revoked successfully")
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamHandler handles team management endpoints (Admin only)
+type TeamHandler struct {
+	teamService services.TeamService
+}
+
+// NewTeamHandler creates a new team handler
+func NewTeamHandler(teamService services.TeamService) *TeamHandler {
+	return &TeamHandler{teamService: teamService}
+}
+
+type changeTeamLeadRequest struct {
+	TeamLeadID string `json:"team_lead_id" binding:"required"`
+}
+
+type addTeamMemberRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+type importTeamMembersRequest struct {
+	// Members holds user IDs or emails
+	Members []string `json:"members" binding:"required,min=1"`
+}
+
+// ListTeams lists active teams, or all teams with ?include_inactive=true
+// GET /api/v1/teams
+func (h *TeamHandler) ListTeams(c *gin.Context) {
+	teams, err := h.teamService.ListTeams(c.Query("include_inactive") == "true")
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, teams, "Teams retrieved successfully")
+}
+
+// GetTeam returns a team with its lead and members
+// GET /api/v1/teams/:id
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	team, err := h.teamService.GetTeam(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 404, "TEAM_NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, team, "Team retrieved successfully")
+}
+
+// CreateTeam creates a team
+// POST /api/v1/teams
+func (h *TeamHandler) CreateTeam(c *gin.Context) {
+	var input services.CreateTeamInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	team, err := h.teamService.CreateTeam(c.GetString("user_id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, team, "Team created successfully")
+}
+
+// UpdateTeam updates a team's name and description
+// PUT /api/v1/teams/:id
+func (h *TeamHandler) UpdateTeam(c *gin.Context) {
+	var input services.UpdateTeamInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	team, err := h.teamService.UpdateTeam(c.GetString("user_id"), c.Param("id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, team, "Team updated successfully")
+}
+
+// DeactivateTeam soft-deletes a team
+// DELETE /api/v1/teams/:id
+func (h *TeamHandler) DeactivateTeam(c *gin.Context) {
+	if err := h.teamService.DeactivateTeam(c.GetString("user_id"), c.Param("id")); err != nil {
+		utils.ErrorResponse(c, 400, "DEACTIVATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Team deactivated successfully")
+}
+
+// ReactivateTeam restores a deactivated team
+// POST /api/v1/teams/:id/reactivate
+func (h *TeamHandler) ReactivateTeam(c *gin.Context) {
+	team, err := h.teamService.ReactivateTeam(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "REACTIVATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, team, "Team reactivated successfully")
+}
+
+// ChangeTeamLead reassigns the team lead
+// PUT /api/v1/teams/:id/lead
+func (h *TeamHandler) ChangeTeamLead(c *gin.Context) {
+	var req changeTeamLeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	team, err := h.teamService.ChangeTeamLead(c.GetString("user_id"), c.Param("id"), req.TeamLeadID)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, team, "Team lead changed successfully")
+}
+
+// AddMember adds a user to a team
+// POST /api/v1/teams/:id/members
+func (h *TeamHandler) AddMember(c *gin.Context) {
+	var req addTeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	team, err := h.teamService.AddMember(c.GetString("user_id"), c.Param("id"), req.UserID)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "ADD_MEMBER_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, team, "Team member added successfully")
+}
+
+// ImportMembers adds many users to a team by user ID or email
+// POST /api/v1/teams/:id/members/import
+func (h *TeamHandler) ImportMembers(c *gin.Context) {
+	var req importTeamMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	result, err := h.teamService.ImportMembers(c.GetString("user_id"), c.Param("id"), req.Members)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "IMPORT_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, result, "Team members imported")
+}
+
+// RemoveMember removes a user from a team
+// DELETE /api/v1/teams/:id/members/:user_id
+func (h *TeamHandler) RemoveMember(c *gin.Context) {
+	if err := h.teamService.RemoveMember(c.GetString("user_id"), c.Param("id"), c.Param("user_id")); err != nil {
+		utils.ErrorResponse(c, 400, "REMOVE_MEMBER_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Team member removed successfully")
+}
+
+// GetTeamHistory returns the audit history of a team
+// GET /api/v1/teams/:id/history
+func (h *TeamHandler) GetTeamHistory(c *gin.Context) {
+	history, err := h.teamService.GetTeamHistory(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 404, "TEAM_NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, history, "Team history retrieved successfully")
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorSetupChallengeRequest represents the request body for enrolling during login
+type TwoFactorSetupChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+// TwoFactorCodeRequest represents a request body carrying a TOTP or recovery code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTwoFactorRequest represents disable two-factor request body
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// TwoFactorHandler handles TOTP enrolment and recovery codes
+type TwoFactorHandler struct {
+	twoFactorService services.TwoFactorService
+}
+
+// NewTwoFactorHandler creates a new two-factor handler
+func NewTwoFactorHandler(twoFactorService services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+// SetupChallenge issues a TOTP secret to a user whose login requires enrolling first. The
+// first code is then sent to POST /auth/login/2fa with the same challenge.
+// POST /api/v1/auth/login/2fa/setup
+func (h *TwoFactorHandler) SetupChallenge(c *gin.Context) {
+	var req TwoFactorSetupChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	setup, err := h.twoFactorService.SetupChallenge(req.ChallengeToken)
+	if errors.Is(err, services.ErrInvalidLoginChallenge) {
+		utils.ErrorResponse(c, 401, "INVALID_LOGIN_CHALLENGE", err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 400, "TWO_FACTOR_SETUP_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, setup, "Add the secret to your authenticator app, then enter a code to finish logging in")
+}
+
+// GetStatus returns the current user's two-factor status
+// GET /api/v1/auth/2fa
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	status, err := h.twoFactorService.GetStatus(c.GetString("user_id"))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, status, "Two-factor status retrieved successfully")
+}
+
+// BeginSetup issues a TOTP secret to the current user
+// POST /api/v1/auth/2fa/setup
+func (h *TwoFactorHandler) BeginSetup(c *gin.Context) {
+	setup, err := h.twoFactorService.BeginSetup(c.GetString("user_id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "TWO_FACTOR_SETUP_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, setup, "Add the secret to your authenticator app, then confirm a code to enable two-factor authentication")
+}
+
+// Enable confirms the pending setup with a code and returns the recovery codes
+// POST /api/v1/auth/2fa/enable
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	recoveryCodes, err := h.twoFactorService.Enable(c.GetString("user_id"), req.Code, clientInfo(c))
+	if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+		utils.ErrorResponse(c, 400, "INVALID_TWO_FACTOR_CODE", err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 400, "TWO_FACTOR_ENABLE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{"recovery_codes": recoveryCodes}, "Two-factor authentication enabled. Store the recovery codes somewhere safe; they are only shown once.")
+}
+
+// Disable turns two-factor authentication off for the current user
+// POST /api/v1/auth/2fa/disable
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	err := h.twoFactorService.Disable(c.GetString("user_id"), req.Password, req.Code, clientInfo(c))
+	if errors.Is(err, services.ErrIncorrectPassword) {
+		utils.ErrorResponse(c, 400, "INCORRECT_PASSWORD", err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+		utils.ErrorResponse(c, 400, "INVALID_TWO_FACTOR_CODE", err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 400, "TWO_FACTOR_DISABLE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Two-factor authentication disabled")
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+// POST /api/v1/auth/2fa/recovery-codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	recoveryCodes, err := h.twoFactorService.RegenerateRecoveryCodes(c.GetString("user_id"), req.Code, clientInfo(c))
+	if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+		utils.ErrorResponse(c, 400, "INVALID_TWO_FACTOR_CODE", err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 400, "RECOVERY_CODES_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{"recovery_codes": recoveryCodes}, "Recovery codes regenerated. The old codes no longer work.")
+}
+
+// Reset removes a user's two-factor authentication so they can enrol again
+// DELETE /api/v1/admin/users/:user_id/2fa
+func (h *TwoFactorHandler) Reset(c *gin.Context) {
+	if err := h.twoFactorService.Reset(c.GetString("user_id"), c.Param("user_id"), clientInfo(c)); err != nil {
+		utils.ErrorResponse(c, 400, "TWO_FACTOR_RESET_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Two-factor authentication reset successfully")
+}
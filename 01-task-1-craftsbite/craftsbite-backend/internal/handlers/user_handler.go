@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/authz"
 	"craftsbite-backend/internal/services"
 	"craftsbite-backend/internal/utils"
+	"errors"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,11 +12,12 @@ import (
 // UserHandler handles user management endpoints
 type UserHandler struct {
 	userService services.UserService
+	authorizer  authz.Authorizer
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(userService services.UserService) *UserHandler {
-	return &UserHandler{userService: userService}
+func NewUserHandler(userService services.UserService, authorizer authz.Authorizer) *UserHandler {
+	return &UserHandler{userService: userService, authorizer: authorizer}
 }
 
 // ListUsers lists all users (Admin only)
@@ -29,15 +31,11 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	utils.SuccessResponse(c, 200, users, "Users retrieved successfully")
 }
 
-// GetUser gets a user by ID (Admin or Self)
+// GetUser gets a user by ID (requires user:read covering the target)
 func (h *UserHandler) GetUser(c *gin.Context) {
 	userID := c.Param("id")
-	currentUserID, _ := c.Get("user_id")
-	currentUserRole, _ := c.Get("role")
 
-	// Check if user is accessing their own data or is admin
-	if userID != currentUserID.(string) && currentUserRole.(string) != models.RoleAdmin.String() {
-		utils.ErrorResponse(c, 403, "FORBIDDEN", "You can only access your own data")
+	if !h.authorize(c, authz.PermUserRead, userID) {
 		return
 	}
 
@@ -67,15 +65,11 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	utils.SuccessResponse(c, 201, user, "User created successfully")
 }
 
-// UpdateUser updates a user (Admin or Self)
+// UpdateUser updates a user (requires user:write covering the target)
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	userID := c.Param("id")
-	currentUserID, _ := c.Get("user_id")
-	currentUserRole, _ := c.Get("role")
 
-	// Check if user is updating their own data or is admin
-	if userID != currentUserID.(string) && currentUserRole.(string) != models.RoleAdmin.String() {
-		utils.ErrorResponse(c, 403, "FORBIDDEN", "You can only update your own data")
+	if !h.authorize(c, authz.PermUserWrite, userID) {
 		return
 	}
 
@@ -85,12 +79,25 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	// Non-admin users cannot change their role
-	if currentUserRole.(string) != models.RoleAdmin.String() && input.Role != nil {
-		utils.ErrorResponse(c, 403, "FORBIDDEN", "You cannot change your own role")
+	// Users change their own password through /auth/change-password, which asks for the current one
+	if input.Password != nil && userID == c.GetString("user_id") {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Use /auth/change-password to change your own password")
 		return
 	}
 
+	// Changing roles needs its own permission, even for users who may edit the profile
+	if input.Role != nil {
+		canAssign, err := h.authorizer.HasPermission(c.GetString("role"), authz.PermUserAssignRole, authz.ScopeAll)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "INTERNAL_ERROR", "Failed to check permissions")
+			return
+		}
+		if !canAssign {
+			utils.ErrorResponse(c, 403, "FORBIDDEN", "You cannot change user roles")
+			return
+		}
+	}
+
 	user, err := h.userService.UpdateUser(userID, input)
 	if err != nil {
 		utils.ErrorResponse(c, 400, "UPDATE_FAILED", err.Error())
@@ -123,10 +130,6 @@ func (h *UserHandler) GetMyTeamMembers(c *gin.Context) {
 
 	response, err := h.userService.GetMyTeamMembers(userID.(string))
 	if err != nil {
-		if err.Error() == "user is not a team lead" {
-			utils.ErrorResponse(c, 403, "FORBIDDEN", "Only team leads can access this endpoint")
-			return
-		}
 		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
 		return
 	}
@@ -154,3 +157,17 @@ func (h *UserHandler) GetMyTeam(c *gin.Context) {
 
 	utils.SuccessResponse(c, 200, team, "My team retrieved successfully")
 }
+
+// authorize checks that the caller may exercise permission on the target user and writes the error response if not
+func (h *UserHandler) authorize(c *gin.Context, permission authz.Permission, targetUserID string) bool {
+	err := h.authorizer.Authorize(c.GetString("role"), c.GetString("user_id"), permission, targetUserID)
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, authz.ErrForbidden) {
+		utils.ErrorResponse(c, 403, "FORBIDDEN", err.Error())
+		return false
+	}
+	utils.ErrorResponse(c, 500, "INTERNAL_ERROR", "Failed to check permissions")
+	return false
+}
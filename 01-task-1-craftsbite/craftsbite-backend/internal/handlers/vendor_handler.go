@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VendorHandler handles vendor and vendor order endpoints
+type VendorHandler struct {
+	vendorService services.VendorService
+}
+
+// NewVendorHandler creates a new vendor handler
+func NewVendorHandler(vendorService services.VendorService) *VendorHandler {
+	return &VendorHandler{vendorService: vendorService}
+}
+
+// ListVendors returns the vendors by name; ?include_inactive=true adds deactivated vendors
+// GET /api/v1/vendors
+func (h *VendorHandler) ListVendors(c *gin.Context) {
+	vendors, err := h.vendorService.ListVendors(c.Query("include_inactive") == "true")
+	if err != nil {
+		utils.ErrorResponse(c, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, vendors, "Vendors retrieved successfully")
+}
+
+// CreateVendor adds a vendor
+// POST /api/v1/vendors
+func (h *VendorHandler) CreateVendor(c *gin.Context) {
+	var input services.CreateVendorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	vendor, err := h.vendorService.CreateVendor(input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, vendor, "Vendor created successfully")
+}
+
+// UpdateVendor edits a vendor's meals, delivery, deadline and templates
+// PUT /api/v1/vendors/:id
+func (h *VendorHandler) UpdateVendor(c *gin.Context) {
+	var input services.UpdateVendorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	vendor, err := h.vendorService.UpdateVendor(c.Param("id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, vendor, "Vendor updated successfully")
+}
+
+// DeactivateVendor stops orders from being generated for a vendor
+// DELETE /api/v1/vendors/:id
+func (h *VendorHandler) DeactivateVendor(c *gin.Context) {
+	if err := h.vendorService.DeactivateVendor(c.Param("id")); err != nil {
+		utils.ErrorResponse(c, 400, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, nil, "Vendor deactivated successfully")
+}
+
+// ListOrders returns the vendor orders between ?start= and ?end=, optionally for one ?vendor_id=
+// GET /api/v1/vendor-orders
+func (h *VendorHandler) ListOrders(c *gin.Context) {
+	startDate := c.Query("start")
+	endDate := c.Query("end")
+	if startDate == "" || endDate == "" {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "start and end query parameters are required")
+		return
+	}
+
+	orders, err := h.vendorService.ListOrders(startDate, endDate, c.Query("vendor_id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "INVALID_RANGE", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, orders, "Vendor orders retrieved successfully")
+}
+
+// GetOrder returns a vendor order with its lines and document
+// GET /api/v1/vendor-orders/:id
+func (h *VendorHandler) GetOrder(c *gin.Context) {
+	order, err := h.vendorService.GetOrder(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 404, "ORDER_NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, order, "Vendor order retrieved successfully")
+}
+
+// GenerateOrder drafts a vendor's order for a date from the live headcount
+// POST /api/v1/vendor-orders
+func (h *VendorHandler) GenerateOrder(c *gin.Context) {
+	var input services.GenerateVendorOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, 400, "VALIDATION_ERROR", "Invalid request body: "+err.Error())
+		return
+	}
+
+	order, err := h.vendorService.GenerateOrder(c.GetString("user_id"), input)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "GENERATE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 201, order, "Vendor order drafted successfully")
+}
+
+// SendOrder delivers a draft order, or a pending amendment, to the vendor
+// POST /api/v1/vendor-orders/:id/send
+func (h *VendorHandler) SendOrder(c *gin.Context) {
+	order, err := h.vendorService.SendOrder(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "SEND_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, order, "Vendor order sent successfully")
+}
+
+// AcknowledgeOrder records the vendor's confirmation of an order
+// POST /api/v1/vendor-orders/:id/acknowledge
+func (h *VendorHandler) AcknowledgeOrder(c *gin.Context) {
+	order, err := h.vendorService.AcknowledgeOrder(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "ACKNOWLEDGE_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, 200, order, "Vendor order acknowledged successfully")
+}
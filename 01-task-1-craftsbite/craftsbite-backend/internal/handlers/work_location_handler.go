@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"craftsbite-backend/internal/export"
 	"craftsbite-backend/internal/services"
 	"craftsbite-backend/internal/utils"
 	"time"
@@ -138,6 +139,9 @@ func (h *WorkLocationHandler) GetMonthlySummary(c *gin.Context) {
     utils.SuccessResponse(c, 200, summary, "Monthly WFH summary retrieved")
 }
 
+// GetTeamMonthlyReport returns the WFH report of ?month= (default: current month). With
+// ?end_month= it returns one report per month; ?format=csv|xlsx exports members and totals.
+// GET /api/v1/work-location/team-monthly-report
 func (h *WorkLocationHandler) GetTeamMonthlyReport(c *gin.Context) {
     requesterID, exists := c.Get("user_id")
     if !exists {
@@ -145,16 +149,62 @@ func (h *WorkLocationHandler) GetTeamMonthlyReport(c *gin.Context) {
         return
     }
 
+    format, ok := exportFormat(c)
+    if !ok {
+        return
+    }
+
     yearMonth := c.Query("month")
     if yearMonth == "" {
         yearMonth = time.Now().Format("2006-01")
     }
+    endMonth := c.Query("end_month")
 
-    rollup, err := h.svc.GetTeamMonthlyReport(requesterID.(string), yearMonth)
+    if endMonth == "" && format == "" {
+        rollup, err := h.svc.GetTeamMonthlyReport(requesterID.(string), yearMonth)
+        if err != nil {
+            utils.ErrorResponse(c, 400, "ROLLUP_ERROR", err.Error())
+            return
+        }
+
+        utils.SuccessResponse(c, 200, rollup, "Monthly WFH report retrieved")
+        return
+    }
+    if endMonth == "" {
+        endMonth = yearMonth
+    }
+
+    rollups, err := h.svc.GetTeamMonthlyReportRange(requesterID.(string), yearMonth, endMonth)
     if err != nil {
         utils.ErrorResponse(c, 400, "ROLLUP_ERROR", err.Error())
         return
     }
 
-    utils.SuccessResponse(c, 200, rollup, "Monthly WFH report retrieved")
+    if format != "" {
+        sendExport(c, format, exportName("wfh-report", yearMonth, endMonth), wfhReportSheets(rollups)...)
+        return
+    }
+    utils.SuccessResponse(c, 200, rollups, "Monthly WFH report retrieved")
+}
+
+// wfhReportSheets renders monthly WFH reports as a sheet of every member and a sheet of the monthly totals
+func wfhReportSheets(rollups []*services.TeamMonthlyReport) []export.Sheet {
+    members := export.Sheet{
+        Name:    "members",
+        Columns: []string{"year_month", "user_id", "name", "email", "wfh_days", "allowance", "is_over_limit", "extra_days"},
+    }
+    months := export.Sheet{
+        Name:    "months",
+        Columns: []string{"year_month", "allowance", "total_employees", "over_limit_count", "total_extra_days"},
+    }
+
+    for _, rollup := range rollups {
+        for _, member := range rollup.Members {
+            members.AddRow(rollup.YearMonth, member.UserID, member.Name, member.Email, member.WFHDays,
+                rollup.Allowance, member.IsOverLimit, member.ExtraDays)
+        }
+        months.AddRow(rollup.YearMonth, rollup.Allowance, rollup.TotalEmployees, rollup.OverLimitCount, rollup.TotalExtraDays)
+    }
+
+    return []export.Sheet{members, months}
 }
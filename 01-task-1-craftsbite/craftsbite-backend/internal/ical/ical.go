@@ -0,0 +1,137 @@
+// Package ical reads the VEVENT entries of an iCalendar (.ics) file, as published for public holidays.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event is a calendar event reduced to whole days
+type Event struct {
+	UID     string
+	Summary string
+	// Start is the first day of the event
+	Start time.Time
+	// End is the day after the last day of the event (DTEND is exclusive)
+	End time.Time
+	// RRule is the raw recurrence rule, if the event repeats
+	RRule string
+}
+
+// Days returns every day the event covers, ignoring recurrence
+func (e Event) Days() []time.Time {
+	var days []time.Time
+	for day := e.Start; day.Before(e.End); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// Parse reads every VEVENT in an iCalendar stream
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var current *Event
+	sawCalendar := false
+
+	for i, line := range lines {
+		name, params, value := splitLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCALENDAR"):
+			sawCalendar = true
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current = &Event{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: END:VEVENT without BEGIN:VEVENT", i+1)
+			}
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("event %q has no DTSTART", current.Summary)
+			}
+			if current.End.IsZero() || !current.End.After(current.Start) {
+				current.End = current.Start.AddDate(0, 0, 1)
+			}
+			events = append(events, *current)
+			current = nil
+		case current == nil:
+			continue
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Summary = unescape(value)
+		case name == "DTSTART":
+			if current.Start, err = parseDate(value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		case name == "DTEND":
+			if current.End, err = parseDate(value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			// A timed event ending during a day still covers that day
+			if !strings.Contains(params, "VALUE=DATE") && len(value) > 8 && value[8:] != "T000000" && value[8:] != "T000000Z" {
+				current.End = current.End.AddDate(0, 0, 1)
+			}
+		case name == "RRULE":
+			current.RRule = value
+		}
+	}
+
+	if !sawCalendar {
+		return nil, fmt.Errorf("not an iCalendar file: BEGIN:VCALENDAR is missing")
+	}
+	if current != nil {
+		return nil, fmt.Errorf("unterminated VEVENT %q", current.Summary)
+	}
+	return events, nil
+}
+
+// unfold joins continuation lines, which start with a space or tab (RFC 5545 section 3.1)
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// splitLine splits "DTSTART;VALUE=DATE:20260326" into name, parameters and value
+func splitLine(line string) (name, params, value string) {
+	head, value, _ := strings.Cut(line, ":")
+	name, params, _ = strings.Cut(head, ";")
+	return strings.ToUpper(name), strings.ToUpper(params), value
+}
+
+// parseDate reads a DATE or DATE-TIME value as a calendar day
+func parseDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	day, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return day, nil
+}
+
+func unescape(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
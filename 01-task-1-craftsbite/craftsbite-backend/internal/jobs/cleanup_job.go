@@ -55,6 +55,6 @@ func StopScheduler(c *cron.Cron) {
 	if c != nil {
 		ctx := c.Stop()
 		<-ctx.Done()
-		logger.Info("Cleanup job scheduler stopped")
+		logger.Info("Job scheduler stopped")
 	}
 }
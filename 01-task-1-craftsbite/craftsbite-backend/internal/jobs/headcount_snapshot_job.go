@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/pkg/logger"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// HeadcountSnapshotJob freezes each meal's headcount once the meal's cutoff has passed
+type HeadcountSnapshotJob struct {
+	snapshotService services.HeadcountSnapshotService
+}
+
+// NewHeadcountSnapshotJob creates a new headcount snapshot job
+func NewHeadcountSnapshotJob(snapshotService services.HeadcountSnapshotService) *HeadcountSnapshotJob {
+	return &HeadcountSnapshotJob{snapshotService: snapshotService}
+}
+
+// Run captures every meal whose cutoff has passed and that has no snapshot yet
+func (j *HeadcountSnapshotJob) Run() {
+	captured, err := j.snapshotService.CaptureDue(time.Now())
+	if err != nil {
+		logger.Error(fmt.Sprintf("Headcount snapshot job failed: %v", err))
+		return
+	}
+	if captured > 0 {
+		logger.Info(fmt.Sprintf("Headcount snapshot job completed: %d meals frozen", captured))
+	}
+}
+
+// StartScheduler starts the cron scheduler for the headcount snapshot job. It also runs once
+// right away, so meals whose cutoff passed while the server was down (up to a week back) are
+// captured at startup.
+func (j *HeadcountSnapshotJob) StartScheduler(cronSchedule string) (*cron.Cron, error) {
+	c := cron.New()
+
+	_, err := c.AddFunc(cronSchedule, j.Run)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule headcount snapshot job: %w", err)
+	}
+
+	c.Start()
+	logger.Info(fmt.Sprintf("Headcount snapshot scheduler started (schedule: %s)", cronSchedule))
+
+	go j.Run()
+
+	return c, nil
+}
@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"craftsbite-backend/internal/services"
+	"craftsbite-backend/pkg/logger"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// VendorOrderJob sends vendor orders once their deadline passes and amends them when the headcount changes
+type VendorOrderJob struct {
+	vendorService services.VendorService
+}
+
+// NewVendorOrderJob creates a new vendor order job
+func NewVendorOrderJob(vendorService services.VendorService) *VendorOrderJob {
+	return &VendorOrderJob{vendorService: vendorService}
+}
+
+// Run processes every order that is due or has a pending amendment
+func (j *VendorOrderJob) Run() {
+	delivered, err := j.vendorService.ProcessDueOrders(time.Now())
+	if err != nil {
+		logger.Error(fmt.Sprintf("Vendor order job failed: %v", err))
+		return
+	}
+	if delivered > 0 {
+		logger.Info(fmt.Sprintf("Vendor order job completed: %d orders delivered", delivered))
+	}
+}
+
+// StartScheduler starts the cron scheduler for the vendor order job
+func (j *VendorOrderJob) StartScheduler(cronSchedule string) (*cron.Cron, error) {
+	c := cron.New()
+
+	_, err := c.AddFunc(cronSchedule, j.Run)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule vendor order job: %w", err)
+	}
+
+	c.Start()
+	logger.Info(fmt.Sprintf("Vendor order scheduler started (schedule: %s)", cronSchedule))
+
+	return c, nil
+}
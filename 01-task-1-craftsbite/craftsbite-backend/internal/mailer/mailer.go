@@ -0,0 +1,91 @@
+// Package mailer sends plain-text email over SMTP or, when no server is configured, writes it
+// to .eml files or notes it in the log during local development.
+package mailer
+
+import (
+	"bytes"
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/pkg/logger"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email messages
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New returns an SMTP mailer, or when SMTP_HOST is not set a file mailer if MAIL_FILE_DIR is
+// set and a log mailer otherwise
+func New(cfg config.MailConfig) Mailer {
+	switch {
+	case cfg.SMTPHost != "":
+		return &smtpMailer{cfg: cfg}
+	case cfg.FileDir != "":
+		return &fileMailer{dir: cfg.FileDir, from: cfg.From}
+	default:
+		return &logMailer{}
+	}
+}
+
+type smtpMailer struct {
+	cfg config.MailConfig
+}
+
+func (m *smtpMailer) Send(msg Message) error {
+	from, err := mail.ParseAddress(m.cfg.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+	if len(msg.To) == 0 {
+		return fmt.Errorf("message has no recipients")
+	}
+
+	var auth smtp.Auth
+	if m.cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", m.cfg.SMTPUsername, m.cfg.SMTPPassword, m.cfg.SMTPHost)
+	}
+
+	addr := m.cfg.SMTPHost + ":" + strconv.Itoa(m.cfg.SMTPPort)
+	if err := smtp.SendMail(addr, auth, from.Address, msg.To, compose(from.String(), msg)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
+
+// compose renders the message with the headers a mail client expects
+func compose(from string, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+// logMailer stands in for an SMTP server in development. It logs only the recipients and
+// subject, since bodies carry secrets such as password reset links; set MAIL_FILE_DIR to read them.
+type logMailer struct{}
+
+func (m *logMailer) Send(msg Message) error {
+	logger.Info(fmt.Sprintf("Mail to %s: %s", strings.Join(msg.To, ", "), msg.Subject))
+	return nil
+}
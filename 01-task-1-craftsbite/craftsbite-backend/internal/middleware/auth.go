@@ -1,14 +1,19 @@
 package middleware
 
 import (
-	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/authz"
 	"craftsbite-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT tokens
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// SessionChecker reports whether the session behind an access token is still active
+type SessionChecker interface {
+	IsSessionActive(sessionID string) (bool, error)
+}
+
+// AuthMiddleware validates JWT tokens and rejects tokens whose session was revoked
+func AuthMiddleware(jwtSecret string, sessions SessionChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Extract token from Authorization header
 		tokenString, err := c.Cookie("auth_token")
@@ -26,37 +31,52 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		// Tokens are bound to a server-side session so they can be revoked before they expire
+		if claims.SessionID == "" {
+			utils.ErrorResponse(c, 401, "UNAUTHORIZED", "Invalid or expired token")
+			c.Abort()
+			return
+		}
+		active, err := sessions.IsSessionActive(claims.SessionID)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "INTERNAL_ERROR", "Failed to verify session")
+			c.Abort()
+			return
+		}
+		if !active {
+			utils.ErrorResponse(c, 401, "SESSION_REVOKED", "Session has expired or been revoked")
+			c.Abort()
+			return
+		}
+
 		// Set user claims in context
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
 }
 
-// RequireRoles checks if the user has one of the required roles
-func RequireRoles(roles ...models.Role) gin.HandlerFunc {
+// RequirePermission checks that the user's role holds a permission at least at the required scope.
+// Checks against a specific target user happen in the services through authz.Authorizer.
+func RequirePermission(authorizer authz.Authorizer, permission authz.Permission, scope authz.Scope) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userRole, exists := c.Get("role")
+		role, exists := c.Get("role")
 		if !exists {
 			utils.ErrorResponse(c, 403, "FORBIDDEN", "User role not found in context")
 			c.Abort()
 			return
 		}
 
-		userRoleStr := userRole.(string)
-
-		// Check if user role matches any of the required roles
-		hasPermission := false
-		for _, role := range roles {
-			if userRoleStr == role.String() {
-				hasPermission = true
-				break
-			}
+		allowed, err := authorizer.HasPermission(role.(string), permission, scope)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "INTERNAL_ERROR", "Failed to check permissions")
+			c.Abort()
+			return
 		}
-
-		if !hasPermission {
+		if !allowed {
 			utils.ErrorResponse(c, 403, "FORBIDDEN", "Insufficient permissions")
 			c.Abort()
 			return
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"craftsbite-backend/internal/utils"
+	"craftsbite-backend/pkg/logger"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RateLimit allows Requests requests per Period. Buckets hold up to Requests tokens and
+// refill continuously, so short bursts are allowed as long as the average stays below the limit.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// PerMinute returns a limit of n requests per minute
+func PerMinute(n int) RateLimit {
+	return RateLimit{Requests: n, Period: time.Minute}
+}
+
+// RateLimitResult is the state of a bucket after taking a token from it
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// ResetAfter is how long until the bucket is full again
+	ResetAfter time.Duration
+	// RetryAfter is how long until the next token is available when the request was refused
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keeps the token buckets. MemoryRateLimitStore serves a single instance;
+// a shared backend such as Redis can implement it to enforce limits across instances.
+type RateLimitStore interface {
+	// Take refills the bucket under key and removes a token from it if one is available
+	Take(key string, limit RateLimit, now time.Time) (RateLimitResult, error)
+}
+
+// RateLimitPolicy is the limit of a route group. Reads (GET, HEAD, OPTIONS) and writes
+// use separate buckets so browsing a report cannot exhaust the budget for changes.
+type RateLimitPolicy struct {
+	Name  string
+	Read  RateLimit
+	Write RateLimit
+}
+
+// RateLimitMiddleware limits requests with token buckets keyed by the authenticated user,
+// or by client IP on routes that run before authentication. It sets the RateLimit-* headers
+// on every response and Retry-After when a request is refused.
+func RateLimitMiddleware(store RateLimitStore, policy RateLimitPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, kind := policy.Write, "write"
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			limit, kind = policy.Read, "read"
+		}
+
+		subject := "ip:" + c.ClientIP()
+		if userID := c.GetString("user_id"); userID != "" {
+			subject = "user:" + userID
+		}
+		key := fmt.Sprintf("%s:%s:%s", policy.Name, kind, subject)
+
+		result, err := store.Take(key, limit, time.Now())
+		if err != nil {
+			// Fail open: an unavailable store must not take the API down with it
+			logger.Warn("Rate limit store unavailable", zap.String("key", key), zap.Error(err))
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Period)))
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			header.Set("Retry-After", strconv.Itoa(retryAfter))
+			utils.ErrorResponse(c, 429, "RATE_LIMITED", fmt.Sprintf("Too many requests, retry in %d seconds", retryAfter))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// rateLimitSweepInterval is how often the memory store drops buckets that have refilled
+const rateLimitSweepInterval = time.Minute
+
+// MemoryRateLimitStore keeps token buckets in process memory
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens   float64
+	capacity float64
+	rate     float64 // tokens per second
+	updated  time.Time
+}
+
+// NewMemoryRateLimitStore creates an empty in-memory store
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*tokenBucket)}
+}
+
+func (s *MemoryRateLimitStore) Take(key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	if limit.Requests <= 0 || limit.Period <= 0 {
+		return RateLimitResult{}, fmt.Errorf("invalid rate limit %d per %s", limit.Requests, limit.Period)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= rateLimitSweepInterval {
+		s.sweep(now)
+	}
+
+	capacity := float64(limit.Requests)
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, updated: now}
+		s.buckets[key] = bucket
+	}
+	bucket.capacity = capacity
+	bucket.rate = capacity / limit.Period.Seconds()
+	bucket.refill(now)
+
+	result := RateLimitResult{}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - bucket.tokens) / bucket.rate)
+	}
+	result.Remaining = int(math.Floor(bucket.tokens))
+	result.ResetAfter = seconds((bucket.capacity - bucket.tokens) / bucket.rate)
+	return result, nil
+}
+
+// sweep drops the buckets that are full again; they behave exactly like new ones
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	for key, bucket := range s.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= bucket.capacity {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+		b.updated = now
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthAuditEvent is a security event recorded in the auth audit log
+type AuthAuditEvent string
+
+const (
+	AuthEventAccountLocked   AuthAuditEvent = "account_locked"
+	AuthEventIPLocked        AuthAuditEvent = "ip_locked"
+	AuthEventAccountUnlocked AuthAuditEvent = "account_unlocked"
+	AuthEventIPUnlocked      AuthAuditEvent = "ip_unlocked"
+
+	AuthEventInvitationCreated  AuthAuditEvent = "invitation_created"
+	AuthEventInvitationRevoked  AuthAuditEvent = "invitation_revoked"
+	AuthEventInvitationAccepted AuthAuditEvent = "invitation_accepted"
+	AuthEventUserRegistered     AuthAuditEvent = "user_registered"
+
+	AuthEventPasswordResetRequested AuthAuditEvent = "password_reset_requested"
+	AuthEventPasswordReset          AuthAuditEvent = "password_reset"
+	AuthEventPasswordChanged        AuthAuditEvent = "password_changed"
+
+	AuthEventTwoFactorEnabled          AuthAuditEvent = "two_factor_enabled"
+	AuthEventTwoFactorDisabled         AuthAuditEvent = "two_factor_disabled"
+	AuthEventTwoFactorReset            AuthAuditEvent = "two_factor_reset"
+	AuthEventTwoFactorRecoveryCodeUsed AuthAuditEvent = "two_factor_recovery_code_used"
+	AuthEventRecoveryCodesRegenerated  AuthAuditEvent = "recovery_codes_regenerated"
+)
+
+// String returns the string representation of the event
+func (e AuthAuditEvent) String() string {
+	return string(e)
+}
+
+// AuthAuditLog is an entry of the auth audit log
+type AuthAuditLog struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Event     AuthAuditEvent `gorm:"type:varchar(50);not null" json:"event"`
+	UserID    *uuid.UUID     `gorm:"type:uuid" json:"user_id,omitempty"`
+	Email     *string        `gorm:"type:varchar(255)" json:"email,omitempty"`
+	IPAddress *string        `gorm:"type:varchar(64)" json:"ip_address,omitempty"`
+	UserAgent *string        `gorm:"type:varchar(512)" json:"user_agent,omitempty"`
+	ActorID   *uuid.UUID     `gorm:"type:uuid" json:"actor_id,omitempty"`
+	Details   *string        `gorm:"type:text" json:"details,omitempty"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (AuthAuditLog) TableName() string {
+	return "auth_audit_logs"
+}
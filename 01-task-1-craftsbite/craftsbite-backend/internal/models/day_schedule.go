@@ -17,6 +17,9 @@ type DaySchedule struct {
 	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 
+	// RuleID is set on schedules expanded from a ScheduleRule rather than stored for the date
+	RuleID *uuid.UUID `gorm:"-" json:"rule_id,omitempty"`
+
 	// Relationships
 	Creator *User `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DiscordLink maps a Discord user ID to an internal user
+type DiscordLink struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	DiscordUserID string     `gorm:"type:varchar(32);not null;uniqueIndex:uq_discord_links_discord_user_id" json:"discord_user_id"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:uq_discord_links_user_id" json:"user_id"`
+	LinkedBy      *uuid.UUID `gorm:"type:uuid" json:"linked_by,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (DiscordLink) TableName() string {
+	return "discord_links"
+}
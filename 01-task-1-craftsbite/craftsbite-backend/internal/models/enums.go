@@ -1,25 +1,8 @@
 package models
 
-// MealType represents the type of meal
+// MealType is the code of a meal type in the meal type catalogue (see MealTypeDefinition)
 type MealType string
 
-const (
-	MealTypeLunch          MealType = "lunch"
-	MealTypeSnacks         MealType = "snacks"
-	MealTypeIftar          MealType = "iftar"
-	MealTypeEventDinner    MealType = "event_dinner"
-	MealTypeOptionalDinner MealType = "optional_dinner"
-)
-
-// IsValid checks if the meal type is valid
-func (m MealType) IsValid() bool {
-	switch m {
-	case MealTypeLunch, MealTypeSnacks, MealTypeIftar, MealTypeEventDinner, MealTypeOptionalDinner:
-		return true
-	}
-	return false
-}
-
 // String returns the string representation of the meal type
 func (m MealType) String() string {
 	return string(m)
@@ -59,12 +42,15 @@ const (
 	HistoryActionOptedOut    HistoryAction = "opted_out"
 	HistoryActionOverrideIn  HistoryAction = "override_in"
 	HistoryActionOverrideOut HistoryAction = "override_out"
+	HistoryActionWaitlisted  HistoryAction = "waitlisted"
+	HistoryActionPromoted    HistoryAction = "waitlist_promoted"
 )
 
 // IsValid checks if the history action is valid
 func (h HistoryAction) IsValid() bool {
 	switch h {
-	case HistoryActionOptedIn, HistoryActionOptedOut, HistoryActionOverrideIn, HistoryActionOverrideOut:
+	case HistoryActionOptedIn, HistoryActionOptedOut, HistoryActionOverrideIn, HistoryActionOverrideOut,
+		HistoryActionWaitlisted, HistoryActionPromoted:
 		return true
 	}
 	return false
@@ -89,3 +75,40 @@ func (w WorkLocationType) IsValid() bool {
 func (w WorkLocationType) String() string {
 	return string(w)
 }
+
+// DietaryRestriction is a preset dietary category the kitchen prepares portions for
+type DietaryRestriction string
+
+const (
+	DietaryVegetarian  DietaryRestriction = "vegetarian"
+	DietaryVegan       DietaryRestriction = "vegan"
+	DietaryHalal       DietaryRestriction = "halal"
+	DietaryGlutenFree  DietaryRestriction = "gluten_free"
+	DietaryLactoseFree DietaryRestriction = "lactose_free"
+	DietaryNutAllergy  DietaryRestriction = "nut_allergy"
+)
+
+// DietaryRestrictions lists the presets in display order
+var DietaryRestrictions = []DietaryRestriction{
+	DietaryVegetarian,
+	DietaryVegan,
+	DietaryHalal,
+	DietaryGlutenFree,
+	DietaryLactoseFree,
+	DietaryNutAllergy,
+}
+
+// IsValid checks if the dietary restriction is one of the presets
+func (d DietaryRestriction) IsValid() bool {
+	for _, preset := range DietaryRestrictions {
+		if d == preset {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the string representation of the dietary restriction
+func (d DietaryRestriction) String() string {
+	return string(d)
+}
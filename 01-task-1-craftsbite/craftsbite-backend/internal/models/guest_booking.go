@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GuestBooking reserves meals for visitors who have no user account, such as clients or interview candidates
+type GuestBooking struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	HostUserID   uuid.UUID `gorm:"type:uuid;not null;index" json:"host_user_id"`
+	Date         string    `gorm:"type:date;not null" json:"date"`
+	MealTypes    string    `gorm:"type:text;not null" json:"meal_types"` // Comma-separated meal types
+	GuestCount   int       `gorm:"not null" json:"guest_count"`
+	GuestNames   *string   `gorm:"type:text" json:"guest_names,omitempty"`
+	DietaryNotes *string   `gorm:"type:varchar(500)" json:"dietary_notes,omitempty"`
+	Purpose      *string   `gorm:"type:varchar(255)" json:"purpose,omitempty"`
+	IsActive     bool      `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Host User `gorm:"foreignKey:HostUserID;constraint:OnDelete:CASCADE" json:"host,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (GuestBooking) TableName() string {
+	return "guest_bookings"
+}
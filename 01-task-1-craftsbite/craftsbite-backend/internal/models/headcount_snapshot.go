@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HeadcountSnapshot is the headcount for a date and meal frozen at the meal cutoff
+type HeadcountSnapshot struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Date             string     `gorm:"type:date;not null;uniqueIndex:uq_headcount_snapshot_date_meal" json:"date"`
+	MealType         MealType   `gorm:"type:varchar(50);not null;uniqueIndex:uq_headcount_snapshot_date_meal" json:"meal_type"`
+	DayStatus        DayStatus  `gorm:"type:varchar(50);not null" json:"day_status"`
+	TotalActiveUsers int        `gorm:"not null;default:0" json:"total_active_users"`
+	Participating    int        `gorm:"not null;default:0" json:"participating"`
+	OptedOut         int        `gorm:"not null;default:0" json:"opted_out"`
+	Guests           int        `gorm:"not null;default:0" json:"guests"`
+	CapturedBy       *uuid.UUID `gorm:"type:uuid" json:"captured_by,omitempty"`
+	CapturedAt       time.Time  `gorm:"autoCreateTime" json:"captured_at"`
+
+	// Relationships
+	Teams []HeadcountSnapshotTeam `gorm:"foreignKey:SnapshotID;constraint:OnDelete:CASCADE" json:"teams,omitempty"`
+	Users []HeadcountSnapshotUser `gorm:"foreignKey:SnapshotID;constraint:OnDelete:CASCADE" json:"users,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (HeadcountSnapshot) TableName() string {
+	return "headcount_snapshots"
+}
+
+// HeadcountSnapshotTeam is the per-team breakdown of a headcount snapshot
+type HeadcountSnapshotTeam struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SnapshotID    uuid.UUID `gorm:"type:uuid;not null;index" json:"snapshot_id"`
+	TeamID        uuid.UUID `gorm:"type:uuid;not null" json:"team_id"`
+	TeamName      string    `gorm:"type:varchar(255);not null" json:"team_name"`
+	TotalMembers  int       `gorm:"not null;default:0" json:"total_members"`
+	Participating int       `gorm:"not null;default:0" json:"participating"`
+	OptedOut      int       `gorm:"not null;default:0" json:"opted_out"`
+	Guests        int       `gorm:"not null;default:0" json:"guests"`
+}
+
+// TableName specifies the table name for GORM
+func (HeadcountSnapshotTeam) TableName() string {
+	return "headcount_snapshot_teams"
+}
+
+// HeadcountSnapshotUser is the per-user breakdown of a headcount snapshot
+type HeadcountSnapshotUser struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SnapshotID      uuid.UUID `gorm:"type:uuid;not null;index" json:"snapshot_id"`
+	UserID          uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	Name            string    `gorm:"type:varchar(255);not null" json:"name"`
+	Email           string    `gorm:"type:varchar(255);not null" json:"email"`
+	IsParticipating bool      `gorm:"not null" json:"is_participating"`
+	Source          string    `gorm:"type:varchar(50);not null" json:"source"`
+}
+
+// TableName specifies the table name for GORM
+func (HeadcountSnapshotUser) TableName() string {
+	return "headcount_snapshot_users"
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation statuses, derived from the invitation's timestamps
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Invitation lets the holder of its token create an account with the invited role, joining
+// the invited team. Only the SHA-256 of the token is stored.
+type Invitation struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Email      string     `gorm:"type:varchar(255);not null" json:"email"`
+	Role       Role       `gorm:"type:varchar(50);not null" json:"role"`
+	TeamID     *uuid.UUID `gorm:"type:uuid" json:"team_id,omitempty"`
+	TokenHash  string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	UserID     *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy  *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Status is filled in from StatusAt when the invitation is returned
+	Status string `gorm:"-" json:"status"`
+
+	// Relationships
+	Team *Team `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (Invitation) TableName() string {
+	return "invitations"
+}
+
+// StatusAt returns whether the invitation is pending, accepted, revoked or expired at now
+func (i *Invitation) StatusAt(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case !now.Before(i.ExpiresAt):
+		return InvitationExpired
+	default:
+		return InvitationPending
+	}
+}
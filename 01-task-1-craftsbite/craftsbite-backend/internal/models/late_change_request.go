@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LateChangeStatus represents where a late change request is in its review
+type LateChangeStatus string
+
+const (
+	LateChangeStatusPending   LateChangeStatus = "pending"
+	LateChangeStatusApproved  LateChangeStatus = "approved"
+	LateChangeStatusRejected  LateChangeStatus = "rejected"
+	LateChangeStatusCancelled LateChangeStatus = "cancelled"
+	LateChangeStatusExpired   LateChangeStatus = "expired"
+)
+
+// String returns the string representation of the status
+func (s LateChangeStatus) String() string {
+	return string(s)
+}
+
+// LateChangeRequest asks a reviewer to change a user's participation after the meal cutoff has passed
+type LateChangeRequest struct {
+	ID              uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          uuid.UUID        `gorm:"type:uuid;not null;index" json:"user_id"`
+	Date            string           `gorm:"type:date;not null" json:"date"`
+	MealType        MealType         `gorm:"type:varchar(50);not null" json:"meal_type"`
+	IsParticipating bool             `gorm:"not null" json:"is_participating"`
+	Reason          string           `gorm:"type:varchar(255);not null" json:"reason"`
+	Status          LateChangeStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	ReviewedBy      *uuid.UUID       `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time       `json:"reviewed_at,omitempty"`
+	ReviewNote      *string          `gorm:"type:varchar(255)" json:"review_note,omitempty"`
+	CreatedAt       time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	User     User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Reviewer *User `gorm:"foreignKey:ReviewedBy;constraint:OnDelete:SET NULL" json:"reviewer,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (LateChangeRequest) TableName() string {
+	return "late_change_requests"
+}
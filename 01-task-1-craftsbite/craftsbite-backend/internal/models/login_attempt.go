@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Login attempt subjects
+const (
+	LoginSubjectAccount = "account"
+	LoginSubjectIP      = "ip"
+)
+
+// LoginAttempt counts the consecutive failed logins of an account, keyed by its normalized
+// email, or of a client IP. Rows are removed once the subject logs in or is unlocked.
+type LoginAttempt struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SubjectType   string     `gorm:"type:varchar(20);not null" json:"subject_type"`
+	Subject       string     `gorm:"type:varchar(255);not null" json:"subject"`
+	Failures      int        `gorm:"not null" json:"failures"`
+	LastFailureAt time.Time  `gorm:"not null" json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// IsLocked reports whether logins for the subject are refused at now
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MealCapacity limits the number of seats for a meal on a scheduled day
+type MealCapacity struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	DayScheduleID uuid.UUID  `gorm:"type:uuid;not null" json:"day_schedule_id"`
+	MealType      MealType   `gorm:"type:varchar(50);not null" json:"meal_type"`
+	Capacity      int        `gorm:"not null" json:"capacity"`
+	UpdatedBy     *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+	// SeatsTaken caches the seat count as of SeatsCountedAt; nil SeatsCountedAt means not counted yet
+	SeatsTaken     int        `gorm:"not null;default:0" json:"-"`
+	SeatsCountedAt *time.Time `json:"-"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (MealCapacity) TableName() string {
+	return "meal_capacities"
+}
+
+// MealWaitlistEntry is an opt-in that arrived after a meal was full. Entries are promoted in CreatedAt order.
+type MealWaitlistEntry struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	Date      string    `gorm:"type:date;not null" json:"date"`
+	MealType  MealType  `gorm:"type:varchar(50);not null" json:"meal_type"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (MealWaitlistEntry) TableName() string {
+	return "meal_waitlist_entries"
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Check-in methods
+const (
+	CheckInMethodQR     = "qr"
+	CheckInMethodManual = "manual"
+)
+
+// MealConsumption records that a user was served a meal, as opposed to MealParticipation which records intent
+type MealConsumption struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	Date        string     `gorm:"type:date;not null" json:"date"`
+	MealType    MealType   `gorm:"type:varchar(50);not null" json:"meal_type"`
+	Method      string     `gorm:"type:varchar(20);not null" json:"method"`
+	CheckedInBy *uuid.UUID `gorm:"type:uuid" json:"checked_in_by,omitempty"`
+	CheckedInAt time.Time  `gorm:"autoCreateTime" json:"checked_in_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (MealConsumption) TableName() string {
+	return "meal_consumptions"
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MealCutoffPolicy is the change deadline of a meal type: DaysBefore days before the meal date
+// at CutoffTime. With DayScheduleID set it overrides the default policy for that day only.
+type MealCutoffPolicy struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MealType      MealType   `gorm:"type:varchar(50);not null" json:"meal_type"`
+	DayScheduleID *uuid.UUID `gorm:"type:uuid" json:"day_schedule_id,omitempty"`
+	DaysBefore    int        `gorm:"not null" json:"days_before"`
+	CutoffTime    string     `gorm:"type:varchar(5);not null" json:"cutoff_time"`
+	UpdatedBy     *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (MealCutoffPolicy) TableName() string {
+	return "meal_cutoff_policies"
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MealPrice is the price of a meal type from EffectiveFrom until the next price of the same meal type.
+// Amounts are in minor currency units.
+type MealPrice struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MealType       MealType   `gorm:"type:varchar(50);not null;uniqueIndex:uq_meal_prices_meal_effective" json:"meal_type"`
+	UnitPrice      int64      `gorm:"not null" json:"unit_price"`
+	SubsidyPercent int        `gorm:"not null;default:0" json:"subsidy_percent"`
+	EffectiveFrom  string     `gorm:"type:date;not null;uniqueIndex:uq_meal_prices_meal_effective" json:"effective_from"`
+	Note           *string    `gorm:"type:varchar(255)" json:"note,omitempty"`
+	CreatedBy      *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (MealPrice) TableName() string {
+	return "meal_prices"
+}
+
+// CompanyShare is the part of the unit price paid by the company, rounded down
+func (p MealPrice) CompanyShare() int64 {
+	return p.UnitPrice * int64(p.SubsidyPercent) / 100
+}
+
+// EmployeeShare is the part of the unit price paid by the employee
+func (p MealPrice) EmployeeShare() int64 {
+	return p.UnitPrice - p.CompanyShare()
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// MealTypeDefinition is an entry of the meal type catalogue
+type MealTypeDefinition struct {
+	Code                 MealType  `gorm:"type:varchar(50);primary_key" json:"code"`
+	Label                string    `gorm:"type:varchar(100);not null" json:"label"`
+	Emoji                string    `gorm:"type:varchar(16);not null" json:"emoji"`
+	SortOrder            int       `gorm:"not null" json:"sort_order"`
+	DefaultAvailable     bool      `gorm:"not null" json:"default_available"`
+	DefaultParticipating bool      `gorm:"not null" json:"default_participating"`
+	IsActive             bool      `gorm:"not null" json:"is_active"`
+	CreatedAt            time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt            time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (MealTypeDefinition) TableName() string {
+	return "meal_types"
+}
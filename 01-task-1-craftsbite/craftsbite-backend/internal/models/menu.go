@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Menu lists the dishes served for a meal on a date. Drafts are only visible to menu managers.
+type Menu struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Date        string     `gorm:"type:date;not null" json:"date"`
+	MealType    MealType   `gorm:"type:varchar(50);not null" json:"meal_type"`
+	Note        *string    `gorm:"type:varchar(500)" json:"note,omitempty"`
+	IsPublished bool       `gorm:"not null" json:"is_published"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	CreatedBy   *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	UpdatedBy   *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Dishes []MenuDish `gorm:"foreignKey:MenuID;constraint:OnDelete:CASCADE" json:"dishes"`
+}
+
+// TableName specifies the table name for GORM
+func (Menu) TableName() string {
+	return "menus"
+}
+
+// MenuDish is a single dish on a menu
+type MenuDish struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MenuID      uuid.UUID `gorm:"type:uuid;not null;index" json:"menu_id"`
+	Position    int       `gorm:"not null" json:"position"`
+	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
+	Description *string   `gorm:"type:varchar(1000)" json:"description,omitempty"`
+	DietaryTags string    `gorm:"type:text;not null" json:"dietary_tags"` // Comma-separated dietary restrictions
+	PhotoURL    *string   `gorm:"type:varchar(2048)" json:"photo_url,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (MenuDish) TableName() string {
+	return "menu_dishes"
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification kinds
+const (
+	NotificationWaitlistPromoted = "waitlist_promoted"
+)
+
+// Notification is an in-app message for a single user
+type Notification struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Kind      string     `gorm:"type:varchar(50);not null" json:"kind"`
+	Title     string     `gorm:"type:varchar(255);not null" json:"title"`
+	Message   string     `gorm:"type:text;not null" json:"message"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ParticipationRule is a user's weekly recurring participation for a meal type,
+// such as "no snacks on Fridays", optionally limited to a date range
+type ParticipationRule struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	MealType        MealType  `gorm:"type:varchar(50);not null" json:"meal_type"`
+	Weekdays        string    `gorm:"type:varchar(100);not null" json:"weekdays"` // Comma-separated lowercase weekday names
+	IsParticipating bool      `gorm:"not null" json:"is_participating"`
+	StartDate       *string   `gorm:"type:date" json:"start_date,omitempty"`
+	EndDate         *string   `gorm:"type:date" json:"end_date,omitempty"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ParticipationRule) TableName() string {
+	return "participation_rules"
+}
+
+// AppliesOn reports whether the rule covers a weekday, given as a lowercase name
+func (r ParticipationRule) AppliesOn(weekday string) bool {
+	for _, day := range strings.Split(r.Weekdays, ",") {
+		if strings.TrimSpace(day) == weekday {
+			return true
+		}
+	}
+	return false
+}
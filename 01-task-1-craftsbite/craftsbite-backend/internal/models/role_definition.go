@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// RoleDefinition is a role stored in the database together with its permission grants.
+// User.Role references RoleDefinition.Name.
+type RoleDefinition struct {
+	Name        string           `gorm:"type:varchar(50);primaryKey" json:"name"`
+	Description string           `gorm:"type:text" json:"description,omitempty"`
+	IsSystem    bool             `gorm:"not null;default:false" json:"is_system"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+	Permissions []RolePermission `gorm:"foreignKey:RoleName;references:Name;constraint:OnDelete:CASCADE" json:"permissions"`
+}
+
+// TableName specifies the table name for GORM
+func (RoleDefinition) TableName() string {
+	return "roles"
+}
+
+// RolePermission grants a permission to a role at a scope (own, team or all)
+type RolePermission struct {
+	RoleName   string `gorm:"type:varchar(50);primaryKey" json:"-"`
+	Permission string `gorm:"type:varchar(100);primaryKey" json:"permission"`
+	Scope      string `gorm:"type:varchar(20);not null" json:"scope"`
+}
+
+// TableName specifies the table name for GORM
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleRule is a recurring day schedule. Each date matching RRule between StartDate and EndDate
+// gets the rule's status and meals unless an explicit DaySchedule exists for it. SourceUID is
+// set on rules imported from an iCalendar event.
+type ScheduleRule struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name           string     `gorm:"type:varchar(255);not null" json:"name"`
+	RRule          string     `gorm:"column:rrule;type:text;not null" json:"rrule"`
+	StartDate      string     `gorm:"type:date;not null" json:"start_date"`
+	EndDate        *string    `gorm:"type:date" json:"end_date,omitempty"`
+	DayStatus      DayStatus  `gorm:"type:varchar(50);not null;default:'normal'" json:"day_status"`
+	Reason         *string    `gorm:"type:text" json:"reason,omitempty"`
+	AvailableMeals *string    `gorm:"type:text" json:"available_meals,omitempty"`
+	Priority       int        `gorm:"not null;default:0" json:"priority"`
+	Active         bool       `gorm:"not null;default:true" json:"active"`
+	CreatedBy      *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	SourceUID      *string    `gorm:"type:text" json:"source_uid,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ScheduleRule) TableName() string {
+	return "schedule_rules"
+}
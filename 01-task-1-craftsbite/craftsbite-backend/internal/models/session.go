@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionRevokeReason records why a session was revoked
+type SessionRevokeReason string
+
+const (
+	SessionRevokeLogout      SessionRevokeReason = "logout"
+	SessionRevokeUser        SessionRevokeReason = "user_revoked"
+	SessionRevokeAdmin       SessionRevokeReason = "admin_revoked"
+	SessionRevokeDeactivated SessionRevokeReason = "user_deactivated"
+	SessionRevokeTokenReuse  SessionRevokeReason = "token_reuse"
+	SessionRevokePassword    SessionRevokeReason = "password_changed"
+)
+
+// Session is a server-side login session backing a rotating refresh token
+type Session struct {
+	ID                uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID            uuid.UUID            `gorm:"type:uuid;not null;index" json:"user_id"`
+	RefreshTokenHash  string               `gorm:"type:varchar(64);not null;uniqueIndex:uq_sessions_refresh_token_hash" json:"-"`
+	PreviousTokenHash *string              `gorm:"type:varchar(64);index" json:"-"`
+	UserAgent         string               `gorm:"type:varchar(512);not null;default:''" json:"user_agent"`
+	IPAddress         string               `gorm:"type:varchar(64);not null;default:''" json:"ip_address"`
+	ExpiresAt         time.Time            `gorm:"not null" json:"expires_at"`
+	LastUsedAt        time.Time            `gorm:"not null" json:"last_used_at"`
+	RevokedAt         *time.Time           `json:"revoked_at,omitempty"`
+	RevokedReason     *SessionRevokeReason `gorm:"type:varchar(50)" json:"revoked_reason,omitempty"`
+	CreatedAt         time.Time            `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for GORM
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// IsActive reports whether the session is neither revoked nor expired
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
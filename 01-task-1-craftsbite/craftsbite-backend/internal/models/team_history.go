@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TeamHistoryAction represents an action in the team audit history
+type TeamHistoryAction string
+
+const (
+	TeamActionCreated       TeamHistoryAction = "created"
+	TeamActionUpdated       TeamHistoryAction = "updated"
+	TeamActionLeadChanged   TeamHistoryAction = "lead_changed"
+	TeamActionMemberAdded   TeamHistoryAction = "member_added"
+	TeamActionMemberRemoved TeamHistoryAction = "member_removed"
+	TeamActionDeactivated   TeamHistoryAction = "deactivated"
+	TeamActionReactivated   TeamHistoryAction = "reactivated"
+)
+
+// String returns the string representation of the team history action
+func (a TeamHistoryAction) String() string {
+	return string(a)
+}
+
+// TeamHistory is an audit record of a change to a team
+type TeamHistory struct {
+	ID            uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TeamID        uuid.UUID         `gorm:"type:uuid;not null;index" json:"team_id"`
+	Action        TeamHistoryAction `gorm:"type:varchar(30);not null" json:"action"`
+	ActorID       *uuid.UUID        `gorm:"type:uuid" json:"actor_id,omitempty"`
+	SubjectID     *uuid.UUID        `gorm:"type:uuid" json:"subject_id,omitempty"`
+	PreviousValue *string           `gorm:"type:text" json:"previous_value,omitempty"`
+	NewValue      *string           `gorm:"type:text" json:"new_value,omitempty"`
+	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationships
+	Actor   *User `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL" json:"actor,omitempty"`
+	Subject *User `gorm:"foreignKey:SubjectID;constraint:OnDelete:SET NULL" json:"subject,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (TeamHistory) TableName() string {
+	return "team_history"
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Login challenge purposes
+const (
+	// LoginChallengeTwoFactor asks for a TOTP or recovery code
+	LoginChallengeTwoFactor = "two_factor"
+	// LoginChallengeTwoFactorSetup asks a user whose role requires 2FA to enrol first
+	LoginChallengeTwoFactorSetup = "two_factor_setup"
+)
+
+// UserTwoFactor is a user's TOTP enrolment. It is pending until EnabledAt is set by
+// confirming a first code.
+type UserTwoFactor struct {
+	UserID       uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Secret       string     `gorm:"type:text;not null" json:"-"` // Sealed with utils.SealSecret
+	EnabledAt    *time.Time `json:"enabled_at,omitempty"`
+	LastUsedStep int64      `gorm:"not null;default:0" json:"-"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (UserTwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// IsEnabled reports whether the enrolment has been confirmed
+func (t *UserTwoFactor) IsEnabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// TwoFactorRecoveryCode is a single-use code that replaces a TOTP code. Only its SHA-256 is stored.
+type TwoFactorRecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (TwoFactorRecoveryCode) TableName() string {
+	return "two_factor_recovery_codes"
+}
+
+// LoginChallenge is the second step of a login whose password was correct. Its token is
+// single use and only its SHA-256 is stored.
+type LoginChallenge struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Purpose   string    `gorm:"type:varchar(30);not null" json:"purpose"`
+	Attempts  int       `gorm:"not null;default:0" json:"attempts"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (LoginChallenge) TableName() string {
+	return "login_challenges"
+}
@@ -15,6 +15,8 @@ type User struct {
 	Role                  Role      `gorm:"type:varchar(50);not null;default:'employee'" json:"role" validate:"required"`
 	Active                bool      `gorm:"not null;default:true" json:"active"`
 	DefaultMealPreference string    `gorm:"type:varchar(20);not null;default:'opt_in'" json:"default_meal_preference"`
+	DietaryRestrictions   string    `gorm:"type:text;not null;default:''" json:"dietary_restrictions"` // Comma-separated DietaryRestriction presets
+	Allergens             string    `gorm:"type:varchar(500);not null;default:''" json:"allergens"`    // Free text for allergies not covered by the presets
 	CreatedAt             time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt             time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Vendor delivery methods
+const (
+	VendorDeliveryEmail   = "email"
+	VendorDeliveryWebhook = "webhook"
+)
+
+// Vendor is a caterer that supplies some meal types and receives orders generated from the headcount
+type Vendor struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name            string    `gorm:"type:varchar(255);not null" json:"name"`
+	MealTypes       string    `gorm:"type:text;not null" json:"meal_types"` // Comma-separated meal types
+	DeliveryMethod  string    `gorm:"type:varchar(20);not null" json:"delivery_method"`
+	Email           *string   `gorm:"type:varchar(255)" json:"email,omitempty"`
+	WebhookURL      *string   `gorm:"type:varchar(2048)" json:"webhook_url,omitempty"`
+	WebhookSecret   *string   `gorm:"type:varchar(255)" json:"-"`
+	LeadDays        int       `gorm:"not null" json:"lead_days"`
+	OrderDeadline   string    `gorm:"type:varchar(5);not null" json:"order_deadline"` // HH:MM on the due day
+	SubjectTemplate *string   `gorm:"type:text" json:"subject_template,omitempty"`
+	BodyTemplate    *string   `gorm:"type:text" json:"body_template,omitempty"`
+	IsActive        bool      `gorm:"not null" json:"is_active"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Vendor) TableName() string {
+	return "vendors"
+}
+
+// VendorOrderStatus represents where a vendor order is in its lifecycle
+type VendorOrderStatus string
+
+const (
+	VendorOrderStatusDraft        VendorOrderStatus = "draft"
+	VendorOrderStatusSent         VendorOrderStatus = "sent"
+	VendorOrderStatusAcknowledged VendorOrderStatus = "acknowledged"
+	VendorOrderStatusAmended      VendorOrderStatus = "amended"
+)
+
+// String returns the string representation of the status
+func (s VendorOrderStatus) String() string {
+	return string(s)
+}
+
+// VendorOrder is the order sent to a vendor for the meals of a date. Revision is bumped every
+// time the headcount changes after the order went out; DeliveredRevision trails it while the
+// amendment is waiting to be delivered.
+type VendorOrder struct {
+	ID                uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	VendorID          uuid.UUID         `gorm:"type:uuid;not null" json:"vendor_id"`
+	Date              string            `gorm:"type:date;not null" json:"date"`
+	Status            VendorOrderStatus `gorm:"type:varchar(20);not null;default:'draft'" json:"status"`
+	Revision          int               `gorm:"not null" json:"revision"`
+	DeliveredRevision int               `gorm:"not null" json:"delivered_revision"`
+	Subject           string            `gorm:"type:varchar(500);not null" json:"subject"`
+	Document          string            `gorm:"type:text;not null" json:"document"`
+	SentAt            *time.Time        `json:"sent_at,omitempty"`
+	AcknowledgedAt    *time.Time        `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy    *uuid.UUID        `gorm:"type:uuid" json:"acknowledged_by,omitempty"`
+	LastError         *string           `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedBy         *uuid.UUID        `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt         time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Vendor *Vendor           `gorm:"foreignKey:VendorID" json:"vendor,omitempty"`
+	Lines  []VendorOrderLine `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"lines"`
+}
+
+// TableName specifies the table name for GORM
+func (VendorOrder) TableName() string {
+	return "vendor_orders"
+}
+
+// VendorOrderLine is the quantity of one meal on a vendor order
+type VendorOrderLine struct {
+	ID            uuid.UUID                  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrderID       uuid.UUID                  `gorm:"type:uuid;not null;index" json:"order_id"`
+	MealType      MealType                   `gorm:"type:varchar(50);not null" json:"meal_type"`
+	Participating int                        `gorm:"not null" json:"participating"`
+	Guests        int                        `gorm:"not null" json:"guests"`
+	Dietary       map[DietaryRestriction]int `gorm:"type:jsonb;serializer:json;not null" json:"dietary"`
+	WithAllergens int                        `gorm:"not null" json:"with_allergens"`
+}
+
+// TableName specifies the table name for GORM
+func (VendorOrderLine) TableName() string {
+	return "vendor_order_lines"
+}
@@ -18,7 +18,7 @@ type WorkLocationHistory struct {
 	CreatedAt        time.Time        `gorm:"autoCreateTime;index:idx_history_created_at" json:"created_at"`
 
 	User             User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
-	OverrideByUserID *User `gorm:"foreignKey:OverrideBy;constraint:OnDelete:SET NULL" json:"override_by,omitempty"`
+	OverrideByUserID *User `gorm:"foreignKey:OverrideBy;constraint:OnDelete:SET NULL" json:"override_by_user,omitempty"`
 }
 
 func (WorkLocationHistory) TableName() string {
@@ -0,0 +1,327 @@
+// Package recurrence implements the date-level subset of iCalendar recurrence rules (RFC 5545 RRULE)
+// used by recurring day schedules. Time-of-day parts (BYHOUR, BYMINUTE, ...) are not supported
+// because schedules apply to whole days.
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the base period of a rule
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxOccurrenceScan bounds how many days are walked when expanding a rule (about 50 years)
+const maxOccurrenceScan = 366 * 50
+
+// WeekdayNum is a BYDAY entry such as "FR", "1MO" (first Monday) or "-1SU" (last Sunday)
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule is a parsed recurrence rule. Occurrences are anchored on a start date supplied when expanding.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []int
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;BYDAY=TH" or "RRULE:FREQ=DAILY;UNTIL=20270309"
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("recurrence rule is empty")
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		val = strings.ToUpper(strings.TrimSpace(val))
+		if seen[key] {
+			return nil, fmt.Errorf("%s is given more than once", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch Frequency(val) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = Frequency(val)
+			default:
+				err = fmt.Errorf("unsupported FREQ %s", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(key, val)
+		case "COUNT":
+			rule.Count, err = parsePositive(key, val)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(val)
+			rule.Until = &until
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseIntList(key, val, -31, 31)
+		case "BYMONTH":
+			rule.ByMonth, err = parseIntList(key, val, 1, 12)
+		case "WKST":
+			if val != "MO" {
+				err = fmt.Errorf("only WKST=MO is supported")
+			}
+		default:
+			err = fmt.Errorf("unsupported rule part %s", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot be combined")
+	}
+	if rule.Freq == Weekly && len(rule.ByMonthDay) > 0 {
+		return nil, fmt.Errorf("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	for _, wd := range rule.ByDay {
+		if wd.N != 0 && rule.Freq != Monthly && rule.Freq != Yearly {
+			return nil, fmt.Errorf("numbered BYDAY values require FREQ=MONTHLY or FREQ=YEARLY")
+		}
+	}
+
+	return rule, nil
+}
+
+// Between returns the occurrences of the rule anchored at start that fall within [from, to], in order.
+// All values are treated as calendar dates; the time of day is ignored.
+func (r *Rule) Between(start, from, to time.Time) []time.Time {
+	start, from, to = truncateDay(start), truncateDay(from), truncateDay(to)
+
+	var occurrences []time.Time
+	count := 0
+	for day, i := start, 0; !day.After(to) && i < maxOccurrenceScan; day, i = day.AddDate(0, 0, 1), i+1 {
+		if r.Until != nil && day.After(*r.Until) {
+			break
+		}
+		if !r.matches(start, day) {
+			continue
+		}
+		count++
+		if !day.Before(from) {
+			occurrences = append(occurrences, day)
+		}
+		if r.Count > 0 && count >= r.Count {
+			break
+		}
+	}
+	return occurrences
+}
+
+// matches reports whether day is an occurrence of a rule anchored at start, ignoring COUNT and UNTIL
+func (r *Rule) matches(start, day time.Time) bool {
+	if !r.inInterval(start, day) {
+		return false
+	}
+
+	if len(r.ByMonth) > 0 && !containsInt(r.ByMonth, int(day.Month())) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 && !matchesMonthDay(r.ByMonthDay, day) {
+		return false
+	}
+	if len(r.ByDay) > 0 && !r.matchesByDay(day) {
+		return false
+	}
+
+	// Without BYDAY or BYMONTHDAY the rule repeats on the start date's weekday, day or anniversary
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		switch r.Freq {
+		case Weekly:
+			return day.Weekday() == start.Weekday()
+		case Monthly:
+			return day.Day() == start.Day()
+		case Yearly:
+			if len(r.ByMonth) == 0 && day.Month() != start.Month() {
+				return false
+			}
+			return day.Day() == start.Day()
+		}
+	}
+	return true
+}
+
+// inInterval checks that day falls in a period that is a multiple of INTERVAL periods after start
+func (r *Rule) inInterval(start, day time.Time) bool {
+	var periods int
+	switch r.Freq {
+	case Daily:
+		periods = daysBetween(start, day)
+	case Weekly:
+		periods = daysBetween(weekStart(start), weekStart(day)) / 7
+	case Monthly:
+		periods = (day.Year()-start.Year())*12 + int(day.Month()) - int(start.Month())
+	case Yearly:
+		periods = day.Year() - start.Year()
+	}
+	return periods%r.Interval == 0
+}
+
+// matchesByDay checks BYDAY; numbered entries count within the month, or within the year for
+// FREQ=YEARLY without BYMONTH
+func (r *Rule) matchesByDay(day time.Time) bool {
+	for _, wd := range r.ByDay {
+		if wd.Day != day.Weekday() {
+			continue
+		}
+		if wd.N == 0 {
+			return true
+		}
+
+		var nth, fromEnd int
+		if r.Freq == Yearly && len(r.ByMonth) == 0 {
+			nth = (day.YearDay()-1)/7 + 1
+			fromEnd = (daysInYear(day.Year())-day.YearDay())/7 + 1
+		} else {
+			nth = (day.Day()-1)/7 + 1
+			fromEnd = (daysInMonth(day)-day.Day())/7 + 1
+		}
+		if wd.N == nth || wd.N == -fromEnd {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesMonthDay(monthDays []int, day time.Time) bool {
+	last := daysInMonth(day)
+	for _, md := range monthDays {
+		if md == day.Day() || (md < 0 && last+md+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePositive(key, val string) (int, error) {
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", key)
+	}
+	return n, nil
+}
+
+func parseUntil(val string) (time.Time, error) {
+	if len(val) < 8 {
+		return time.Time{}, fmt.Errorf("UNTIL must be a date (YYYYMMDD) or date-time")
+	}
+	until, err := time.Parse("20060102", val[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("UNTIL must be a date (YYYYMMDD) or date-time")
+	}
+	return until, nil
+}
+
+func parseByDay(val string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY value %q", item)
+		}
+		code := item[len(item)-2:]
+		weekday, ok := weekdayCodes[code]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY value %q", item)
+		}
+		n := 0
+		if prefix := item[:len(item)-2]; prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid BYDAY value %q", item)
+			}
+		}
+		days = append(days, WeekdayNum{N: n, Day: weekday})
+	}
+	return days, nil
+}
+
+func parseIntList(key, val string, min, max int) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(val, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, fmt.Errorf("invalid %s value %q", key, item)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func containsInt(values []int, n int) bool {
+	for _, v := range values {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// weekStart returns the Monday of the week containing t
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
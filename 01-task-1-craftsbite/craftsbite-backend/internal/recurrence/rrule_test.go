@@ -0,0 +1,159 @@
+package recurrence
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func dates(values ...string) []time.Time {
+	var out []time.Time
+	for _, v := range values {
+		out = append(out, date(v))
+	}
+	return out
+}
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		from  string
+		to    string
+		want  []time.Time
+	}{
+		{
+			name:  "weekly every other week on two days",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			start: "2026-01-05",
+			from:  "2026-01-01",
+			to:    "2026-02-01",
+			want:  dates("2026-01-05", "2026-01-07", "2026-01-19", "2026-01-21"),
+		},
+		{
+			name:  "weekly every other week on the start weekday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2",
+			start: "2026-01-07",
+			from:  "2026-01-07",
+			to:    "2026-02-10",
+			want:  dates("2026-01-07", "2026-01-21", "2026-02-04"),
+		},
+		{
+			name:  "last Friday of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: "2026-01-01",
+			from:  "2026-01-01",
+			to:    "2026-04-30",
+			want:  dates("2026-01-30", "2026-02-27", "2026-03-27", "2026-04-24"),
+		},
+		{
+			name:  "last Sunday of the month",
+			rule:  "RRULE:FREQ=MONTHLY;BYDAY=-1SU",
+			start: "2026-01-01",
+			from:  "2026-01-01",
+			to:    "2026-03-31",
+			want:  dates("2026-01-25", "2026-02-22", "2026-03-29"),
+		},
+		{
+			name:  "last day of the month across February",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: "2026-01-01",
+			from:  "2026-01-01",
+			to:    "2026-04-30",
+			want:  dates("2026-01-31", "2026-02-28", "2026-03-31", "2026-04-30"),
+		},
+		{
+			name:  "last day of February in a leap year",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: "2028-02-01",
+			from:  "2028-02-01",
+			to:    "2028-03-31",
+			want:  dates("2028-02-29", "2028-03-31"),
+		},
+		{
+			name:  "count is taken from the start, not from the window",
+			rule:  "FREQ=DAILY;COUNT=5",
+			start: "2026-03-01",
+			from:  "2026-03-04",
+			to:    "2026-03-31",
+			want:  dates("2026-03-04", "2026-03-05"),
+		},
+		{
+			name:  "count used up before the window",
+			rule:  "FREQ=WEEKLY;BYDAY=TU;COUNT=2",
+			start: "2026-03-01",
+			from:  "2026-03-15",
+			to:    "2026-03-31",
+			want:  nil,
+		},
+		{
+			name:  "until includes its own date",
+			rule:  "FREQ=DAILY;UNTIL=20260310",
+			start: "2026-03-08",
+			from:  "2026-03-01",
+			to:    "2026-03-31",
+			want:  dates("2026-03-08", "2026-03-09", "2026-03-10"),
+		},
+		{
+			name:  "until as a date-time on an occurrence",
+			rule:  "FREQ=WEEKLY;BYDAY=TU;UNTIL=20260310T235959Z",
+			start: "2026-03-01",
+			from:  "2026-03-01",
+			to:    "2026-03-31",
+			want:  dates("2026-03-03", "2026-03-10"),
+		},
+		{
+			name:  "until before the window",
+			rule:  "FREQ=DAILY;UNTIL=20260310",
+			start: "2026-03-01",
+			from:  "2026-03-11",
+			to:    "2026-03-31",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			got := rule.Between(date(tt.start), date(tt.from), date(tt.to))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Between = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	rules := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=3;UNTIL=20260310",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=WEEKLY;BYDAY=-1FR",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=DAILY;BYHOUR=9",
+	}
+
+	for _, value := range rules {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", value)
+		}
+	}
+}
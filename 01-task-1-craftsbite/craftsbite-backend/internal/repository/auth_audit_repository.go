@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuthAuditFilter narrows the auth audit log; zero fields are ignored
+type AuthAuditFilter struct {
+	Event  string
+	UserID string
+	Since  *time.Time
+	Until  *time.Time
+	Limit  int
+}
+
+// AuthAuditRepository defines data access for the auth audit log
+type AuthAuditRepository interface {
+	Create(entry *models.AuthAuditLog) error
+	FindAll(filter AuthAuditFilter) ([]models.AuthAuditLog, error)
+}
+
+type authAuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthAuditRepository creates a new auth audit repository
+func NewAuthAuditRepository(db *gorm.DB) AuthAuditRepository {
+	return &authAuditRepository{db: db}
+}
+
+// Create appends an entry to the audit log
+func (r *authAuditRepository) Create(entry *models.AuthAuditLog) error {
+	if err := r.db.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to write auth audit log: %w", err)
+	}
+	return nil
+}
+
+// FindAll returns the entries matching a filter, newest first
+func (r *authAuditRepository) FindAll(filter AuthAuditFilter) ([]models.AuthAuditLog, error) {
+	query := r.db.Model(&models.AuthAuditLog{})
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var entries []models.AuthAuditLog
+	if err := query.Order("created_at DESC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to find auth audit log: %w", err)
+	}
+	return entries, nil
+}
@@ -17,6 +17,7 @@ type BulkOptOutRepository interface {
 	Delete(id string) error
 	Deactivate(id string) error
 	FindActiveByUserAndMealType (userID uuid.UUID, mealType models.MealType, date string) (*models.BulkOptOut, error)
+	FindActiveByUsersAndDateRange(userIDs []string, startDate, endDate string) ([]models.BulkOptOut, error)
 }
 
 // bulkOptOutRepository implements BulkOptOutRepository
@@ -93,3 +94,18 @@ func (r *bulkOptOutRepository) FindActiveByUserAndMealType(userID uuid.UUID, mea
     }
     return &bulkOptOut, nil
 }
+
+// FindActiveByUsersAndDateRange finds active bulk opt-outs for the given users that overlap a date range
+func (r *bulkOptOutRepository) FindActiveByUsersAndDateRange(userIDs []string, startDate, endDate string) ([]models.BulkOptOut, error) {
+	if len(userIDs) == 0 {
+		return []models.BulkOptOut{}, nil
+	}
+	var bulkOptOuts []models.BulkOptOut
+	err := r.db.Where("user_id IN ? AND is_active = ? AND start_date <= ? AND end_date >= ?",
+		userIDs, true, endDate, startDate).
+		Find(&bulkOptOuts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active bulk opt-outs by users: %w", err)
+	}
+	return bulkOptOuts, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CapacityRepository defines data access for per-day meal capacities
+type CapacityRepository interface {
+	FindBySchedule(scheduleID string) ([]models.MealCapacity, error)
+	// Find returns the capacity of a meal on a day schedule, or nil when it is unlimited
+	Find(scheduleID, mealType string) (*models.MealCapacity, error)
+	// FindForUpdate is Find with a row lock held until the surrounding transaction ends
+	FindForUpdate(scheduleID, mealType string) (*models.MealCapacity, error)
+	UpdateSeatCount(id string, seatsTaken int, countedAt time.Time) error
+	Save(capacity *models.MealCapacity) error
+	Delete(id string) error
+}
+
+type capacityRepository struct {
+	db *gorm.DB
+}
+
+// NewCapacityRepository creates a new capacity repository
+func NewCapacityRepository(db *gorm.DB) CapacityRepository {
+	return &capacityRepository{db: db}
+}
+
+func (r *capacityRepository) FindBySchedule(scheduleID string) ([]models.MealCapacity, error) {
+	var capacities []models.MealCapacity
+	if err := r.db.Where("day_schedule_id = ?", scheduleID).Order("meal_type ASC").Find(&capacities).Error; err != nil {
+		return nil, fmt.Errorf("failed to find meal capacities: %w", err)
+	}
+	return capacities, nil
+}
+
+func (r *capacityRepository) Find(scheduleID, mealType string) (*models.MealCapacity, error) {
+	var capacity models.MealCapacity
+	err := r.db.Where("day_schedule_id = ? AND meal_type = ?", scheduleID, mealType).First(&capacity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find meal capacity: %w", err)
+	}
+	return &capacity, nil
+}
+
+func (r *capacityRepository) FindForUpdate(scheduleID, mealType string) (*models.MealCapacity, error) {
+	var capacity models.MealCapacity
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("day_schedule_id = ? AND meal_type = ?", scheduleID, mealType).
+		First(&capacity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to lock meal capacity: %w", err)
+	}
+	return &capacity, nil
+}
+
+func (r *capacityRepository) UpdateSeatCount(id string, seatsTaken int, countedAt time.Time) error {
+	err := r.db.Model(&models.MealCapacity{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"seats_taken": seatsTaken, "seats_counted_at": countedAt}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update meal capacity seat count: %w", err)
+	}
+	return nil
+}
+
+// Save creates the capacity, or updates it if it already has an ID
+func (r *capacityRepository) Save(capacity *models.MealCapacity) error {
+	if err := r.db.Save(capacity).Error; err != nil {
+		return fmt.Errorf("failed to save meal capacity: %w", err)
+	}
+	return nil
+}
+
+func (r *capacityRepository) Delete(id string) error {
+	if err := r.db.Delete(&models.MealCapacity{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete meal capacity: %w", err)
+	}
+	return nil
+}
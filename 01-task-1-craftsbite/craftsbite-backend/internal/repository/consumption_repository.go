@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ConsumptionRepository defines data access for meal check-ins
+type ConsumptionRepository interface {
+	Create(consumption *models.MealConsumption) error
+	FindByID(id string) (*models.MealConsumption, error)
+	// Find returns a user's check-in for a meal, or nil
+	Find(userID, date, mealType string) (*models.MealConsumption, error)
+	// FindByDateAndMeal returns a meal's check-ins in arrival order, with users loaded
+	FindByDateAndMeal(date, mealType string) ([]models.MealConsumption, error)
+	FindByDateRange(startDate, endDate string) ([]models.MealConsumption, error)
+	Delete(id string) error
+}
+
+type consumptionRepository struct {
+	db *gorm.DB
+}
+
+// NewConsumptionRepository creates a new consumption repository
+func NewConsumptionRepository(db *gorm.DB) ConsumptionRepository {
+	return &consumptionRepository{db: db}
+}
+
+func (r *consumptionRepository) Create(consumption *models.MealConsumption) error {
+	if err := r.db.Create(consumption).Error; err != nil {
+		return fmt.Errorf("failed to record check-in: %w", err)
+	}
+	return nil
+}
+
+func (r *consumptionRepository) FindByID(id string) (*models.MealConsumption, error) {
+	var consumption models.MealConsumption
+	if err := r.db.Where("id = ?", id).First(&consumption).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find check-in: %w", err)
+	}
+	return &consumption, nil
+}
+
+func (r *consumptionRepository) Find(userID, date, mealType string) (*models.MealConsumption, error) {
+	var consumption models.MealConsumption
+	err := r.db.Where("user_id = ? AND date = ? AND meal_type = ?", userID, date, mealType).First(&consumption).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find check-in: %w", err)
+	}
+	return &consumption, nil
+}
+
+func (r *consumptionRepository) FindByDateAndMeal(date, mealType string) ([]models.MealConsumption, error) {
+	var consumptions []models.MealConsumption
+	err := r.db.Preload("User").
+		Where("date = ? AND meal_type = ?", date, mealType).
+		Order("checked_in_at ASC").
+		Find(&consumptions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find check-ins: %w", err)
+	}
+	return consumptions, nil
+}
+
+func (r *consumptionRepository) FindByDateRange(startDate, endDate string) ([]models.MealConsumption, error) {
+	var consumptions []models.MealConsumption
+	if err := r.db.Where("date >= ? AND date <= ?", startDate, endDate).Find(&consumptions).Error; err != nil {
+		return nil, fmt.Errorf("failed to find check-ins: %w", err)
+	}
+	return consumptions, nil
+}
+
+func (r *consumptionRepository) Delete(id string) error {
+	if err := r.db.Delete(&models.MealConsumption{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete check-in: %w", err)
+	}
+	return nil
+}
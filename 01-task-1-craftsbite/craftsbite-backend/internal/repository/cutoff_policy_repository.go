@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// CutoffPolicyRepository defines data access for meal cutoff policies and their per-day overrides
+type CutoffPolicyRepository interface {
+	// FindDefaults returns the policies that apply when a day has no override
+	FindDefaults() ([]models.MealCutoffPolicy, error)
+	// FindBySchedule returns the overrides attached to a day schedule
+	FindBySchedule(scheduleID string) ([]models.MealCutoffPolicy, error)
+	// Find returns the policy of a meal type, or nil; a nil scheduleID selects the default policy
+	Find(scheduleID *string, mealType string) (*models.MealCutoffPolicy, error)
+	Save(policy *models.MealCutoffPolicy) error
+	Delete(id string) error
+}
+
+type cutoffPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewCutoffPolicyRepository creates a new cutoff policy repository
+func NewCutoffPolicyRepository(db *gorm.DB) CutoffPolicyRepository {
+	return &cutoffPolicyRepository{db: db}
+}
+
+func (r *cutoffPolicyRepository) FindDefaults() ([]models.MealCutoffPolicy, error) {
+	var policies []models.MealCutoffPolicy
+	if err := r.db.Where("day_schedule_id IS NULL").Order("meal_type ASC").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to find cutoff policies: %w", err)
+	}
+	return policies, nil
+}
+
+func (r *cutoffPolicyRepository) FindBySchedule(scheduleID string) ([]models.MealCutoffPolicy, error) {
+	var policies []models.MealCutoffPolicy
+	if err := r.db.Where("day_schedule_id = ?", scheduleID).Order("meal_type ASC").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to find cutoff overrides: %w", err)
+	}
+	return policies, nil
+}
+
+func (r *cutoffPolicyRepository) Find(scheduleID *string, mealType string) (*models.MealCutoffPolicy, error) {
+	query := r.db.Where("meal_type = ?", mealType)
+	if scheduleID == nil {
+		query = query.Where("day_schedule_id IS NULL")
+	} else {
+		query = query.Where("day_schedule_id = ?", *scheduleID)
+	}
+
+	var policy models.MealCutoffPolicy
+	if err := query.First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find cutoff policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// Save creates the policy, or updates it if it already has an ID
+func (r *cutoffPolicyRepository) Save(policy *models.MealCutoffPolicy) error {
+	if err := r.db.Save(policy).Error; err != nil {
+		return fmt.Errorf("failed to save cutoff policy: %w", err)
+	}
+	return nil
+}
+
+func (r *cutoffPolicyRepository) Delete(id string) error {
+	if err := r.db.Delete(&models.MealCutoffPolicy{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete cutoff policy: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// DiscordLinkRepository defines data access for Discord user links
+type DiscordLinkRepository interface {
+	Create(link *models.DiscordLink) error
+	FindByDiscordUserID(discordUserID string) (*models.DiscordLink, error)
+	FindByUserID(userID string) (*models.DiscordLink, error)
+	FindAll() ([]models.DiscordLink, error)
+	DeleteByDiscordUserID(discordUserID string) error
+}
+
+type discordLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewDiscordLinkRepository creates a new Discord link repository
+func NewDiscordLinkRepository(db *gorm.DB) DiscordLinkRepository {
+	return &discordLinkRepository{db: db}
+}
+
+// Create inserts a new Discord link
+func (r *discordLinkRepository) Create(link *models.DiscordLink) error {
+	if err := r.db.Create(link).Error; err != nil {
+		return fmt.Errorf("failed to create discord link: %w", err)
+	}
+	return nil
+}
+
+// FindByDiscordUserID returns the link for a Discord user with the user preloaded, or nil
+func (r *discordLinkRepository) FindByDiscordUserID(discordUserID string) (*models.DiscordLink, error) {
+	var link models.DiscordLink
+	err := r.db.Preload("User").Where("discord_user_id = ?", discordUserID).First(&link).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find discord link: %w", err)
+	}
+	return &link, nil
+}
+
+// FindByUserID returns the link for an internal user, or nil
+func (r *discordLinkRepository) FindByUserID(userID string) (*models.DiscordLink, error) {
+	var link models.DiscordLink
+	err := r.db.Where("user_id = ?", userID).First(&link).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find discord link: %w", err)
+	}
+	return &link, nil
+}
+
+// FindAll returns all Discord links with users preloaded
+func (r *discordLinkRepository) FindAll() ([]models.DiscordLink, error) {
+	var links []models.DiscordLink
+	if err := r.db.Preload("User").Order("created_at DESC").Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to list discord links: %w", err)
+	}
+	return links, nil
+}
+
+// DeleteByDiscordUserID removes the link for a Discord user
+func (r *discordLinkRepository) DeleteByDiscordUserID(discordUserID string) error {
+	result := r.db.Where("discord_user_id = ?", discordUserID).Delete(&models.DiscordLink{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete discord link: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("discord link not found")
+	}
+	return nil
+}
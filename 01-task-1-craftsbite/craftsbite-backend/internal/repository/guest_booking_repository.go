@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// GuestBookingRepository defines data access for guest meal bookings
+type GuestBookingRepository interface {
+	CreateChecked(booking *models.GuestBooking, check func(active []models.GuestBooking) error) error
+	FindByID(id string) (*models.GuestBooking, error)
+	FindByHost(hostUserID string) ([]models.GuestBooking, error)
+	FindActiveByDate(date string) ([]models.GuestBooking, error)
+	Deactivate(id string) error
+}
+
+type guestBookingRepository struct {
+	db *gorm.DB
+}
+
+// NewGuestBookingRepository creates a new guest booking repository
+func NewGuestBookingRepository(db *gorm.DB) GuestBookingRepository {
+	return &guestBookingRepository{db: db}
+}
+
+// CreateChecked inserts a booking if check accepts the date's active bookings. Bookings of
+// the same date are serialised with an advisory lock, so two bookings cannot both pass a
+// check against the same places. An error from check is returned as is.
+func (r *guestBookingRepository) CreateChecked(booking *models.GuestBooking, check func(active []models.GuestBooking) error) error {
+	var checkErr error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "guest_bookings:"+booking.Date).Error; err != nil {
+			return err
+		}
+		var active []models.GuestBooking
+		if err := tx.Where("date = ? AND is_active = ?", booking.Date, true).Find(&active).Error; err != nil {
+			return err
+		}
+		if checkErr = check(active); checkErr != nil {
+			return checkErr
+		}
+		return tx.Create(booking).Error
+	})
+	if checkErr != nil {
+		return checkErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create guest booking: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a booking, or nil
+func (r *guestBookingRepository) FindByID(id string) (*models.GuestBooking, error) {
+	var booking models.GuestBooking
+	if err := r.db.Where("id = ?", id).First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find guest booking: %w", err)
+	}
+	return &booking, nil
+}
+
+// FindByHost returns a host's bookings, most recent date first
+func (r *guestBookingRepository) FindByHost(hostUserID string) ([]models.GuestBooking, error) {
+	var bookings []models.GuestBooking
+	err := r.db.Where("host_user_id = ?", hostUserID).
+		Order("date DESC, created_at DESC").
+		Find(&bookings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find guest bookings: %w", err)
+	}
+	return bookings, nil
+}
+
+// FindActiveByDate returns the active bookings of a date with their hosts
+func (r *guestBookingRepository) FindActiveByDate(date string) ([]models.GuestBooking, error) {
+	var bookings []models.GuestBooking
+	err := r.db.Preload("Host").
+		Where("date = ? AND is_active = ?", date, true).
+		Order("created_at ASC").
+		Find(&bookings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find guest bookings: %w", err)
+	}
+	return bookings, nil
+}
+
+// Deactivate cancels a booking
+func (r *guestBookingRepository) Deactivate(id string) error {
+	if err := r.db.Model(&models.GuestBooking{}).
+		Where("id = ?", id).
+		Update("is_active", false).Error; err != nil {
+		return fmt.Errorf("failed to cancel guest booking: %w", err)
+	}
+	return nil
+}
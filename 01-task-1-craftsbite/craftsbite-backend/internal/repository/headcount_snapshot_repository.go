@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// HeadcountSnapshotRepository defines data access for frozen headcount snapshots
+type HeadcountSnapshotRepository interface {
+	Create(snapshot *models.HeadcountSnapshot) error
+	FindByDate(date string) ([]models.HeadcountSnapshot, error)
+	FindByDateAndMeal(date, mealType string) (*models.HeadcountSnapshot, error)
+	FindByDateWithDetails(date string) ([]models.HeadcountSnapshot, error)
+	FindByDateRangeForUsers(startDate, endDate string, userIDs []string) ([]models.HeadcountSnapshot, error)
+	FindByDateRangeWithTeams(startDate, endDate string) ([]models.HeadcountSnapshot, error)
+}
+
+type headcountSnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewHeadcountSnapshotRepository creates a new headcount snapshot repository
+func NewHeadcountSnapshotRepository(db *gorm.DB) HeadcountSnapshotRepository {
+	return &headcountSnapshotRepository{db: db}
+}
+
+// Create inserts a snapshot together with its team and user breakdowns in one transaction
+func (r *headcountSnapshotRepository) Create(snapshot *models.HeadcountSnapshot) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(snapshot).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create headcount snapshot: %w", err)
+	}
+	return nil
+}
+
+// FindByDate returns all snapshots for a date with team breakdowns preloaded
+func (r *headcountSnapshotRepository) FindByDate(date string) ([]models.HeadcountSnapshot, error) {
+	var snapshots []models.HeadcountSnapshot
+	err := r.db.Preload("Teams").Where("date = ?", date).Order("meal_type ASC").Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find headcount snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// FindByDateAndMeal returns a single snapshot with team and user breakdowns, or nil
+func (r *headcountSnapshotRepository) FindByDateAndMeal(date, mealType string) (*models.HeadcountSnapshot, error) {
+	var snapshot models.HeadcountSnapshot
+	err := r.db.Preload("Teams").Preload("Users").
+		Where("date = ? AND meal_type = ?", date, mealType).
+		First(&snapshot).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find headcount snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// FindByDateWithDetails returns all snapshots for a date with team and user breakdowns preloaded
+func (r *headcountSnapshotRepository) FindByDateWithDetails(date string) ([]models.HeadcountSnapshot, error) {
+	var snapshots []models.HeadcountSnapshot
+	err := r.db.Preload("Teams").Preload("Users").Where("date = ?", date).Order("meal_type ASC").Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find headcount snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// FindByDateRangeForUsers returns the snapshots within a date range with the user breakdown
+// limited to the given users
+func (r *headcountSnapshotRepository) FindByDateRangeForUsers(startDate, endDate string, userIDs []string) ([]models.HeadcountSnapshot, error) {
+	var snapshots []models.HeadcountSnapshot
+	err := r.db.Preload("Users", "user_id IN ?", userIDs).
+		Where("date BETWEEN ? AND ?", startDate, endDate).
+		Order("date ASC, meal_type ASC").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find headcount snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// FindByDateRangeWithTeams returns the snapshots within a date range with team breakdowns preloaded
+func (r *headcountSnapshotRepository) FindByDateRangeWithTeams(startDate, endDate string) ([]models.HeadcountSnapshot, error) {
+	var snapshots []models.HeadcountSnapshot
+	err := r.db.Preload("Teams").
+		Where("date BETWEEN ? AND ?", startDate, endDate).
+		Order("date ASC, meal_type ASC").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find headcount snapshots: %w", err)
+	}
+	return snapshots, nil
+}
@@ -13,6 +13,7 @@ type HistoryRepository interface {
 	Create(history *models.MealParticipationHistory) error
 	FindByUser(userID string, limit int) ([]models.MealParticipationHistory, error)
 	FindByUserAndDateRange(userID, startDate, endDate string) ([]models.MealParticipationHistory, error)
+	FindByDateRange(startDate, endDate string) ([]models.MealParticipationHistory, error)
 	DeleteOlderThan(months int) (int64, error)
 	FindAll(limit int) ([]models.MealParticipationHistory, error)
 }
@@ -66,6 +67,18 @@ func (r *historyRepository) FindByUserAndDateRange(userID, startDate, endDate st
 	return history, nil
 }
 
+// FindByDateRange finds the history records of every user for meal dates within a range, oldest first
+func (r *historyRepository) FindByDateRange(startDate, endDate string) ([]models.MealParticipationHistory, error) {
+	var history []models.MealParticipationHistory
+	err := r.db.Where("date BETWEEN ? AND ?", startDate, endDate).
+		Order("created_at ASC").
+		Find(&history).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find history records by date range: %w", err)
+	}
+	return history, nil
+}
+
 // DeleteOlderThan deletes history records older than the specified number of months
 func (r *historyRepository) DeleteOlderThan(months int) (int64, error) {
 	cutoffDate := time.Now().AddDate(0, -months, 0)
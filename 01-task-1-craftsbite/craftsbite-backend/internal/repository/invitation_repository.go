@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvitationRepository defines data access for invitations
+type InvitationRepository interface {
+	Create(invitation *models.Invitation) error
+	FindByID(id string) (*models.Invitation, error)
+	FindByTokenHash(tokenHash string) (*models.Invitation, error)
+	FindPendingByEmail(email string, now time.Time) (*models.Invitation, error)
+	FindAll(status string, now time.Time) ([]models.Invitation, error)
+	Update(invitation *models.Invitation) error
+	Accept(invitation *models.Invitation, user *models.User, now time.Time) (bool, error)
+}
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+// NewInvitationRepository creates a new invitation repository
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+// Create stores a new invitation
+func (r *invitationRepository) Create(invitation *models.Invitation) error {
+	if err := r.db.Omit("Team").Create(invitation).Error; err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns an invitation with its team, or nil
+func (r *invitationRepository) FindByID(id string) (*models.Invitation, error) {
+	return r.findOne("id = ?", id)
+}
+
+// FindByTokenHash returns the invitation a token belongs to, or nil
+func (r *invitationRepository) FindByTokenHash(tokenHash string) (*models.Invitation, error) {
+	return r.findOne("token_hash = ?", tokenHash)
+}
+
+// FindPendingByEmail returns the invitation still open for an email address, or nil
+func (r *invitationRepository) FindPendingByEmail(email string, now time.Time) (*models.Invitation, error) {
+	return r.findOne("LOWER(email) = LOWER(?) AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", email, now)
+}
+
+func (r *invitationRepository) findOne(query string, args ...interface{}) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.db.Preload("Team").Where(query, args...).First(&invitation).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+	return &invitation, nil
+}
+
+// FindAll returns invitations newest first, optionally only those with a status at now
+func (r *invitationRepository) FindAll(status string, now time.Time) ([]models.Invitation, error) {
+	query := r.db.Preload("Team")
+	switch status {
+	case models.InvitationPending:
+		query = query.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", now)
+	case models.InvitationAccepted:
+		query = query.Where("accepted_at IS NOT NULL")
+	case models.InvitationRevoked:
+		query = query.Where("accepted_at IS NULL AND revoked_at IS NOT NULL")
+	case models.InvitationExpired:
+		query = query.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at <= ?", now)
+	}
+
+	var invitations []models.Invitation
+	if err := query.Order("created_at DESC").Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// Update saves changes to an invitation
+func (r *invitationRepository) Update(invitation *models.Invitation) error {
+	if err := r.db.Omit("Team").Save(invitation).Error; err != nil {
+		return fmt.Errorf("failed to update invitation: %w", err)
+	}
+	return nil
+}
+
+// Accept marks a pending invitation accepted and creates its user, adding them to the
+// invited team, in one transaction. It returns false without creating anything when the
+// invitation was accepted, revoked or expired in the meantime.
+func (r *invitationRepository) Accept(invitation *models.Invitation, user *models.User, now time.Time) (bool, error) {
+	accepted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Teams").Create(user).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Invitation{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", invitation.ID, now).
+			Updates(map[string]interface{}{"accepted_at": now, "user_id": user.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Rolls back the user created above
+			return errInvitationTaken
+		}
+
+		if invitation.TeamID != nil {
+			member := &models.TeamMember{TeamID: *invitation.TeamID, UserID: user.ID}
+			if err := tx.Create(member).Error; err != nil {
+				return err
+			}
+		}
+		accepted = true
+		return nil
+	})
+	if err == errInvitationTaken {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	invitation.AcceptedAt = &now
+	invitation.UserID = &user.ID
+	return accepted, nil
+}
+
+// errInvitationTaken aborts the accept transaction when the invitation is no longer pending
+var errInvitationTaken = fmt.Errorf("invitation is no longer pending")
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// LateChangeRepository defines data access for post-cutoff change requests
+type LateChangeRepository interface {
+	Create(request *models.LateChangeRequest) error
+	FindByID(id string) (*models.LateChangeRequest, error)
+	FindByUserID(userID string) ([]models.LateChangeRequest, error)
+	FindPending(userIDs []string) ([]models.LateChangeRequest, error)
+	FindPendingByUserDateMeal(userID, date, mealType string) (*models.LateChangeRequest, error)
+	UpdateIfStatus(request *models.LateChangeRequest, from models.LateChangeStatus) (bool, error)
+}
+
+type lateChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewLateChangeRepository creates a new late change request repository
+func NewLateChangeRepository(db *gorm.DB) LateChangeRepository {
+	return &lateChangeRepository{db: db}
+}
+
+// Create inserts a new request
+func (r *lateChangeRepository) Create(request *models.LateChangeRequest) error {
+	if err := r.db.Create(request).Error; err != nil {
+		return fmt.Errorf("failed to create late change request: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a request with its requester and reviewer, or nil
+func (r *lateChangeRepository) FindByID(id string) (*models.LateChangeRequest, error) {
+	var request models.LateChangeRequest
+	err := r.db.Preload("User").Preload("Reviewer").Where("id = ?", id).First(&request).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find late change request: %w", err)
+	}
+	return &request, nil
+}
+
+// FindByUserID returns a user's requests, newest first
+func (r *lateChangeRepository) FindByUserID(userID string) ([]models.LateChangeRequest, error) {
+	var requests []models.LateChangeRequest
+	err := r.db.Preload("Reviewer").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&requests).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find late change requests: %w", err)
+	}
+	return requests, nil
+}
+
+// FindPending returns pending requests, oldest first. A nil userIDs returns every user's requests.
+func (r *lateChangeRepository) FindPending(userIDs []string) ([]models.LateChangeRequest, error) {
+	if userIDs != nil && len(userIDs) == 0 {
+		return []models.LateChangeRequest{}, nil
+	}
+
+	query := r.db.Preload("User").Where("status = ?", models.LateChangeStatusPending)
+	if userIDs != nil {
+		query = query.Where("user_id IN ?", userIDs)
+	}
+
+	var requests []models.LateChangeRequest
+	if err := query.Order("date ASC, created_at ASC").Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("failed to find pending late change requests: %w", err)
+	}
+	return requests, nil
+}
+
+// FindPendingByUserDateMeal finds the pending request for a user, date and meal, or nil
+func (r *lateChangeRepository) FindPendingByUserDateMeal(userID, date, mealType string) (*models.LateChangeRequest, error) {
+	var request models.LateChangeRequest
+	err := r.db.Where("user_id = ? AND date = ? AND meal_type = ? AND status = ?",
+		userID, date, mealType, models.LateChangeStatusPending).
+		First(&request).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find pending late change request: %w", err)
+	}
+	return &request, nil
+}
+
+// UpdateIfStatus saves a request's review fields only while its status is still from, and
+// reports whether it was, so concurrent reviews and cancellations cannot both act on a request
+func (r *lateChangeRepository) UpdateIfStatus(request *models.LateChangeRequest, from models.LateChangeStatus) (bool, error) {
+	result := r.db.Model(&models.LateChangeRequest{}).
+		Where("id = ? AND status = ?", request.ID, from).
+		Updates(map[string]interface{}{
+			"status":      request.Status,
+			"reviewed_by": request.ReviewedBy,
+			"reviewed_at": request.ReviewedAt,
+			"review_note": request.ReviewNote,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update late change request: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
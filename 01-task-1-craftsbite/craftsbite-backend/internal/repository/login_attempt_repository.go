@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptRepository defines data access for failed login counters
+type LoginAttemptRepository interface {
+	Find(subjectType, subject string) (*models.LoginAttempt, error)
+	FindByID(id string) (*models.LoginAttempt, error)
+	FindLocked(now time.Time) ([]models.LoginAttempt, error)
+	RecordFailure(subjectType, subject string, now, windowStart time.Time) (*models.LoginAttempt, error)
+	Lock(id string, until time.Time) error
+	Delete(subjectType, subject string) error
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository creates a new login attempt repository
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+// Find returns the counter of a subject, or nil
+func (r *loginAttemptRepository) Find(subjectType, subject string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	err := r.db.Where("subject_type = ? AND subject = ?", subjectType, subject).First(&attempt).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find login attempts: %w", err)
+	}
+	return &attempt, nil
+}
+
+// FindByID returns a counter by ID, or nil
+func (r *loginAttemptRepository) FindByID(id string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	err := r.db.Where("id = ?", id).First(&attempt).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find login attempts: %w", err)
+	}
+	return &attempt, nil
+}
+
+// FindLocked returns the subjects locked at now, longest lockout first
+func (r *loginAttemptRepository) FindLocked(now time.Time) ([]models.LoginAttempt, error) {
+	var attempts []models.LoginAttempt
+	if err := r.db.Where("locked_until > ?", now).Order("locked_until DESC").Find(&attempts).Error; err != nil {
+		return nil, fmt.Errorf("failed to find lockouts: %w", err)
+	}
+	return attempts, nil
+}
+
+// RecordFailure atomically counts a failed login and returns the updated counter. The count
+// restarts at one when the previous failure happened before windowStart.
+func (r *loginAttemptRepository) RecordFailure(subjectType, subject string, now, windowStart time.Time) (*models.LoginAttempt, error) {
+	attempt := models.LoginAttempt{
+		SubjectType:   subjectType,
+		Subject:       subject,
+		Failures:      1,
+		LastFailureAt: now,
+	}
+	err := r.db.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "subject_type"}, {Name: "subject"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failures":        gorm.Expr("CASE WHEN login_attempts.last_failure_at < ? THEN 1 ELSE login_attempts.failures + 1 END", windowStart),
+				"last_failure_at": now,
+				"updated_at":      now,
+			}),
+		},
+		clause.Returning{},
+	).Create(&attempt).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to record failed login: %w", err)
+	}
+	return &attempt, nil
+}
+
+// Lock refuses logins for a subject until the given time
+func (r *loginAttemptRepository) Lock(id string, until time.Time) error {
+	if err := r.db.Model(&models.LoginAttempt{}).Where("id = ?", id).Update("locked_until", until).Error; err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+	return nil
+}
+
+// Delete clears the counter and any lockout of a subject
+func (r *loginAttemptRepository) Delete(subjectType, subject string) error {
+	if err := r.db.Where("subject_type = ? AND subject = ?", subjectType, subject).Delete(&models.LoginAttempt{}).Error; err != nil {
+		return fmt.Errorf("failed to clear login attempts: %w", err)
+	}
+	return nil
+}
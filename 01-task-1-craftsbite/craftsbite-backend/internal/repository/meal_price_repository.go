@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// MealPriceRepository defines data access for the meal price list
+type MealPriceRepository interface {
+	Create(price *models.MealPrice) error
+	FindByID(id string) (*models.MealPrice, error)
+	FindAll() ([]models.MealPrice, error)
+	FindByMealAndDate(mealType, effectiveFrom string) (*models.MealPrice, error)
+	Delete(id string) error
+}
+
+type mealPriceRepository struct {
+	db *gorm.DB
+}
+
+// NewMealPriceRepository creates a new meal price repository
+func NewMealPriceRepository(db *gorm.DB) MealPriceRepository {
+	return &mealPriceRepository{db: db}
+}
+
+// Create inserts a price list entry
+func (r *mealPriceRepository) Create(price *models.MealPrice) error {
+	if err := r.db.Create(price).Error; err != nil {
+		return fmt.Errorf("failed to create meal price: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a price list entry, or nil
+func (r *mealPriceRepository) FindByID(id string) (*models.MealPrice, error) {
+	var price models.MealPrice
+	if err := r.db.Where("id = ?", id).First(&price).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find meal price: %w", err)
+	}
+	return &price, nil
+}
+
+// FindAll returns the whole price list ordered by meal type and effective date
+func (r *mealPriceRepository) FindAll() ([]models.MealPrice, error) {
+	var prices []models.MealPrice
+	if err := r.db.Order("meal_type ASC, effective_from ASC").Find(&prices).Error; err != nil {
+		return nil, fmt.Errorf("failed to find meal prices: %w", err)
+	}
+	return prices, nil
+}
+
+// FindByMealAndDate finds the entry of a meal type taking effect on a date, or nil
+func (r *mealPriceRepository) FindByMealAndDate(mealType, effectiveFrom string) (*models.MealPrice, error) {
+	var price models.MealPrice
+	err := r.db.Where("meal_type = ? AND effective_from = ?", mealType, effectiveFrom).First(&price).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find meal price: %w", err)
+	}
+	return &price, nil
+}
+
+// Delete removes a price list entry
+func (r *mealPriceRepository) Delete(id string) error {
+	if err := r.db.Delete(&models.MealPrice{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete meal price: %w", err)
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ type MealRepository interface {
 	FindByUserDateMeal(userID, date, mealType string) (*models.MealParticipation, error)
 	FindByDate(date string) ([]models.MealParticipation, error)
 	FindByDateAndMeal(date, mealType string) ([]models.MealParticipation, error)
+	FindByUsersAndDates(userIDs, dates []string) ([]models.MealParticipation, error)
 }
 
 // mealRepository implements MealRepository
@@ -103,3 +104,16 @@ func (r *mealRepository) FindByDateAndMeal(date, mealType string) ([]models.Meal
 	}
 	return participations, nil
 }
+
+// FindByUsersAndDates finds all participations for the given users on the given dates
+func (r *mealRepository) FindByUsersAndDates(userIDs, dates []string) ([]models.MealParticipation, error) {
+	if len(userIDs) == 0 || len(dates) == 0 {
+		return []models.MealParticipation{}, nil
+	}
+	var participations []models.MealParticipation
+	err := r.db.Where("user_id IN ? AND date IN ?", userIDs, dates).Find(&participations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find meal participations by users and dates: %w", err)
+	}
+	return participations, nil
+}
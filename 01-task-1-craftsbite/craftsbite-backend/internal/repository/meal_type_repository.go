@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// MealTypeRepository defines data access for the meal type catalogue
+type MealTypeRepository interface {
+	Create(mealType *models.MealTypeDefinition) error
+	FindByCode(code string) (*models.MealTypeDefinition, error)
+	// FindAll returns every meal type, active or not, in display order
+	FindAll() ([]models.MealTypeDefinition, error)
+	Update(mealType *models.MealTypeDefinition) error
+}
+
+type mealTypeRepository struct {
+	db *gorm.DB
+}
+
+// NewMealTypeRepository creates a new meal type repository
+func NewMealTypeRepository(db *gorm.DB) MealTypeRepository {
+	return &mealTypeRepository{db: db}
+}
+
+func (r *mealTypeRepository) Create(mealType *models.MealTypeDefinition) error {
+	if err := r.db.Create(mealType).Error; err != nil {
+		return fmt.Errorf("failed to create meal type: %w", err)
+	}
+	return nil
+}
+
+func (r *mealTypeRepository) FindByCode(code string) (*models.MealTypeDefinition, error) {
+	var mealType models.MealTypeDefinition
+	if err := r.db.Where("code = ?", code).First(&mealType).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find meal type: %w", err)
+	}
+	return &mealType, nil
+}
+
+func (r *mealTypeRepository) FindAll() ([]models.MealTypeDefinition, error) {
+	var mealTypes []models.MealTypeDefinition
+	if err := r.db.Order("sort_order ASC, code ASC").Find(&mealTypes).Error; err != nil {
+		return nil, fmt.Errorf("failed to find meal types: %w", err)
+	}
+	return mealTypes, nil
+}
+
+func (r *mealTypeRepository) Update(mealType *models.MealTypeDefinition) error {
+	if err := r.db.Save(mealType).Error; err != nil {
+		return fmt.Errorf("failed to update meal type: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MenuRepository defines data access for menus and their dishes
+type MenuRepository interface {
+	// Save creates or updates a menu and replaces its dishes with menu.Dishes
+	Save(menu *models.Menu) error
+	FindByID(id string) (*models.Menu, error)
+	FindByDateAndMeal(date, mealType string) (*models.Menu, error)
+	// FindByDateRange returns menus with dishes ordered by date and meal type
+	FindByDateRange(startDate, endDate string, publishedOnly bool) ([]models.Menu, error)
+	Delete(id string) error
+}
+
+type menuRepository struct {
+	db *gorm.DB
+}
+
+// NewMenuRepository creates a new menu repository
+func NewMenuRepository(db *gorm.DB) MenuRepository {
+	return &menuRepository{db: db}
+}
+
+func (r *menuRepository) Save(menu *models.Menu) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		dishes := menu.Dishes
+		if err := tx.Omit("Dishes").Save(menu).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("menu_id = ?", menu.ID).Delete(&models.MenuDish{}).Error; err != nil {
+			return err
+		}
+		for i := range dishes {
+			dishes[i].ID = uuid.Nil
+			dishes[i].MenuID = menu.ID
+			dishes[i].Position = i + 1
+		}
+		if len(dishes) > 0 {
+			if err := tx.Create(&dishes).Error; err != nil {
+				return err
+			}
+		}
+		menu.Dishes = dishes
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save menu: %w", err)
+	}
+	return nil
+}
+
+func (r *menuRepository) FindByID(id string) (*models.Menu, error) {
+	var menu models.Menu
+	if err := r.withDishes().Where("id = ?", id).First(&menu).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find menu: %w", err)
+	}
+	return &menu, nil
+}
+
+func (r *menuRepository) FindByDateAndMeal(date, mealType string) (*models.Menu, error) {
+	var menu models.Menu
+	if err := r.withDishes().Where("date = ? AND meal_type = ?", date, mealType).First(&menu).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find menu: %w", err)
+	}
+	return &menu, nil
+}
+
+func (r *menuRepository) FindByDateRange(startDate, endDate string, publishedOnly bool) ([]models.Menu, error) {
+	query := r.withDishes().Where("date >= ? AND date <= ?", startDate, endDate)
+	if publishedOnly {
+		query = query.Where("is_published")
+	}
+
+	var menus []models.Menu
+	if err := query.Order("date ASC, meal_type ASC").Find(&menus).Error; err != nil {
+		return nil, fmt.Errorf("failed to find menus: %w", err)
+	}
+	return menus, nil
+}
+
+func (r *menuRepository) Delete(id string) error {
+	if err := r.db.Delete(&models.Menu{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete menu: %w", err)
+	}
+	return nil
+}
+
+func (r *menuRepository) withDishes() *gorm.DB {
+	return r.db.Preload("Dishes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	})
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepository defines data access for in-app notifications
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	// FindByUser returns a user's most recent notifications first
+	FindByUser(userID string, unreadOnly bool, limit int) ([]models.Notification, error)
+	// MarkRead marks one of a user's notifications as read, reporting whether it exists
+	MarkRead(userID, id string) (bool, error)
+	MarkAllRead(userID string) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(notification *models.Notification) error {
+	if err := r.db.Create(notification).Error; err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepository) FindByUser(userID string, unreadOnly bool, limit int) ([]models.Notification, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	if err := query.Order("created_at DESC").Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("failed to find notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) MarkRead(userID, id string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Notification{}).Where("id = ? AND user_id = ?", id, userID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to find notification: %w", err)
+	}
+	if count == 0 {
+		return false, nil
+	}
+
+	err := r.db.Model(&models.Notification{}).
+		Where("id = ? AND read_at IS NULL", id).
+		Update("read_at", time.Now()).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	return true, nil
+}
+
+func (r *notificationRepository) MarkAllRead(userID string) error {
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return nil
+}
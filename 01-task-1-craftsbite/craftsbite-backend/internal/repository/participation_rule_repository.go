@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ParticipationRuleRepository defines data access for recurring personal participation rules
+type ParticipationRuleRepository interface {
+	Create(rule *models.ParticipationRule) error
+	FindByID(id string) (*models.ParticipationRule, error)
+	FindByUser(userID string) ([]models.ParticipationRule, error)
+	FindByUsersAndDateRange(userIDs []string, startDate, endDate string) ([]models.ParticipationRule, error)
+	Update(rule *models.ParticipationRule) error
+	Delete(id string) error
+}
+
+type participationRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewParticipationRuleRepository creates a new participation rule repository
+func NewParticipationRuleRepository(db *gorm.DB) ParticipationRuleRepository {
+	return &participationRuleRepository{db: db}
+}
+
+// Create inserts a rule
+func (r *participationRuleRepository) Create(rule *models.ParticipationRule) error {
+	if err := r.db.Create(rule).Error; err != nil {
+		return fmt.Errorf("failed to create participation rule: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a rule, or nil
+func (r *participationRuleRepository) FindByID(id string) (*models.ParticipationRule, error) {
+	var rule models.ParticipationRule
+	if err := r.db.Where("id = ?", id).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find participation rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// FindByUser returns all of a user's rules
+func (r *participationRuleRepository) FindByUser(userID string) ([]models.ParticipationRule, error) {
+	var rules []models.ParticipationRule
+	err := r.db.Where("user_id = ?", userID).
+		Order("meal_type ASC, created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find participation rules: %w", err)
+	}
+	return rules, nil
+}
+
+// FindByUsersAndDateRange returns the rules of the given users that are in effect at some point in a date range
+func (r *participationRuleRepository) FindByUsersAndDateRange(userIDs []string, startDate, endDate string) ([]models.ParticipationRule, error) {
+	if len(userIDs) == 0 {
+		return []models.ParticipationRule{}, nil
+	}
+	var rules []models.ParticipationRule
+	err := r.db.Where("user_id IN ? AND (start_date IS NULL OR start_date <= ?) AND (end_date IS NULL OR end_date >= ?)",
+		userIDs, endDate, startDate).
+		Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find participation rules by users: %w", err)
+	}
+	return rules, nil
+}
+
+// Update saves a rule
+func (r *participationRuleRepository) Update(rule *models.ParticipationRule) error {
+	if err := r.db.Save(rule).Error; err != nil {
+		return fmt.Errorf("failed to update participation rule: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a rule
+func (r *participationRuleRepository) Delete(id string) error {
+	if err := r.db.Delete(&models.ParticipationRule{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete participation rule: %w", err)
+	}
+	return nil
+}
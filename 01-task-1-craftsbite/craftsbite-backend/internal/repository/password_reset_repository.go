@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PasswordResetRepository defines data access for password reset tokens
+type PasswordResetRepository interface {
+	Create(token *models.PasswordResetToken) error
+	FindByTokenHash(tokenHash string) (*models.PasswordResetToken, error)
+	FindLatestByUserID(userID string) (*models.PasswordResetToken, error)
+	Use(id string, now time.Time) (bool, error)
+	InvalidateForUser(userID string, now time.Time) error
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetRepository creates a new password reset repository
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create stores a new reset token
+func (r *passwordResetRepository) Create(token *models.PasswordResetToken) error {
+	if err := r.db.Create(token).Error; err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+	return nil
+}
+
+// FindByTokenHash returns the reset token with a hash, or nil
+func (r *passwordResetRepository) FindByTokenHash(tokenHash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find password reset token: %w", err)
+	}
+	return &token, nil
+}
+
+// FindLatestByUserID returns the user's most recently issued reset token, or nil
+func (r *passwordResetRepository) FindLatestByUserID(userID string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").First(&token).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find password reset token: %w", err)
+	}
+	return &token, nil
+}
+
+// Use marks a token used if it is still valid at now. It returns false when the token was
+// already used, superseded or expired, so a token cannot be used twice concurrently.
+func (r *passwordResetRepository) Use(id string, now time.Time) (bool, error) {
+	result := r.db.Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to use password reset token: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateForUser marks every unused reset token of a user as used
+func (r *passwordResetRepository) InvalidateForUser(userID string, now time.Time) error {
+	err := r.db.Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", now).Error
+	if err != nil {
+		return fmt.Errorf("failed to invalidate password reset tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// RoleRepository defines data access for roles and their permission grants
+type RoleRepository interface {
+	FindAll() ([]models.RoleDefinition, error)
+	FindByName(name string) (*models.RoleDefinition, error)
+	Create(role *models.RoleDefinition) error
+	Update(role *models.RoleDefinition) error
+	Delete(name string) error
+	CountUsers(name string) (int64, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// FindAll returns every role with its permissions
+func (r *roleRepository) FindAll() ([]models.RoleDefinition, error) {
+	var roles []models.RoleDefinition
+	if err := r.db.Preload("Permissions").Order("name ASC").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to find roles: %w", err)
+	}
+	return roles, nil
+}
+
+// FindByName returns a role with its permissions, or nil
+func (r *roleRepository) FindByName(name string) (*models.RoleDefinition, error) {
+	var role models.RoleDefinition
+	err := r.db.Preload("Permissions").Where("name = ?", name).First(&role).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	return &role, nil
+}
+
+// Create inserts a role and its permissions
+func (r *roleRepository) Create(role *models.RoleDefinition) error {
+	if err := r.db.Create(role).Error; err != nil {
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+	return nil
+}
+
+// Update saves a role's description and replaces its permissions in one transaction
+func (r *roleRepository) Update(role *models.RoleDefinition) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RoleDefinition{}).Where("name = ?", role.Name).
+			Update("description", role.Description).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_name = ?", role.Name).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		for i := range role.Permissions {
+			role.Permissions[i].RoleName = role.Name
+		}
+		if len(role.Permissions) > 0 {
+			return tx.Create(&role.Permissions).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a role; its permissions are removed by cascade
+func (r *roleRepository) Delete(name string) error {
+	if err := r.db.Where("name = ?", name).Delete(&models.RoleDefinition{}).Error; err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	return nil
+}
+
+// CountUsers returns how many users hold a role
+func (r *roleRepository) CountUsers(name string) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.User{}).Where("role = ?", name).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count users with role: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ScheduleRuleRepository defines the interface for recurring schedule rule data access
+type ScheduleRuleRepository interface {
+	Create(rule *models.ScheduleRule) error
+	FindByID(id string) (*models.ScheduleRule, error)
+	FindBySourceUID(uid string) (*models.ScheduleRule, error)
+	FindAll() ([]models.ScheduleRule, error)
+	FindActiveInRange(startDate, endDate string) ([]models.ScheduleRule, error)
+	Update(rule *models.ScheduleRule) error
+	Delete(id string) error
+}
+
+type scheduleRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduleRuleRepository creates a new schedule rule repository
+func NewScheduleRuleRepository(db *gorm.DB) ScheduleRuleRepository {
+	return &scheduleRuleRepository{db: db}
+}
+
+// Create creates a schedule rule
+func (r *scheduleRuleRepository) Create(rule *models.ScheduleRule) error {
+	if err := r.db.Create(rule).Error; err != nil {
+		return fmt.Errorf("failed to create schedule rule: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a schedule rule by ID, or nil
+func (r *scheduleRuleRepository) FindByID(id string) (*models.ScheduleRule, error) {
+	var rule models.ScheduleRule
+	err := r.db.Where("id = ?", id).First(&rule).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schedule rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// FindBySourceUID finds the rule imported from an iCalendar event, or nil
+func (r *scheduleRuleRepository) FindBySourceUID(uid string) (*models.ScheduleRule, error) {
+	var rule models.ScheduleRule
+	err := r.db.Where("source_uid = ?", uid).First(&rule).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schedule rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// FindAll returns every schedule rule, highest priority first
+func (r *scheduleRuleRepository) FindAll() ([]models.ScheduleRule, error) {
+	var rules []models.ScheduleRule
+	if err := r.db.Order("priority DESC, created_at DESC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to find schedule rules: %w", err)
+	}
+	return rules, nil
+}
+
+// FindActiveInRange returns active rules whose date span overlaps the range, highest priority first
+func (r *scheduleRuleRepository) FindActiveInRange(startDate, endDate string) ([]models.ScheduleRule, error) {
+	var rules []models.ScheduleRule
+	err := r.db.Where("active = ? AND start_date <= ? AND (end_date IS NULL OR end_date >= ?)", true, endDate, startDate).
+		Order("priority DESC, created_at DESC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schedule rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Update saves a schedule rule
+func (r *scheduleRuleRepository) Update(rule *models.ScheduleRule) error {
+	if err := r.db.Save(rule).Error; err != nil {
+		return fmt.Errorf("failed to update schedule rule: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a schedule rule by ID
+func (r *scheduleRuleRepository) Delete(id string) error {
+	if err := r.db.Delete(&models.ScheduleRule{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete schedule rule: %w", err)
+	}
+	return nil
+}
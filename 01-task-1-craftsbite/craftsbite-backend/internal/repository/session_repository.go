@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SessionRepository defines data access for login sessions
+type SessionRepository interface {
+	Create(session *models.Session) error
+	FindByID(id string) (*models.Session, error)
+	FindByRefreshTokenHash(hash string) (*models.Session, error)
+	FindByPreviousTokenHash(hash string) (*models.Session, error)
+	FindActiveByUserID(userID string) ([]models.Session, error)
+	Rotate(id, oldHash, newHash string) (bool, error)
+	Revoke(id string, reason models.SessionRevokeReason) error
+	RevokeAllByUserID(userID string, reason models.SessionRevokeReason, exceptID string) (int64, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create inserts a new session
+func (r *sessionRepository) Create(session *models.Session) error {
+	if err := r.db.Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns a session by ID, or nil
+func (r *sessionRepository) FindByID(id string) (*models.Session, error) {
+	var session models.Session
+	err := r.db.Where("id = ?", id).First(&session).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+	return &session, nil
+}
+
+// FindByRefreshTokenHash returns the session whose current refresh token matches, with the user preloaded, or nil
+func (r *sessionRepository) FindByRefreshTokenHash(hash string) (*models.Session, error) {
+	var session models.Session
+	err := r.db.Preload("User").Where("refresh_token_hash = ?", hash).First(&session).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+	return &session, nil
+}
+
+// FindByPreviousTokenHash returns the session whose already-rotated refresh token matches, or nil
+func (r *sessionRepository) FindByPreviousTokenHash(hash string) (*models.Session, error) {
+	var session models.Session
+	err := r.db.Where("previous_token_hash = ?", hash).First(&session).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+	return &session, nil
+}
+
+// FindActiveByUserID returns the unrevoked, unexpired sessions of a user, most recently used first
+func (r *sessionRepository) FindActiveByUserID(userID string) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// Rotate swaps the refresh token of an active session. It only succeeds if oldHash is still
+// the current token, so two concurrent refreshes with the same token cannot both win.
+func (r *sessionRepository) Rotate(id, oldHash, newHash string) (bool, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", id, oldHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  newHash,
+			"previous_token_hash": oldHash,
+			"last_used_at":        time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to rotate session: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Revoke marks a single session as revoked
+func (r *sessionRepository) Revoke(id string, reason models.SessionRevokeReason) error {
+	err := r.db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllByUserID revokes every active session of a user, optionally keeping exceptID
+func (r *sessionRepository) RevokeAllByUserID(userID string, reason models.SessionRevokeReason, exceptID string) (int64, error) {
+	query := r.db.Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptID != "" {
+		query = query.Where("id <> ?", exceptID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"revoked_at":     time.Now(),
+		"revoked_reason": reason,
+	})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
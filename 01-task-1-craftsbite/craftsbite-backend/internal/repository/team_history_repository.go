@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// TeamHistoryRepository defines data access for the team audit history
+type TeamHistoryRepository interface {
+	Create(history *models.TeamHistory) error
+	FindByTeamID(teamID string) ([]models.TeamHistory, error)
+}
+
+type teamHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewTeamHistoryRepository creates a new team history repository
+func NewTeamHistoryRepository(db *gorm.DB) TeamHistoryRepository {
+	return &teamHistoryRepository{db: db}
+}
+
+// Create inserts a team history record
+func (r *teamHistoryRepository) Create(history *models.TeamHistory) error {
+	if err := r.db.Create(history).Error; err != nil {
+		return fmt.Errorf("failed to create team history record: %w", err)
+	}
+	return nil
+}
+
+// FindByTeamID returns the history of a team, newest first, with actor and subject preloaded
+func (r *teamHistoryRepository) FindByTeamID(teamID string) ([]models.TeamHistory, error) {
+	var history []models.TeamHistory
+	err := r.db.Where("team_id = ?", teamID).
+		Preload("Actor").
+		Preload("Subject").
+		Order("created_at DESC").
+		Find(&history).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find team history: %w", err)
+	}
+	return history, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TeamRepository defines the interface for team data access
@@ -16,6 +17,9 @@ type TeamRepository interface {
 	Update(team *models.Team) error
 	Delete(id string) error
 	FindAll() ([]models.Team, error)
+	FindAllIncludingInactive() ([]models.Team, error)
+	FindActiveByName(name string) (*models.Team, error)
+	FindMembershipByUserID(userID string) (*models.TeamMember, error)
 	AddMember(teamID, userID string) error
 	RemoveMember(teamID, userID string) error
 	GetTeamMembers(teamID string) ([]models.User, error)
@@ -64,9 +68,9 @@ func (r *teamRepository) FindByTeamLeadID(teamLeadID string) ([]models.Team, err
 	return teams, nil
 }
 
-// Update updates a team
+// Update updates a team's own columns; the team lead and members are left untouched
 func (r *teamRepository) Update(team *models.Team) error {
-	if err := r.db.Save(team).Error; err != nil {
+	if err := r.db.Omit(clause.Associations).Save(team).Error; err != nil {
 		return fmt.Errorf("failed to update team: %w", err)
 	}
 	return nil
@@ -89,6 +93,41 @@ func (r *teamRepository) FindAll() ([]models.Team, error) {
 	return teams, nil
 }
 
+// FindAllIncludingInactive finds all teams, active or not, with team lead and members preloaded
+func (r *teamRepository) FindAllIncludingInactive() ([]models.Team, error) {
+	var teams []models.Team
+	if err := r.db.Preload("TeamLead").Preload("Members").Order("name ASC").Find(&teams).Error; err != nil {
+		return nil, fmt.Errorf("failed to find teams: %w", err)
+	}
+	return teams, nil
+}
+
+// FindActiveByName finds an active team by case-insensitive name, or nil
+func (r *teamRepository) FindActiveByName(name string) (*models.Team, error) {
+	var team models.Team
+	err := r.db.Where("LOWER(name) = LOWER(?) AND active = ?", name, true).First(&team).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find team: %w", err)
+	}
+	return &team, nil
+}
+
+// FindMembershipByUserID finds the team membership of a user, active team or not, or nil
+func (r *teamRepository) FindMembershipByUserID(userID string) (*models.TeamMember, error) {
+	var member models.TeamMember
+	err := r.db.Preload("Team").Where("user_id = ?", userID).First(&member).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find team membership: %w", err)
+	}
+	return &member, nil
+}
+
 // AddMember adds a user to a team
 func (r *teamRepository) AddMember(teamID, userID string) error {
 	teamUUID, err := uuid.Parse(teamID)
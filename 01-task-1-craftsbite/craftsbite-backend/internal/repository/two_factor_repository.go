@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TwoFactorRepository defines data access for TOTP enrolments, recovery codes and the login
+// challenges that ask for them
+type TwoFactorRepository interface {
+	Find(userID string) (*models.UserTwoFactor, error)
+	Save(twoFactor *models.UserTwoFactor) error
+	UseStep(userID string, step int64) (bool, error)
+	Delete(userID string) error
+
+	ReplaceRecoveryCodes(userID string, codeHashes []string) error
+	UseRecoveryCode(userID, codeHash string, now time.Time) (bool, error)
+	CountRecoveryCodes(userID string) (int64, error)
+
+	CreateChallenge(challenge *models.LoginChallenge) error
+	FindChallenge(tokenHash string) (*models.LoginChallenge, error)
+	CountChallengeAttempt(id string) (int, error)
+	DeleteChallenge(id string) error
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository creates a new two-factor repository
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+// Find returns a user's enrolment, or nil
+func (r *twoFactorRepository) Find(userID string) (*models.UserTwoFactor, error) {
+	var twoFactor models.UserTwoFactor
+	err := r.db.Where("user_id = ?", userID).First(&twoFactor).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find two-factor enrolment: %w", err)
+	}
+	return &twoFactor, nil
+}
+
+// Save creates or replaces a user's enrolment
+func (r *twoFactorRepository) Save(twoFactor *models.UserTwoFactor) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled_at", "last_used_step", "updated_at"}),
+	}).Create(twoFactor).Error
+	if err != nil {
+		return fmt.Errorf("failed to save two-factor enrolment: %w", err)
+	}
+	return nil
+}
+
+// UseStep records the time step of an accepted code. It returns false when that step or a
+// later one was already used, which means the code is being replayed.
+func (r *twoFactorRepository) UseStep(userID string, step int64) (bool, error) {
+	result := r.db.Model(&models.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record two-factor code: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Delete removes a user's enrolment, recovery codes and pending challenges
+func (r *twoFactorRepository) Delete(userID string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.LoginChallenge{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserTwoFactor{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete two-factor enrolment: %w", err)
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes swaps a user's recovery codes for a new set
+func (r *twoFactorRepository) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]models.TwoFactorRecoveryCode, len(codeHashes))
+		for i, hash := range codeHashes {
+			codes[i] = models.TwoFactorRecoveryCode{UserID: userUUID, CodeHash: hash}
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code used and reports whether there was one
+func (r *twoFactorRepository) UseRecoveryCode(userID, codeHash string, now time.Time) (bool, error) {
+	result := r.db.Model(&models.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CountRecoveryCodes returns how many unused recovery codes a user has left
+func (r *twoFactorRepository) CountRecoveryCodes(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+// CreateChallenge stores a login challenge, replacing the user's earlier ones and clearing
+// expired challenges
+func (r *twoFactorRepository) CreateChallenge(challenge *models.LoginChallenge) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? OR expires_at <= ?", challenge.UserID, time.Now()).Delete(&models.LoginChallenge{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(challenge).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create login challenge: %w", err)
+	}
+	return nil
+}
+
+// FindChallenge returns the challenge with a token hash, or nil
+func (r *twoFactorRepository) FindChallenge(tokenHash string) (*models.LoginChallenge, error) {
+	var challenge models.LoginChallenge
+	err := r.db.Where("token_hash = ?", tokenHash).First(&challenge).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find login challenge: %w", err)
+	}
+	return &challenge, nil
+}
+
+// CountChallengeAttempt counts a wrong code against a challenge and returns the new count
+func (r *twoFactorRepository) CountChallengeAttempt(id string) (int, error) {
+	var challenge models.LoginChallenge
+	err := r.db.Model(&challenge).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "attempts"}}}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count login challenge attempt: %w", err)
+	}
+	return challenge.Attempts, nil
+}
+
+// DeleteChallenge removes a challenge once it is used up
+func (r *twoFactorRepository) DeleteChallenge(id string) error {
+	if err := r.db.Where("id = ?", id).Delete(&models.LoginChallenge{}).Error; err != nil {
+		return fmt.Errorf("failed to delete login challenge: %w", err)
+	}
+	return nil
+}
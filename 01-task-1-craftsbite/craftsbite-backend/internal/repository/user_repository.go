@@ -15,6 +15,7 @@ type UserRepository interface {
 	Update(user *models.User) error
 	Delete(id string) error
 	FindAll(filters map[string]interface{}) ([]models.User, error)
+	FindByIDs(ids []string) ([]models.User, error)
 }
 
 // userRepository implements UserRepository
@@ -90,3 +91,15 @@ func (r *userRepository) FindAll(filters map[string]interface{}) ([]models.User,
 	}
 	return users, nil
 }
+
+// FindByIDs finds all users with the given IDs
+func (r *userRepository) FindByIDs(ids []string) ([]models.User, error) {
+	if len(ids) == 0 {
+		return []models.User{}, nil
+	}
+	var users []models.User
+	if err := r.db.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+	return users, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VendorRepository defines data access for vendors and their orders
+type VendorRepository interface {
+	Create(vendor *models.Vendor) error
+	Update(vendor *models.Vendor) error
+	FindByID(id string) (*models.Vendor, error)
+	FindAll(includeInactive bool) ([]models.Vendor, error)
+
+	// SaveOrder creates or updates an order and replaces its lines with order.Lines
+	SaveOrder(order *models.VendorOrder) error
+	FindOrderByID(id string) (*models.VendorOrder, error)
+	FindOrder(vendorID, date string) (*models.VendorOrder, error)
+	// FindOrders returns orders with their vendor and lines, optionally limited to one vendor
+	FindOrders(startDate, endDate, vendorID string) ([]models.VendorOrder, error)
+}
+
+type vendorRepository struct {
+	db *gorm.DB
+}
+
+// NewVendorRepository creates a new vendor repository
+func NewVendorRepository(db *gorm.DB) VendorRepository {
+	return &vendorRepository{db: db}
+}
+
+func (r *vendorRepository) Create(vendor *models.Vendor) error {
+	if err := r.db.Create(vendor).Error; err != nil {
+		return fmt.Errorf("failed to create vendor: %w", err)
+	}
+	return nil
+}
+
+func (r *vendorRepository) Update(vendor *models.Vendor) error {
+	if err := r.db.Save(vendor).Error; err != nil {
+		return fmt.Errorf("failed to update vendor: %w", err)
+	}
+	return nil
+}
+
+func (r *vendorRepository) FindByID(id string) (*models.Vendor, error) {
+	var vendor models.Vendor
+	if err := r.db.Where("id = ?", id).First(&vendor).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find vendor: %w", err)
+	}
+	return &vendor, nil
+}
+
+func (r *vendorRepository) FindAll(includeInactive bool) ([]models.Vendor, error) {
+	query := r.db.Order("name ASC")
+	if !includeInactive {
+		query = query.Where("is_active")
+	}
+
+	var vendors []models.Vendor
+	if err := query.Find(&vendors).Error; err != nil {
+		return nil, fmt.Errorf("failed to find vendors: %w", err)
+	}
+	return vendors, nil
+}
+
+func (r *vendorRepository) SaveOrder(order *models.VendorOrder) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		lines := order.Lines
+		if err := tx.Omit("Lines", "Vendor").Save(order).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("order_id = ?", order.ID).Delete(&models.VendorOrderLine{}).Error; err != nil {
+			return err
+		}
+		for i := range lines {
+			lines[i].ID = uuid.Nil
+			lines[i].OrderID = order.ID
+		}
+		if len(lines) > 0 {
+			if err := tx.Create(&lines).Error; err != nil {
+				return err
+			}
+		}
+		order.Lines = lines
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save vendor order: %w", err)
+	}
+	return nil
+}
+
+func (r *vendorRepository) FindOrderByID(id string) (*models.VendorOrder, error) {
+	var order models.VendorOrder
+	if err := r.withDetails().Where("id = ?", id).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find vendor order: %w", err)
+	}
+	return &order, nil
+}
+
+func (r *vendorRepository) FindOrder(vendorID, date string) (*models.VendorOrder, error) {
+	var order models.VendorOrder
+	if err := r.withDetails().Where("vendor_id = ? AND date = ?", vendorID, date).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find vendor order: %w", err)
+	}
+	return &order, nil
+}
+
+func (r *vendorRepository) FindOrders(startDate, endDate, vendorID string) ([]models.VendorOrder, error) {
+	query := r.withDetails().Where("date >= ? AND date <= ?", startDate, endDate)
+	if vendorID != "" {
+		query = query.Where("vendor_id = ?", vendorID)
+	}
+
+	var orders []models.VendorOrder
+	if err := query.Order("date ASC, created_at ASC").Find(&orders).Error; err != nil {
+		return nil, fmt.Errorf("failed to find vendor orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (r *vendorRepository) withDetails() *gorm.DB {
+	return r.db.Preload("Vendor").Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("meal_type ASC")
+	})
+}
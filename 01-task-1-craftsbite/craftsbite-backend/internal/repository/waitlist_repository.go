@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"craftsbite-backend/internal/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// WaitlistRepository defines data access for meal waitlists
+type WaitlistRepository interface {
+	Create(entry *models.MealWaitlistEntry) error
+	// Find returns a user's entry on a meal's waitlist, or nil
+	Find(userID, date, mealType string) (*models.MealWaitlistEntry, error)
+	// FindByDateAndMeal returns a meal's waitlist in promotion order, with users loaded
+	FindByDateAndMeal(date, mealType string) ([]models.MealWaitlistEntry, error)
+	FindByUserAndDate(userID, date string) ([]models.MealWaitlistEntry, error)
+	// Delete removes an entry and reports whether it was still there
+	Delete(id string) (bool, error)
+}
+
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+// NewWaitlistRepository creates a new waitlist repository
+func NewWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+func (r *waitlistRepository) Create(entry *models.MealWaitlistEntry) error {
+	if err := r.db.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create waitlist entry: %w", err)
+	}
+	return nil
+}
+
+func (r *waitlistRepository) Find(userID, date, mealType string) (*models.MealWaitlistEntry, error) {
+	var entry models.MealWaitlistEntry
+	err := r.db.Where("user_id = ? AND date = ? AND meal_type = ?", userID, date, mealType).First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find waitlist entry: %w", err)
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepository) FindByDateAndMeal(date, mealType string) ([]models.MealWaitlistEntry, error) {
+	var entries []models.MealWaitlistEntry
+	err := r.db.Preload("User").
+		Where("date = ? AND meal_type = ?", date, mealType).
+		Order("created_at ASC, id ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find waitlist: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *waitlistRepository) FindByUserAndDate(userID, date string) ([]models.MealWaitlistEntry, error) {
+	var entries []models.MealWaitlistEntry
+	if err := r.db.Where("user_id = ? AND date = ?", userID, date).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to find waitlist entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *waitlistRepository) Delete(id string) (bool, error) {
+	result := r.db.Delete(&models.MealWaitlistEntry{}, "id = ?", id)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete waitlist entry: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
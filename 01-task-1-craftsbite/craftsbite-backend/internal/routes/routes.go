@@ -1,10 +1,10 @@
 package routes
 
 import (
+	"craftsbite-backend/internal/authz"
 	"craftsbite-backend/internal/config"
 	"craftsbite-backend/internal/handlers"
 	"craftsbite-backend/internal/middleware"
-	"craftsbite-backend/internal/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,49 +20,133 @@ type Handlers struct {
     History     *handlers.HistoryHandler
     WorkLocation *handlers.WorkLocationHandler
     WFHPeriod    *handlers.WFHPeriodHandler
+    Discord      *handlers.DiscordHandler
+    Snapshot     *handlers.HeadcountSnapshotHandler
+    Session      *handlers.SessionHandler
+    Team         *handlers.TeamHandler
+    Role         *handlers.RoleHandler
+    LateChange   *handlers.LateChangeHandler
+    Billing      *handlers.BillingHandler
+    Guest        *handlers.GuestBookingHandler
+    Cutoff       *handlers.CutoffPolicyHandler
+    MealType     *handlers.MealTypeHandler
+    Menu         *handlers.MenuHandler
+    Capacity     *handlers.CapacityHandler
+    Notification *handlers.NotificationHandler
+    Consumption  *handlers.ConsumptionHandler
+    Vendor       *handlers.VendorHandler
+    LoginProtection *handlers.LoginProtectionHandler
+    Invitation      *handlers.InvitationHandler
+    TwoFactor       *handlers.TwoFactorHandler
 }
 
-func RegisterRoutes(router *gin.Engine, h *Handlers, cfg *config.Config) {
+// guards bundles the middleware used to protect route groups
+type guards struct {
+    auth       gin.HandlerFunc
+    authorizer authz.Authorizer
+    // limit rate limits authenticated routes per user; authLimit limits the public auth routes per IP
+    limit     gin.HandlerFunc
+    authLimit gin.HandlerFunc
+}
+
+// can requires the caller's role to hold a permission at least at the given scope
+func (g guards) can(permission authz.Permission, scope authz.Scope) gin.HandlerFunc {
+    return middleware.RequirePermission(g.authorizer, permission, scope)
+}
+
+func RegisterRoutes(router *gin.Engine, h *Handlers, cfg *config.Config, sessions middleware.SessionChecker, authorizer authz.Authorizer, limits middleware.RateLimitStore) {
+    g := guards{
+        auth:       middleware.AuthMiddleware(cfg.JWT.Secret, sessions),
+        authorizer: authorizer,
+        limit:      noRateLimit,
+        authLimit:  noRateLimit,
+    }
+    if cfg.RateLimit.Enabled {
+        g.limit = middleware.RateLimitMiddleware(limits, middleware.RateLimitPolicy{
+            Name:  "api",
+            Read:  middleware.PerMinute(cfg.RateLimit.ReadRequestsPerMinute),
+            Write: middleware.PerMinute(cfg.RateLimit.RequestsPerMinute),
+        })
+        g.authLimit = middleware.RateLimitMiddleware(limits, middleware.RateLimitPolicy{
+            Name:  "auth",
+            Read:  middleware.PerMinute(cfg.RateLimit.AuthRequestsPerMinute),
+            Write: middleware.PerMinute(cfg.RateLimit.AuthRequestsPerMinute),
+        })
+    }
+
     // Health check endpoint (public)
     router.GET("/health", healthCheck(cfg))
 
+    // Discord interactions endpoint (public, verified by Ed25519 signature)
+    router.POST("/interactions", h.Discord.Interactions)
+
     v1 := router.Group("/api/v1")
     {
-        registerAuthRoutes(v1, h, cfg)
-        registerUserRoutes(v1, h, cfg)
-        registerMealRoutes(v1, h, cfg)
-        registerScheduleRoutes(v1, h, cfg)
-        registerHeadcountRoutes(v1, h, cfg)
-        registerAdminRoutes(v1, h, cfg)
-        registerWorkLocationRoutes(v1, h, cfg)
-        registerWFHPeriodRoutes(v1, h, cfg)
+        registerAuthRoutes(v1, h, g)
+        registerUserRoutes(v1, h, g)
+        registerMealRoutes(v1, h, g)
+        registerScheduleRoutes(v1, h, g)
+        registerHeadcountRoutes(v1, h, g)
+        registerAdminRoutes(v1, h, g)
+        registerWorkLocationRoutes(v1, h, g)
+        registerWFHPeriodRoutes(v1, h, g)
+        registerTeamRoutes(v1, h, g)
+        registerRoleRoutes(v1, h, g)
+        registerBillingRoutes(v1, h, g)
+        registerMenuRoutes(v1, h, g)
+        registerCheckInRoutes(v1, h, g)
+        registerVendorRoutes(v1, h, g)
     }
 }
 
-func registerAuthRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Config) {
+// noRateLimit stands in for the rate limiters when rate limiting is disabled
+func noRateLimit(c *gin.Context) {
+    c.Next()
+}
+
+func registerAuthRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
     // Public auth routes
     auth := v1.Group("/auth")
+    auth.Use(g.authLimit)
     {
         auth.POST("/login", h.Auth.Login)
+        auth.POST("/login/2fa", h.Auth.VerifyTwoFactor)
+        auth.POST("/login/2fa/setup", h.TwoFactor.SetupChallenge)
         auth.POST("/register", h.Auth.Register)
+        auth.GET("/accept-invite", h.Auth.GetInvitation)
+        auth.POST("/accept-invite", h.Auth.AcceptInvite)
+        auth.POST("/forgot-password", h.Auth.ForgotPassword)
+        auth.POST("/reset-password", h.Auth.ResetPassword)
+        auth.POST("/refresh", h.Auth.Refresh)
     }
 
     // Protected auth routes
     authProtected := v1.Group("/auth")
-    authProtected.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+    authProtected.Use(g.auth, g.limit)
     {
         authProtected.GET("/me", h.Auth.GetCurrentUser)
         authProtected.POST("/logout", h.Auth.Logout)
+        authProtected.POST("/change-password", h.Auth.ChangePassword)
+
+        authProtected.GET("/sessions", h.Session.ListMySessions)
+        authProtected.DELETE("/sessions", h.Session.RevokeMyOtherSessions)
+        authProtected.DELETE("/sessions/:id", h.Session.RevokeMySession)
+
+        authProtected.GET("/2fa", h.TwoFactor.GetStatus)
+        authProtected.POST("/2fa/setup", h.TwoFactor.BeginSetup)
+        authProtected.POST("/2fa/enable", h.TwoFactor.Enable)
+        authProtected.POST("/2fa/disable", h.TwoFactor.Disable)
+        authProtected.POST("/2fa/recovery-codes", h.TwoFactor.RegenerateRecoveryCodes)
     }
 }
 
-func registerUserRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Config) {
+func registerUserRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
     users := v1.Group("/users")
-    users.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+    users.Use(g.auth, g.limit)
     {
-        users.GET("", middleware.RequireRoles(models.RoleAdmin, models.RoleLogistics), h.User.ListUsers)
-        users.POST("", middleware.RequireRoles(models.RoleAdmin), h.User.CreateUser)
-        users.DELETE("/:id", middleware.RequireRoles(models.RoleAdmin), h.User.DeactivateUser)
+        users.GET("", g.can(authz.PermUserRead, authz.ScopeAll), h.User.ListUsers)
+        users.POST("", g.can(authz.PermUserWrite, authz.ScopeAll), h.User.CreateUser)
+        users.DELETE("/:id", g.can(authz.PermUserWrite, authz.ScopeAll), h.User.DeactivateUser)
 
         // Admin or Self routes
         users.GET("/:id", h.User.GetUser)
@@ -71,17 +155,29 @@ func registerUserRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Config) {
         // Preference routes
         users.GET("/me/preferences", h.Preference.GetPreferences)
         users.PUT("/me/preferences", h.Preference.UpdatePreferences)
+        users.GET("/me/preferences/rules", h.Preference.ListRules)
+        users.POST("/me/preferences/rules", h.Preference.CreateRule)
+        users.PUT("/me/preferences/rules/:id", h.Preference.UpdateRule)
+        users.DELETE("/me/preferences/rules/:id", h.Preference.DeleteRule)
+
+        // Notification routes
+        users.GET("/me/notifications", h.Notification.ListNotifications)
+        users.POST("/me/notifications/read", h.Notification.MarkAllRead)
+        users.POST("/me/notifications/:id/read", h.Notification.MarkRead)
+
+        // QR code shown at the canteen kiosk
+        users.GET("/me/check-in-token", h.Consumption.GetMyCheckInToken)
 
         // Team Lead routes
-        users.GET("/me/team-members", middleware.RequireRoles(models.RoleTeamLead), h.User.GetMyTeamMembers)
+        users.GET("/me/team-members", g.can(authz.PermTeamRead, authz.ScopeTeam), h.User.GetMyTeamMembers)
 
-        users.GET("/me/team", middleware.RequireRoles(models.RoleEmployee, models.RoleTeamLead), h.User.GetMyTeam)
+        users.GET("/me/team", g.can(authz.PermTeamRead, authz.ScopeOwn), h.User.GetMyTeam)
     }
 }
 
-func registerMealRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Config) {
+func registerMealRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
     meals := v1.Group("/meals")
-    meals.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+    meals.Use(g.auth, g.limit)
     {
         // User routes
         meals.GET("/today", h.Meal.GetTodayMeals)
@@ -89,7 +185,32 @@ func registerMealRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Config) {
         meals.POST("/participation", h.Meal.SetParticipation)
 
         // Override routes
-        meals.POST("/participation/override", middleware.RequireRoles(models.RoleAdmin, models.RoleTeamLead), h.Meal.OverrideParticipation)
+        meals.POST("/participation/override", g.can(authz.PermParticipationOverride, authz.ScopeTeam), h.Meal.OverrideParticipation)
+
+        // Meal type catalogue
+        meals.GET("/types", h.MealType.ListMealTypes)
+        meals.POST("/types", g.can(authz.PermMealTypeManage, authz.ScopeAll), h.MealType.CreateMealType)
+        meals.PUT("/types/:code", g.can(authz.PermMealTypeManage, authz.ScopeAll), h.MealType.UpdateMealType)
+        meals.DELETE("/types/:code", g.can(authz.PermMealTypeManage, authz.ScopeAll), h.MealType.DeactivateMealType)
+
+        // Per-meal cutoff policies
+        meals.GET("/cutoff-policies", h.Cutoff.ListPolicies)
+        meals.PUT("/cutoff-policies/:meal_type", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Cutoff.SetPolicy)
+        meals.DELETE("/cutoff-policies/:meal_type", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Cutoff.DeletePolicy)
+
+        // Late change requests after the cutoff
+        meals.GET("/late-requests", h.LateChange.ListMyRequests)
+        meals.POST("/late-requests", h.LateChange.CreateRequest)
+        meals.GET("/late-requests/rules", h.LateChange.GetRules)
+        meals.DELETE("/late-requests/:id", h.LateChange.CancelRequest)
+        meals.GET("/late-requests/pending", g.can(authz.PermLateChangeReview, authz.ScopeTeam), h.LateChange.ListPending)
+        meals.POST("/late-requests/:id/approve", g.can(authz.PermLateChangeReview, authz.ScopeTeam), h.LateChange.Approve)
+        meals.POST("/late-requests/:id/reject", g.can(authz.PermLateChangeReview, authz.ScopeTeam), h.LateChange.Reject)
+
+        // Guest booking routes
+        meals.GET("/guests", h.Guest.ListMyBookings)
+        meals.POST("/guests", h.Guest.CreateBooking)
+        meals.DELETE("/guests/:id", h.Guest.CancelBooking)
 
         // Bulk opt-out routes
         meals.GET("/bulk-optouts", h.BulkOptOut.GetBulkOptOuts)
@@ -100,66 +221,107 @@ func registerMealRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Config) {
         meals.GET("/history", h.History.GetHistory)
         meals.GET("/participation-audit", h.History.GetAuditTrail)
 
-        meals.GET("/team-participation", middleware.RequireRoles(models.RoleTeamLead), h.Meal.GetTeamParticipation)
-        meals.GET("/all-teams-participation", middleware.RequireRoles(models.RoleAdmin, models.RoleLogistics), h.Meal.GetAllTeamsParticipation)
+        meals.GET("/team-participation", g.can(authz.PermParticipationRead, authz.ScopeTeam), h.Meal.GetTeamParticipation)
+        meals.GET("/all-teams-participation", g.can(authz.PermParticipationRead, authz.ScopeAll), h.Meal.GetAllTeamsParticipation)
     }
 }
 
-func registerScheduleRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Config) {
+func registerScheduleRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
     schedules := v1.Group("/schedules")
-    schedules.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+    schedules.Use(g.auth, g.limit)
     {
         // Read routes - all authenticated users
         schedules.GET("/:date", h.Schedule.GetSchedule)
         schedules.GET("/range", h.Schedule.GetScheduleRange)
+        schedules.GET("/effective", h.Schedule.GetEffectiveSchedule)
+        schedules.GET("/rules", h.Schedule.ListRules)
+        schedules.GET("/:date/cutoffs", h.Cutoff.GetDayCutoffs)
+        schedules.GET("/:date/capacities", h.Capacity.GetDayCapacities)
+        schedules.GET("/:date/capacities/:meal_type/waitlist", g.can(authz.PermHeadcountRead, authz.ScopeAll), h.Capacity.GetWaitlist)
+
+        schedules.POST("", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Schedule.CreateSchedule)
+        schedules.PUT("/:date", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Schedule.UpdateSchedule)
+        schedules.DELETE("/:date", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Schedule.DeleteSchedule)
+        schedules.PUT("/:date/cutoffs/:meal_type", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Cutoff.SetDayOverride)
+        schedules.DELETE("/:date/cutoffs/:meal_type", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Cutoff.DeleteDayOverride)
+        schedules.PUT("/:date/capacities/:meal_type", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Capacity.SetCapacity)
+        schedules.DELETE("/:date/capacities/:meal_type", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Capacity.DeleteCapacity)
 
-        schedules.POST("", middleware.RequireRoles(models.RoleAdmin, models.RoleLogistics), h.Schedule.CreateSchedule)
-        schedules.PUT("/:date", middleware.RequireRoles(models.RoleAdmin, models.RoleLogistics), h.Schedule.UpdateSchedule)
-        schedules.DELETE("/:date", middleware.RequireRoles(models.RoleAdmin, models.RoleLogistics), h.Schedule.DeleteSchedule)
+        // Recurring rules and holiday calendars
+        schedules.POST("/rules", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Schedule.CreateRule)
+        schedules.PUT("/rules/:id", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Schedule.UpdateRule)
+        schedules.DELETE("/rules/:id", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Schedule.DeleteRule)
+        schedules.POST("/preview", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Schedule.PreviewSchedule)
+        schedules.POST("/import/ics", g.can(authz.PermScheduleWrite, authz.ScopeAll), h.Schedule.ImportHolidays)
     }
 }
 
-func registerHeadcountRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Config) {
+func registerHeadcountRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
     headcount := v1.Group("/headcount")
-    headcount.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
-    headcount.Use(middleware.RequireRoles(models.RoleAdmin, models.RoleLogistics))
+    headcount.Use(g.auth, g.limit)
+    headcount.Use(g.can(authz.PermHeadcountRead, authz.ScopeAll))
     {
         headcount.GET("/today", h.Headcount.GetTodayHeadcount)
         headcount.GET("/forecast", h.Headcount.GetForecast)
+        headcount.GET("/forecast/backtest", h.Headcount.GetForecastBacktest)
         headcount.GET("/:date/announcement", h.Headcount.GetAnnouncement)
         headcount.GET("/:date/stream", h.Headcount.StreamHeadcount)
+        headcount.GET("/:date/snapshot", h.Snapshot.GetSnapshots)
+        headcount.POST("/:date/snapshot", g.can(authz.PermHeadcountSnapshot, authz.ScopeAll), h.Snapshot.CaptureSnapshot)
+        headcount.GET("/:date/snapshot/diff", h.Snapshot.DiffSnapshot)
+        headcount.GET("/:date/snapshot/:meal_type", h.Snapshot.GetSnapshot)
+        headcount.GET("/:date/guests", h.Guest.ListByDate)
         headcount.GET("/:date/:meal_type", h.Headcount.GetDetailedHeadcount)
         headcount.GET("/:date", h.Headcount.GetHeadcountByDate)
     }
 }
 
-func registerAdminRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Config) {
+func registerAdminRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
     admin := v1.Group("/admin")
-    admin.Use(middleware.AuthMiddleware(cfg.JWT.Secret))    
+    admin.Use(g.auth, g.limit)
     {
-        admin.POST("/meals/bulk-optouts", middleware.RequireRoles(models.RoleAdmin, models.RoleTeamLead), h.BulkOptOut.AdminBulkOptOut)
-        admin.GET("/meals/history/:user_id", middleware.RequireRoles(models.RoleAdmin, models.RoleLogistics), h.History.GetUserHistoryAdmin)
+        admin.POST("/meals/bulk-optouts", g.can(authz.PermBulkOptOutManage, authz.ScopeTeam), h.BulkOptOut.AdminBulkOptOut)
+        admin.GET("/meals/history/:user_id", g.can(authz.PermHistoryRead, authz.ScopeAll), h.History.GetUserHistoryAdmin)
+
+        admin.GET("/discord-links", g.can(authz.PermDiscordManage, authz.ScopeAll), h.Discord.ListLinks)
+        admin.POST("/discord-links", g.can(authz.PermDiscordManage, authz.ScopeAll), h.Discord.LinkUser)
+        admin.DELETE("/discord-links/:discord_user_id", g.can(authz.PermDiscordManage, authz.ScopeAll), h.Discord.UnlinkUser)
+
+        admin.GET("/users/:user_id/sessions", g.can(authz.PermSessionManage, authz.ScopeAll), h.Session.ListUserSessions)
+        admin.DELETE("/users/:user_id/sessions", g.can(authz.PermSessionManage, authz.ScopeAll), h.Session.RevokeUserSessions)
+        admin.DELETE("/sessions/:id", g.can(authz.PermSessionManage, authz.ScopeAll), h.Session.RevokeSession)
+
+        admin.GET("/login-lockouts", g.can(authz.PermAuthSecurityManage, authz.ScopeAll), h.LoginProtection.ListLockouts)
+        admin.DELETE("/login-lockouts/:id", g.can(authz.PermAuthSecurityManage, authz.ScopeAll), h.LoginProtection.Unlock)
+        admin.POST("/users/:user_id/unlock", g.can(authz.PermAuthSecurityManage, authz.ScopeAll), h.LoginProtection.UnlockUser)
+        admin.DELETE("/users/:user_id/2fa", g.can(authz.PermAuthSecurityManage, authz.ScopeAll), h.TwoFactor.Reset)
+        admin.GET("/auth-audit-log", g.can(authz.PermAuthSecurityManage, authz.ScopeAll), h.LoginProtection.ListAuditLog)
+
+        admin.GET("/invitations", g.can(authz.PermUserWrite, authz.ScopeAll), h.Invitation.ListInvitations)
+        admin.POST("/invitations", g.can(authz.PermUserWrite, authz.ScopeAll), h.Invitation.CreateInvitation)
+        admin.POST("/invitations/:id/resend", g.can(authz.PermUserWrite, authz.ScopeAll), h.Invitation.ResendInvitation)
+        admin.DELETE("/invitations/:id", g.can(authz.PermUserWrite, authz.ScopeAll), h.Invitation.RevokeInvitation)
     }
 }
 
-func registerWorkLocationRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Config) {
+func registerWorkLocationRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
     wl := v1.Group("/work-location")
-    wl.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+    wl.Use(g.auth, g.limit)
     {
         wl.GET("", h.WorkLocation.GetMyWorkLocation)
         wl.POST("", h.WorkLocation.SetMyWorkLocation)
         wl.GET("/monthly-summary", h.WorkLocation.GetMonthlySummary)
-        wl.GET("/team-monthly-report", middleware.RequireRoles(models.RoleAdmin, models.RoleLogistics, models.RoleTeamLead), h.WorkLocation.GetTeamMonthlyReport)
+        wl.GET("/team-monthly-report", g.can(authz.PermWorkLocationReport, authz.ScopeTeam), h.WorkLocation.GetTeamMonthlyReport)
         
-        wl.POST("/override", middleware.RequireRoles(models.RoleAdmin, models.RoleTeamLead), h.WorkLocation.SetWorkLocationFor)
-        wl.GET("/list", middleware.RequireRoles(models.RoleAdmin, models.RoleTeamLead), h.WorkLocation.ListWorkLocationsByDate)
+        wl.POST("/override", g.can(authz.PermWorkLocationOverride, authz.ScopeTeam), h.WorkLocation.SetWorkLocationFor)
+        wl.GET("/list", g.can(authz.PermWorkLocationRead, authz.ScopeTeam), h.WorkLocation.ListWorkLocationsByDate)
     }
 }
 
-func registerWFHPeriodRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Config) {
+func registerWFHPeriodRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
     periods := v1.Group("/wfh-periods")
-    periods.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
-    periods.Use(middleware.RequireRoles(models.RoleAdmin, models.RoleLogistics))
+    periods.Use(g.auth, g.limit)
+    periods.Use(g.can(authz.PermWFHPeriodManage, authz.ScopeAll))
     {
         periods.POST("", h.WFHPeriod.CreateWFHPeriod)
         periods.GET("", h.WFHPeriod.ListWFHPeriods)
@@ -167,6 +329,106 @@ func registerWFHPeriodRoutes(v1 *gin.RouterGroup, h *Handlers, cfg *config.Confi
     }
 }
 
+func registerTeamRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
+    teams := v1.Group("/teams")
+    teams.Use(g.auth, g.limit)
+    teams.Use(g.can(authz.PermTeamManage, authz.ScopeAll))
+    {
+        teams.GET("", h.Team.ListTeams)
+        teams.POST("", h.Team.CreateTeam)
+        teams.GET("/:id", h.Team.GetTeam)
+        teams.PUT("/:id", h.Team.UpdateTeam)
+        teams.DELETE("/:id", h.Team.DeactivateTeam)
+        teams.POST("/:id/reactivate", h.Team.ReactivateTeam)
+        teams.PUT("/:id/lead", h.Team.ChangeTeamLead)
+        teams.GET("/:id/history", h.Team.GetTeamHistory)
+        teams.POST("/:id/members", h.Team.AddMember)
+        teams.POST("/:id/members/import", h.Team.ImportMembers)
+        teams.DELETE("/:id/members/:user_id", h.Team.RemoveMember)
+    }
+}
+
+func registerRoleRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
+    roles := v1.Group("/roles")
+    roles.Use(g.auth, g.limit)
+    roles.Use(g.can(authz.PermRoleManage, authz.ScopeAll))
+    {
+        roles.GET("", h.Role.ListRoles)
+        roles.GET("/permissions", h.Role.ListPermissions)
+        roles.POST("", h.Role.CreateRole)
+        roles.GET("/:name", h.Role.GetRole)
+        roles.PUT("/:name", h.Role.UpdateRole)
+        roles.DELETE("/:name", h.Role.DeleteRole)
+    }
+}
+
+func registerBillingRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
+    billing := v1.Group("/billing")
+    billing.Use(g.auth, g.limit)
+    {
+        billing.GET("/prices", h.Billing.ListPrices)
+        billing.POST("/prices", g.can(authz.PermBillingManage, authz.ScopeAll), h.Billing.CreatePrice)
+        billing.DELETE("/prices/:id", g.can(authz.PermBillingManage, authz.ScopeAll), h.Billing.DeletePrice)
+
+        // Statements support ?month=YYYY-MM and ?format=csv
+        billing.GET("/statements", g.can(authz.PermBillingRead, authz.ScopeAll), h.Billing.GetOrganizationStatement)
+        billing.GET("/statements/me", g.can(authz.PermBillingRead, authz.ScopeOwn), h.Billing.GetMyStatement)
+        billing.GET("/statements/users/:user_id", g.can(authz.PermBillingRead, authz.ScopeOwn), h.Billing.GetUserStatement)
+        billing.GET("/statements/teams/:team_id", g.can(authz.PermBillingRead, authz.ScopeTeam), h.Billing.GetTeamStatement)
+    }
+}
+
+func registerMenuRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
+    menus := v1.Group("/menus")
+    menus.Use(g.auth, g.limit)
+    {
+        // Published menus for ?start=&end=; menu managers also see drafts
+        menus.GET("", h.Menu.GetMenus)
+        menus.PUT("", g.can(authz.PermMenuManage, authz.ScopeAll), h.Menu.SaveMenu)
+        menus.POST("/clone", g.can(authz.PermMenuManage, authz.ScopeAll), h.Menu.CloneMenus)
+        menus.POST("/:id/publish", g.can(authz.PermMenuManage, authz.ScopeAll), h.Menu.PublishMenu)
+        menus.POST("/:id/unpublish", g.can(authz.PermMenuManage, authz.ScopeAll), h.Menu.UnpublishMenu)
+        menus.DELETE("/:id", g.can(authz.PermMenuManage, authz.ScopeAll), h.Menu.DeleteMenu)
+    }
+}
+
+func registerCheckInRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
+    checkIns := v1.Group("/check-ins")
+    checkIns.Use(g.auth, g.limit)
+    {
+        checkIns.POST("", g.can(authz.PermCheckInRecord, authz.ScopeAll), h.Consumption.CheckIn)
+        checkIns.GET("", g.can(authz.PermCheckInRecord, authz.ScopeAll), h.Consumption.ListCheckIns)
+        checkIns.DELETE("/:id", g.can(authz.PermCheckInRecord, authz.ScopeAll), h.Consumption.UndoCheckIn)
+
+        // Opted-in vs. checked-in per date, team and user
+        checkIns.GET("/report", g.can(authz.PermConsumptionRead, authz.ScopeAll), h.Consumption.GetReport)
+    }
+}
+
+func registerVendorRoutes(v1 *gin.RouterGroup, h *Handlers, g guards) {
+    vendors := v1.Group("/vendors")
+    vendors.Use(g.auth, g.limit)
+    vendors.Use(g.can(authz.PermVendorManage, authz.ScopeAll))
+    {
+        vendors.GET("", h.Vendor.ListVendors)
+        vendors.POST("", h.Vendor.CreateVendor)
+        vendors.PUT("/:id", h.Vendor.UpdateVendor)
+        vendors.DELETE("/:id", h.Vendor.DeactivateVendor)
+    }
+
+    // Orders are generated and sent at each vendor's deadline; these endpoints draft, resend and acknowledge them
+    orders := v1.Group("/vendor-orders")
+    orders.Use(g.auth, g.limit)
+    orders.Use(g.can(authz.PermVendorManage, authz.ScopeAll))
+    {
+        orders.GET("", h.Vendor.ListOrders)
+        orders.POST("", h.Vendor.GenerateOrder)
+        orders.GET("/:id", h.Vendor.GetOrder)
+        orders.POST("/:id/send", h.Vendor.SendOrder)
+        orders.POST("/:id/acknowledge", h.Vendor.AcknowledgeOrder)
+    }
+}
+
 func healthCheck(cfg *config.Config) gin.HandlerFunc {
     return func(c *gin.Context) {
         c.JSON(200, gin.H{
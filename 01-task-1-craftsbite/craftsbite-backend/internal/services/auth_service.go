@@ -11,37 +11,59 @@ import (
 
 // LoginResponse represents the response after successful login
 type LoginResponse struct {
-	Token     string       `json:"token,omitempty"`
-	User      *models.User `json:"user"`
-	ExpiresAt time.Time    `json:"expires_at"`
+	Token            string       `json:"token,omitempty"`
+	User             *models.User `json:"user"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	RefreshToken     string       `json:"-"`
+	RefreshExpiresAt time.Time    `json:"refresh_expires_at"`
+	// TwoFactor is set instead of a session when the login needs a second step
+	TwoFactor *TwoFactorChallenge `json:"two_factor,omitempty"`
+	// RecoveryCodes are returned once, when a login enrolled the user in two-factor authentication
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
 }
 
 // AuthService defines the interface for authentication operations
 type AuthService interface {
-	Login(email, password string) (*LoginResponse, error)
+	Login(email, password string, client ClientInfo) (*LoginResponse, error)
+	VerifyTwoFactor(challengeToken, code string, client ClientInfo) (*LoginResponse, error)
+	Refresh(refreshToken string) (*LoginResponse, error)
+	Logout(sessionID string) error
 	GetCurrentUser(userID string) (*models.User, error)
 }
 
 // authService implements AuthService
 type authService struct {
-	userRepo repository.UserRepository
-	config   *config.Config
+	userRepo        repository.UserRepository
+	sessionService  SessionService
+	loginProtection LoginProtectionService
+	twoFactor       TwoFactorService
+	config          *config.Config
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo repository.UserRepository, cfg *config.Config) AuthService {
+func NewAuthService(userRepo repository.UserRepository, sessionService SessionService, loginProtection LoginProtectionService, twoFactor TwoFactorService, cfg *config.Config) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		config:   cfg,
+		userRepo:        userRepo,
+		sessionService:  sessionService,
+		loginProtection: loginProtection,
+		twoFactor:       twoFactor,
+		config:          cfg,
 	}
 }
 
-// Login authenticates a user and returns a JWT token
-func (s *authService) Login(email, password string) (*LoginResponse, error) {
+// Login authenticates a user and starts a new session. Repeated failures are throttled
+// and locked out with a *LoginThrottledError. When the user has two-factor authentication,
+// or their role requires it, no session is started and the response carries a challenge
+// for VerifyTwoFactor instead.
+func (s *authService) Login(email, password string, client ClientInfo) (*LoginResponse, error) {
+	if err := s.loginProtection.CheckLogin(email, client); err != nil {
+		return nil, err
+	}
+
 	// Find user by email
 	user, err := s.userRepo.FindByEmail(email)
 	if err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+		return nil, s.loginFailed(email, nil, client)
 	}
 
 	// Check if user is active
@@ -51,27 +73,67 @@ func (s *authService) Login(email, password string) (*LoginResponse, error) {
 
 	// Verify password
 	if !utils.CheckPasswordHash(password, user.Password) {
-		return nil, fmt.Errorf("invalid credentials")
-	}
-
-	// Generate JWT token
-	expiresAt := time.Now().Add(s.config.JWT.Expiration)
-	token, err := utils.GenerateToken(
-		user.ID.String(),
-		user.Email,
-		user.Role.String(),
-		s.config.JWT.Secret,
-		s.config.JWT.Expiration,
-	)
+		return nil, s.loginFailed(email, user, client)
+	}
+
+	challenge, err := s.twoFactor.Challenge(user)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, err
+	}
+	if challenge != nil {
+		return &LoginResponse{User: user, TwoFactor: challenge}, nil
+	}
+	if err := s.loginProtection.RecordSuccess(email); err != nil {
+		return nil, err
 	}
 
-	return &LoginResponse{
-		Token:     token,
-		User:      user,
-		ExpiresAt: expiresAt,
-	}, nil
+	tokens, err := s.sessionService.StartSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLoginResponse(user, tokens), nil
+}
+
+// VerifyTwoFactor completes a login challenge with a TOTP or recovery code and starts the
+// session. A setup challenge also returns the recovery codes of the new enrolment.
+func (s *authService) VerifyTwoFactor(challengeToken, code string, client ClientInfo) (*LoginResponse, error) {
+	user, recoveryCodes, err := s.twoFactor.CompleteChallenge(challengeToken, code, client)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.sessionService.StartSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newLoginResponse(user, tokens)
+	response.RecoveryCodes = recoveryCodes
+	return response, nil
+}
+
+// loginFailed counts a failed login and returns the error reported to the client
+func (s *authService) loginFailed(email string, user *models.User, client ClientInfo) error {
+	if err := s.loginProtection.RecordFailure(email, user, client); err != nil {
+		return err
+	}
+	return fmt.Errorf("invalid credentials")
+}
+
+// Refresh exchanges a refresh token for a new access token and a rotated refresh token
+func (s *authService) Refresh(refreshToken string) (*LoginResponse, error) {
+	tokens, user, err := s.sessionService.Refresh(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLoginResponse(user, tokens), nil
+}
+
+// Logout revokes the caller's session
+func (s *authService) Logout(sessionID string) error {
+	return s.sessionService.RevokeSession(sessionID, models.SessionRevokeLogout)
 }
 
 // GetCurrentUser retrieves the current user by ID
@@ -87,3 +149,13 @@ func (s *authService) GetCurrentUser(userID string) (*models.User, error) {
 
 	return user, nil
 }
+
+func newLoginResponse(user *models.User, tokens *SessionTokens) *LoginResponse {
+	return &LoginResponse{
+		Token:            tokens.AccessToken,
+		User:             user,
+		ExpiresAt:        tokens.AccessExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+	}
+}
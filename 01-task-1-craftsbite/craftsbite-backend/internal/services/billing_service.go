@@ -0,0 +1,487 @@
+package services
+
+import (
+	"craftsbite-backend/internal/authz"
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sources of a statement line
+const (
+	BillingSourceSnapshot = "snapshot"
+	BillingSourceResolved = "resolved"
+)
+
+// CreateMealPriceInput represents input for adding an entry to the price list.
+// UnitPrice is in minor currency units; SubsidyPercent is the share paid by the company.
+type CreateMealPriceInput struct {
+	MealType       string `json:"meal_type" binding:"required"`
+	UnitPrice      *int64 `json:"unit_price" binding:"required"`
+	SubsidyPercent int    `json:"subsidy_percent"`
+	EffectiveFrom  string `json:"effective_from" binding:"required"`
+	Note           string `json:"note"`
+}
+
+// StatementLine is a single billed meal
+type StatementLine struct {
+	Date          string          `json:"date"`
+	MealType      models.MealType `json:"meal_type"`
+	UnitPrice     int64           `json:"unit_price"`
+	CompanyShare  int64           `json:"company_share"`
+	EmployeeShare int64           `json:"employee_share"`
+	Priced        bool            `json:"priced"`
+	Source        string          `json:"source"`
+}
+
+// StatementMealTotal sums the billed meals of one meal type
+type StatementMealTotal struct {
+	MealType      models.MealType `json:"meal_type"`
+	Meals         int             `json:"meals"`
+	Total         int64           `json:"total"`
+	CompanyShare  int64           `json:"company_share"`
+	EmployeeShare int64           `json:"employee_share"`
+}
+
+// StatementTotals are the amounts of a statement, in minor currency units
+type StatementTotals struct {
+	Meals         int                  `json:"meals"`
+	UnpricedMeals int                  `json:"unpriced_meals"`
+	Total         int64                `json:"total"`
+	CompanyShare  int64                `json:"company_share"`
+	EmployeeShare int64                `json:"employee_share"`
+	ByMeal        []StatementMealTotal `json:"by_meal"`
+}
+
+// MonthlyStatement is a user's billing statement for a month
+type MonthlyStatement struct {
+	UserID      string          `json:"user_id"`
+	Name        string          `json:"name"`
+	Email       string          `json:"email"`
+	Month       string          `json:"month"`
+	Currency    string          `json:"currency"`
+	PeriodStart string          `json:"period_start"`
+	PeriodEnd   string          `json:"period_end"`
+	Totals      StatementTotals `json:"totals"`
+	Lines       []StatementLine `json:"lines,omitempty"`
+}
+
+// GroupStatement is the monthly statement of a team or of the whole organization
+type GroupStatement struct {
+	TeamID      string             `json:"team_id,omitempty"`
+	TeamName    string             `json:"team_name,omitempty"`
+	Month       string             `json:"month"`
+	Currency    string             `json:"currency"`
+	PeriodStart string             `json:"period_start"`
+	PeriodEnd   string             `json:"period_end"`
+	Totals      StatementTotals    `json:"totals"`
+	Users       []MonthlyStatement `json:"users"`
+}
+
+// BillingService defines the interface for meal pricing and monthly statements
+type BillingService interface {
+	ListPrices() ([]models.MealPrice, error)
+	CreatePrice(adminID string, input CreateMealPriceInput) (*models.MealPrice, error)
+	DeletePrice(id string) error
+	GetUserStatement(actorID, actorRole, userID, month string) (*MonthlyStatement, error)
+	GetTeamStatement(actorID, actorRole, teamID, month string) (*GroupStatement, error)
+	GetOrganizationStatement(month string) (*GroupStatement, error)
+}
+
+type billingService struct {
+	priceRepo    repository.MealPriceRepository
+	snapshotRepo repository.HeadcountSnapshotRepository
+	userRepo     repository.UserRepository
+	teamRepo     repository.TeamRepository
+	calendar     ScheduleCalendar
+	resolver     ParticipationResolver
+	catalog      MealCatalog
+	authorizer   authz.Authorizer
+	currency     string
+}
+
+// NewBillingService creates a new billing service
+func NewBillingService(
+	priceRepo repository.MealPriceRepository,
+	snapshotRepo repository.HeadcountSnapshotRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	calendar ScheduleCalendar,
+	resolver ParticipationResolver,
+	catalog MealCatalog,
+	authorizer authz.Authorizer,
+	cfg *config.Config,
+) BillingService {
+	return &billingService{
+		priceRepo:    priceRepo,
+		snapshotRepo: snapshotRepo,
+		userRepo:     userRepo,
+		teamRepo:     teamRepo,
+		calendar:     calendar,
+		resolver:     resolver,
+		catalog:      catalog,
+		authorizer:   authorizer,
+		currency:     cfg.Billing.Currency,
+	}
+}
+
+// ListPrices returns the whole price list, including past and future entries
+func (s *billingService) ListPrices() ([]models.MealPrice, error) {
+	return s.priceRepo.FindAll()
+}
+
+// CreatePrice adds a price that applies from its effective date until the next entry of the same meal type
+func (s *billingService) CreatePrice(adminID string, input CreateMealPriceInput) (*models.MealPrice, error) {
+	mealType, err := s.catalog.Validate(input.MealType)
+	if err != nil {
+		return nil, err
+	}
+	if *input.UnitPrice < 0 {
+		return nil, fmt.Errorf("unit price cannot be negative")
+	}
+	if input.SubsidyPercent < 0 || input.SubsidyPercent > 100 {
+		return nil, fmt.Errorf("subsidy percent must be between 0 and 100")
+	}
+	if err := validateDate(input.EffectiveFrom); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.priceRepo.FindByMealAndDate(input.MealType, input.EffectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("a %s price taking effect on %s already exists", input.MealType, input.EffectiveFrom)
+	}
+
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid admin ID: %w", err)
+	}
+	price := &models.MealPrice{
+		ID:             uuid.New(),
+		MealType:       mealType,
+		UnitPrice:      *input.UnitPrice,
+		SubsidyPercent: input.SubsidyPercent,
+		EffectiveFrom:  input.EffectiveFrom,
+		CreatedBy:      &adminUUID,
+	}
+	if note := strings.TrimSpace(input.Note); note != "" {
+		price.Note = &note
+	}
+
+	if err := s.priceRepo.Create(price); err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// DeletePrice removes a price that has not taken effect yet. Prices already in effect are kept
+// so past statements do not change; add a newer price instead.
+func (s *billingService) DeletePrice(id string) error {
+	price, err := s.priceRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if price == nil {
+		return fmt.Errorf("meal price not found")
+	}
+	if dateKey(price.EffectiveFrom) <= time.Now().Format("2006-01-02") {
+		return fmt.Errorf("prices already in effect cannot be deleted, add a new price instead")
+	}
+	return s.priceRepo.Delete(id)
+}
+
+// GetUserStatement builds a user's statement for a month (YYYY-MM)
+func (s *billingService) GetUserStatement(actorID, actorRole, userID, month string) (*MonthlyStatement, error) {
+	if err := s.authorizer.Authorize(actorRole, actorID, authz.PermBillingRead, userID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := s.buildStatements([]models.User{*user}, month, true)
+	if err != nil {
+		return nil, err
+	}
+	return &statements[0], nil
+}
+
+// GetTeamStatement builds the statement of every active member of a team.
+// Team-scoped grants only cover teams the actor leads.
+func (s *billingService) GetTeamStatement(actorID, actorRole, teamID, month string) (*GroupStatement, error) {
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := s.authorizer.ScopeFor(actorRole, authz.PermBillingRead)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.Covers(authz.ScopeTeam) || (scope != authz.ScopeAll && team.TeamLeadID.String() != actorID) {
+		return nil, fmt.Errorf("%w: %s is limited to teams you lead", authz.ErrForbidden, authz.PermBillingRead)
+	}
+
+	members, err := s.teamRepo.GetTeamMembers(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.buildGroup(members, month)
+	if err != nil {
+		return nil, err
+	}
+	group.TeamID = team.ID.String()
+	group.TeamName = team.Name
+	return group, nil
+}
+
+// GetOrganizationStatement builds the statement of every active user
+func (s *billingService) GetOrganizationStatement(month string) (*GroupStatement, error) {
+	users, err := s.userRepo.FindAll(map[string]interface{}{"active": true})
+	if err != nil {
+		return nil, err
+	}
+	return s.buildGroup(users, month)
+}
+
+func (s *billingService) buildGroup(users []models.User, month string) (*GroupStatement, error) {
+	start, end, err := s.billingPeriod(month)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := s.buildStatements(users, month, false)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(statements, func(i, j int) bool {
+		return statements[i].Name < statements[j].Name
+	})
+
+	group := &GroupStatement{
+		Month:       month,
+		Currency:    s.currency,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Users:       statements,
+	}
+	byMeal := make(map[models.MealType]*StatementMealTotal)
+	for _, statement := range statements {
+		group.Totals.Meals += statement.Totals.Meals
+		group.Totals.UnpricedMeals += statement.Totals.UnpricedMeals
+		group.Totals.Total += statement.Totals.Total
+		group.Totals.CompanyShare += statement.Totals.CompanyShare
+		group.Totals.EmployeeShare += statement.Totals.EmployeeShare
+		for _, mealTotal := range statement.Totals.ByMeal {
+			total, ok := byMeal[mealTotal.MealType]
+			if !ok {
+				total = &StatementMealTotal{MealType: mealTotal.MealType}
+				byMeal[mealTotal.MealType] = total
+			}
+			total.Meals += mealTotal.Meals
+			total.Total += mealTotal.Total
+			total.CompanyShare += mealTotal.CompanyShare
+			total.EmployeeShare += mealTotal.EmployeeShare
+		}
+	}
+	group.Totals.ByMeal = sortedMealTotals(byMeal)
+	return group, nil
+}
+
+// buildStatements bills each user for every meal they took part in during the month, up to today.
+// Meals with a frozen headcount snapshot are billed from the snapshot; later days use resolved participation.
+func (s *billingService) buildStatements(users []models.User, month string, withLines bool) ([]MonthlyStatement, error) {
+	start, end, err := s.billingPeriod(month)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID.String()
+	}
+
+	schedules, err := s.calendar.FindByDateRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	schedulesByDate := make(map[string]*models.DaySchedule, len(schedules))
+	for i := range schedules {
+		schedulesByDate[dateKey(schedules[i].Date)] = &schedules[i]
+	}
+
+	defaultMeals, err := s.catalog.DefaultMeals()
+	if err != nil {
+		return nil, err
+	}
+
+	// Meals served on each date, and the union across the month for one batch resolution
+	var dates []string
+	mealsByDate := make(map[string][]models.MealType)
+	mealSet := make(map[string]bool)
+	startDay, _ := time.Parse("2006-01-02", start)
+	endDay, _ := time.Parse("2006-01-02", end)
+	for day := startDay; !day.After(endDay); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		dates = append(dates, date)
+		meals := defaultMeals
+		if schedule := schedulesByDate[date]; schedule != nil && schedule.AvailableMeals != nil {
+			meals = parseMealTypes(*schedule.AvailableMeals)
+		}
+		mealsByDate[date] = meals
+		for _, meal := range meals {
+			mealSet[string(meal)] = true
+		}
+	}
+	mealTypes := make([]string, 0, len(mealSet))
+	for meal := range mealSet {
+		mealTypes = append(mealTypes, meal)
+	}
+
+	resolution, err := s.resolver.ResolveBatch(userIDs, dates, mealTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.snapshotRepo.FindByDateRangeForUsers(start, end, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	frozen := make(map[ParticipationKey]bool)
+	snapshotted := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		date := dateKey(snapshot.Date)
+		snapshotted[date+"|"+string(snapshot.MealType)] = true
+		for _, entry := range snapshot.Users {
+			frozen[ParticipationKey{UserID: entry.UserID.String(), Date: date, MealType: string(snapshot.MealType)}] = entry.IsParticipating
+		}
+	}
+
+	prices, err := s.priceRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	statements := make([]MonthlyStatement, 0, len(users))
+	for _, user := range users {
+		userID := user.ID.String()
+		statement := MonthlyStatement{
+			UserID:      userID,
+			Name:        user.Name,
+			Email:       user.Email,
+			Month:       month,
+			Currency:    s.currency,
+			PeriodStart: start,
+			PeriodEnd:   end,
+		}
+		byMeal := make(map[models.MealType]*StatementMealTotal)
+
+		for _, date := range dates {
+			for _, meal := range mealsByDate[date] {
+				key := ParticipationKey{UserID: userID, Date: date, MealType: string(meal)}
+				source := BillingSourceResolved
+				var participating bool
+				if snapshotted[date+"|"+string(meal)] {
+					// Users missing from the snapshot were not active when it was taken
+					source = BillingSourceSnapshot
+					participating = frozen[key]
+				} else {
+					res, _ := resolution.Get(userID, date, string(meal))
+					participating = res.IsParticipating
+				}
+				if !participating {
+					continue
+				}
+
+				line := StatementLine{Date: date, MealType: meal, Source: source}
+				if price := priceOn(prices, meal, date); price != nil {
+					line.Priced = true
+					line.UnitPrice = price.UnitPrice
+					line.CompanyShare = price.CompanyShare()
+					line.EmployeeShare = price.EmployeeShare()
+				}
+				addStatementLine(&statement, byMeal, line)
+				if withLines {
+					statement.Lines = append(statement.Lines, line)
+				}
+			}
+		}
+
+		statement.Totals.ByMeal = sortedMealTotals(byMeal)
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+// billingPeriod returns the first day of a month and the last day to bill, which is today for the current month
+func (s *billingService) billingPeriod(month string) (string, string, error) {
+	first, err := time.Parse("2006-01", month)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid month format, expected YYYY-MM")
+	}
+	last := first.AddDate(0, 1, -1)
+
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	if first.After(today) {
+		return "", "", fmt.Errorf("cannot bill a future month: %s", month)
+	}
+	if last.After(today) {
+		last = today
+	}
+	return first.Format("2006-01-02"), last.Format("2006-01-02"), nil
+}
+
+func addStatementLine(statement *MonthlyStatement, byMeal map[models.MealType]*StatementMealTotal, line StatementLine) {
+	total, ok := byMeal[line.MealType]
+	if !ok {
+		total = &StatementMealTotal{MealType: line.MealType}
+		byMeal[line.MealType] = total
+	}
+	total.Meals++
+	total.Total += line.UnitPrice
+	total.CompanyShare += line.CompanyShare
+	total.EmployeeShare += line.EmployeeShare
+
+	statement.Totals.Meals++
+	if !line.Priced {
+		statement.Totals.UnpricedMeals++
+	}
+	statement.Totals.Total += line.UnitPrice
+	statement.Totals.CompanyShare += line.CompanyShare
+	statement.Totals.EmployeeShare += line.EmployeeShare
+}
+
+func sortedMealTotals(byMeal map[models.MealType]*StatementMealTotal) []StatementMealTotal {
+	totals := make([]StatementMealTotal, 0, len(byMeal))
+	for _, total := range byMeal {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].MealType < totals[j].MealType
+	})
+	return totals
+}
+
+// priceOn returns the price of a meal in effect on a date, or nil if none was set yet.
+// prices must be ordered by meal type and effective date.
+func priceOn(prices []models.MealPrice, mealType models.MealType, date string) *models.MealPrice {
+	var current *models.MealPrice
+	for i := range prices {
+		if prices[i].MealType != mealType || dateKey(prices[i].EffectiveFrom) > date {
+			continue
+		}
+		current = &prices[i]
+	}
+	return current
+}
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"craftsbite-backend/internal/authz"
 	"craftsbite-backend/internal/models"
 	"craftsbite-backend/internal/repository"
+	"errors"
 	"fmt"
 	"time"
 
@@ -49,15 +51,19 @@ type bulkOptOutService struct {
 	bulkOptOutRepo repository.BulkOptOutRepository
 	historyRepo    repository.HistoryRepository
 	teamRepo       repository.TeamRepository
+	catalog        MealCatalog
+	authorizer     authz.Authorizer
 }
 
 // NewBulkOptOutService creates a new bulk opt-out service
-func NewBulkOptOutService(db *gorm.DB, bulkOptOutRepo repository.BulkOptOutRepository, historyRepo repository.HistoryRepository, teamRepo repository.TeamRepository) BulkOptOutService {
+func NewBulkOptOutService(db *gorm.DB, bulkOptOutRepo repository.BulkOptOutRepository, historyRepo repository.HistoryRepository, teamRepo repository.TeamRepository, catalog MealCatalog, authorizer authz.Authorizer) BulkOptOutService {
 	return &bulkOptOutService{
 		db:             db,
 		bulkOptOutRepo: bulkOptOutRepo,
 		historyRepo:    historyRepo,
 		teamRepo:       teamRepo,
+		catalog:        catalog,
+		authorizer:     authorizer,
 	}
 }
 
@@ -89,9 +95,9 @@ func (s *bulkOptOutService) CreateBulkOptOut(userID string, input CreateBulkOptO
 	}
 
 	// Validate meal type
-	mealType := models.MealType(input.MealType)
-	if !mealType.IsValid() {
-		return nil, fmt.Errorf("invalid meal_type: must be one of lunch, snacks, iftar, event_dinner, optional_dinner")
+	mealType, err := s.catalog.Validate(input.MealType)
+	if err != nil {
+		return nil, err
 	}
 
 	// Parse user UUID
@@ -181,9 +187,9 @@ func (s *bulkOptOutService) AdminBulkOptOut(actorID, actorRole string, input Adm
 
 	var mealTypes []models.MealType
 	for _, mt := range input.MealTypes {
-		mealType := models.MealType(mt)
-		if !mealType.IsValid() {
-			return nil, fmt.Errorf("invalid meal_type '%s': must be one of lunch, snacks, iftar, event_dinner, optional_dinner", mt)
+		mealType, err := s.catalog.Validate(mt)
+		if err != nil {
+			return nil, err
 		}
 		mealTypes = append(mealTypes, mealType)
 	}
@@ -276,16 +282,13 @@ func (s *bulkOptOutService) validateUsers(actorID, actorRole string, userIDs []s
 			continue
 		}
 
-		if actorRole == string(models.RoleTeamLead) {
-			isMember, err := s.teamRepo.IsUserInAnyTeamLedBy(actorID, userID)
-			if err != nil {
-				result.Failed = append(result.Failed, AdminBulkOptOutFailure{UserID: userID, Reason: "failed to verify team membership"})
-				continue
-			}
-			if !isMember {
-				result.Failed = append(result.Failed, AdminBulkOptOutFailure{UserID: userID, Reason: "user is not a member of your team"})
-				continue
+		if err := s.authorizer.Authorize(actorRole, actorID, authz.PermBulkOptOutManage, userID); err != nil {
+			reason := "failed to verify team membership"
+			if errors.Is(err, authz.ErrForbidden) {
+				reason = "user is not a member of your team"
 			}
+			result.Failed = append(result.Failed, AdminBulkOptOutFailure{UserID: userID, Reason: reason})
+			continue
 		}
 
 		result.Succeeded = append(result.Succeeded, userID)
@@ -0,0 +1,610 @@
+package services
+
+import (
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"craftsbite-backend/pkg/logger"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxMealCapacity bounds the seats that can be configured for a meal
+	maxMealCapacity = 10000
+	// seatCountTTL is how long a meal's cached seat count is trusted before the seats are
+	// counted again, so counts drift no further than the changes of a few minutes
+	seatCountTTL = 5 * time.Minute
+	// seatRecountMargin is how many free seats the cached count must show for a seat to be
+	// handed out without counting. Changes that bypass the capacity service, such as deleted
+	// bulk opt-outs, participation rules, default preferences and new users, can fill seats
+	// the cache still shows as free, so a nearly full meal is always counted live.
+	seatRecountMargin = 20
+)
+
+// ParticipationWrite records a user's participation within the transaction that holds the
+// meal's seat lock
+type ParticipationWrite func(tx *gorm.DB) error
+
+// MealCapacityInput represents input for setting a meal's capacity on a day
+type MealCapacityInput struct {
+	Capacity *int `json:"capacity" binding:"required"`
+}
+
+// MealCapacityStatus describes how many of a meal's seats are taken on a date
+type MealCapacityStatus struct {
+	MealType   models.MealType `json:"meal_type"`
+	Capacity   int             `json:"capacity"`
+	Taken      int             `json:"taken"`
+	Available  int             `json:"available"`
+	Waitlisted int             `json:"waitlisted"`
+}
+
+// CapacityService manages seat limits on scheduled days and the waitlists behind them. Meals without a
+// capacity are unlimited. Seats are the active users resolved as participating; guests are not counted.
+type CapacityService interface {
+	GetDayCapacities(date string) ([]MealCapacityStatus, error)
+	SetCapacity(adminID, date, mealType string, input MealCapacityInput) (*MealCapacityStatus, error)
+	// DeleteCapacity makes a meal unlimited again and promotes everyone on its waitlist
+	DeleteCapacity(date, mealType string) error
+	GetWaitlist(date, mealType string) ([]models.MealWaitlistEntry, error)
+
+	// RequestSeat gives a user opting in a seat if one is free, runs optIn to record it and returns 0.
+	// Otherwise it queues them and returns their waitlist position.
+	RequestSeat(userID, date, mealType string, optIn ParticipationWrite) (int, error)
+	// UpdateSeat runs write, which sets a user's participation without a capacity check (an
+	// opt-out or an override), takes them off the waitlist and promotes waitlisted users, in
+	// order, into any seat that frees up
+	UpdateSeat(userID, date, mealType string, participating bool, write ParticipationWrite) error
+	// WaitlistPositions returns the user's position on each meal's waitlist they are on for a date
+	WaitlistPositions(userID, date string) (map[models.MealType]int, error)
+}
+
+type capacityService struct {
+	db            *gorm.DB
+	capacityRepo  repository.CapacityRepository
+	waitlistRepo  repository.WaitlistRepository
+	scheduleRepo  repository.ScheduleRepository
+	userRepo      repository.UserRepository
+	resolver      ParticipationResolver
+	catalog       MealCatalog
+	notifications NotificationService
+}
+
+// NewCapacityService creates a new capacity service
+func NewCapacityService(
+	db *gorm.DB,
+	capacityRepo repository.CapacityRepository,
+	waitlistRepo repository.WaitlistRepository,
+	scheduleRepo repository.ScheduleRepository,
+	userRepo repository.UserRepository,
+	resolver ParticipationResolver,
+	catalog MealCatalog,
+	notifications NotificationService,
+) CapacityService {
+	return &capacityService{
+		db:            db,
+		capacityRepo:  capacityRepo,
+		waitlistRepo:  waitlistRepo,
+		scheduleRepo:  scheduleRepo,
+		userRepo:      userRepo,
+		resolver:      resolver,
+		catalog:       catalog,
+		notifications: notifications,
+	}
+}
+
+// GetDayCapacities returns the seat usage of every meal with a capacity on a date
+func (s *capacityService) GetDayCapacities(date string) ([]MealCapacityStatus, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	schedule, err := s.scheduleRepo.FindByDate(date)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return []MealCapacityStatus{}, nil
+	}
+
+	capacities, err := s.capacityRepo.FindBySchedule(schedule.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	return s.statuses(date, capacities)
+}
+
+// SetCapacity creates or replaces a meal's capacity on a date. Raising it promotes waitlisted users
+// into the new seats; lowering it never removes anyone who already has a seat.
+func (s *capacityService) SetCapacity(adminID, date, mealType string, input MealCapacityInput) (*MealCapacityStatus, error) {
+	schedule, err := s.storedSchedule(date)
+	if err != nil {
+		return nil, err
+	}
+	meal, err := s.catalog.Validate(mealType)
+	if err != nil {
+		return nil, err
+	}
+	if *input.Capacity < 0 || *input.Capacity > maxMealCapacity {
+		return nil, fmt.Errorf("capacity must be between 0 and %d", maxMealCapacity)
+	}
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid admin ID")
+	}
+
+	capacity, err := s.capacityRepo.Find(schedule.ID.String(), string(meal))
+	if err != nil {
+		return nil, err
+	}
+	if capacity == nil {
+		capacity = &models.MealCapacity{DayScheduleID: schedule.ID, MealType: meal}
+	}
+	capacity.Capacity = *input.Capacity
+	capacity.UpdatedBy = &adminUUID
+
+	if err := s.capacityRepo.Save(capacity); err != nil {
+		return nil, err
+	}
+	if err := s.fillSeats(date, string(meal)); err != nil {
+		return nil, err
+	}
+
+	statuses, err := s.statuses(date, []models.MealCapacity{*capacity})
+	if err != nil {
+		return nil, err
+	}
+	return &statuses[0], nil
+}
+
+func (s *capacityService) DeleteCapacity(date, mealType string) error {
+	if _, err := s.storedSchedule(date); err != nil {
+		return err
+	}
+
+	var promoted []models.MealWaitlistEntry
+	err := s.withSeatLock(date, mealType, func(tx *gorm.DB, capacity *models.MealCapacity) error {
+		if capacity == nil {
+			return fmt.Errorf("no capacity set for %s on %s", mealType, date)
+		}
+		if err := repository.NewCapacityRepository(tx).Delete(capacity.ID.String()); err != nil {
+			return err
+		}
+		var err error
+		promoted, err = s.promote(tx, date, mealType, math.MaxInt)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.notifyPromotions(promoted)
+	return nil
+}
+
+func (s *capacityService) GetWaitlist(date, mealType string) ([]models.MealWaitlistEntry, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	return s.waitlistRepo.FindByDateAndMeal(date, mealType)
+}
+
+func (s *capacityService) RequestSeat(userID, date, mealType string, optIn ParticipationWrite) (int, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user ID")
+	}
+
+	position := 0
+	var promoted []models.MealWaitlistEntry
+	err = s.withSeatLock(date, mealType, func(tx *gorm.DB, capacity *models.MealCapacity) error {
+		if capacity == nil {
+			return optIn(tx)
+		}
+
+		waitlist := repository.NewWaitlistRepository(tx)
+		entry, err := waitlist.Find(userID, date, mealType)
+		if err != nil {
+			return err
+		}
+		if entry != nil {
+			position, err = s.position(waitlist, entry)
+			return err
+		}
+
+		held, err := s.holdsSeat(userID, date, mealType)
+		if err != nil {
+			return err
+		}
+		if held {
+			return optIn(tx)
+		}
+
+		taken, err := s.seatsTaken(capacity, date)
+		if err != nil {
+			return err
+		}
+		// Seats that freed up without an opt-out, e.g. through a bulk opt-out, go to the waitlist first
+		promoted, err = s.promote(tx, date, mealType, capacity.Capacity-taken)
+		if err != nil {
+			return err
+		}
+		taken += len(promoted)
+
+		if taken < capacity.Capacity {
+			if err := optIn(tx); err != nil {
+				return err
+			}
+			return s.saveSeatCount(tx, capacity, taken+1)
+		}
+
+		entry = &models.MealWaitlistEntry{UserID: userUUID, Date: date, MealType: models.MealType(mealType)}
+		if err := waitlist.Create(entry); err != nil {
+			return err
+		}
+		history := &models.MealParticipationHistory{
+			ID:       uuid.New(),
+			UserID:   userUUID,
+			Date:     date,
+			MealType: models.MealType(mealType),
+			Action:   models.HistoryActionWaitlisted,
+		}
+		if err := repository.NewHistoryRepository(tx).Create(history); err != nil {
+			return err
+		}
+		if position, err = s.position(waitlist, entry); err != nil {
+			return err
+		}
+		return s.saveSeatCount(tx, capacity, taken)
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.notifyPromotions(promoted)
+	return position, nil
+}
+
+func (s *capacityService) UpdateSeat(userID, date, mealType string, participating bool, write ParticipationWrite) error {
+	var promoted []models.MealWaitlistEntry
+	err := s.withSeatLock(date, mealType, func(tx *gorm.DB, capacity *models.MealCapacity) error {
+		waitlist := repository.NewWaitlistRepository(tx)
+		entry, err := waitlist.Find(userID, date, mealType)
+		if err != nil {
+			return err
+		}
+		if entry != nil {
+			if _, err := waitlist.Delete(entry.ID.String()); err != nil {
+				return err
+			}
+		}
+
+		if capacity == nil {
+			if err := write(tx); err != nil {
+				return err
+			}
+			// Without a capacity there is no reason left to wait
+			promoted, err = s.promote(tx, date, mealType, math.MaxInt)
+			return err
+		}
+
+		held, err := s.holdsSeat(userID, date, mealType)
+		if err != nil {
+			return err
+		}
+		taken, err := s.seatsTaken(capacity, date)
+		if err != nil {
+			return err
+		}
+		if err := write(tx); err != nil {
+			return err
+		}
+		switch {
+		case held && !participating:
+			taken--
+		case !held && participating:
+			taken++
+		}
+
+		promoted, err = s.promote(tx, date, mealType, capacity.Capacity-taken)
+		if err != nil {
+			return err
+		}
+		return s.saveSeatCount(tx, capacity, taken+len(promoted))
+	})
+	if err != nil {
+		return err
+	}
+	s.notifyPromotions(promoted)
+	return nil
+}
+
+// fillSeats counts a meal's seats again and promotes waitlisted users, in order, into the free ones
+func (s *capacityService) fillSeats(date, mealType string) error {
+	var promoted []models.MealWaitlistEntry
+	err := s.withSeatLock(date, mealType, func(tx *gorm.DB, capacity *models.MealCapacity) error {
+		var err error
+		if capacity == nil {
+			promoted, err = s.promote(tx, date, mealType, math.MaxInt)
+			return err
+		}
+
+		capacity.SeatsCountedAt = nil
+		taken, err := s.seatsTaken(capacity, date)
+		if err != nil {
+			return err
+		}
+		promoted, err = s.promote(tx, date, mealType, capacity.Capacity-taken)
+		if err != nil {
+			return err
+		}
+		return s.saveSeatCount(tx, capacity, taken+len(promoted))
+	})
+	if err != nil {
+		return err
+	}
+	s.notifyPromotions(promoted)
+	return nil
+}
+
+func (s *capacityService) WaitlistPositions(userID, date string) (map[models.MealType]int, error) {
+	entries, err := s.waitlistRepo.FindByUserAndDate(userID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[models.MealType]int, len(entries))
+	for i := range entries {
+		position, err := s.position(s.waitlistRepo, &entries[i])
+		if err != nil {
+			return nil, err
+		}
+		positions[entries[i].MealType] = position
+	}
+	return positions, nil
+}
+
+// promote moves up to free users off the front of a meal's waitlist into seats and returns them.
+// It runs under the meal's seat lock; the promoted users are notified once it is released.
+func (s *capacityService) promote(tx *gorm.DB, date, mealType string, free int) ([]models.MealWaitlistEntry, error) {
+	if free <= 0 {
+		return nil, nil
+	}
+
+	entries, err := repository.NewWaitlistRepository(tx).FindByDateAndMeal(date, mealType)
+	if err != nil {
+		return nil, err
+	}
+	var promoted []models.MealWaitlistEntry
+	for _, entry := range entries {
+		if len(promoted) == free {
+			break
+		}
+		claimed, err := s.promoteEntry(tx, entry)
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			promoted = append(promoted, entry)
+		}
+	}
+	return promoted, nil
+}
+
+// promoteEntry takes a user off the waitlist and opts them in, and records the promotion.
+// It returns false when the entry was already gone.
+func (s *capacityService) promoteEntry(tx *gorm.DB, entry models.MealWaitlistEntry) (bool, error) {
+	userID := entry.UserID.String()
+	date := dateKey(entry.Date)
+
+	// Removing the entry first claims it, so it cannot be promoted twice
+	claimed, err := repository.NewWaitlistRepository(tx).Delete(entry.ID.String())
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	mealRepo := repository.NewMealRepository(tx)
+	existing, err := mealRepo.FindByUserDateMeal(userID, date, string(entry.MealType))
+	if err != nil {
+		return false, fmt.Errorf("failed to check existing participation: %w", err)
+	}
+	participationID := uuid.New()
+	if existing != nil {
+		participationID = existing.ID
+	}
+
+	participation := &models.MealParticipation{
+		ID:              participationID,
+		UserID:          entry.UserID,
+		Date:            date,
+		MealType:        entry.MealType,
+		IsParticipating: true,
+	}
+	if err := mealRepo.CreateOrUpdate(participation); err != nil {
+		return false, err
+	}
+
+	reason := "Promoted from the waitlist"
+	history := &models.MealParticipationHistory{
+		ID:       uuid.New(),
+		UserID:   entry.UserID,
+		Date:     date,
+		MealType: entry.MealType,
+		Action:   models.HistoryActionPromoted,
+		Reason:   &reason,
+	}
+	if err := repository.NewHistoryRepository(tx).Create(history); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// notifyPromotions tells promoted users they have a seat. The seat is taken either way; a lost
+// notification only means the user finds out from their status.
+func (s *capacityService) notifyPromotions(entries []models.MealWaitlistEntry) {
+	for _, entry := range entries {
+		if err := s.notifyPromoted(entry.UserID, dateKey(entry.Date), entry.MealType); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to notify user %s of waitlist promotion: %v", entry.UserID, err))
+		}
+	}
+}
+
+func (s *capacityService) notifyPromoted(userID uuid.UUID, date string, mealType models.MealType) error {
+	label := string(mealType)
+	definition, err := s.catalog.Lookup(string(mealType))
+	if err != nil {
+		return err
+	}
+	if definition != nil {
+		label = definition.Label
+	}
+
+	humanDate := date
+	if parsed, err := time.Parse("2006-01-02", date); err == nil {
+		humanDate = parsed.Format("Monday, 2 January")
+	}
+
+	return s.notifications.Notify(
+		userID,
+		models.NotificationWaitlistPromoted,
+		fmt.Sprintf("You have a seat for %s", label),
+		fmt.Sprintf("A seat opened up for %s on %s and you have been moved off the waitlist. Opt out before the cutoff if you can no longer make it.", label, humanDate),
+	)
+}
+
+// position returns an entry's 1-based place on its meal's waitlist
+func (s *capacityService) position(waitlist repository.WaitlistRepository, entry *models.MealWaitlistEntry) (int, error) {
+	entries, err := waitlist.FindByDateAndMeal(dateKey(entry.Date), string(entry.MealType))
+	if err != nil {
+		return 0, err
+	}
+	for i := range entries {
+		if entries[i].ID == entry.ID {
+			return i + 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// statuses resolves seat usage and waitlist length for each capacity on a date
+func (s *capacityService) statuses(date string, capacities []models.MealCapacity) ([]MealCapacityStatus, error) {
+	mealTypes := make([]string, len(capacities))
+	for i, capacity := range capacities {
+		mealTypes[i] = string(capacity.MealType)
+	}
+	holders, err := s.seatHolders(date, mealTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MealCapacityStatus, 0, len(capacities))
+	for _, capacity := range capacities {
+		waitlist, err := s.waitlistRepo.FindByDateAndMeal(date, string(capacity.MealType))
+		if err != nil {
+			return nil, err
+		}
+		taken := len(holders[string(capacity.MealType)])
+		statuses = append(statuses, MealCapacityStatus{
+			MealType:   capacity.MealType,
+			Capacity:   capacity.Capacity,
+			Taken:      taken,
+			Available:  max(capacity.Capacity-taken, 0),
+			Waitlisted: len(waitlist),
+		})
+	}
+	return statuses, nil
+}
+
+// seatHolders returns, per meal type, the set of active users resolved as participating on a date
+func (s *capacityService) seatHolders(date string, mealTypes []string) (map[string]map[string]bool, error) {
+	users, err := s.userRepo.FindAll(map[string]interface{}{"active": true})
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID.String()
+	}
+
+	resolution, err := s.resolver.ResolveBatch(userIDs, []string{date}, mealTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	holders := make(map[string]map[string]bool, len(mealTypes))
+	for _, mealType := range mealTypes {
+		holders[mealType] = map[string]bool{}
+		for _, userID := range userIDs {
+			if res, ok := resolution.Get(userID, date, mealType); ok && res.IsParticipating {
+				holders[mealType][userID] = true
+			}
+		}
+	}
+	return holders, nil
+}
+
+// withSeatLock runs fn in a transaction that holds a row lock on the meal's capacity, so the
+// seats of a meal are counted and taken by one request at a time. capacity is nil for an
+// unlimited meal, which needs no lock.
+func (s *capacityService) withSeatLock(date, mealType string, fn func(tx *gorm.DB, capacity *models.MealCapacity) error) error {
+	schedule, err := s.scheduleRepo.FindByDate(date)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var capacity *models.MealCapacity
+		if schedule != nil {
+			capacity, err = repository.NewCapacityRepository(tx).FindForUpdate(schedule.ID.String(), mealType)
+			if err != nil {
+				return err
+			}
+		}
+		return fn(tx, capacity)
+	})
+}
+
+// seatsTaken returns a meal's cached seat count. The seats are counted again when the cache
+// is older than seatCountTTL or shows fewer than seatRecountMargin free seats. Callers hold
+// the meal's seat lock.
+func (s *capacityService) seatsTaken(capacity *models.MealCapacity, date string) (int, error) {
+	fresh := capacity.SeatsCountedAt != nil && time.Since(*capacity.SeatsCountedAt) < seatCountTTL
+	if fresh && capacity.Capacity-capacity.SeatsTaken >= seatRecountMargin {
+		return capacity.SeatsTaken, nil
+	}
+
+	mealType := string(capacity.MealType)
+	holders, err := s.seatHolders(date, []string{mealType})
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	capacity.SeatsCountedAt = &now
+	return len(holders[mealType]), nil
+}
+
+// saveSeatCount stores a meal's seat count after a change made under its seat lock
+func (s *capacityService) saveSeatCount(tx *gorm.DB, capacity *models.MealCapacity, taken int) error {
+	return repository.NewCapacityRepository(tx).UpdateSeatCount(capacity.ID.String(), max(taken, 0), *capacity.SeatsCountedAt)
+}
+
+// holdsSeat reports whether a user is resolved as participating in a meal
+func (s *capacityService) holdsSeat(userID, date, mealType string) (bool, error) {
+	participating, _, err := s.resolver.ResolveParticipation(userID, date, mealType)
+	return participating, err
+}
+
+func (s *capacityService) storedSchedule(date string) (*models.DaySchedule, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	schedule, err := s.scheduleRepo.FindByDate(date)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, fmt.Errorf("no day schedule exists for %s, create one before setting capacities", date)
+	}
+	return schedule, nil
+}
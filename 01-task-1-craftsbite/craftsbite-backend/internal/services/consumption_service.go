@@ -0,0 +1,507 @@
+package services
+
+import (
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"craftsbite-backend/internal/utils"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxConsumptionReportDays bounds the range of a consumption report
+const maxConsumptionReportDays = 92
+
+// CheckInToken is the value a user shows as a QR code at the canteen
+type CheckInToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CheckInInput identifies the user being served by exactly one of a QR token, a user ID or an email.
+// Date defaults to today.
+type CheckInInput struct {
+	Token    string `json:"token"`
+	UserID   string `json:"user_id"`
+	Email    string `json:"email"`
+	MealType string `json:"meal_type" binding:"required"`
+	Date     string `json:"date"`
+}
+
+// CheckInResult is a recorded check-in and whether the user had opted in; a check-in without one is a walk-in
+type CheckInResult struct {
+	Consumption *models.MealConsumption `json:"consumption"`
+	OptedIn     bool                    `json:"opted_in"`
+}
+
+// ConsumptionCounts compares intent with what happened. No-shows opted in but never checked in;
+// walk-ins checked in without opting in. NoShowRate is NoShows / OptedIn.
+type ConsumptionCounts struct {
+	OptedIn    int     `json:"opted_in"`
+	CheckedIn  int     `json:"checked_in"`
+	NoShows    int     `json:"no_shows"`
+	WalkIns    int     `json:"walk_ins"`
+	NoShowRate float64 `json:"no_show_rate"`
+}
+
+// DailyConsumption is the consumption of one date, in total and per meal
+type DailyConsumption struct {
+	Date   string                                 `json:"date"`
+	Totals ConsumptionCounts                      `json:"totals"`
+	Meals  map[models.MealType]*ConsumptionCounts `json:"meals"`
+}
+
+// TeamConsumption is the consumption of a team's members over the report range
+type TeamConsumption struct {
+	TeamID   string            `json:"team_id"`
+	TeamName string            `json:"team_name"`
+	Totals   ConsumptionCounts `json:"totals"`
+}
+
+// UserConsumption is a user's consumption over the report range
+type UserConsumption struct {
+	UserID string            `json:"user_id"`
+	Name   string            `json:"name"`
+	Email  string            `json:"email"`
+	Totals ConsumptionCounts `json:"totals"`
+}
+
+// ConsumptionReport compares opted-in with checked-in meals over a date range. Users are listed
+// most no-shows first and only when they opted in or checked in at least once.
+type ConsumptionReport struct {
+	StartDate string             `json:"start_date"`
+	EndDate   string             `json:"end_date"`
+	Totals    ConsumptionCounts  `json:"totals"`
+	Days      []DailyConsumption `json:"days"`
+	Teams     []TeamConsumption  `json:"teams"`
+	Users     []UserConsumption  `json:"users"`
+}
+
+// ConsumptionService records meal check-ins and reports them against participation
+type ConsumptionService interface {
+	IssueCheckInToken(userID string) (*CheckInToken, error)
+	CheckIn(staffID string, input CheckInInput) (*CheckInResult, error)
+	ListCheckIns(date, mealType string) ([]models.MealConsumption, error)
+	// UndoCheckIn removes a check-in recorded by mistake
+	UndoCheckIn(id string) error
+	// GetReport reports consumption up to today; with teamID set only that team's members are counted
+	GetReport(startDate, endDate, teamID string) (*ConsumptionReport, error)
+}
+
+type consumptionService struct {
+	consumptionRepo repository.ConsumptionRepository
+	userRepo        repository.UserRepository
+	teamRepo        repository.TeamRepository
+	snapshotRepo    repository.HeadcountSnapshotRepository
+	calendar        ScheduleCalendar
+	resolver        ParticipationResolver
+	catalog         MealCatalog
+	tokenSecret     string
+	tokenTTL        time.Duration
+}
+
+// NewConsumptionService creates a new consumption service
+func NewConsumptionService(
+	consumptionRepo repository.ConsumptionRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	snapshotRepo repository.HeadcountSnapshotRepository,
+	calendar ScheduleCalendar,
+	resolver ParticipationResolver,
+	catalog MealCatalog,
+	cfg *config.Config,
+) ConsumptionService {
+	return &consumptionService{
+		consumptionRepo: consumptionRepo,
+		userRepo:        userRepo,
+		teamRepo:        teamRepo,
+		snapshotRepo:    snapshotRepo,
+		calendar:        calendar,
+		resolver:        resolver,
+		catalog:         catalog,
+		tokenSecret:     cfg.JWT.Secret,
+		tokenTTL:        cfg.Meal.CheckInTokenTTL,
+	}
+}
+
+func (s *consumptionService) IssueCheckInToken(userID string) (*CheckInToken, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+	expiresAt := time.Now().Add(s.tokenTTL)
+	return &CheckInToken{
+		Token:     utils.GenerateCheckInToken(userID, s.tokenSecret, expiresAt),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *consumptionService) CheckIn(staffID string, input CheckInInput) (*CheckInResult, error) {
+	staffUUID, err := uuid.Parse(staffID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid staff ID")
+	}
+
+	today := time.Now().Format("2006-01-02")
+	date := input.Date
+	if date == "" {
+		date = today
+	}
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	if date > today {
+		return nil, fmt.Errorf("cannot check in for a future date")
+	}
+	mealType, err := s.catalog.Validate(input.MealType)
+	if err != nil {
+		return nil, err
+	}
+
+	user, method, err := s.identify(input)
+	if err != nil {
+		return nil, err
+	}
+	if method == models.CheckInMethodQR && date != today {
+		return nil, fmt.Errorf("QR check-ins are only accepted for today's meals")
+	}
+	userID := user.ID.String()
+
+	existing, err := s.consumptionRepo.Find(userID, date, string(mealType))
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%s already checked in for %s at %s", user.Name, mealType, existing.CheckedInAt.Format("15:04"))
+	}
+
+	optedIn, _, err := s.resolver.ResolveParticipation(userID, date, string(mealType))
+	if err != nil {
+		return nil, err
+	}
+
+	consumption := &models.MealConsumption{
+		UserID:      user.ID,
+		Date:        date,
+		MealType:    mealType,
+		Method:      method,
+		CheckedInBy: &staffUUID,
+	}
+	if err := s.consumptionRepo.Create(consumption); err != nil {
+		return nil, err
+	}
+	consumption.User = user
+
+	return &CheckInResult{Consumption: consumption, OptedIn: optedIn}, nil
+}
+
+// identify finds the active user a check-in is for and how they were identified
+func (s *consumptionService) identify(input CheckInInput) (*models.User, string, error) {
+	token := strings.TrimSpace(input.Token)
+	userID := strings.TrimSpace(input.UserID)
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+
+	given := 0
+	for _, value := range []string{token, userID, email} {
+		if value != "" {
+			given++
+		}
+	}
+	if given != 1 {
+		return nil, "", fmt.Errorf("provide exactly one of token, user_id or email")
+	}
+
+	var user *models.User
+	var err error
+	method := models.CheckInMethodManual
+	switch {
+	case token != "":
+		method = models.CheckInMethodQR
+		tokenUserID, tokenErr := utils.ValidateCheckInToken(token, s.tokenSecret, time.Now())
+		if errors.Is(tokenErr, utils.ErrExpiredCheckInToken) {
+			return nil, "", fmt.Errorf("check-in code has expired, ask the user to refresh it")
+		}
+		if tokenErr != nil {
+			return nil, "", fmt.Errorf("invalid check-in code")
+		}
+		user, err = s.userRepo.FindByID(tokenUserID)
+	case userID != "":
+		if _, parseErr := uuid.Parse(userID); parseErr != nil {
+			return nil, "", fmt.Errorf("invalid user ID")
+		}
+		user, err = s.userRepo.FindByID(userID)
+	default:
+		user, err = s.userRepo.FindByEmail(email)
+	}
+	if err != nil || user == nil {
+		return nil, "", fmt.Errorf("user not found")
+	}
+	if !user.Active {
+		return nil, "", fmt.Errorf("user %s is deactivated", user.Name)
+	}
+	return user, method, nil
+}
+
+func (s *consumptionService) ListCheckIns(date, mealType string) ([]models.MealConsumption, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	return s.consumptionRepo.FindByDateAndMeal(date, mealType)
+}
+
+func (s *consumptionService) UndoCheckIn(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("invalid check-in ID")
+	}
+	consumption, err := s.consumptionRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if consumption == nil {
+		return fmt.Errorf("check-in not found")
+	}
+	return s.consumptionRepo.Delete(id)
+}
+
+func (s *consumptionService) GetReport(startDate, endDate, teamID string) (*ConsumptionReport, error) {
+	startDay, endDay, err := parseCalendarRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if endDay.Sub(startDay) >= maxConsumptionReportDays*24*time.Hour {
+		return nil, fmt.Errorf("date range cannot exceed %d days", maxConsumptionReportDays)
+	}
+
+	// Nothing can have been eaten yet after today
+	today := time.Now().Format("2006-01-02")
+	if startDate > today {
+		return nil, fmt.Errorf("report range must not start in the future")
+	}
+	if endDate > today {
+		endDate = today
+		endDay, _ = time.Parse("2006-01-02", today)
+	}
+
+	users, teams, err := s.reportUsers(teamID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID.String()
+	}
+
+	schedules, err := s.calendar.FindByDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	schedulesByDate := make(map[string]*models.DaySchedule, len(schedules))
+	for i := range schedules {
+		schedulesByDate[dateKey(schedules[i].Date)] = &schedules[i]
+	}
+	defaultMeals, err := s.catalog.DefaultMeals()
+	if err != nil {
+		return nil, err
+	}
+
+	consumptions, err := s.consumptionRepo.FindByDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	consumed := make(map[ParticipationKey]bool, len(consumptions))
+	for _, consumption := range consumptions {
+		consumed[ParticipationKey{UserID: consumption.UserID.String(), Date: dateKey(consumption.Date), MealType: string(consumption.MealType)}] = true
+	}
+
+	// Meals served on each date plus any meal someone checked in for, and their union for one batch resolution
+	var dates []string
+	mealsByDate := make(map[string][]models.MealType)
+	mealSet := make(map[string]bool)
+	for day := startDay; !day.After(endDay); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		dates = append(dates, date)
+		meals := defaultMeals
+		if schedule := schedulesByDate[date]; schedule != nil && schedule.AvailableMeals != nil {
+			meals = parseMealTypes(*schedule.AvailableMeals)
+		}
+		mealsByDate[date] = append([]models.MealType{}, meals...)
+		for _, meal := range meals {
+			mealSet[string(meal)] = true
+		}
+	}
+	for key := range consumed {
+		if !containsMeal(mealsByDate[key.Date], models.MealType(key.MealType)) {
+			mealsByDate[key.Date] = append(mealsByDate[key.Date], models.MealType(key.MealType))
+			mealSet[key.MealType] = true
+		}
+	}
+	mealTypes := make([]string, 0, len(mealSet))
+	for meal := range mealSet {
+		mealTypes = append(mealTypes, meal)
+	}
+
+	resolution, err := s.resolver.ResolveBatch(userIDs, dates, mealTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshots freeze who had opted in at the cutoff, so later edits do not turn into no-shows
+	snapshots, err := s.snapshotRepo.FindByDateRangeForUsers(startDate, endDate, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	frozen := make(map[ParticipationKey]bool)
+	snapshotted := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		date := dateKey(snapshot.Date)
+		snapshotted[date+"|"+string(snapshot.MealType)] = true
+		for _, entry := range snapshot.Users {
+			frozen[ParticipationKey{UserID: entry.UserID.String(), Date: date, MealType: string(snapshot.MealType)}] = entry.IsParticipating
+		}
+	}
+
+	report := &ConsumptionReport{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Days:      make([]DailyConsumption, 0, len(dates)),
+		Teams:     []TeamConsumption{},
+		Users:     []UserConsumption{},
+	}
+	byUser := make(map[string]*ConsumptionCounts, len(users))
+	byTeam := make(map[string]*ConsumptionCounts, len(teams))
+	for _, user := range users {
+		byUser[user.ID.String()] = &ConsumptionCounts{}
+	}
+	for _, team := range teams {
+		byTeam[team.ID.String()] = &ConsumptionCounts{}
+	}
+	teamsByUser := make(map[string][]string)
+	for _, team := range teams {
+		for _, member := range team.Members {
+			teamsByUser[member.ID.String()] = append(teamsByUser[member.ID.String()], team.ID.String())
+		}
+	}
+
+	for _, date := range dates {
+		day := DailyConsumption{Date: date, Meals: make(map[models.MealType]*ConsumptionCounts)}
+		for _, meal := range mealsByDate[date] {
+			counts := &ConsumptionCounts{}
+			for _, userID := range userIDs {
+				key := ParticipationKey{UserID: userID, Date: date, MealType: string(meal)}
+				var optedIn bool
+				if snapshotted[date+"|"+string(meal)] {
+					optedIn = frozen[key]
+				} else {
+					res, _ := resolution.Get(userID, date, string(meal))
+					optedIn = res.IsParticipating
+				}
+				checkedIn := consumed[key]
+				if !optedIn && !checkedIn {
+					continue
+				}
+
+				counts.add(optedIn, checkedIn)
+				day.Totals.add(optedIn, checkedIn)
+				report.Totals.add(optedIn, checkedIn)
+				byUser[userID].add(optedIn, checkedIn)
+				for _, id := range teamsByUser[userID] {
+					byTeam[id].add(optedIn, checkedIn)
+				}
+			}
+			counts.finish()
+			day.Meals[meal] = counts
+		}
+		day.Totals.finish()
+		report.Days = append(report.Days, day)
+	}
+	report.Totals.finish()
+
+	for _, team := range teams {
+		totals := byTeam[team.ID.String()]
+		totals.finish()
+		report.Teams = append(report.Teams, TeamConsumption{TeamID: team.ID.String(), TeamName: team.Name, Totals: *totals})
+	}
+	for _, user := range users {
+		totals := byUser[user.ID.String()]
+		if totals.OptedIn == 0 && totals.CheckedIn == 0 {
+			continue
+		}
+		totals.finish()
+		report.Users = append(report.Users, UserConsumption{UserID: user.ID.String(), Name: user.Name, Email: user.Email, Totals: *totals})
+	}
+	sort.SliceStable(report.Users, func(i, j int) bool {
+		if report.Users[i].Totals.NoShows != report.Users[j].Totals.NoShows {
+			return report.Users[i].Totals.NoShows > report.Users[j].Totals.NoShows
+		}
+		return report.Users[i].Name < report.Users[j].Name
+	})
+
+	return report, nil
+}
+
+// reportUsers returns the active users a report covers and the teams to break it down by
+func (s *consumptionService) reportUsers(teamID string) ([]models.User, []models.Team, error) {
+	if teamID == "" {
+		users, err := s.userRepo.FindAll(map[string]interface{}{"active": true})
+		if err != nil {
+			return nil, nil, err
+		}
+		teams, err := s.teamRepo.FindAllWithMembers()
+		if err != nil {
+			return nil, nil, err
+		}
+		return users, teams, nil
+	}
+
+	if _, err := uuid.Parse(teamID); err != nil {
+		return nil, nil, fmt.Errorf("invalid team ID")
+	}
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		return nil, nil, err
+	}
+	users := make([]models.User, 0, len(team.Members))
+	for _, member := range team.Members {
+		if member.Active {
+			users = append(users, member)
+		}
+	}
+	team.Members = users
+	return users, []models.Team{*team}, nil
+}
+
+func (c *ConsumptionCounts) add(optedIn, checkedIn bool) {
+	switch {
+	case optedIn && checkedIn:
+		c.OptedIn++
+		c.CheckedIn++
+	case optedIn:
+		c.OptedIn++
+		c.NoShows++
+	case checkedIn:
+		c.CheckedIn++
+		c.WalkIns++
+	}
+}
+
+// finish computes the no-show rate once all meals have been added
+func (c *ConsumptionCounts) finish() {
+	c.NoShowRate = 0
+	if c.OptedIn > 0 {
+		c.NoShowRate = math.Round(float64(c.NoShows)/float64(c.OptedIn)*10000) / 10000
+	}
+}
+
+func containsMeal(meals []models.MealType, meal models.MealType) bool {
+	for _, m := range meals {
+		if m == meal {
+			return true
+		}
+	}
+	return false
+}
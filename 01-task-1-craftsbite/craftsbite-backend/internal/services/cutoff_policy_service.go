@@ -0,0 +1,291 @@
+package services
+
+import (
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxCutoffDaysBefore bounds how far ahead of a meal a deadline can be set
+const maxCutoffDaysBefore = 30
+
+// Sources of an effective cutoff
+const (
+	CutoffSourceSchedule = "schedule"
+	CutoffSourcePolicy   = "policy"
+	CutoffSourceDefault  = "default"
+)
+
+// CutoffPolicyInput represents input for setting a cutoff policy or a per-day override
+type CutoffPolicyInput struct {
+	DaysBefore *int   `json:"days_before" binding:"required"`
+	CutoffTime string `json:"cutoff_time" binding:"required"`
+}
+
+// EffectiveCutoff describes the deadline rule that applies to a meal type and where it came from.
+// Deadline is only set when the cutoff was resolved for a specific date.
+type EffectiveCutoff struct {
+	MealType   models.MealType `json:"meal_type"`
+	DaysBefore int             `json:"days_before"`
+	CutoffTime string          `json:"cutoff_time"`
+	Timezone   string          `json:"timezone"`
+	Source     string          `json:"source"`
+	Deadline   *time.Time      `json:"deadline,omitempty"`
+}
+
+// CutoffPolicyService resolves and manages per-meal change deadlines. A meal's deadline comes from an
+// override on the stored day schedule, else the meal type's policy, else the global MEAL_CUTOFF_TIME
+// on the previous day.
+type CutoffPolicyService interface {
+	ListPolicies() ([]EffectiveCutoff, error)
+	SetPolicy(adminID, mealType string, input CutoffPolicyInput) (*models.MealCutoffPolicy, error)
+	DeletePolicy(mealType string) error
+
+	GetDayCutoffs(date string) ([]EffectiveCutoff, error)
+	SetDayOverride(adminID, date, mealType string, input CutoffPolicyInput) (*models.MealCutoffPolicy, error)
+	DeleteDayOverride(date, mealType string) error
+
+	// Deadline returns when changes to a meal on a date close
+	Deadline(date, mealType string) (time.Time, error)
+	// Deadlines returns the deadline of each given meal on a date
+	Deadlines(date string, mealTypes []models.MealType) (map[models.MealType]time.Time, error)
+}
+
+type cutoffPolicyService struct {
+	policyRepo     repository.CutoffPolicyRepository
+	scheduleRepo   repository.ScheduleRepository
+	catalog        MealCatalog
+	cutoffTime     string
+	cutoffTimezone string
+}
+
+// NewCutoffPolicyService creates a new cutoff policy service
+func NewCutoffPolicyService(policyRepo repository.CutoffPolicyRepository, scheduleRepo repository.ScheduleRepository, catalog MealCatalog, cfg *config.Config) CutoffPolicyService {
+	return &cutoffPolicyService{
+		policyRepo:     policyRepo,
+		scheduleRepo:   scheduleRepo,
+		catalog:        catalog,
+		cutoffTime:     cfg.Meal.CutoffTime,
+		cutoffTimezone: cfg.Meal.CutoffTimezone,
+	}
+}
+
+// ListPolicies returns the default cutoff of every meal type
+func (s *cutoffPolicyService) ListPolicies() ([]EffectiveCutoff, error) {
+	mealTypes, err := s.catalog.ActiveCodes()
+	if err != nil {
+		return nil, err
+	}
+	policies, err := s.policyRepo.FindDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return s.effectiveCutoffs(mealTypes, policies, nil), nil
+}
+
+// SetPolicy creates or replaces the default cutoff policy of a meal type
+func (s *cutoffPolicyService) SetPolicy(adminID, mealType string, input CutoffPolicyInput) (*models.MealCutoffPolicy, error) {
+	return s.savePolicy(adminID, nil, mealType, input)
+}
+
+// DeletePolicy removes a meal type's policy so it falls back to the global cutoff
+func (s *cutoffPolicyService) DeletePolicy(mealType string) error {
+	return s.deletePolicy(nil, mealType)
+}
+
+// GetDayCutoffs returns the effective cutoff and deadline of every meal type on a date
+func (s *cutoffPolicyService) GetDayCutoffs(date string) ([]EffectiveCutoff, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	day, _ := time.Parse("2006-01-02", date)
+
+	mealTypes, err := s.catalog.ActiveCodes()
+	if err != nil {
+		return nil, err
+	}
+	policies, err := s.policiesFor(date)
+	if err != nil {
+		return nil, err
+	}
+	return s.effectiveCutoffs(mealTypes, policies, &day), nil
+}
+
+// SetDayOverride creates or replaces the cutoff of a meal type on a date. The date needs a stored
+// day schedule, which the override belongs to and is deleted with.
+func (s *cutoffPolicyService) SetDayOverride(adminID, date, mealType string, input CutoffPolicyInput) (*models.MealCutoffPolicy, error) {
+	schedule, err := s.storedSchedule(date)
+	if err != nil {
+		return nil, err
+	}
+	return s.savePolicy(adminID, &schedule.ID, mealType, input)
+}
+
+// DeleteDayOverride removes a date's override so the meal type's policy applies again
+func (s *cutoffPolicyService) DeleteDayOverride(date, mealType string) error {
+	schedule, err := s.storedSchedule(date)
+	if err != nil {
+		return err
+	}
+	return s.deletePolicy(&schedule.ID, mealType)
+}
+
+func (s *cutoffPolicyService) Deadline(date, mealType string) (time.Time, error) {
+	deadlines, err := s.Deadlines(date, []models.MealType{models.MealType(mealType)})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return deadlines[models.MealType(mealType)], nil
+}
+
+func (s *cutoffPolicyService) Deadlines(date string, mealTypes []models.MealType) (map[models.MealType]time.Time, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	day, _ := time.Parse("2006-01-02", date)
+
+	policies, err := s.policiesFor(date)
+	if err != nil {
+		return nil, err
+	}
+
+	deadlines := make(map[models.MealType]time.Time, len(mealTypes))
+	for _, cutoff := range s.effectiveCutoffs(mealTypes, policies, &day) {
+		if cutoff.Deadline == nil {
+			return nil, fmt.Errorf("invalid cutoff time %q for %s", cutoff.CutoffTime, cutoff.MealType)
+		}
+		deadlines[cutoff.MealType] = *cutoff.Deadline
+	}
+	return deadlines, nil
+}
+
+// policiesFor returns the policies that apply on a date, day overrides first
+func (s *cutoffPolicyService) policiesFor(date string) ([]models.MealCutoffPolicy, error) {
+	policies := []models.MealCutoffPolicy{}
+
+	// Overrides hang off stored schedules; schedules expanded from rules have none
+	schedule, err := s.scheduleRepo.FindByDate(date)
+	if err != nil {
+		return nil, err
+	}
+	if schedule != nil {
+		overrides, err := s.policyRepo.FindBySchedule(schedule.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, overrides...)
+	}
+
+	defaults, err := s.policyRepo.FindDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return append(policies, defaults...), nil
+}
+
+// effectiveCutoffs picks the first matching policy for each meal type, falling back to the
+// global cutoff. With day set, each cutoff also carries its deadline for that date.
+func (s *cutoffPolicyService) effectiveCutoffs(mealTypes []models.MealType, policies []models.MealCutoffPolicy, day *time.Time) []EffectiveCutoff {
+	cutoffs := make([]EffectiveCutoff, 0, len(mealTypes))
+	for _, mealType := range mealTypes {
+		cutoff := EffectiveCutoff{
+			MealType:   mealType,
+			DaysBefore: 1,
+			CutoffTime: s.cutoffTime,
+			Timezone:   s.cutoffTimezone,
+			Source:     CutoffSourceDefault,
+		}
+		for _, policy := range policies {
+			if policy.MealType != mealType {
+				continue
+			}
+			cutoff.DaysBefore = policy.DaysBefore
+			cutoff.CutoffTime = policy.CutoffTime
+			cutoff.Source = CutoffSourcePolicy
+			if policy.DayScheduleID != nil {
+				cutoff.Source = CutoffSourceSchedule
+			}
+			break
+		}
+
+		if day != nil {
+			if deadline, err := clockTimeOn(day.AddDate(0, 0, -cutoff.DaysBefore), cutoff.CutoffTime, s.cutoffTimezone); err == nil {
+				cutoff.Deadline = &deadline
+			}
+		}
+		cutoffs = append(cutoffs, cutoff)
+	}
+	return cutoffs
+}
+
+func (s *cutoffPolicyService) savePolicy(adminID string, scheduleID *uuid.UUID, mealType string, input CutoffPolicyInput) (*models.MealCutoffPolicy, error) {
+	meal, err := s.catalog.Validate(strings.ToLower(strings.TrimSpace(mealType)))
+	if err != nil {
+		return nil, err
+	}
+	if *input.DaysBefore < 0 || *input.DaysBefore > maxCutoffDaysBefore {
+		return nil, fmt.Errorf("days_before must be between 0 and %d", maxCutoffDaysBefore)
+	}
+	if _, err := time.Parse("15:04", input.CutoffTime); err != nil {
+		return nil, fmt.Errorf("invalid cutoff time, expected HH:MM")
+	}
+
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid admin ID")
+	}
+
+	policy, err := s.policyRepo.Find(scheduleKey(scheduleID), string(meal))
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		policy = &models.MealCutoffPolicy{MealType: meal, DayScheduleID: scheduleID}
+	}
+	policy.DaysBefore = *input.DaysBefore
+	policy.CutoffTime = input.CutoffTime
+	policy.UpdatedBy = &adminUUID
+
+	if err := s.policyRepo.Save(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *cutoffPolicyService) deletePolicy(scheduleID *uuid.UUID, mealType string) error {
+	policy, err := s.policyRepo.Find(scheduleKey(scheduleID), mealType)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return fmt.Errorf("no cutoff policy for %s", mealType)
+	}
+	return s.policyRepo.Delete(policy.ID.String())
+}
+
+func (s *cutoffPolicyService) storedSchedule(date string) (*models.DaySchedule, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	schedule, err := s.scheduleRepo.FindByDate(date)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, fmt.Errorf("no day schedule exists for %s, create one before overriding its cutoffs", date)
+	}
+	return schedule, nil
+}
+
+func scheduleKey(scheduleID *uuid.UUID) *string {
+	if scheduleID == nil {
+		return nil
+	}
+	key := scheduleID.String()
+	return &key
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"craftsbite-backend/internal/authz"
+	"craftsbite-backend/internal/discord"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Slash command names registered with Discord
+const (
+	DiscordCommandMeal      = "meal"
+	DiscordCommandLocation  = "location"
+	DiscordCommandStatus    = "status"
+	DiscordCommandHeadcount = "headcount"
+)
+
+// DiscordCommandResult is the outcome of a slash command
+type DiscordCommandResult struct {
+	Content string
+	// UpdatedDate is set when the command changed meal participation on that date
+	UpdatedDate string
+}
+
+// DiscordService defines the interface for Discord slash command handling and account linking
+type DiscordService interface {
+	HandleCommand(discordUserID string, data discord.CommandData) (*DiscordCommandResult, error)
+	LinkUser(adminID, discordUserID, userID string) (*models.DiscordLink, error)
+	UnlinkUser(discordUserID string) error
+	ListLinks() ([]models.DiscordLink, error)
+}
+
+type discordService struct {
+	linkRepo            repository.DiscordLinkRepository
+	userRepo            repository.UserRepository
+	teamRepo            repository.TeamRepository
+	mealService         MealService
+	workLocationService WorkLocationService
+	headcountService    HeadcountService
+	authorizer          authz.Authorizer
+}
+
+// NewDiscordService creates a new Discord service
+func NewDiscordService(
+	linkRepo repository.DiscordLinkRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	mealService MealService,
+	workLocationService WorkLocationService,
+	headcountService HeadcountService,
+	authorizer authz.Authorizer,
+) DiscordService {
+	return &discordService{
+		linkRepo:            linkRepo,
+		userRepo:            userRepo,
+		teamRepo:            teamRepo,
+		mealService:         mealService,
+		workLocationService: workLocationService,
+		headcountService:    headcountService,
+		authorizer:          authorizer,
+	}
+}
+
+// HandleCommand resolves the caller from their Discord ID and runs the slash command.
+// Errors meant for the caller are returned as result content, not as an error.
+func (s *discordService) HandleCommand(discordUserID string, data discord.CommandData) (*DiscordCommandResult, error) {
+	link, err := s.linkRepo.FindByDiscordUserID(discordUserID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return &DiscordCommandResult{Content: "Your Discord account is not linked to CraftsBite. Please ask an admin to link it."}, nil
+	}
+	if !link.User.Active {
+		return &DiscordCommandResult{Content: "Your CraftsBite account is deactivated."}, nil
+	}
+	user := &link.User
+
+	date := data.StringOption("date")
+	if date == "" {
+		date = time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	}
+	if err := validateDate(date); err != nil {
+		return &DiscordCommandResult{Content: "❌ " + err.Error()}, nil
+	}
+
+	switch data.Name {
+	case DiscordCommandMeal:
+		return s.handleMeal(user, date, data)
+	case DiscordCommandLocation:
+		return s.handleLocation(user, date, data)
+	case DiscordCommandStatus:
+		return s.statusReply(user, date, "")
+	case DiscordCommandHeadcount:
+		return s.handleHeadcount(user, date, data.StringOption("scope"))
+	default:
+		return &DiscordCommandResult{Content: fmt.Sprintf("Unknown command: /%s", data.Name)}, nil
+	}
+}
+
+func (s *discordService) handleMeal(user *models.User, date string, data discord.CommandData) (*DiscordCommandResult, error) {
+	// SetParticipation checks the meal against the meal type catalogue
+	mealType := data.StringOption("meal")
+	participating, ok := data.BoolOption("participating")
+	if !ok {
+		return &DiscordCommandResult{Content: "❌ participating is required"}, nil
+	}
+
+	if err := s.mealService.SetParticipation(user.ID.String(), date, mealType, participating); err != nil {
+		return &DiscordCommandResult{Content: "❌ " + err.Error()}, nil
+	}
+
+	result, err := s.statusReply(user, date, "✅ Meal participation updated.")
+	if err != nil {
+		return nil, err
+	}
+	result.UpdatedDate = date
+	return result, nil
+}
+
+func (s *discordService) handleLocation(user *models.User, date string, data discord.CommandData) (*DiscordCommandResult, error) {
+	location := data.StringOption("location")
+	if err := s.workLocationService.SetMyLocation(user.ID.String(), date, location); err != nil {
+		return &DiscordCommandResult{Content: "❌ " + err.Error()}, nil
+	}
+	return s.statusReply(user, date, "✅ Work location updated.")
+}
+
+// statusReply renders the caller's work location and meal participation for a date
+func (s *discordService) statusReply(user *models.User, date, header string) (*DiscordCommandResult, error) {
+	userID := user.ID.String()
+
+	location, err := s.workLocationService.GetMyLocation(userID, date)
+	if err != nil {
+		return nil, err
+	}
+	participations, err := s.mealService.GetParticipation(userID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	if header != "" {
+		sb.WriteString(header + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("📅 %s — %s\n", date, user.Name))
+	sb.WriteString(fmt.Sprintf("📍 Work location: %s", location.Location))
+	if len(participations) == 0 {
+		sb.WriteString("\n📭 No meals scheduled.")
+	}
+	for _, p := range participations {
+		status := "❌ not joining"
+		if p.IsParticipating {
+			status = "✅ joining"
+		} else if p.Waitlisted {
+			status = fmt.Sprintf("⏳ waitlisted (#%d)", p.WaitlistPosition)
+		}
+		sb.WriteString(fmt.Sprintf("\n• %s: %s (%s)", p.MealType, status, p.Source))
+	}
+
+	return &DiscordCommandResult{Content: sb.String()}, nil
+}
+
+func (s *discordService) handleHeadcount(user *models.User, date, scope string) (*DiscordCommandResult, error) {
+	isOrgViewer, err := s.authorizer.HasPermission(user.Role.String(), authz.PermHeadcountRead, authz.ScopeAll)
+	if err != nil {
+		return nil, err
+	}
+	teamScope, err := s.authorizer.ScopeFor(user.Role.String(), authz.PermParticipationRead)
+	if err != nil {
+		return nil, err
+	}
+
+	if scope == "" {
+		scope = "team"
+		if isOrgViewer {
+			scope = "org"
+		}
+	}
+
+	switch scope {
+	case "org":
+		if !isOrgViewer {
+			return &DiscordCommandResult{Content: "❌ You don't have permission to view the org-wide headcount."}, nil
+		}
+		message, err := s.headcountService.GenerateAnnouncement(date)
+		if err != nil {
+			return nil, err
+		}
+		return &DiscordCommandResult{Content: message}, nil
+	case "team":
+		if !teamScope.Covers(authz.ScopeTeam) {
+			return &DiscordCommandResult{Content: "❌ You don't have permission to view team headcount."}, nil
+		}
+		return s.teamHeadcountReply(user, date, teamScope)
+	default:
+		return &DiscordCommandResult{Content: "❌ scope must be 'team' or 'org'"}, nil
+	}
+}
+
+// teamHeadcountReply renders per-team meal counts; below ScopeAll only the teams the user leads are shown
+func (s *discordService) teamHeadcountReply(user *models.User, date string, scope authz.Scope) (*DiscordCommandResult, error) {
+	summary, err := s.headcountService.GetHeadcountByDate(date)
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil {
+		return &DiscordCommandResult{Content: fmt.Sprintf("📅 %s\n📭 No meals scheduled.", date)}, nil
+	}
+
+	var allowed map[string]bool
+	if scope != authz.ScopeAll {
+		teams, err := s.teamRepo.FindByTeamLeadID(user.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		allowed = make(map[string]bool, len(teams))
+		for _, team := range teams {
+			allowed[team.ID.String()] = true
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📅 Team headcount — %s", date))
+	shown := 0
+	for _, team := range summary.Teams {
+		if allowed != nil && !allowed[team.TeamID] {
+			continue
+		}
+		shown++
+		ls := team.LocationSplit
+		sb.WriteString(fmt.Sprintf("\n\n👥 %s (%d members) — 🏢 %d | 🏠 %d | ❓ %d",
+			team.TeamName, team.TotalMembers, ls.Office, ls.WFH, ls.NotSet))
+
+		mealTypes := make([]string, 0, len(team.Meals))
+		for mt := range team.Meals {
+			mealTypes = append(mealTypes, mt)
+		}
+		sort.Strings(mealTypes)
+		for _, mt := range mealTypes {
+			counts := team.Meals[mt]
+			sb.WriteString(fmt.Sprintf("\n• %s: %d joining, %d not joining", mt, counts.Participating, counts.OptedOut))
+		}
+	}
+	if shown == 0 {
+		sb.WriteString("\nNo teams found.")
+	}
+
+	return &DiscordCommandResult{Content: sb.String()}, nil
+}
+
+// LinkUser links a Discord user ID to an active internal user
+func (s *discordService) LinkUser(adminID, discordUserID, userID string) (*models.DiscordLink, error) {
+	discordUserID = strings.TrimSpace(discordUserID)
+	if discordUserID == "" {
+		return nil, fmt.Errorf("discord_user_id is required")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Active {
+		return nil, fmt.Errorf("user account is deactivated")
+	}
+
+	existing, err := s.linkRepo.FindByDiscordUserID(discordUserID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("discord user is already linked")
+	}
+	existing, err = s.linkRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("user is already linked to a discord account")
+	}
+
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid admin ID")
+	}
+
+	link := &models.DiscordLink{
+		DiscordUserID: discordUserID,
+		UserID:        user.ID,
+		LinkedBy:      &adminUUID,
+	}
+	if err := s.linkRepo.Create(link); err != nil {
+		return nil, err
+	}
+	link.User = *user
+	return link, nil
+}
+
+// UnlinkUser removes the link for a Discord user
+func (s *discordService) UnlinkUser(discordUserID string) error {
+	return s.linkRepo.DeleteByDiscordUserID(discordUserID)
+}
+
+// ListLinks returns all Discord links
+func (s *discordService) ListLinks() ([]models.DiscordLink, error) {
+	return s.linkRepo.FindAll()
+}
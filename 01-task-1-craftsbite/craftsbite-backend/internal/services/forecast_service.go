@@ -0,0 +1,563 @@
+package services
+
+import (
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Statistical forecast tuning. Each meal is forecast from the same weekday of the
+// previous weeks: the ratio between how many people actually ate and how many the
+// rules predicted at the same distance from the meal is exponentially smoothed and
+// applied to today's rule-based count.
+const (
+	forecastLookbackWeeks   = 8
+	forecastSmoothing       = 0.4
+	forecastConfidence      = 0.9
+	forecastZScore          = 1.645 // two-sided 90% interval
+	minForecastObservations = 3
+	defaultBacktestWeeks    = 4
+	maxBacktestWeeks        = 12
+)
+
+// Forecast methods
+const (
+	// ForecastMethodStatistical marks an estimate learned from past weeks
+	ForecastMethodStatistical = "statistical"
+	// ForecastMethodRuleBased marks an estimate that fell back to the rule-based
+	// count because there were too few past observations
+	ForecastMethodRuleBased = "rule_based"
+)
+
+// MealForecast is the forecast headcount of one meal
+type MealForecast struct {
+	RuleBased    int    `json:"rule_based"`
+	Estimate     int    `json:"estimate"`
+	Low          int    `json:"low"`
+	High         int    `json:"high"`
+	Method       string `json:"method"`
+	Observations int    `json:"observations"`
+}
+
+// TeamForecast is the forecast headcount of one team
+type TeamForecast struct {
+	TeamID   string                  `json:"team_id"`
+	TeamName string                  `json:"team_name"`
+	Meals    map[string]MealForecast `json:"meals"`
+}
+
+// DailyForecast is the forecast headcount of a day
+type DailyForecast struct {
+	Date       string                  `json:"date"`
+	DayStatus  models.DayStatus        `json:"day_status"`
+	Confidence float64                 `json:"confidence"`
+	Meals      map[string]MealForecast `json:"meals"`
+	Teams      []TeamForecast          `json:"teams"`
+}
+
+// ForecastErrors summarizes how far forecasts were from what was observed
+type ForecastErrors struct {
+	Samples                     int      `json:"samples"`
+	MeanAbsoluteError           float64  `json:"mean_absolute_error"`
+	MeanAbsolutePercentageError float64  `json:"mean_absolute_percentage_error"`
+	Bias                        float64  `json:"bias"`
+	Coverage                    *float64 `json:"coverage,omitempty"`
+}
+
+// BacktestPoint is one replayed forecast of a past meal
+type BacktestPoint struct {
+	Date      string          `json:"date"`
+	MealType  models.MealType `json:"meal_type"`
+	Actual    int             `json:"actual"`
+	RuleBased int             `json:"rule_based"`
+	Estimate  int             `json:"estimate"`
+	Low       int             `json:"low"`
+	High      int             `json:"high"`
+	Method    string          `json:"method"`
+}
+
+// BacktestErrors compares the statistical and rule-based forecast errors
+type BacktestErrors struct {
+	Statistical ForecastErrors `json:"statistical"`
+	RuleBased   ForecastErrors `json:"rule_based"`
+}
+
+// BacktestWeek is the forecast error of one past week
+type BacktestWeek struct {
+	WeekStart string `json:"week_start"`
+	BacktestErrors
+}
+
+// ForecastBacktest reports the error of forecasts replayed over past weeks
+type ForecastBacktest struct {
+	StartDate   string                    `json:"start_date"`
+	EndDate     string                    `json:"end_date"`
+	HorizonDays int                       `json:"horizon_days"`
+	Confidence  float64                   `json:"confidence"`
+	Overall     BacktestErrors            `json:"overall"`
+	Meals       map[string]BacktestErrors `json:"meals"`
+	Weeks       []BacktestWeek            `json:"weeks"`
+	Points      []BacktestPoint           `json:"points"`
+}
+
+// ForecastService defines the interface for statistical headcount forecasting
+type ForecastService interface {
+	// GetForecast returns the rule-based forecast of the coming days alongside a
+	// statistical estimate and confidence range per meal and team
+	GetForecast(days int) ([]DailyForecast, error)
+	// Backtest replays the statistical forecast over the past weeks, made horizonDays
+	// before each meal's snapshot, and reports its error against what was observed
+	Backtest(weeks, horizonDays int) (*ForecastBacktest, error)
+}
+
+type forecastService struct {
+	headcountService HeadcountService
+	snapshotRepo     repository.HeadcountSnapshotRepository
+	historyRepo      repository.HistoryRepository
+	consumptionRepo  repository.ConsumptionRepository
+	teamRepo         repository.TeamRepository
+	maxForecastDays  int
+}
+
+// NewForecastService creates a new forecast service
+func NewForecastService(
+	headcountService HeadcountService,
+	snapshotRepo repository.HeadcountSnapshotRepository,
+	historyRepo repository.HistoryRepository,
+	consumptionRepo repository.ConsumptionRepository,
+	teamRepo repository.TeamRepository,
+	cfg *config.Config,
+) ForecastService {
+	return &forecastService{
+		headcountService: headcountService,
+		snapshotRepo:     snapshotRepo,
+		historyRepo:      historyRepo,
+		consumptionRepo:  consumptionRepo,
+		teamRepo:         teamRepo,
+		maxForecastDays:  cfg.Headcount.MaxForecastDays,
+	}
+}
+
+// GetForecast returns the rule-based forecast of the coming days alongside a
+// statistical estimate and confidence range per meal and team
+func (s *forecastService) GetForecast(days int) ([]DailyForecast, error) {
+	summaries, err := s.headcountService.GetForecast(days)
+	if err != nil {
+		return nil, err
+	}
+	if len(summaries) == 0 {
+		return []DailyForecast{}, nil
+	}
+
+	now := time.Now()
+	first, err := time.Parse("2006-01-02", summaries[0].Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid forecast date: %w", err)
+	}
+	history, err := s.loadHistory(first.AddDate(0, 0, -7*forecastLookbackWeeks), now)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts := make([]DailyForecast, 0, len(summaries))
+	for _, summary := range summaries {
+		target, err := time.Parse("2006-01-02", summary.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid forecast date: %w", err)
+		}
+
+		forecast := DailyForecast{
+			Date:       summary.Date,
+			DayStatus:  summary.DayStatus,
+			Confidence: forecastConfidence,
+			Meals:      make(map[string]MealForecast, len(summary.Meals)),
+			Teams:      make([]TeamForecast, 0, len(summary.Teams)),
+		}
+		for meal, counts := range summary.Meals {
+			forecast.Meals[meal] = history.forecast(target, meal, "", counts.Participating, now)
+		}
+		for _, team := range summary.Teams {
+			teamForecast := TeamForecast{
+				TeamID:   team.TeamID,
+				TeamName: team.TeamName,
+				Meals:    make(map[string]MealForecast, len(team.Meals)),
+			}
+			for meal, counts := range team.Meals {
+				teamForecast.Meals[meal] = history.forecast(target, meal, team.TeamID, counts.Participating, now)
+			}
+			forecast.Teams = append(forecast.Teams, teamForecast)
+		}
+		forecasts = append(forecasts, forecast)
+	}
+
+	return forecasts, nil
+}
+
+// Backtest replays the statistical forecast over the past weeks and reports its
+// error against what was observed
+func (s *forecastService) Backtest(weeks, horizonDays int) (*ForecastBacktest, error) {
+	if weeks <= 0 {
+		weeks = defaultBacktestWeeks
+	}
+	if weeks > maxBacktestWeeks {
+		return nil, fmt.Errorf("backtest cannot cover more than %d weeks", maxBacktestWeeks)
+	}
+	if horizonDays < 0 || horizonDays > s.maxForecastDays {
+		return nil, fmt.Errorf("horizon must be between 0 and %d days", s.maxForecastDays)
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start := today.AddDate(0, 0, -7*weeks)
+	end := today.AddDate(0, 0, -1)
+
+	history, err := s.loadHistory(start.AddDate(0, 0, -7*forecastLookbackWeeks), end)
+	if err != nil {
+		return nil, err
+	}
+
+	backtest := &ForecastBacktest{
+		StartDate:   start.Format("2006-01-02"),
+		EndDate:     end.Format("2006-01-02"),
+		HorizonDays: horizonDays,
+		Confidence:  forecastConfidence,
+		Meals:       make(map[string]BacktestErrors),
+		Weeks:       []BacktestWeek{},
+		Points:      []BacktestPoint{},
+	}
+
+	overall := newBacktestAccumulator()
+	meals := make(map[string]*backtestAccumulator)
+	weekly := make(map[string]*backtestAccumulator)
+	var weekStarts []string
+
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		for _, snapshot := range history.byDate[date] {
+			meal := string(snapshot.MealType)
+			// Replay the forecast as it would have been made horizonDays before the snapshot
+			asOf := snapshot.CapturedAt.AddDate(0, 0, -horizonDays)
+			rule, actual, ok := history.observe(date, meal, "", asOf)
+			if !ok {
+				continue
+			}
+			forecast := history.forecast(d, meal, "", rule, asOf)
+
+			backtest.Points = append(backtest.Points, BacktestPoint{
+				Date:      date,
+				MealType:  snapshot.MealType,
+				Actual:    actual,
+				RuleBased: rule,
+				Estimate:  forecast.Estimate,
+				Low:       forecast.Low,
+				High:      forecast.High,
+				Method:    forecast.Method,
+			})
+
+			weekStart := d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7)).Format("2006-01-02")
+			if weekly[weekStart] == nil {
+				weekly[weekStart] = newBacktestAccumulator()
+				weekStarts = append(weekStarts, weekStart)
+			}
+			if meals[meal] == nil {
+				meals[meal] = newBacktestAccumulator()
+			}
+			for _, acc := range []*backtestAccumulator{overall, meals[meal], weekly[weekStart]} {
+				acc.add(forecast, actual)
+			}
+		}
+	}
+
+	backtest.Overall = overall.result()
+	for meal, acc := range meals {
+		backtest.Meals[meal] = acc.result()
+	}
+	for _, weekStart := range weekStarts {
+		backtest.Weeks = append(backtest.Weeks, BacktestWeek{
+			WeekStart:      weekStart,
+			BacktestErrors: weekly[weekStart].result(),
+		})
+	}
+
+	return backtest, nil
+}
+
+// loadHistory gathers the snapshots, participation changes and check-ins of the
+// meals between start and end, together with the current team memberships
+func (s *forecastService) loadHistory(start, end time.Time) (*forecastHistory, error) {
+	startDate := start.Format("2006-01-02")
+	endDate := end.Format("2006-01-02")
+
+	snapshots, err := s.snapshotRepo.FindByDateRangeWithTeams(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.historyRepo.FindByDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	consumptions, err := s.consumptionRepo.FindByDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := s.teamRepo.FindAllWithMembers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+
+	history := &forecastHistory{
+		snapshots:   make(map[string]*models.HeadcountSnapshot, len(snapshots)),
+		byDate:      make(map[string][]*models.HeadcountSnapshot),
+		changes:     make(map[string][]participationChange),
+		checkIns:    make(map[string][]string),
+		teamsByUser: make(map[string][]string),
+	}
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		date := dateKey(snapshot.Date)
+		history.snapshots[forecastKey(date, string(snapshot.MealType))] = snapshot
+		history.byDate[date] = append(history.byDate[date], snapshot)
+	}
+	for _, entry := range entries {
+		delta := participationDelta(entry.Action)
+		if delta == 0 {
+			continue
+		}
+		key := forecastKey(dateKey(entry.Date), string(entry.MealType))
+		history.changes[key] = append(history.changes[key], participationChange{
+			userID: entry.UserID.String(),
+			at:     entry.CreatedAt,
+			delta:  delta,
+		})
+	}
+	for _, consumption := range consumptions {
+		key := forecastKey(dateKey(consumption.Date), string(consumption.MealType))
+		history.checkIns[key] = append(history.checkIns[key], consumption.UserID.String())
+	}
+	for _, team := range teams {
+		for _, member := range team.Members {
+			userID := member.ID.String()
+			history.teamsByUser[userID] = append(history.teamsByUser[userID], team.ID.String())
+		}
+	}
+
+	return history, nil
+}
+
+// participationChange is one recorded opt-in or opt-out of a meal
+type participationChange struct {
+	userID string
+	at     time.Time
+	delta  int
+}
+
+// participationDelta is how a history action moves a meal's headcount
+func participationDelta(action models.HistoryAction) int {
+	switch action {
+	case models.HistoryActionOptedIn, models.HistoryActionOverrideIn, models.HistoryActionPromoted:
+		return 1
+	case models.HistoryActionOptedOut, models.HistoryActionOverrideOut:
+		return -1
+	}
+	return 0
+}
+
+func forecastKey(date, meal string) string {
+	return date + "|" + meal
+}
+
+// forecastHistory indexes past meals by date and meal type
+type forecastHistory struct {
+	snapshots   map[string]*models.HeadcountSnapshot
+	byDate      map[string][]*models.HeadcountSnapshot
+	changes     map[string][]participationChange
+	checkIns    map[string][]string
+	teamsByUser map[string][]string
+}
+
+// inGroup reports whether a user counts towards a team, or towards the whole
+// company when teamID is empty
+func (h *forecastHistory) inGroup(userID, teamID string) bool {
+	if teamID == "" {
+		return true
+	}
+	for _, id := range h.teamsByUser[userID] {
+		if id == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+// observe returns, for a past meal, the headcount the rules showed at asOf and the
+// number of people who actually ate. The latter is the check-in count when
+// check-ins were recorded and the snapshot headcount otherwise.
+func (h *forecastHistory) observe(date, meal, teamID string, asOf time.Time) (rule, actual int, ok bool) {
+	key := forecastKey(date, meal)
+	snapshot := h.snapshots[key]
+	if snapshot == nil {
+		return 0, 0, false
+	}
+
+	final := snapshot.Participating
+	if teamID != "" {
+		found := false
+		for _, team := range snapshot.Teams {
+			if team.TeamID.String() == teamID {
+				final, found = team.Participating, true
+				break
+			}
+		}
+		if !found {
+			return 0, 0, false
+		}
+	}
+
+	// Undo the changes made between asOf and the snapshot
+	rule = final
+	for _, change := range h.changes[key] {
+		if change.at.After(asOf) && !change.at.After(snapshot.CapturedAt) && h.inGroup(change.userID, teamID) {
+			rule -= change.delta
+		}
+	}
+	if rule < 0 {
+		rule = 0
+	}
+
+	actual = final
+	if checkIns := h.checkIns[key]; len(checkIns) > 0 {
+		actual = 0
+		for _, userID := range checkIns {
+			if h.inGroup(userID, teamID) {
+				actual++
+			}
+		}
+	}
+
+	return rule, actual, true
+}
+
+// forecast estimates a meal's headcount on target from the same weekday of the
+// previous weeks, using only snapshots captured before asOf
+func (h *forecastHistory) forecast(target time.Time, meal, teamID string, ruleBased int, asOf time.Time) MealForecast {
+	var ratios []float64
+	for k := forecastLookbackWeeks; k >= 1; k-- {
+		date := target.AddDate(0, 0, -7*k).Format("2006-01-02")
+		snapshot := h.snapshots[forecastKey(date, meal)]
+		if snapshot == nil || !snapshot.CapturedAt.Before(asOf) {
+			continue
+		}
+		// Compare against what the rules showed the same distance ahead of that meal
+		rule, actual, ok := h.observe(date, meal, teamID, asOf.AddDate(0, 0, -7*k))
+		if !ok || rule == 0 {
+			continue
+		}
+		ratios = append(ratios, float64(actual)/float64(rule))
+	}
+
+	forecast := MealForecast{
+		RuleBased:    ruleBased,
+		Estimate:     ruleBased,
+		Low:          ruleBased,
+		High:         ruleBased,
+		Method:       ForecastMethodRuleBased,
+		Observations: len(ratios),
+	}
+	if len(ratios) < minForecastObservations {
+		return forecast
+	}
+
+	level, sigma := smoothRatios(ratios)
+	base := float64(ruleBased)
+	forecast.Method = ForecastMethodStatistical
+	forecast.Estimate = int(math.Round(base * level))
+	forecast.Low = int(math.Floor(base * math.Max(level-forecastZScore*sigma, 0)))
+	forecast.High = int(math.Ceil(base * (level + forecastZScore*sigma)))
+	return forecast
+}
+
+// smoothRatios exponentially smooths ratios given oldest first and returns the
+// final level with the root mean square of its one-step-ahead errors
+func smoothRatios(ratios []float64) (level, sigma float64) {
+	level = ratios[0]
+	var squared float64
+	for _, ratio := range ratios[1:] {
+		err := ratio - level
+		squared += err * err
+		level = forecastSmoothing*ratio + (1-forecastSmoothing)*level
+	}
+	return level, math.Sqrt(squared / float64(len(ratios)-1))
+}
+
+// forecastAccumulator collects the errors of one kind of forecast
+type forecastAccumulator struct {
+	samples     int
+	absolute    float64
+	percentage  float64
+	percentageN int
+	bias        float64
+	covered     int
+	withRange   bool
+}
+
+func (a *forecastAccumulator) add(forecast, actual int, covered bool) {
+	diff := float64(forecast - actual)
+	a.samples++
+	a.absolute += math.Abs(diff)
+	a.bias += diff
+	if actual > 0 {
+		a.percentage += math.Abs(diff) / float64(actual) * 100
+		a.percentageN++
+	}
+	if covered {
+		a.covered++
+	}
+}
+
+func (a *forecastAccumulator) result() ForecastErrors {
+	errors := ForecastErrors{Samples: a.samples}
+	if a.samples == 0 {
+		return errors
+	}
+	n := float64(a.samples)
+	errors.MeanAbsoluteError = roundTo(a.absolute/n, 2)
+	errors.Bias = roundTo(a.bias/n, 2)
+	if a.percentageN > 0 {
+		errors.MeanAbsolutePercentageError = roundTo(a.percentage/float64(a.percentageN), 2)
+	}
+	if a.withRange {
+		coverage := roundTo(float64(a.covered)/n, 2)
+		errors.Coverage = &coverage
+	}
+	return errors
+}
+
+// backtestAccumulator collects statistical and rule-based errors side by side
+type backtestAccumulator struct {
+	statistical forecastAccumulator
+	ruleBased   forecastAccumulator
+}
+
+func newBacktestAccumulator() *backtestAccumulator {
+	return &backtestAccumulator{statistical: forecastAccumulator{withRange: true}}
+}
+
+func (a *backtestAccumulator) add(forecast MealForecast, actual int) {
+	a.statistical.add(forecast.Estimate, actual, actual >= forecast.Low && actual <= forecast.High)
+	a.ruleBased.add(forecast.RuleBased, actual, false)
+}
+
+func (a *backtestAccumulator) result() BacktestErrors {
+	return BacktestErrors{
+		Statistical: a.statistical.result(),
+		RuleBased:   a.ruleBased.result(),
+	}
+}
+
+func roundTo(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
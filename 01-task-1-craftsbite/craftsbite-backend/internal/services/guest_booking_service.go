@@ -0,0 +1,200 @@
+package services
+
+import (
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CreateGuestBookingInput represents input for booking meals for guests
+type CreateGuestBookingInput struct {
+	Date         string   `json:"date" binding:"required"`
+	MealTypes    []string `json:"meal_types" binding:"required,min=1"`
+	GuestCount   int      `json:"guest_count" binding:"required,min=1"`
+	GuestNames   []string `json:"guest_names"`
+	DietaryNotes string   `json:"dietary_notes"`
+	Purpose      string   `json:"purpose"`
+}
+
+// GuestBookingService defines the interface for guest meal bookings
+type GuestBookingService interface {
+	CreateBooking(hostID string, input CreateGuestBookingInput) (*models.GuestBooking, error)
+	ListMyBookings(hostID string) ([]models.GuestBooking, error)
+	ListByDate(date string) ([]models.GuestBooking, error)
+	CancelBooking(hostID, id string) (*models.GuestBooking, error)
+}
+
+type guestBookingService struct {
+	guestRepo   repository.GuestBookingRepository
+	mealService MealService
+	dailyCap    int
+}
+
+// NewGuestBookingService creates a new guest booking service
+func NewGuestBookingService(guestRepo repository.GuestBookingRepository, mealService MealService, cfg *config.Config) GuestBookingService {
+	return &guestBookingService{
+		guestRepo:   guestRepo,
+		mealService: mealService,
+		dailyCap:    cfg.Meal.GuestDailyCap,
+	}
+}
+
+// CreateBooking books meals for guests of a host. Bookings follow each meal's cutoff and
+// the number of guests per meal and day is capped.
+func (s *guestBookingService) CreateBooking(hostID string, input CreateGuestBookingInput) (*models.GuestBooking, error) {
+	if len(input.GuestNames) > input.GuestCount {
+		return nil, fmt.Errorf("%d guest names given for %d guests", len(input.GuestNames), input.GuestCount)
+	}
+
+	mealTypes, err := s.validateMeals(hostID, input.Date, input.MealTypes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateChangeWindow(input.Date, mealTypes); err != nil {
+		return nil, err
+	}
+
+	booking := &models.GuestBooking{
+		ID:         uuid.New(),
+		HostUserID: uuid.MustParse(hostID),
+		Date:       input.Date,
+		MealTypes:  serializeMealTypes(mealTypes),
+		GuestCount: input.GuestCount,
+		IsActive:   true,
+	}
+	if names := trimmedNonEmpty(input.GuestNames); len(names) > 0 {
+		joined := strings.Join(names, ", ")
+		booking.GuestNames = &joined
+	}
+	if notes := strings.TrimSpace(input.DietaryNotes); notes != "" {
+		if len(notes) > 500 {
+			return nil, fmt.Errorf("dietary notes cannot exceed 500 characters")
+		}
+		booking.DietaryNotes = &notes
+	}
+	if purpose := strings.TrimSpace(input.Purpose); purpose != "" {
+		if len(purpose) > 255 {
+			return nil, fmt.Errorf("purpose cannot exceed 255 characters")
+		}
+		booking.Purpose = &purpose
+	}
+
+	// Enforce the daily cap per meal against the bookings already made
+	err = s.guestRepo.CreateChecked(booking, func(active []models.GuestBooking) error {
+		booked := guestCounts(active)
+		for _, mealType := range mealTypes {
+			if booked[string(mealType)]+input.GuestCount > s.dailyCap {
+				return fmt.Errorf("guest limit reached for %s on %s: %d of %d places left",
+					mealType, input.Date, max(s.dailyCap-booked[string(mealType)], 0), s.dailyCap)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// ListMyBookings returns the bookings made by a host
+func (s *guestBookingService) ListMyBookings(hostID string) ([]models.GuestBooking, error) {
+	return s.guestRepo.FindByHost(hostID)
+}
+
+// ListByDate returns the active bookings of a date with their hosts
+func (s *guestBookingService) ListByDate(date string) ([]models.GuestBooking, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	return s.guestRepo.FindActiveByDate(date)
+}
+
+// CancelBooking cancels one of the host's bookings; cancelling is subject to the same cutoff as booking
+func (s *guestBookingService) CancelBooking(hostID, id string) (*models.GuestBooking, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid booking ID")
+	}
+	booking, err := s.guestRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if booking == nil || booking.HostUserID.String() != hostID {
+		return nil, fmt.Errorf("guest booking not found")
+	}
+	if !booking.IsActive {
+		return nil, fmt.Errorf("guest booking is already cancelled")
+	}
+	if err := s.validateChangeWindow(dateKey(booking.Date), parseMealTypes(booking.MealTypes)); err != nil {
+		return nil, err
+	}
+
+	if err := s.guestRepo.Deactivate(id); err != nil {
+		return nil, err
+	}
+	booking.IsActive = false
+	return booking, nil
+}
+
+// validateMeals checks that each meal is served on the date, returning them without duplicates
+func (s *guestBookingService) validateMeals(hostID, date string, requested []string) ([]models.MealType, error) {
+	statuses, err := s.mealService.GetParticipation(hostID, date)
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[models.MealType]bool, len(statuses))
+	for _, status := range statuses {
+		if status.Source == "weekend" || status.Source == "day_schedule" {
+			return nil, fmt.Errorf("no meals are served on %s", date)
+		}
+		available[status.MealType] = true
+	}
+
+	seen := make(map[models.MealType]bool, len(requested))
+	mealTypes := make([]models.MealType, 0, len(requested))
+	for _, meal := range requested {
+		mealType := models.MealType(strings.TrimSpace(meal))
+		if !available[mealType] {
+			return nil, fmt.Errorf("%s is not available on %s", meal, date)
+		}
+		if !seen[mealType] {
+			seen[mealType] = true
+			mealTypes = append(mealTypes, mealType)
+		}
+	}
+	return mealTypes, nil
+}
+
+// validateChangeWindow checks that every booked meal can still be changed on the date
+func (s *guestBookingService) validateChangeWindow(date string, mealTypes []models.MealType) error {
+	for _, mealType := range mealTypes {
+		if err := s.mealService.ValidateChangeWindow(date, string(mealType)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// guestCounts sums booked guests per meal type
+func guestCounts(bookings []models.GuestBooking) map[string]int {
+	counts := make(map[string]int)
+	for _, booking := range bookings {
+		for _, mealType := range parseMealTypes(booking.MealTypes) {
+			counts[string(mealType)] += booking.GuestCount
+		}
+	}
+	return counts
+}
+
+func trimmedNonEmpty(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if trimmed := strings.TrimSpace(v); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
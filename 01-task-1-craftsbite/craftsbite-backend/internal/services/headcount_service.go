@@ -13,6 +13,7 @@ import (
 type HeadcountService interface {
 	GetTodayHeadcount() ([]*DailyHeadcountSummary, error)
 	GetHeadcountByDate(date string) (*DailyHeadcountSummary, error)
+	GetHeadcountRange(startDate, endDate string) ([]*DailyHeadcountSummary, error)
 	GetDetailedHeadcount(date, mealType string) (*DetailedHeadcount, error)
 	GenerateAnnouncement(date string) (string, error)
 	GetForecast(days int) ([]*DailyHeadcountSummary, error)
@@ -20,8 +21,17 @@ type HeadcountService interface {
 
 // MealHeadcount represents participation breakdown for a single meal
 type MealHeadcount struct {
-	Participating int `json:"participating"`
-	OptedOut      int `json:"opted_out"`
+	Participating int            `json:"participating"`
+	OptedOut      int            `json:"opted_out"`
+	Guests        int            `json:"guests"`
+	Dietary       *DietaryCounts `json:"dietary,omitempty"`
+}
+
+// DietaryCounts counts the participants of a meal per dietary restriction.
+// A participant with several restrictions is counted under each of them.
+type DietaryCounts struct {
+	Restrictions  map[models.DietaryRestriction]int `json:"restrictions"`
+	WithAllergens int                               `json:"with_allergens"`
 }
 
 // DailyHeadcountSummary represents the headcount summary for a day
@@ -54,7 +64,10 @@ type DetailedHeadcount struct {
 	MealType        string            `json:"meal_type"`
 	Participants    []ParticipantInfo `json:"participants"`
 	NonParticipants []ParticipantInfo `json:"non_participants"`
-	TotalCount      int               `json:"total_count"`
+	TotalCount      int                   `json:"total_count"`
+	Dietary         *DietaryCounts        `json:"dietary"`
+	Guests          int                   `json:"guests"`
+	GuestBookings   []models.GuestBooking `json:"guest_bookings"`
 }
 
 // ParticipantInfo represents a user's participation info
@@ -62,29 +75,37 @@ type ParticipantInfo struct {
 	UserID          string `json:"user_id"`
 	Name            string `json:"name"`
 	Email           string `json:"email"`
-	IsParticipating bool   `json:"is_participating"`
-	Source          string `json:"source"`
+	IsParticipating     bool                        `json:"is_participating"`
+	Source              string                      `json:"source"`
+	DietaryRestrictions []models.DietaryRestriction `json:"dietary_restrictions,omitempty"`
+	Allergens           string                      `json:"allergens,omitempty"`
 }
 
 // headcountService implements HeadcountService
 type headcountService struct {
 	userRepo         repository.UserRepository
-	scheduleRepo     repository.ScheduleRepository
+	scheduleRepo     ScheduleCalendar
 	resolver         ParticipationResolver
 	teamRepo         repository.TeamRepository
 	workLocationRepo repository.WorkLocationRepository
 	wfhPeriodRepo    repository.WFHPeriodRepository
+	guestRepo        repository.GuestBookingRepository
+	menuRepo         repository.MenuRepository
+	catalog          MealCatalog
 	maxForecastDays   int
 }
 
 // NewHeadcountService creates a new headcount service
 func NewHeadcountService(
 	userRepo repository.UserRepository,
-	scheduleRepo repository.ScheduleRepository,
+	scheduleRepo ScheduleCalendar,
 	resolver ParticipationResolver,
 	teamRepo repository.TeamRepository,
 	workLocationRepo repository.WorkLocationRepository,
 	wfhPeriodRepo repository.WFHPeriodRepository,
+	guestRepo repository.GuestBookingRepository,
+	menuRepo repository.MenuRepository,
+	catalog MealCatalog,
 	cfg *config.Config,
 ) HeadcountService {
 	return &headcountService{
@@ -94,6 +115,9 @@ func NewHeadcountService(
 		teamRepo:         teamRepo,
 		workLocationRepo: workLocationRepo,
 		wfhPeriodRepo:    wfhPeriodRepo,
+		guestRepo:        guestRepo,
+		menuRepo:         menuRepo,
+		catalog:          catalog,
 		maxForecastDays: cfg.Headcount.MaxForecastDays,
 	}
 }
@@ -118,6 +142,26 @@ func (s *headcountService) GetHeadcountByDate(date string) (*DailyHeadcountSumma
 	return s.getHeadcountByDate(date)
 }
 
+// GetHeadcountRange returns the headcount summary of every scheduled date from startDate to endDate
+func (s *headcountService) GetHeadcountRange(startDate, endDate string) ([]*DailyHeadcountSummary, error) {
+	dates, err := reportDates(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*DailyHeadcountSummary, 0, len(dates))
+	for _, date := range dates {
+		summary, err := s.getHeadcountByDate(date)
+		if err != nil {
+			return nil, err
+		}
+		if summary != nil {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries, nil
+}
+
 func (s *headcountService) getHeadcountByDate(date string) (*DailyHeadcountSummary, error) {
 	if _, err := time.Parse("2006-01-02", date); err != nil {
 		return nil, fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
@@ -150,16 +194,19 @@ func (s *headcountService) getHeadcountByDate(date string) (*DailyHeadcountSumma
 
 	totalActiveUsers := len(users)
 
-	userLocationMap := make(map[string]string)
-	globalLocationSplit := LocationSplit{}
-
+	userIDs := make([]string, 0, len(users))
 	for _, user := range users {
-		loc, err := s.resolveUserLocation(user.ID.String(), date)
-		if err != nil {
-			return nil, err
-		}
-		userLocationMap[user.ID.String()] = loc
-		switch loc {
+		userIDs = append(userIDs, user.ID.String())
+	}
+
+	userLocationMap, err := s.resolveUserLocations(userIDs, date)
+	if err != nil {
+		return nil, err
+	}
+
+	globalLocationSplit := LocationSplit{}
+	for _, uid := range userIDs {
+		switch userLocationMap[uid] {
 		case "office":
 			globalLocationSplit.Office++
 		case "wfh":
@@ -169,36 +216,25 @@ func (s *headcountService) getHeadcountByDate(date string) (*DailyHeadcountSumma
 		}
 	}
 
-	// ── Meal headcount (unchanged logic) ───────────────────────
-	meals := make(map[string]MealHeadcount)
-	type userMealResult struct {
-		isParticipating bool
-		source          string
-	}
-
-	userParticipation := make(map[string]map[string]userMealResult)
+	// ── Meal headcount ─────────────────────────────────────────
+	mealKeys := mealTypeKeys(availableMeals)
 
-	for _, mealType := range availableMeals {
-		mtKey := string(mealType)
-		userParticipation[mtKey] = make(map[string]userMealResult)
-		participating := 0
+	resolution, err := s.resolver.ResolveBatch(userIDs, []string{date}, mealKeys)
+	if err != nil {
+		return nil, err
+	}
 
-		for _, user := range users {
-			uid := user.ID.String()
-			isP, src, err := s.resolver.ResolveParticipation(uid, date, mtKey)
-			if err != nil {
-				return nil, err
-			}
-			userParticipation[mtKey][uid] = userMealResult{isParticipating: isP, source: src}
-			if isP {
-				participating++
-			}
-		}
+	bookings, err := s.guestRepo.FindActiveByDate(date)
+	if err != nil {
+		return nil, err
+	}
 
-		meals[mtKey] = MealHeadcount{
-			Participating: participating,
-			OptedOut:      totalActiveUsers - participating,
-		}
+	meals := make(map[string]MealHeadcount)
+	guests := guestCounts(bookings)
+	for _, mtKey := range mealKeys {
+		counts := countMeal(users, resolution, date, mtKey)
+		counts.Guests = guests[mtKey]
+		meals[mtKey] = counts
 	}
 
 	// ── Team breakdown ─────────────────────────────────────────
@@ -207,6 +243,22 @@ func (s *headcountService) getHeadcountByDate(date string) (*DailyHeadcountSumma
 		return nil, err
 	}
 
+	// Guests count toward their host's team; a host in several teams is attributed to the first one
+	hostTeam := make(map[string]string)
+	for _, team := range teams {
+		for _, member := range team.Members {
+			if _, ok := hostTeam[member.ID.String()]; !ok {
+				hostTeam[member.ID.String()] = team.ID.String()
+			}
+		}
+	}
+	bookingsByTeam := make(map[string][]models.GuestBooking)
+	for _, booking := range bookings {
+		if teamID, ok := hostTeam[booking.HostUserID.String()]; ok {
+			bookingsByTeam[teamID] = append(bookingsByTeam[teamID], booking)
+		}
+	}
+
 	teamHeadcounts := make([]TeamHeadcount, 0, len(teams))
 	for _, team := range teams {
 		th := TeamHeadcount{
@@ -230,19 +282,11 @@ func (s *headcountService) getHeadcountByDate(date string) (*DailyHeadcountSumma
 			}
 		}
 
-		for _, mealType := range availableMeals {
-			mtKey := string(mealType)
-			participating := 0
-			for _, member := range team.Members {
-				uid := member.ID.String()
-				if res, ok := userParticipation[mtKey][uid]; ok && res.isParticipating {
-					participating++
-				}
-			}
-			th.Meals[mtKey] = MealHeadcount{
-				Participating: participating,
-				OptedOut:      len(team.Members) - participating,
-			}
+		teamGuests := guestCounts(bookingsByTeam[team.ID.String()])
+		for _, mtKey := range mealKeys {
+			counts := countMeal(team.Members, resolution, date, mtKey)
+			counts.Guests = teamGuests[mtKey]
+			th.Meals[mtKey] = counts
 		}
 
 		teamHeadcounts = append(teamHeadcounts, th)
@@ -274,63 +318,144 @@ func (s *headcountService) GetDetailedHeadcount(date, mealType string) (*Detaile
 		return nil, err
 	}
 
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID.String())
+	}
+
+	resolution, err := s.resolver.ResolveBatch(userIDs, []string{date}, []string{mealType})
+	if err != nil {
+		return nil, err
+	}
+
 	participants := []ParticipantInfo{}
 	nonParticipants := []ParticipantInfo{}
 	totalCount := 0
+	dietary := newDietaryCounts()
 
 	for _, user := range users {
 		if !user.Active {
 			continue
 		}
 
-		isParticipating, source, err := s.resolver.ResolveParticipation(user.ID.String(), date, mealType)
-		if err != nil {
-			return nil, err
-		}
+		res, _ := resolution.Get(user.ID.String(), date, mealType)
 
 		info := ParticipantInfo{
-			UserID:          user.ID.String(),
-			Name:            user.Name,
-			Email:           user.Email,
-			IsParticipating: isParticipating,
-			Source:          source,
+			UserID:              user.ID.String(),
+			Name:                user.Name,
+			Email:               user.Email,
+			IsParticipating:     res.IsParticipating,
+			Source:              res.Source,
+			DietaryRestrictions: parseDietaryRestrictions(user.DietaryRestrictions),
+			Allergens:           user.Allergens,
 		}
 
-		if isParticipating {
+		if res.IsParticipating {
 			participants = append(participants, info)
 			totalCount++
+			dietary.add(user)
 		} else {
 			nonParticipants = append(nonParticipants, info)
 		}
 	}
 
+	bookings, err := s.guestRepo.FindActiveByDate(date)
+	if err != nil {
+		return nil, err
+	}
+	mealBookings := []models.GuestBooking{}
+	for _, booking := range bookings {
+		for _, mt := range parseMealTypes(booking.MealTypes) {
+			if string(mt) == mealType {
+				mealBookings = append(mealBookings, booking)
+				break
+			}
+		}
+	}
+
 	return &DetailedHeadcount{
 		Date:            date,
 		MealType:        mealType,
 		Participants:    participants,
 		NonParticipants: nonParticipants,
 		TotalCount:      totalCount,
+		Dietary:         dietary,
+		Guests:          guestCounts(mealBookings)[mealType],
+		GuestBookings:   mealBookings,
 	}, nil
 }
 
-func (s *headcountService) resolveUserLocation(userID, date string) (string, error) {
-	wl, err := s.workLocationRepo.FindByUserAndDate(userID, date)
-	if err != nil {
-		return "", err
+// countMeal counts participants of a meal among users, with their dietary breakdown.
+// Users missing from the resolution count as opted out.
+func countMeal(users []models.User, resolution ParticipationResolution, date, mealType string) MealHeadcount {
+	counts := MealHeadcount{Dietary: newDietaryCounts()}
+	for _, user := range users {
+		if res, ok := resolution.Get(user.ID.String(), date, mealType); ok && res.IsParticipating {
+			counts.Participating++
+			counts.Dietary.add(user)
+		}
+	}
+	counts.OptedOut = len(users) - counts.Participating
+	return counts
+}
+
+func newDietaryCounts() *DietaryCounts {
+	return &DietaryCounts{Restrictions: make(map[models.DietaryRestriction]int)}
+}
+
+// add counts a participant's dietary profile
+func (d *DietaryCounts) add(user models.User) {
+	for _, restriction := range parseDietaryRestrictions(user.DietaryRestrictions) {
+		d.Restrictions[restriction]++
+	}
+	if strings.TrimSpace(user.Allergens) != "" {
+		d.WithAllergens++
 	}
-	if wl != nil {
-		return string(wl.Location), nil
+}
+
+// summary renders the counts as "3 vegetarian, 1 nut allergy, 2 with other allergies", or "" if empty
+func (d *DietaryCounts) summary() string {
+	if d == nil {
+		return ""
+	}
+	var parts []string
+	for _, restriction := range models.DietaryRestrictions {
+		if n := d.Restrictions[restriction]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, strings.ReplaceAll(restriction.String(), "_", " ")))
+		}
+	}
+	if d.WithAllergens > 0 {
+		parts = append(parts, fmt.Sprintf("%d with other allergies", d.WithAllergens))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// resolveUserLocations resolves the work location of each user on a date.
+// An explicit work location wins, then an active company-wide WFH period, otherwise "not_set".
+func (s *headcountService) resolveUserLocations(userIDs []string, date string) (map[string]string, error) {
+	wls, err := s.workLocationRepo.FindByDateAndUserIDs(date, userIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	period, err := s.wfhPeriodRepo.FindActiveByDate(date)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+
+	fallback := "not_set"
 	if period != nil {
-		return "wfh", nil
+		fallback = "wfh"
 	}
 
-	return "not_set", nil
+	locations := make(map[string]string, len(userIDs))
+	for _, uid := range userIDs {
+		locations[uid] = fallback
+	}
+	for _, wl := range wls {
+		locations[wl.UserID.String()] = string(wl.Location)
+	}
+	return locations, nil
 }
 
 func (s *headcountService) GenerateAnnouncement(date string) (string, error) {
@@ -402,31 +527,39 @@ func (s *headcountService) GenerateAnnouncement(date string) (string, error) {
 		summary.TotalActiveUsers, ls.Office, ls.WFH, ls.NotSet,
 	))
 
-	mealOrder := []string{"lunch", "snacks", "iftar", "event_dinner", "optional_dinner"}
-	mealEmoji := map[string]string{
-		"lunch":           "🍽️ ",
-		"snacks":          "🍪",
-		"iftar":           "🌙",
-		"event_dinner":    "🍴",
-		"optional_dinner": "🥘",
-	}
-	mealLabel := map[string]string{
-		"lunch":           "Lunch",
-		"snacks":          "Snacks",
-		"iftar":           "Iftar",
-		"event_dinner":    "Event Dinner",
-		"optional_dinner": "Optional Dinner",
+	mealTypes, err := s.catalog.List(true)
+	if err != nil {
+		return "", err
+	}
+	menus, err := s.menuRepo.FindByDateRange(date, date, true)
+	if err != nil {
+		return "", err
+	}
+	dishes := make(map[models.MealType][]string, len(menus))
+	for _, menu := range menus {
+		for _, dish := range menu.Dishes {
+			dishes[menu.MealType] = append(dishes[menu.MealType], dish.Name)
+		}
 	}
 	sb.WriteString("\n")
-	for _, mt := range mealOrder {
-		counts, ok := summary.Meals[mt]
+	for _, mealType := range mealTypes {
+		counts, ok := summary.Meals[string(mealType.Code)]
 		if !ok {
 			continue
 		}
 		sb.WriteString(fmt.Sprintf(
 			"\n%s  %-15s →  %d joining, %d not joining",
-			mealEmoji[mt], mealLabel[mt], counts.Participating, counts.OptedOut,
+			mealType.Emoji, mealType.Label, counts.Participating, counts.OptedOut,
 		))
+		if counts.Guests > 0 {
+			sb.WriteString(fmt.Sprintf(" (+%d guests)", counts.Guests))
+		}
+		if dietary := counts.Dietary.summary(); dietary != "" {
+			sb.WriteString(fmt.Sprintf("\n      🥗 Dietary: %s", dietary))
+		}
+		if names := dishes[mealType.Code]; len(names) > 0 {
+			sb.WriteString(fmt.Sprintf("\n      📋 Menu: %s", strings.Join(names, ", ")))
+		}
 	}
 
 	sb.WriteString("\n\nPlease confirm your meal preference if you haven't already. Thank you! 🙏")
@@ -0,0 +1,471 @@
+package services
+
+import (
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"craftsbite-backend/pkg/logger"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// snapshotCatchUpDays is how many past days CaptureDue checks, so a meal whose cutoff passed
+// while the server was down, even across midnight, is still captured when it comes back
+const snapshotCatchUpDays = 7
+
+// HeadcountSnapshotService defines the interface for headcount snapshots frozen at the meal cutoff
+type HeadcountSnapshotService interface {
+	CaptureSnapshot(date string, capturedBy *string) ([]models.HeadcountSnapshot, error)
+	CaptureDue(now time.Time) (int, error)
+	GetSnapshots(date string) ([]models.HeadcountSnapshot, error)
+	GetSnapshot(date, mealType string) (*models.HeadcountSnapshot, error)
+	DiffSnapshot(date string) (*HeadcountSnapshotDiff, error)
+}
+
+// HeadcountSnapshotDiff compares the frozen headcount of a date against the live headcount
+type HeadcountSnapshotDiff struct {
+	Date  string             `json:"date"`
+	Meals []MealSnapshotDiff `json:"meals"`
+}
+
+// MealSnapshotDiff compares a single meal. CapturedAt is nil when the meal has no snapshot.
+// Delta is the change in meals served, guests included.
+type MealSnapshotDiff struct {
+	MealType   string              `json:"meal_type"`
+	CapturedAt *time.Time          `json:"captured_at"`
+	Snapshot   MealHeadcount       `json:"snapshot"`
+	Live       MealHeadcount       `json:"live"`
+	Delta      int                 `json:"delta"`
+	Teams      []TeamSnapshotDiff  `json:"teams"`
+	Changes    []ParticipantChange `json:"changes"`
+}
+
+// TeamSnapshotDiff compares a single team's participation for a meal
+type TeamSnapshotDiff struct {
+	TeamID   string        `json:"team_id"`
+	TeamName string        `json:"team_name"`
+	Snapshot MealHeadcount `json:"snapshot"`
+	Live     MealHeadcount `json:"live"`
+	Delta    int           `json:"delta"`
+}
+
+// ParticipantChange is a user whose participation differs between snapshot and live.
+// Snapshot or Live is nil when the user is missing on that side (e.g. deactivated or created after cutoff).
+type ParticipantChange struct {
+	UserID   string            `json:"user_id"`
+	Name     string            `json:"name"`
+	Email    string            `json:"email"`
+	Snapshot *ParticipantState `json:"snapshot"`
+	Live     *ParticipantState `json:"live"`
+}
+
+// ParticipantState is a user's resolved participation on one side of a diff
+type ParticipantState struct {
+	IsParticipating bool   `json:"is_participating"`
+	Source          string `json:"source"`
+}
+
+type headcountSnapshotService struct {
+	snapshotRepo     repository.HeadcountSnapshotRepository
+	headcountService HeadcountService
+	catalog          MealCatalog
+	calendar         ScheduleCalendar
+	cutoffs          CutoffPolicyService
+	timezone         string
+}
+
+// NewHeadcountSnapshotService creates a new headcount snapshot service
+func NewHeadcountSnapshotService(
+	snapshotRepo repository.HeadcountSnapshotRepository,
+	headcountService HeadcountService,
+	catalog MealCatalog,
+	calendar ScheduleCalendar,
+	cutoffs CutoffPolicyService,
+	cfg *config.Config,
+) HeadcountSnapshotService {
+	return &headcountSnapshotService{
+		snapshotRepo:     snapshotRepo,
+		headcountService: headcountService,
+		catalog:          catalog,
+		calendar:         calendar,
+		cutoffs:          cutoffs,
+		timezone:         cfg.Meal.CutoffTimezone,
+	}
+}
+
+// CaptureSnapshot freezes the live headcount of every scheduled meal on a date.
+// Meals that already have a snapshot are left untouched, so capturing is idempotent.
+// capturedBy is nil for the automatic capture at cutoff.
+func (s *headcountSnapshotService) CaptureSnapshot(date string, capturedBy *string) ([]models.HeadcountSnapshot, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+
+	var capturedByID *uuid.UUID
+	if capturedBy != nil {
+		id, err := uuid.Parse(*capturedBy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user ID")
+		}
+		capturedByID = &id
+	}
+
+	if _, err := s.capture(date, capturedByID, nil); err != nil {
+		return nil, err
+	}
+	return s.snapshotRepo.FindByDate(date)
+}
+
+// CaptureDue freezes each scheduled meal whose change deadline has passed by now and that has
+// no snapshot yet, so every meal is captured at its own cutoff. Dates from snapshotCatchUpDays
+// ago up to the furthest deadline a cutoff policy can set are checked. It returns how many
+// meals were captured.
+func (s *headcountSnapshotService) CaptureDue(now time.Time) (int, error) {
+	loc, err := time.LoadLocation(s.timezone)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timezone: %w", err)
+	}
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	startDate := today.AddDate(0, 0, -snapshotCatchUpDays).Format("2006-01-02")
+	endDate := today.AddDate(0, 0, maxCutoffDaysBefore).Format("2006-01-02")
+
+	days, err := s.calendar.EffectiveDays(startDate, endDate, nil)
+	if err != nil {
+		return 0, err
+	}
+	existing, err := s.snapshotRepo.FindByDateRangeWithTeams(startDate, endDate)
+	if err != nil {
+		return 0, err
+	}
+	captured := make(map[string]bool, len(existing))
+	for _, snapshot := range existing {
+		captured[dateKey(snapshot.Date)+"/"+string(snapshot.MealType)] = true
+	}
+
+	total := 0
+	for _, day := range days {
+		var pending []models.MealType
+		for _, mealType := range day.AvailableMeals {
+			if !captured[day.Date+"/"+string(mealType)] {
+				pending = append(pending, mealType)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		deadlines, err := s.cutoffs.Deadlines(day.Date, pending)
+		if err != nil {
+			return total, err
+		}
+		due := make(map[string]bool)
+		for mealType, deadline := range deadlines {
+			if !now.Before(deadline) {
+				due[string(mealType)] = true
+			}
+		}
+		if len(due) == 0 {
+			continue
+		}
+
+		created, err := s.capture(day.Date, nil, due)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Headcount snapshot for %s failed: %v", day.Date, err))
+			continue
+		}
+		total += created
+	}
+	return total, nil
+}
+
+// capture creates the missing snapshots of a date's scheduled meals, only of the meals in
+// only when it is not nil, and returns how many it created
+func (s *headcountSnapshotService) capture(date string, capturedByID *uuid.UUID, only map[string]bool) (int, error) {
+	existing, err := s.snapshotRepo.FindByDate(date)
+	if err != nil {
+		return 0, err
+	}
+	captured := make(map[string]bool, len(existing))
+	for _, snapshot := range existing {
+		captured[string(snapshot.MealType)] = true
+	}
+
+	summary, err := s.headcountService.GetHeadcountByDate(date)
+	if err != nil {
+		return 0, err
+	}
+	if summary == nil {
+		return 0, nil
+	}
+
+	created := 0
+	for _, mealType := range sortedMealKeys(summary.Meals) {
+		if captured[mealType] || (only != nil && !only[mealType]) {
+			continue
+		}
+
+		detail, err := s.headcountService.GetDetailedHeadcount(date, mealType)
+		if err != nil {
+			return created, err
+		}
+
+		counts := summary.Meals[mealType]
+		snapshot := &models.HeadcountSnapshot{
+			Date:             date,
+			MealType:         models.MealType(mealType),
+			DayStatus:        summary.DayStatus,
+			TotalActiveUsers: summary.TotalActiveUsers,
+			Participating:    counts.Participating,
+			OptedOut:         counts.OptedOut,
+			Guests:           counts.Guests,
+			CapturedBy:       capturedByID,
+		}
+
+		for _, team := range summary.Teams {
+			teamID, err := uuid.Parse(team.TeamID)
+			if err != nil {
+				return created, fmt.Errorf("invalid team ID %s: %w", team.TeamID, err)
+			}
+			teamCounts := team.Meals[mealType]
+			snapshot.Teams = append(snapshot.Teams, models.HeadcountSnapshotTeam{
+				TeamID:        teamID,
+				TeamName:      team.TeamName,
+				TotalMembers:  team.TotalMembers,
+				Participating: teamCounts.Participating,
+				OptedOut:      teamCounts.OptedOut,
+				Guests:        teamCounts.Guests,
+			})
+		}
+
+		participants := append(append([]ParticipantInfo{}, detail.Participants...), detail.NonParticipants...)
+		for _, p := range participants {
+			userID, err := uuid.Parse(p.UserID)
+			if err != nil {
+				return created, fmt.Errorf("invalid user ID %s: %w", p.UserID, err)
+			}
+			snapshot.Users = append(snapshot.Users, models.HeadcountSnapshotUser{
+				UserID:          userID,
+				Name:            p.Name,
+				Email:           p.Email,
+				IsParticipating: p.IsParticipating,
+				Source:          p.Source,
+			})
+		}
+
+		if err := s.snapshotRepo.Create(snapshot); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// GetSnapshots returns the snapshots of every meal on a date with team breakdowns
+func (s *headcountSnapshotService) GetSnapshots(date string) ([]models.HeadcountSnapshot, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	return s.snapshotRepo.FindByDate(date)
+}
+
+// GetSnapshot returns the snapshot of a single meal with team and user breakdowns
+func (s *headcountSnapshotService) GetSnapshot(date, mealType string) (*models.HeadcountSnapshot, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	// Retired meal types keep their snapshots, so any catalogued type is accepted
+	definition, err := s.catalog.Lookup(mealType)
+	if err != nil {
+		return nil, err
+	}
+	if definition == nil {
+		return nil, fmt.Errorf("invalid meal type: %s", mealType)
+	}
+
+	snapshot, err := s.snapshotRepo.FindByDateAndMeal(date, mealType)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, fmt.Errorf("no snapshot found for %s on %s", mealType, date)
+	}
+	return snapshot, nil
+}
+
+// DiffSnapshot compares the snapshots of a date against the live headcount.
+// Meals scheduled after the snapshot was taken appear with an empty snapshot side.
+func (s *headcountSnapshotService) DiffSnapshot(date string) (*HeadcountSnapshotDiff, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.snapshotRepo.FindByDateWithDetails(date)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.headcountService.GetHeadcountByDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotByMeal := make(map[string]*models.HeadcountSnapshot, len(snapshots))
+	mealSet := make(map[string]MealHeadcount)
+	for i := range snapshots {
+		mt := string(snapshots[i].MealType)
+		snapshotByMeal[mt] = &snapshots[i]
+		mealSet[mt] = MealHeadcount{}
+	}
+	if summary != nil {
+		for mt := range summary.Meals {
+			mealSet[mt] = MealHeadcount{}
+		}
+	}
+
+	diff := &HeadcountSnapshotDiff{Date: date, Meals: []MealSnapshotDiff{}}
+	for _, mealType := range sortedMealKeys(mealSet) {
+		detail, err := s.headcountService.GetDetailedHeadcount(date, mealType)
+		if err != nil {
+			return nil, err
+		}
+
+		mealDiff := MealSnapshotDiff{
+			MealType: mealType,
+			Live: MealHeadcount{
+				Participating: len(detail.Participants),
+				OptedOut:      len(detail.NonParticipants),
+				Guests:        detail.Guests,
+			},
+			Teams:   []TeamSnapshotDiff{},
+			Changes: []ParticipantChange{},
+		}
+
+		snapshot := snapshotByMeal[mealType]
+		if snapshot != nil {
+			capturedAt := snapshot.CapturedAt
+			mealDiff.CapturedAt = &capturedAt
+			mealDiff.Snapshot = MealHeadcount{
+				Participating: snapshot.Participating,
+				OptedOut:      snapshot.OptedOut,
+				Guests:        snapshot.Guests,
+			}
+		}
+		mealDiff.Delta = mealsServed(mealDiff.Live) - mealsServed(mealDiff.Snapshot)
+		mealDiff.Teams = diffTeams(snapshot, summary, mealType)
+		mealDiff.Changes = diffParticipants(snapshot, detail)
+
+		diff.Meals = append(diff.Meals, mealDiff)
+	}
+
+	return diff, nil
+}
+
+// diffTeams pairs snapshot and live team counts for a meal by team ID
+func diffTeams(snapshot *models.HeadcountSnapshot, summary *DailyHeadcountSummary, mealType string) []TeamSnapshotDiff {
+	teams := []TeamSnapshotDiff{}
+	index := make(map[string]int)
+
+	if snapshot != nil {
+		for _, team := range snapshot.Teams {
+			index[team.TeamID.String()] = len(teams)
+			teams = append(teams, TeamSnapshotDiff{
+				TeamID:   team.TeamID.String(),
+				TeamName: team.TeamName,
+				Snapshot: MealHeadcount{Participating: team.Participating, OptedOut: team.OptedOut, Guests: team.Guests},
+			})
+		}
+	}
+
+	if summary != nil {
+		for _, team := range summary.Teams {
+			live, ok := team.Meals[mealType]
+			if !ok {
+				live = MealHeadcount{OptedOut: team.TotalMembers}
+			}
+			i, found := index[team.TeamID]
+			if !found {
+				i = len(teams)
+				teams = append(teams, TeamSnapshotDiff{TeamID: team.TeamID, TeamName: team.TeamName})
+			}
+			teams[i].Live = live
+		}
+	}
+
+	for i := range teams {
+		teams[i].Delta = mealsServed(teams[i].Live) - mealsServed(teams[i].Snapshot)
+	}
+	return teams
+}
+
+// mealsServed is the number of meals a headcount orders: participants and their guests
+func mealsServed(counts MealHeadcount) int {
+	return counts.Participating + counts.Guests
+}
+
+// diffParticipants lists users whose participation changed, appeared, or disappeared since the snapshot
+func diffParticipants(snapshot *models.HeadcountSnapshot, detail *DetailedHeadcount) []ParticipantChange {
+	changes := []ParticipantChange{}
+	if snapshot == nil {
+		return changes
+	}
+
+	live := make(map[string]ParticipantInfo)
+	for _, p := range detail.Participants {
+		live[p.UserID] = p
+	}
+	for _, p := range detail.NonParticipants {
+		live[p.UserID] = p
+	}
+
+	seen := make(map[string]bool, len(snapshot.Users))
+	for _, u := range snapshot.Users {
+		uid := u.UserID.String()
+		seen[uid] = true
+
+		before := &ParticipantState{IsParticipating: u.IsParticipating, Source: u.Source}
+		current, ok := live[uid]
+		if !ok {
+			changes = append(changes, ParticipantChange{UserID: uid, Name: u.Name, Email: u.Email, Snapshot: before})
+			continue
+		}
+		if current.IsParticipating != u.IsParticipating {
+			changes = append(changes, ParticipantChange{
+				UserID:   uid,
+				Name:     current.Name,
+				Email:    current.Email,
+				Snapshot: before,
+				Live:     &ParticipantState{IsParticipating: current.IsParticipating, Source: current.Source},
+			})
+		}
+	}
+
+	for uid, current := range live {
+		if seen[uid] {
+			continue
+		}
+		changes = append(changes, ParticipantChange{
+			UserID: uid,
+			Name:   current.Name,
+			Email:  current.Email,
+			Live:   &ParticipantState{IsParticipating: current.IsParticipating, Source: current.Source},
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// sortedMealKeys returns the meal types of a headcount map in a stable order
+func sortedMealKeys(meals map[string]MealHeadcount) []string {
+	keys := make([]string, 0, len(meals))
+	for mt := range meals {
+		keys = append(keys, mt)
+	}
+	sort.Strings(keys)
+	return keys
+}
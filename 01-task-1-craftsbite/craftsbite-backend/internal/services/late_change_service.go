@@ -0,0 +1,445 @@
+package services
+
+import (
+	"craftsbite-backend/internal/authz"
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"craftsbite-backend/pkg/logger"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxLateChangeTextLength matches the varchar(255) reason and note columns
+const maxLateChangeTextLength = 255
+
+// errLateChangeNotPending is returned when another reviewer or the requester acted on a
+// request first
+var errLateChangeNotPending = errors.New("request is no longer pending")
+
+// CreateLateChangeInput represents input for requesting a change after the cutoff
+type CreateLateChangeInput struct {
+	Date          string `json:"date" binding:"required"`
+	MealType      string `json:"meal_type" binding:"required"`
+	Participating *bool  `json:"participating" binding:"required"`
+	Reason        string `json:"reason" binding:"required"`
+}
+
+// LateChangeRule is the deadline on the meal day until which late changes to a meal are accepted
+type LateChangeRule struct {
+	MealType models.MealType `json:"meal_type"`
+	Deadline string          `json:"deadline"`
+	Timezone string          `json:"timezone"`
+}
+
+// LateChangeService defines the post-cutoff change request and approval workflow
+type LateChangeService interface {
+	CreateRequest(userID string, input CreateLateChangeInput) (*models.LateChangeRequest, error)
+	ListMyRequests(userID string) ([]models.LateChangeRequest, error)
+	CancelRequest(userID, id string) error
+	ListPending(reviewerID string) ([]models.LateChangeRequest, error)
+	Approve(reviewerID, id, note string) (*models.LateChangeRequest, error)
+	Reject(reviewerID, id, note string) (*models.LateChangeRequest, error)
+	Rules() []LateChangeRule
+}
+
+type lateChangeService struct {
+	lateChangeRepo repository.LateChangeRepository
+	userRepo       repository.UserRepository
+	teamRepo       repository.TeamRepository
+	mealService    MealService
+	cutoffs        CutoffPolicyService
+	authorizer     authz.Authorizer
+	deadlines      map[string]string
+	cutoffTimezone string
+}
+
+// NewLateChangeService creates a new late change service
+func NewLateChangeService(
+	lateChangeRepo repository.LateChangeRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	mealService MealService,
+	cutoffs CutoffPolicyService,
+	authorizer authz.Authorizer,
+	cfg *config.Config,
+) LateChangeService {
+	return &lateChangeService{
+		lateChangeRepo: lateChangeRepo,
+		userRepo:       userRepo,
+		teamRepo:       teamRepo,
+		mealService:    mealService,
+		cutoffs:        cutoffs,
+		authorizer:     authorizer,
+		deadlines:      cfg.Meal.LateChangeDeadlines,
+		cutoffTimezone: cfg.Meal.CutoffTimezone,
+	}
+}
+
+// CreateRequest files a request to opt in or out of a meal whose cutoff has passed.
+// Before the cutoff users change their participation directly instead.
+func (s *lateChangeService) CreateRequest(userID string, input CreateLateChangeInput) (*models.LateChangeRequest, error) {
+	if err := validateDate(input.Date); err != nil {
+		return nil, err
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+	if len(reason) > maxLateChangeTextLength {
+		return nil, fmt.Errorf("reason cannot exceed %d characters", maxLateChangeTextLength)
+	}
+
+	deadline, err := s.deadline(input.Date, input.MealType)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff, err := s.cutoffs.Deadline(input.Date, input.MealType)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if now.Before(cutoff) {
+		return nil, fmt.Errorf("the cutoff for %s on %s has not passed yet, change your participation directly", input.MealType, input.Date)
+	}
+	if now.After(deadline) {
+		return nil, fmt.Errorf("late changes to %s on %s closed at %s", input.MealType, input.Date, deadline.Format("2006-01-02 15:04 MST"))
+	}
+
+	current, err := s.currentParticipation(userID, input.Date, input.MealType)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsParticipating == *input.Participating {
+		state := "opted out of"
+		if current.IsParticipating {
+			state = "participating in"
+		}
+		return nil, fmt.Errorf("you are already %s %s on %s", state, input.MealType, input.Date)
+	}
+
+	existing, err := s.lateChangeRepo.FindPendingByUserDateMeal(userID, input.Date, input.MealType)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("a late change request for %s on %s is already pending", input.MealType, input.Date)
+	}
+
+	request := &models.LateChangeRequest{
+		ID:              uuid.New(),
+		UserID:          uuid.MustParse(userID),
+		Date:            input.Date,
+		MealType:        models.MealType(input.MealType),
+		IsParticipating: *input.Participating,
+		Reason:          reason,
+		Status:          models.LateChangeStatusPending,
+	}
+	if err := s.lateChangeRepo.Create(request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// ListMyRequests returns a user's late change requests, newest first
+func (s *lateChangeService) ListMyRequests(userID string) ([]models.LateChangeRequest, error) {
+	requests, err := s.lateChangeRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return requests, s.expireStale(requests)
+}
+
+// CancelRequest withdraws a user's own pending request
+func (s *lateChangeService) CancelRequest(userID, id string) error {
+	request, err := s.findRequest(id)
+	if err != nil {
+		return err
+	}
+	if request.UserID.String() != userID {
+		return fmt.Errorf("late change request not found")
+	}
+	if request.Status != models.LateChangeStatusPending {
+		return fmt.Errorf("only pending requests can be cancelled (status: %s)", request.Status)
+	}
+
+	request.Status = models.LateChangeStatusCancelled
+	return s.transition(request, models.LateChangeStatusPending)
+}
+
+// ListPending returns the pending requests a reviewer can act on.
+// Team-scoped reviewers (team leads) only see members of the teams they lead.
+func (s *lateChangeService) ListPending(reviewerID string) ([]models.LateChangeRequest, error) {
+	reviewer, err := s.findUser(reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := s.authorizer.ScopeFor(reviewer.Role.String(), authz.PermLateChangeReview)
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []string
+	if scope != authz.ScopeAll {
+		teams, err := s.teamRepo.FindByTeamLeadID(reviewerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load teams: %w", err)
+		}
+		userIDs = []string{}
+		for _, team := range teams {
+			for _, member := range team.Members {
+				if member.ID.String() != reviewerID {
+					userIDs = append(userIDs, member.ID.String())
+				}
+			}
+		}
+	}
+
+	requests, err := s.lateChangeRepo.FindPending(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.expireStale(requests); err != nil {
+		return nil, err
+	}
+
+	pending := make([]models.LateChangeRequest, 0, len(requests))
+	for _, request := range requests {
+		if request.Status == models.LateChangeStatusPending {
+			pending = append(pending, request)
+		}
+	}
+	return pending, nil
+}
+
+// Approve claims a pending request as approved, then applies it through the override path.
+// A request whose deadline has passed is expired instead.
+func (s *lateChangeService) Approve(reviewerID, id, note string) (*models.LateChangeRequest, error) {
+	request, err := s.reviewable(reviewerID, id, note)
+	if err != nil {
+		return nil, err
+	}
+
+	date := dateKey(request.Date)
+	deadline, err := s.deadline(date, string(request.MealType))
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(deadline) {
+		request.Status = models.LateChangeStatusExpired
+		if err := s.transition(request, models.LateChangeStatusPending); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("the late change deadline for %s on %s has passed, the request has expired", request.MealType, date)
+	}
+
+	// Claim the request first so that a concurrent review or cancellation cannot act on it too
+	if err := s.markReviewed(request, reviewerID, models.LateChangeStatusApproved, note); err != nil {
+		return nil, err
+	}
+
+	reason := truncateText("Late change approved: "+request.Reason, maxLateChangeTextLength)
+	if err := s.mealService.ApplyLateChange(reviewerID, request.UserID.String(), date, string(request.MealType), request.IsParticipating, reason); err != nil {
+		s.releaseClaim(request)
+		return nil, err
+	}
+	return s.lateChangeRepo.FindByID(id)
+}
+
+// Reject declines a pending request
+func (s *lateChangeService) Reject(reviewerID, id, note string) (*models.LateChangeRequest, error) {
+	request, err := s.reviewable(reviewerID, id, note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.markReviewed(request, reviewerID, models.LateChangeStatusRejected, note); err != nil {
+		return nil, err
+	}
+	return s.lateChangeRepo.FindByID(id)
+}
+
+// Rules lists the per-meal late change deadlines
+func (s *lateChangeService) Rules() []LateChangeRule {
+	rules := make([]LateChangeRule, 0, len(s.deadlines))
+	for mealType, deadline := range s.deadlines {
+		rules = append(rules, LateChangeRule{
+			MealType: models.MealType(mealType),
+			Deadline: deadline,
+			Timezone: s.cutoffTimezone,
+		})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Deadline < rules[j].Deadline
+	})
+	return rules
+}
+
+// reviewable loads a pending request and checks the reviewer may decide it
+func (s *lateChangeService) reviewable(reviewerID, id, note string) (*models.LateChangeRequest, error) {
+	if len(strings.TrimSpace(note)) > maxLateChangeTextLength {
+		return nil, fmt.Errorf("note cannot exceed %d characters", maxLateChangeTextLength)
+	}
+
+	request, err := s.findRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != models.LateChangeStatusPending {
+		return nil, fmt.Errorf("request has already been %s", request.Status)
+	}
+	if request.UserID.String() == reviewerID {
+		return nil, fmt.Errorf("you cannot review your own request")
+	}
+
+	reviewer, err := s.findUser(reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Authorize(reviewer.Role.String(), reviewerID, authz.PermLateChangeReview, request.UserID.String()); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (s *lateChangeService) markReviewed(request *models.LateChangeRequest, reviewerID string, status models.LateChangeStatus, note string) error {
+	reviewerUUID := uuid.MustParse(reviewerID)
+	now := time.Now()
+	request.Status = status
+	request.ReviewedBy = &reviewerUUID
+	request.ReviewedAt = &now
+	if note = strings.TrimSpace(note); note != "" {
+		request.ReviewNote = &note
+	}
+	return s.transition(request, models.LateChangeStatusPending)
+}
+
+// releaseClaim puts an approved request back to pending when applying it failed
+func (s *lateChangeService) releaseClaim(request *models.LateChangeRequest) {
+	request.Status = models.LateChangeStatusPending
+	request.ReviewedBy = nil
+	request.ReviewedAt = nil
+	request.ReviewNote = nil
+	if err := s.transition(request, models.LateChangeStatusApproved); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to release late change request %s after it could not be applied: %v", request.ID, err))
+	}
+}
+
+// transition saves a request's new status if it still has status from
+func (s *lateChangeService) transition(request *models.LateChangeRequest, from models.LateChangeStatus) error {
+	updated, err := s.lateChangeRepo.UpdateIfStatus(request, from)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errLateChangeNotPending
+	}
+	return nil
+}
+
+// expireStale marks pending requests whose deadline has passed as expired
+func (s *lateChangeService) expireStale(requests []models.LateChangeRequest) error {
+	now := time.Now()
+	for i := range requests {
+		request := &requests[i]
+		if request.Status != models.LateChangeStatusPending {
+			continue
+		}
+		deadline, err := s.deadline(dateKey(request.Date), string(request.MealType))
+		// A meal whose rule was removed no longer accepts late changes
+		if err == nil && !now.After(deadline) {
+			continue
+		}
+		request.Status = models.LateChangeStatusExpired
+		err = s.transition(request, models.LateChangeStatusPending)
+		if errors.Is(err, errLateChangeNotPending) {
+			// Reviewed or cancelled in the meantime; report what happened instead
+			current, err := s.lateChangeRepo.FindByID(request.ID.String())
+			if err != nil {
+				return err
+			}
+			if current != nil {
+				*request = *current
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deadline returns when late changes to a meal on a date close
+func (s *lateChangeService) deadline(date, mealType string) (time.Time, error) {
+	clock, ok := s.deadlines[mealType]
+	if !ok {
+		return time.Time{}, fmt.Errorf("late changes are not accepted for %s", mealType)
+	}
+	mealDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format, expected YYYY-MM-DD")
+	}
+	return clockTimeOn(mealDate, clock, s.cutoffTimezone)
+}
+
+// currentParticipation resolves the user's participation, failing if the meal is not served that day
+func (s *lateChangeService) currentParticipation(userID, date, mealType string) (*ParticipationStatus, error) {
+	statuses, err := s.mealService.GetParticipation(userID, date)
+	if err != nil {
+		return nil, err
+	}
+	for i := range statuses {
+		if string(statuses[i].MealType) != mealType {
+			continue
+		}
+		if statuses[i].Source == "weekend" || statuses[i].Source == "day_schedule" {
+			return nil, fmt.Errorf("no meals are served on %s", date)
+		}
+		return &statuses[i], nil
+	}
+	return nil, fmt.Errorf("%s is not available on %s", mealType, date)
+}
+
+func (s *lateChangeService) findRequest(id string) (*models.LateChangeRequest, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid request ID")
+	}
+	request, err := s.lateChangeRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, fmt.Errorf("late change request not found")
+	}
+	return request, nil
+}
+
+func (s *lateChangeService) findUser(userID string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	return user, nil
+}
+
+// truncateText cuts s to at most max bytes without splitting a UTF-8 character
+func truncateText(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
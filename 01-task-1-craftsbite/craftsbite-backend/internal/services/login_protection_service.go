@@ -0,0 +1,302 @@
+package services
+
+import (
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"craftsbite-backend/pkg/logger"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAuditLogLimit = 100
+	maxAuditLogLimit     = 500
+)
+
+// LoginThrottledError refuses a login attempt while the account or client IP is locked
+// out, or before the delay after the account's last failed login has passed
+type LoginThrottledError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	seconds := int(math.Ceil(e.RetryAfter.Seconds()))
+	if e.Locked {
+		return fmt.Sprintf("too many failed login attempts, login is locked for %d more seconds", seconds)
+	}
+	return fmt.Sprintf("too many failed login attempts, try again in %d seconds", seconds)
+}
+
+// LoginProtectionService slows down and locks out repeated failed logins. Failures are
+// counted per account, by email so unknown addresses behave like real ones, and per client IP.
+type LoginProtectionService interface {
+	CheckLogin(email string, client ClientInfo) error
+	RecordFailure(email string, user *models.User, client ClientInfo) error
+	RecordSuccess(email string) error
+	ListLockouts() ([]models.LoginAttempt, error)
+	Unlock(actorID, lockoutID string) error
+	UnlockUser(actorID, userID string) error
+	ListAuditLog(event, userID, startDate, endDate string, limit int) ([]models.AuthAuditLog, error)
+}
+
+type loginProtectionService struct {
+	attemptRepo repository.LoginAttemptRepository
+	auditRepo   repository.AuthAuditRepository
+	userRepo    repository.UserRepository
+	cfg         config.LoginConfig
+}
+
+// NewLoginProtectionService creates a new login protection service
+func NewLoginProtectionService(
+	attemptRepo repository.LoginAttemptRepository,
+	auditRepo repository.AuthAuditRepository,
+	userRepo repository.UserRepository,
+	cfg *config.Config,
+) LoginProtectionService {
+	return &loginProtectionService{
+		attemptRepo: attemptRepo,
+		auditRepo:   auditRepo,
+		userRepo:    userRepo,
+		cfg:         cfg.Login,
+	}
+}
+
+// CheckLogin returns a *LoginThrottledError when a login attempt must be refused without
+// looking at the password
+func (s *loginProtectionService) CheckLogin(email string, client ClientInfo) error {
+	now := time.Now()
+
+	account, err := s.attemptRepo.Find(models.LoginSubjectAccount, normalizeEmail(email))
+	if err != nil {
+		return err
+	}
+	if account != nil {
+		if account.IsLocked(now) {
+			return &LoginThrottledError{Locked: true, RetryAfter: account.LockedUntil.Sub(now)}
+		}
+		if wait := s.retryDelay(account.Failures) - now.Sub(account.LastFailureAt); wait > 0 {
+			return &LoginThrottledError{RetryAfter: wait}
+		}
+	}
+
+	if client.IPAddress == "" {
+		return nil
+	}
+	ip, err := s.attemptRepo.Find(models.LoginSubjectIP, client.IPAddress)
+	if err != nil {
+		return err
+	}
+	if ip != nil && ip.IsLocked(now) {
+		return &LoginThrottledError{Locked: true, RetryAfter: ip.LockedUntil.Sub(now)}
+	}
+	return nil
+}
+
+// retryDelay is how long an account waits after its latest failure: RetryDelay doubled for
+// every earlier failure, capped at the lockout duration
+func (s *loginProtectionService) retryDelay(failures int) time.Duration {
+	if failures <= 0 || s.cfg.RetryDelay <= 0 {
+		return 0
+	}
+	delay := s.cfg.RetryDelay
+	for i := 1; i < failures && delay < s.cfg.LockoutDuration; i++ {
+		delay *= 2
+	}
+	if delay > s.cfg.LockoutDuration {
+		delay = s.cfg.LockoutDuration
+	}
+	return delay
+}
+
+// RecordFailure counts a failed login against the account and the client IP and locks
+// whichever reached its limit. user is nil when no account has the email.
+func (s *loginProtectionService) RecordFailure(email string, user *models.User, client ClientInfo) error {
+	now := time.Now()
+	windowStart := now.Add(-s.cfg.FailureWindow)
+	email = normalizeEmail(email)
+
+	account, err := s.attemptRepo.RecordFailure(models.LoginSubjectAccount, email, now, windowStart)
+	if err != nil {
+		return err
+	}
+	if account.Failures >= s.cfg.MaxAccountFailures && !account.IsLocked(now) {
+		if err := s.lock(account, now); err != nil {
+			return err
+		}
+		entry := newAuthAuditLog(models.AuthEventAccountLocked, client)
+		entry.Email = &email
+		if user != nil {
+			entry.UserID = &user.ID
+		}
+		s.audit(entry, fmt.Sprintf("%d failed logins, locked until %s", account.Failures, account.LockedUntil.Format(time.RFC3339)))
+	}
+
+	if client.IPAddress == "" {
+		return nil
+	}
+	ip, err := s.attemptRepo.RecordFailure(models.LoginSubjectIP, client.IPAddress, now, windowStart)
+	if err != nil {
+		return err
+	}
+	if ip.Failures >= s.cfg.MaxIPFailures && !ip.IsLocked(now) {
+		if err := s.lock(ip, now); err != nil {
+			return err
+		}
+		entry := newAuthAuditLog(models.AuthEventIPLocked, client)
+		entry.Email = &email
+		s.audit(entry, fmt.Sprintf("%d failed logins, locked until %s", ip.Failures, ip.LockedUntil.Format(time.RFC3339)))
+	}
+	return nil
+}
+
+func (s *loginProtectionService) lock(attempt *models.LoginAttempt, now time.Time) error {
+	until := now.Add(s.cfg.LockoutDuration)
+	if err := s.attemptRepo.Lock(attempt.ID.String(), until); err != nil {
+		return err
+	}
+	attempt.LockedUntil = &until
+	return nil
+}
+
+// RecordSuccess clears the account's failures. The client IP's failures are kept so that
+// logging in to one account does not reset guessing at others.
+func (s *loginProtectionService) RecordSuccess(email string) error {
+	return s.attemptRepo.Delete(models.LoginSubjectAccount, normalizeEmail(email))
+}
+
+// ListLockouts returns the accounts and IPs that are locked out
+func (s *loginProtectionService) ListLockouts() ([]models.LoginAttempt, error) {
+	return s.attemptRepo.FindLocked(time.Now())
+}
+
+// Unlock lifts a lockout and clears its failures
+func (s *loginProtectionService) Unlock(actorID, lockoutID string) error {
+	attempt, err := s.attemptRepo.FindByID(lockoutID)
+	if err != nil {
+		return err
+	}
+	if attempt == nil {
+		return fmt.Errorf("lockout not found")
+	}
+
+	var user *models.User
+	if attempt.SubjectType == models.LoginSubjectAccount {
+		// The counter may belong to an address without an account
+		user, _ = s.userRepo.FindByEmail(attempt.Subject)
+	}
+	return s.unlock(actorID, attempt, user)
+}
+
+// UnlockUser lifts the lockout of a user's account and clears its failures
+func (s *loginProtectionService) UnlockUser(actorID, userID string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	attempt, err := s.attemptRepo.Find(models.LoginSubjectAccount, normalizeEmail(user.Email))
+	if err != nil {
+		return err
+	}
+	if attempt == nil {
+		return fmt.Errorf("user has no failed logins")
+	}
+	return s.unlock(actorID, attempt, user)
+}
+
+func (s *loginProtectionService) unlock(actorID string, attempt *models.LoginAttempt, user *models.User) error {
+	actorUUID, err := uuid.Parse(actorID)
+	if err != nil {
+		return fmt.Errorf("invalid actor ID")
+	}
+
+	if err := s.attemptRepo.Delete(attempt.SubjectType, attempt.Subject); err != nil {
+		return err
+	}
+
+	entry := &models.AuthAuditLog{ActorID: &actorUUID}
+	if attempt.SubjectType == models.LoginSubjectIP {
+		entry.Event = models.AuthEventIPUnlocked
+		entry.IPAddress = &attempt.Subject
+	} else {
+		entry.Event = models.AuthEventAccountUnlocked
+		entry.Email = &attempt.Subject
+		if user != nil {
+			entry.UserID = &user.ID
+		}
+	}
+	s.audit(entry, fmt.Sprintf("cleared %d failed logins", attempt.Failures))
+	return nil
+}
+
+// ListAuditLog returns auth audit entries, newest first, optionally narrowed to an event,
+// a user and a YYYY-MM-DD date range
+func (s *loginProtectionService) ListAuditLog(event, userID, startDate, endDate string, limit int) ([]models.AuthAuditLog, error) {
+	filter := repository.AuthAuditFilter{Event: event, UserID: userID, Limit: limit}
+	if userID != "" {
+		if _, err := uuid.Parse(userID); err != nil {
+			return nil, fmt.Errorf("invalid user ID")
+		}
+	}
+	if startDate != "" {
+		since, err := time.Parse("2006-01-02", startDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date format, expected YYYY-MM-DD")
+		}
+		filter.Since = &since
+	}
+	if endDate != "" {
+		end, err := time.Parse("2006-01-02", endDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end date format, expected YYYY-MM-DD")
+		}
+		until := end.AddDate(0, 0, 1)
+		filter.Until = &until
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLogLimit
+	}
+	if filter.Limit > maxAuditLogLimit {
+		filter.Limit = maxAuditLogLimit
+	}
+
+	return s.auditRepo.FindAll(filter)
+}
+
+func (s *loginProtectionService) audit(entry *models.AuthAuditLog, details string) {
+	recordAuthEvent(s.auditRepo, entry, details)
+}
+
+// recordAuthEvent writes an audit entry. A failed write is logged rather than failing the
+// request that caused the event.
+func recordAuthEvent(auditRepo repository.AuthAuditRepository, entry *models.AuthAuditLog, details string) {
+	if details != "" {
+		entry.Details = &details
+	}
+	if err := auditRepo.Create(entry); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to record auth event %s: %v", entry.Event, err))
+	}
+}
+
+// newAuthAuditLog starts an audit entry for an event caused by a client
+func newAuthAuditLog(event models.AuthAuditEvent, client ClientInfo) *models.AuthAuditLog {
+	entry := &models.AuthAuditLog{Event: event}
+	if client.IPAddress != "" {
+		entry.IPAddress = &client.IPAddress
+	}
+	if client.UserAgent != "" {
+		entry.UserAgent = &client.UserAgent
+	}
+	return entry
+}
+
+// normalizeEmail is the form of an email address failed logins are counted under
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// mealCatalogTTL bounds how stale the cached catalogue can be when another instance edits it
+const mealCatalogTTL = time.Minute
+
+// mealTypeCodePattern matches the meal_types.code check constraint
+var mealTypeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// CreateMealTypeInput represents input for adding a meal type
+type CreateMealTypeInput struct {
+	Code                 string `json:"code" binding:"required"`
+	Label                string `json:"label" binding:"required"`
+	Emoji                string `json:"emoji"`
+	SortOrder            int    `json:"sort_order"`
+	DefaultAvailable     bool   `json:"default_available"`
+	DefaultParticipating *bool  `json:"default_participating"`
+}
+
+// UpdateMealTypeInput represents input for editing a meal type; omitted fields are left unchanged
+type UpdateMealTypeInput struct {
+	Label                *string `json:"label"`
+	Emoji                *string `json:"emoji"`
+	SortOrder            *int    `json:"sort_order"`
+	DefaultAvailable     *bool   `json:"default_available"`
+	DefaultParticipating *bool   `json:"default_participating"`
+	IsActive             *bool   `json:"is_active"`
+}
+
+// MealCatalog is the database-backed catalogue of meal types. Validation and rendering of meal types
+// go through it so new meals can be added without a redeploy.
+type MealCatalog interface {
+	// List returns the meal types in display order, optionally including retired ones
+	List(includeInactive bool) ([]models.MealTypeDefinition, error)
+	// Lookup returns a meal type by code, active or not, or nil if it is unknown
+	Lookup(code string) (*models.MealTypeDefinition, error)
+	// Validate returns the code of an active meal type, or an error listing the valid codes
+	Validate(code string) (models.MealType, error)
+	// ActiveCodes returns the codes of the active meal types in display order
+	ActiveCodes() ([]models.MealType, error)
+	// DefaultMeals returns the meals served on a day whose schedule does not list any
+	DefaultMeals() ([]models.MealType, error)
+
+	Create(input CreateMealTypeInput) (*models.MealTypeDefinition, error)
+	Update(code string, input UpdateMealTypeInput) (*models.MealTypeDefinition, error)
+	// Deactivate retires a meal type; it stays in the catalogue so past records keep rendering
+	Deactivate(code string) error
+}
+
+type mealCatalog struct {
+	mealTypeRepo repository.MealTypeRepository
+
+	mu       sync.RWMutex
+	entries  []models.MealTypeDefinition
+	loadedAt time.Time
+}
+
+// NewMealCatalog creates a meal catalogue backed by the meal_types table
+func NewMealCatalog(mealTypeRepo repository.MealTypeRepository) MealCatalog {
+	return &mealCatalog{mealTypeRepo: mealTypeRepo}
+}
+
+func (c *mealCatalog) List(includeInactive bool) ([]models.MealTypeDefinition, error) {
+	entries, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.MealTypeDefinition, 0, len(entries))
+	for _, entry := range entries {
+		if includeInactive || entry.IsActive {
+			list = append(list, entry)
+		}
+	}
+	return list, nil
+}
+
+func (c *mealCatalog) Lookup(code string) (*models.MealTypeDefinition, error) {
+	entries, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if string(entries[i].Code) == code {
+			entry := entries[i]
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *mealCatalog) Validate(code string) (models.MealType, error) {
+	active, err := c.ActiveCodes()
+	if err != nil {
+		return "", err
+	}
+	for _, mealType := range active {
+		if string(mealType) == code {
+			return mealType, nil
+		}
+	}
+	return "", fmt.Errorf("invalid meal_type '%s': must be one of %s", code, strings.Join(mealTypeKeys(active), ", "))
+}
+
+func (c *mealCatalog) ActiveCodes() ([]models.MealType, error) {
+	entries, err := c.List(false)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]models.MealType, len(entries))
+	for i, entry := range entries {
+		codes[i] = entry.Code
+	}
+	return codes, nil
+}
+
+func (c *mealCatalog) DefaultMeals() ([]models.MealType, error) {
+	entries, err := c.List(false)
+	if err != nil {
+		return nil, err
+	}
+	meals := []models.MealType{}
+	for _, entry := range entries {
+		if entry.DefaultAvailable {
+			meals = append(meals, entry.Code)
+		}
+	}
+	return meals, nil
+}
+
+func (c *mealCatalog) Create(input CreateMealTypeInput) (*models.MealTypeDefinition, error) {
+	code := strings.ToLower(strings.TrimSpace(input.Code))
+	if !mealTypeCodePattern.MatchString(code) || len(code) > 50 {
+		return nil, fmt.Errorf("invalid code: use lowercase letters, digits and underscores, starting with a letter")
+	}
+	label := strings.TrimSpace(input.Label)
+	if label == "" {
+		return nil, fmt.Errorf("label is required")
+	}
+
+	existing, err := c.mealTypeRepo.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("meal type '%s' already exists", code)
+	}
+
+	mealType := &models.MealTypeDefinition{
+		Code:                 models.MealType(code),
+		Label:                label,
+		Emoji:                strings.TrimSpace(input.Emoji),
+		SortOrder:            input.SortOrder,
+		DefaultAvailable:     input.DefaultAvailable,
+		DefaultParticipating: input.DefaultParticipating == nil || *input.DefaultParticipating,
+		IsActive:             true,
+	}
+	if err := validateMealTypeDisplay(mealType); err != nil {
+		return nil, err
+	}
+	if err := c.mealTypeRepo.Create(mealType); err != nil {
+		return nil, err
+	}
+
+	c.invalidate()
+	return mealType, nil
+}
+
+func (c *mealCatalog) Update(code string, input UpdateMealTypeInput) (*models.MealTypeDefinition, error) {
+	mealType, err := c.find(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Label != nil {
+		mealType.Label = strings.TrimSpace(*input.Label)
+		if mealType.Label == "" {
+			return nil, fmt.Errorf("label cannot be empty")
+		}
+	}
+	if input.Emoji != nil {
+		mealType.Emoji = strings.TrimSpace(*input.Emoji)
+	}
+	if input.SortOrder != nil {
+		mealType.SortOrder = *input.SortOrder
+	}
+	if input.DefaultAvailable != nil {
+		mealType.DefaultAvailable = *input.DefaultAvailable
+	}
+	if input.DefaultParticipating != nil {
+		mealType.DefaultParticipating = *input.DefaultParticipating
+	}
+	if input.IsActive != nil {
+		mealType.IsActive = *input.IsActive
+	}
+	if err := validateMealTypeDisplay(mealType); err != nil {
+		return nil, err
+	}
+
+	if err := c.mealTypeRepo.Update(mealType); err != nil {
+		return nil, err
+	}
+
+	c.invalidate()
+	return mealType, nil
+}
+
+func (c *mealCatalog) Deactivate(code string) error {
+	mealType, err := c.find(code)
+	if err != nil {
+		return err
+	}
+	if !mealType.IsActive {
+		return fmt.Errorf("meal type '%s' is already inactive", code)
+	}
+
+	mealType.IsActive = false
+	if err := c.mealTypeRepo.Update(mealType); err != nil {
+		return err
+	}
+
+	c.invalidate()
+	return nil
+}
+
+func (c *mealCatalog) find(code string) (*models.MealTypeDefinition, error) {
+	mealType, err := c.mealTypeRepo.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if mealType == nil {
+		return nil, fmt.Errorf("meal type '%s' not found", code)
+	}
+	return mealType, nil
+}
+
+func (c *mealCatalog) invalidate() {
+	c.mu.Lock()
+	c.entries = nil
+	c.mu.Unlock()
+}
+
+// load returns the catalogue, reloading it from the database when stale
+func (c *mealCatalog) load() ([]models.MealTypeDefinition, error) {
+	c.mu.RLock()
+	entries, loadedAt := c.entries, c.loadedAt
+	c.mu.RUnlock()
+	if entries != nil && time.Since(loadedAt) < mealCatalogTTL {
+		return entries, nil
+	}
+
+	entries, err := c.mealTypeRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries = entries
+	c.loadedAt = time.Now()
+	c.mu.Unlock()
+
+	return entries, nil
+}
+
+// validateMealTypeDisplay checks the display fields against their column sizes
+func validateMealTypeDisplay(mealType *models.MealTypeDefinition) error {
+	if utf8.RuneCountInString(mealType.Label) > 100 {
+		return fmt.Errorf("label cannot exceed 100 characters")
+	}
+	if utf8.RuneCountInString(mealType.Emoji) > 16 {
+		return fmt.Errorf("emoji cannot exceed 16 characters")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"craftsbite-backend/internal/authz"
 	"craftsbite-backend/internal/config"
 	"craftsbite-backend/internal/models"
 	"craftsbite-backend/internal/repository"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // MealService defines the interface for meal participation business logic
@@ -17,8 +19,15 @@ type MealService interface {
 	GetParticipation(userID, date string) ([]ParticipationStatus, error)
 	SetParticipation(userID, date, mealType string, participating bool) error
 	OverrideParticipation(adminID, userID, date, mealType string, participating bool, reason string) error
+	// ApplyLateChange writes an approved post-cutoff change as an override by the reviewer.
+	// It skips the cutoff check, so callers must have authorized the reviewer.
+	ApplyLateChange(reviewerID, userID, date, mealType string, participating bool, reason string) error
+	// ValidateChangeWindow checks that a meal on a date can still be changed: within the forward window
+	// and before the meal's cutoff
+	ValidateChangeWindow(date, mealType string) error
 	GetTeamParticipation(teamLeadID, date string) (*TeamParticipationResponse, error)
 	GetAllTeamsParticipation(date string) (*TeamParticipationResponse, error)
+	GetAllTeamsParticipationRange(startDate, endDate string) ([]*TeamParticipationResponse, error)
 }
 
 // TodayMealsResponse represents the response for today's meals
@@ -27,26 +36,33 @@ type TodayMealsResponse struct {
 	DayStatus      models.DayStatus      `json:"day_status"`
 	AvailableMeals []models.MealType     `json:"available_meals"`
 	Participations []ParticipationStatus `json:"participations"`
+	Menus          []models.Menu         `json:"menus"`
 }
 
 // ParticipationStatus represents a user's participation status for a meal
 type ParticipationStatus struct {
-	MealType        models.MealType `json:"meal_type"`
-	IsParticipating bool            `json:"is_participating"`
-	Source          string          `json:"source"`
+	MealType         models.MealType `json:"meal_type"`
+	IsParticipating  bool            `json:"is_participating"`
+	Source           string          `json:"source"`
+	Deadline         time.Time       `json:"deadline"`
+	Waitlisted       bool            `json:"waitlisted"`
+	WaitlistPosition int             `json:"waitlist_position,omitempty"`
 }
 
 // mealService implements MealService
 type mealService struct {
 	mealRepo       repository.MealRepository
-	scheduleRepo   repository.ScheduleRepository
+	scheduleRepo   ScheduleCalendar
 	historyRepo    repository.HistoryRepository
 	userRepo       repository.UserRepository
 	teamRepo       repository.TeamRepository
     wlRepo              repository.WorkLocationRepository
+	menuRepo       repository.MenuRepository
 	resolver       ParticipationResolver
-	cutoffTime     string
-	cutoffTimezone string
+	cutoffs        CutoffPolicyService
+	capacity       CapacityService
+	catalog        MealCatalog
+	authorizer     authz.Authorizer
     forwardWindowDays int
     monthlyWFHAllowance int
 }
@@ -74,12 +90,17 @@ type TeamParticipationResponse struct {
 // NewMealService creates a new meal service
 func NewMealService(
 	mealRepo repository.MealRepository,
-	scheduleRepo repository.ScheduleRepository,
+	scheduleRepo ScheduleCalendar,
 	historyRepo repository.HistoryRepository,
 	userRepo repository.UserRepository,
 	teamRepo repository.TeamRepository,
 	workLocationRepo repository.WorkLocationRepository,
+	menuRepo repository.MenuRepository,
 	resolver ParticipationResolver,
+	cutoffs CutoffPolicyService,
+	capacity CapacityService,
+	catalog MealCatalog,
+	authorizer authz.Authorizer,
 	cfg *config.Config,
 ) MealService {
 	return &mealService{
@@ -89,9 +110,12 @@ func NewMealService(
 		userRepo:       userRepo,
 		teamRepo:       teamRepo,
 		wlRepo:         workLocationRepo,
+		menuRepo:       menuRepo,
 		resolver:       resolver,
-		cutoffTime:     cfg.Meal.CutoffTime,
-		cutoffTimezone: cfg.Meal.CutoffTimezone,
+		cutoffs:        cutoffs,
+		capacity:       capacity,
+		catalog:        catalog,
+		authorizer:     authorizer,
 	    forwardWindowDays: cfg.Meal.ForwardWindowDays,
 		monthlyWFHAllowance: cfg.WorkLocation.MonthlyWFHAllowance,
 	}
@@ -125,18 +149,18 @@ func (s *mealService) GetTodayMeals(userID string) (*TodayMealsResponse, error)
 	}
 
 	// Resolve participation for each available meal
-	for _, mealType := range response.AvailableMeals {
-		isParticipating, source, err := s.resolver.ResolveParticipation(userID, tomorrow, string(mealType))
-		if err != nil {
-			return nil, err
-		}
+	participations, err := s.resolveUserMeals(userID, tomorrow, response.AvailableMeals)
+	if err != nil {
+		return nil, err
+	}
+	response.Participations = participations
 
-		response.Participations = append(response.Participations, ParticipationStatus{
-			MealType:        mealType,
-			IsParticipating: isParticipating,
-			Source:          source,
-		})
+	// Published menus of tomorrow's meals
+	menus, err := s.menuRepo.FindByDateRange(tomorrow, tomorrow, true)
+	if err != nil {
+		return nil, err
 	}
+	response.Menus = menus
 
 	return response, nil
 }
@@ -154,37 +178,75 @@ func (s *mealService) GetParticipation(userID, date string) ([]ParticipationStat
 		return nil, err
 	}
 
-	availableMeals := []models.MealType{models.MealTypeLunch, models.MealTypeSnacks}
+	availableMeals, err := s.catalog.DefaultMeals()
+	if err != nil {
+		return nil, err
+	}
 	if schedule != nil && schedule.AvailableMeals != nil {
 		availableMeals = parseMealTypes(*schedule.AvailableMeals)
 	}
 
-	participations := []ParticipationStatus{}
-	for _, mealType := range availableMeals {
-		isParticipating, source, err := s.resolver.ResolveParticipation(userID, date, string(mealType))
-		if err != nil {
-			return nil, err
-		}
+	return s.resolveUserMeals(userID, date, availableMeals)
+}
 
+// resolveUserMeals resolves a single user's participation and the change deadline for each of the given meals
+func (s *mealService) resolveUserMeals(userID, date string, mealTypes []models.MealType) ([]ParticipationStatus, error) {
+	resolution, err := s.resolver.ResolveBatch([]string{userID}, []string{date}, mealTypeKeys(mealTypes))
+	if err != nil {
+		return nil, err
+	}
+
+	deadlines, err := s.cutoffs.Deadlines(date, mealTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := s.capacity.WaitlistPositions(userID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	participations := []ParticipationStatus{}
+	for _, mealType := range mealTypes {
+		res, _ := resolution.Get(userID, date, string(mealType))
 		participations = append(participations, ParticipationStatus{
-			MealType:        mealType,
-			IsParticipating: isParticipating,
-			Source:          source,
+			MealType:         mealType,
+			IsParticipating:  res.IsParticipating,
+			Source:           res.Source,
+			Deadline:         deadlines[mealType],
+			Waitlisted:       positions[mealType] > 0,
+			WaitlistPosition: positions[mealType],
 		})
 	}
 
 	return participations, nil
 }
 
-// SetParticipation sets a user's participation for a specific date and meal
+// SetParticipation sets a user's participation for a specific date and meal.
+// Opting in to a full meal puts the user on its waitlist instead; opting out frees a seat for the next in line.
 func (s *mealService) SetParticipation(userID, date, mealType string, participating bool) error {
-	err := s.validateDateWindow(date)
+	err := s.validateDateWindow(date, mealType)
 	if err != nil {
 		return err
 	}
 
+	if participating {
+		_, err := s.capacity.RequestSeat(userID, date, mealType, func(tx *gorm.DB) error {
+			return s.writeParticipation(tx, userID, date, mealType, true)
+		})
+		return err
+	}
+	return s.capacity.UpdateSeat(userID, date, mealType, false, func(tx *gorm.DB) error {
+		return s.writeParticipation(tx, userID, date, mealType, false)
+	})
+}
+
+// writeParticipation upserts a user's own participation and records it in history
+func (s *mealService) writeParticipation(tx *gorm.DB, userID, date, mealType string, participating bool) error {
+	mealRepo := repository.NewMealRepository(tx)
+
 	// Check if existing record exists to get its ID for proper upsert
-	existing, err := s.mealRepo.FindByUserDateMeal(userID, date, mealType)
+	existing, err := mealRepo.FindByUserDateMeal(userID, date, mealType)
 	if err != nil {
 		return fmt.Errorf("failed to check existing participation: %w", err)
 	}
@@ -212,7 +274,7 @@ func (s *mealService) SetParticipation(userID, date, mealType string, participat
 		participation.OptedOutAt = &now
 	}
 
-	if err := s.mealRepo.CreateOrUpdate(participation); err != nil {
+	if err := mealRepo.CreateOrUpdate(participation); err != nil {
 		return err
 	}
 
@@ -230,13 +292,13 @@ func (s *mealService) SetParticipation(userID, date, mealType string, participat
 		Action:   action,
 	}
 
-	return s.historyRepo.Create(history)
+	return repository.NewHistoryRepository(tx).Create(history)
 }
 
-// OverrideParticipation allows an admin or team lead to override a user's participation
-// Team leads can only override their own team members
+// OverrideParticipation allows a user holding participation:override to override a user's participation
+// Team-scoped grants can only override their own team members
 func (s *mealService) OverrideParticipation(requesterID, userID, date, mealType string, participating bool, reason string) error {
-	err := s.validateDateWindow(date)
+	err := s.validateDateWindow(date, mealType)
 	if err != nil {
 		return err
 	}
@@ -253,20 +315,47 @@ func (s *mealService) OverrideParticipation(requesterID, userID, date, mealType
 		return fmt.Errorf("failed to find requester: %w", err)
 	}
 
-	// If requester is a team lead, verify they manage the target user
-	if requester.Role == models.RoleTeamLead {
-		isMember, err := s.teamRepo.IsUserInAnyTeamLedBy(requesterID, userID)
-		if err != nil {
-			return fmt.Errorf("failed to check team membership: %w", err)
-		}
-		if !isMember {
-			return fmt.Errorf("team lead can only override participation for their own team members")
-		}
+	// Team-scoped grants (team leads) only cover members of the teams they lead
+	if err := s.authorizer.Authorize(requester.Role.String(), requesterID, authz.PermParticipationOverride, userID); err != nil {
+		return err
 	}
-	// Admin and Logistics roles can override anyone (no additional check needed)
+
+	return s.applyOverride(requesterUUID, userID, date, mealType, participating, reason)
+}
+
+// ApplyLateChange records an approved late change request as an override by the reviewer
+func (s *mealService) ApplyLateChange(reviewerID, userID, date, mealType string, participating bool, reason string) error {
+	if err := validateDate(date); err != nil {
+		return err
+	}
+
+	reviewerUUID, err := uuid.Parse(reviewerID)
+	if err != nil {
+		return fmt.Errorf("invalid reviewer ID: %w", err)
+	}
+
+	return s.applyOverride(reviewerUUID, userID, date, mealType, participating, reason)
+}
+
+// ValidateChangeWindow checks the same date window and cutoff as SetParticipation
+func (s *mealService) ValidateChangeWindow(date, mealType string) error {
+	return s.validateDateWindow(date, mealType)
+}
+
+// applyOverride upserts a participation on behalf of another user and records it in history.
+// Overrides bypass meal capacity; an override out frees a seat for the waitlist.
+func (s *mealService) applyOverride(requesterUUID uuid.UUID, userID, date, mealType string, participating bool, reason string) error {
+	return s.capacity.UpdateSeat(userID, date, mealType, participating, func(tx *gorm.DB) error {
+		return s.writeOverride(tx, requesterUUID, userID, date, mealType, participating, reason)
+	})
+}
+
+// writeOverride upserts an overridden participation and records it in history
+func (s *mealService) writeOverride(tx *gorm.DB, requesterUUID uuid.UUID, userID, date, mealType string, participating bool, reason string) error {
+	mealRepo := repository.NewMealRepository(tx)
 
 	// Check if existing record exists to get its ID for proper upsert
-	existing, err := s.mealRepo.FindByUserDateMeal(userID, date, mealType)
+	existing, err := mealRepo.FindByUserDateMeal(userID, date, mealType)
 	if err != nil {
 		return fmt.Errorf("failed to check existing participation: %w", err)
 	}
@@ -289,7 +378,7 @@ func (s *mealService) OverrideParticipation(requesterID, userID, date, mealType
 		OverrideReason:  &reason,
 	}
 
-	if err := s.mealRepo.CreateOrUpdate(participation); err != nil {
+	if err := mealRepo.CreateOrUpdate(participation); err != nil {
 		return err
 	}
 
@@ -318,58 +407,77 @@ func (s *mealService) OverrideParticipation(requesterID, userID, date, mealType
 		ChangedByUserID: &requesterUUID,
 	}
 
-	return s.historyRepo.Create(history)
+	return repository.NewHistoryRepository(tx).Create(history)
 }
 
-// validateCutoffTime checks if the current time is before the cutoff time for the given date
-// Cutoff is on the PREVIOUS day at the configured time (e.g., 9:00 PM the day before)
-func (s *mealService) validateCutoffTime(targetDate time.Time) error {
-	// Load timezone
-	loc, err := time.LoadLocation(s.cutoffTimezone)
-	if err != nil {
-		return fmt.Errorf("invalid timezone: %w", err)
-	}
-
-	// Parse cutoff time (e.g., "21:00" for 9:00 PM)
-	cutoffParts := s.cutoffTime
-	cutoffTime, err := time.Parse("15:04", cutoffParts)
+// validateCutoffTime checks if the current time is before the meal's cutoff for the given date
+// The cutoff comes from the meal's cutoff policy (e.g., 9:00 PM the day before for lunch)
+func (s *mealService) validateCutoffTime(date, mealType string) error {
+	cutoffDateTime, err := s.cutoffs.Deadline(date, mealType)
 	if err != nil {
-		return fmt.Errorf("invalid cutoff time format: %w", err)
+		return err
 	}
 
-	// Cutoff is on the PREVIOUS day at the configured time
-	cutoffDate := targetDate.AddDate(0, 0, -1)
-	cutoffDateTime := time.Date(
-		cutoffDate.Year(),
-		cutoffDate.Month(),
-		cutoffDate.Day(),
-		cutoffTime.Hour(),
-		cutoffTime.Minute(),
-		0, 0, loc,
-	)
-
 	// Get current time in the configured timezone
-	now := time.Now().In(loc)
+	now := time.Now().In(cutoffDateTime.Location())
 
 	// Check if current time is past the cutoff
 	if now.After(cutoffDateTime) {
-		return fmt.Errorf("cutoff time (%s %s on %s) has passed for date %s",
-			s.cutoffTime, s.cutoffTimezone, cutoffDate.Format("2006-01-02"), targetDate.Format("2006-01-02"))
+		return fmt.Errorf("cutoff time (%s) has passed for %s on %s",
+			cutoffDateTime.Format("2006-01-02 15:04 MST"), mealType, date)
 	}
 
 	return nil
 }
 
-func (s *mealService) getMealStatus(userID, date string, availableMeals []models.MealType) (map[string]bool, error) {
-    mealStatus := make(map[string]bool)
-    for _, mealType := range availableMeals {
-        isParticipating, _, err := s.resolver.ResolveParticipation(userID, date, string(mealType))
-        if err != nil {
-            return nil, err
-        }
-        mealStatus[string(mealType)] = isParticipating
+// teamMealStatus holds the batch-resolved meal status and WFH counts for a set of team members
+type teamMealStatus struct {
+    date       string
+    mealTypes  []models.MealType
+    resolution ParticipationResolution
+    wfhCounts  map[string]int64
+    allowance  int
+}
+
+// loadTeamMealStatus resolves participation and monthly WFH counts for all given users at once
+func (s *mealService) loadTeamMealStatus(userIDs []string, date string, availableMeals []models.MealType) (*teamMealStatus, error) {
+    resolution, err := s.resolver.ResolveBatch(userIDs, []string{date}, mealTypeKeys(availableMeals))
+    if err != nil {
+        return nil, err
+    }
+
+    wfhCounts, err := s.wlRepo.GetMonthlyWFHCountsByUsers(time.Now().Format("2006-01"), userIDs)
+    if err != nil {
+        // Over-limit flags are informational; fall back to no flags
+        wfhCounts = map[string]int64{}
+    }
+
+    return &teamMealStatus{
+        date:       date,
+        mealTypes:  availableMeals,
+        resolution: resolution,
+        wfhCounts:  wfhCounts,
+        allowance:  s.monthlyWFHAllowance,
+    }, nil
+}
+
+func (t *teamMealStatus) member(user models.User) TeamMemberParticipation {
+    return t.memberFor(user.ID.String(), user.Name, user.Email)
+}
+
+func (t *teamMealStatus) memberFor(userID, name, email string) TeamMemberParticipation {
+    mealStatus := make(map[string]bool, len(t.mealTypes))
+    for _, mealType := range t.mealTypes {
+        res, _ := t.resolution.Get(userID, t.date, string(mealType))
+        mealStatus[string(mealType)] = res.IsParticipating
+    }
+    return TeamMemberParticipation{
+        UserID: userID,
+        Name:   name,
+        Email:  email,
+        Meals:  mealStatus,
+        IsOverWFHLimit: t.wfhCounts[userID] > int64(t.allowance),
     }
-    return mealStatus, nil
 }
 
 func (s *mealService) getAvailableMeals(date string) ([]models.MealType, error) {
@@ -397,22 +505,23 @@ func (s *mealService) getTeamParticipationWithMeals(teamLeadID, date string, ava
         return nil, fmt.Errorf("failed to find teams: %w", err)
     }
 
+    var userIDs []string
+    for _, team := range teams {
+        for _, member := range team.Members {
+            userIDs = append(userIDs, member.ID.String())
+        }
+    }
+
+    status, err := s.loadTeamMealStatus(userIDs, date, availableMeals)
+    if err != nil {
+        return nil, err
+    }
+
     var teamGroups []TeamParticipationGroup
     for _, team := range teams {
         var members []TeamMemberParticipation
         for _, member := range team.Members {
-            memberID := member.ID.String()
-            mealStatus, err := s.getMealStatus(memberID, date, availableMeals)
-            if err != nil {
-                return nil, err
-            }
-            members = append(members, TeamMemberParticipation{
-                UserID: memberID,
-                Name:   member.Name,
-                Email:  member.Email,
-                Meals:  mealStatus,
-    			IsOverWFHLimit: s.isOverWFHLimit(memberID),
-            })
+            members = append(members, status.member(member))
         }
         if members == nil {
             members = []TeamMemberParticipation{}
@@ -435,6 +544,10 @@ func (s *mealService) getTeamParticipationWithMeals(teamLeadID, date string, ava
 }
 
 func (s *mealService) GetAllTeamsParticipation(date string) (*TeamParticipationResponse, error) {
+    if err := validateDate(date); err != nil {
+        return nil, err
+    }
+
     teams, err := s.teamRepo.FindAllWithMembers()
     if err != nil {
         return nil, fmt.Errorf("failed to find teams: %w", err)
@@ -445,34 +558,26 @@ func (s *mealService) GetAllTeamsParticipation(date string) (*TeamParticipationR
         return nil, err
     }
 
-    var teamGroups []TeamParticipationGroup
+    var userIDs []string
     for _, team := range teams {
-        leadMealStatus, err := s.getMealStatus(team.TeamLeadID.String(), date, availableMeals)
-        if err != nil {
-            return nil, err
+        userIDs = append(userIDs, team.TeamLeadID.String())
+        for _, member := range team.Members {
+            userIDs = append(userIDs, member.ID.String())
         }
+    }
 
-        leadMember := TeamMemberParticipation{
-            UserID: team.TeamLeadID.String(),
-            Name:   team.TeamLead.Name,
-            Email:  team.TeamLead.Email,
-            Meals:  leadMealStatus,
-		    IsOverWFHLimit: s.isOverWFHLimit(team.TeamLeadID.String()),
-        }
+    status, err := s.loadTeamMealStatus(userIDs, date, availableMeals)
+    if err != nil {
+        return nil, err
+    }
+
+    var teamGroups []TeamParticipationGroup
+    for _, team := range teams {
+        leadMember := status.memberFor(team.TeamLeadID.String(), team.TeamLead.Name, team.TeamLead.Email)
 
         var members []TeamMemberParticipation
         for _, member := range team.Members {
-            mealStatus, err := s.getMealStatus(member.ID.String(), date, availableMeals)
-            if err != nil {
-                return nil, err
-            }
-            members = append(members, TeamMemberParticipation{
-                UserID: member.ID.String(),
-                Name:   member.Name,
-                Email:  member.Email,
-                Meals:  mealStatus,
-				IsOverWFHLimit: s.isOverWFHLimit(member.ID.String()),
-            })
+            members = append(members, status.member(member))
         }
 
         teamGroups = append(teamGroups, TeamParticipationGroup{
@@ -492,11 +597,32 @@ func (s *mealService) GetAllTeamsParticipation(date string) (*TeamParticipationR
     }, nil
 }
 
+// GetAllTeamsParticipationRange returns every team's participation for each date from startDate to endDate
+func (s *mealService) GetAllTeamsParticipationRange(startDate, endDate string) ([]*TeamParticipationResponse, error) {
+    dates, err := reportDates(startDate, endDate)
+    if err != nil {
+        return nil, err
+    }
+
+    responses := make([]*TeamParticipationResponse, 0, len(dates))
+    for _, date := range dates {
+        response, err := s.GetAllTeamsParticipation(date)
+        if err != nil {
+            return nil, err
+        }
+        responses = append(responses, response)
+    }
+    return responses, nil
+}
+
 // Helper function to validate date window and cutoff time
-func (s *mealService) validateDateWindow(date string) error {
+func (s *mealService) validateDateWindow(date, mealType string) error {
 	if err := validateDate(date); err != nil {
 		return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
 	}
+	if _, err := s.catalog.Validate(mealType); err != nil {
+		return err
+	}
 
 	parsedDate, _ := time.Parse("2006-01-02", date)
 
@@ -512,18 +638,9 @@ func (s *mealService) validateDateWindow(date string) error {
 		return fmt.Errorf("cannot set participation more than %d days in advance (requested: %s)", s.forwardWindowDays, date)
 	}
 
-	if err := s.validateCutoffTime(parsedDate); err != nil {
+	if err := s.validateCutoffTime(date, mealType); err != nil {
 		return err
 	}
 
 	return nil
 }
-
-func (s *mealService) isOverWFHLimit(userID string) bool {
-    yearMonth := time.Now().Format("2006-01")
-    count, err := s.wlRepo.CountWFHByUserAndMonth(userID, yearMonth)
-    if err != nil {
-        return false
-    }
-    return count > int64(s.monthlyWFHAllowance)
-}
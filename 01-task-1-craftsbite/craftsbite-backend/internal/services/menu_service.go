@@ -0,0 +1,370 @@
+package services
+
+import (
+	"craftsbite-backend/internal/authz"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Menu limits
+const (
+	maxMenuRangeDays  = 62
+	maxMenuCloneDays  = 31
+	maxDishesPerMenu  = 30
+	maxDishNameLength = 255
+	maxDishDescLength = 1000
+	maxMenuNoteLength = 500
+	maxPhotoURLLength = 2048
+)
+
+// DishInput represents a dish on a menu
+type DishInput struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	DietaryTags []string `json:"dietary_tags"`
+	PhotoURL    string   `json:"photo_url"`
+}
+
+// SaveMenuInput creates or replaces the menu of a meal on a date. Dishes are stored in the given order.
+type SaveMenuInput struct {
+	Date     string      `json:"date" binding:"required"`
+	MealType string      `json:"meal_type" binding:"required"`
+	Note     string      `json:"note"`
+	Dishes   []DishInput `json:"dishes" binding:"required"`
+	Publish  bool        `json:"publish"`
+}
+
+// CloneMenusInput copies the menus of Days days starting at SourceStart to the same weekdays from TargetStart
+type CloneMenusInput struct {
+	SourceStart string `json:"source_start" binding:"required"`
+	TargetStart string `json:"target_start" binding:"required"`
+	Days        int    `json:"days"`
+	Overwrite   bool   `json:"overwrite"`
+}
+
+// MenuCloneSkip is a menu that was not cloned
+type MenuCloneSkip struct {
+	Date     string          `json:"date"`
+	MealType models.MealType `json:"meal_type"`
+	Reason   string          `json:"reason"`
+}
+
+// MenuCloneResult reports the draft menus a clone created and the ones it skipped
+type MenuCloneResult struct {
+	Created []models.Menu   `json:"created"`
+	Skipped []MenuCloneSkip `json:"skipped"`
+}
+
+// MenuService defines menu planning and publishing
+type MenuService interface {
+	// GetMenus returns the menus in a date range; drafts are included only for menu managers
+	GetMenus(role, startDate, endDate string) ([]models.Menu, error)
+	// PublishedMenus returns the published menus of a date
+	PublishedMenus(date string) ([]models.Menu, error)
+	SaveMenu(actorID string, input SaveMenuInput) (*models.Menu, error)
+	PublishMenu(actorID, id string) (*models.Menu, error)
+	UnpublishMenu(actorID, id string) (*models.Menu, error)
+	DeleteMenu(id string) error
+	CloneMenus(actorID string, input CloneMenusInput) (*MenuCloneResult, error)
+}
+
+type menuService struct {
+	menuRepo   repository.MenuRepository
+	catalog    MealCatalog
+	authorizer authz.Authorizer
+}
+
+// NewMenuService creates a new menu service
+func NewMenuService(menuRepo repository.MenuRepository, catalog MealCatalog, authorizer authz.Authorizer) MenuService {
+	return &menuService{
+		menuRepo:   menuRepo,
+		catalog:    catalog,
+		authorizer: authorizer,
+	}
+}
+
+func (s *menuService) GetMenus(role, startDate, endDate string) ([]models.Menu, error) {
+	start, end, err := parseCalendarRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if end.Sub(start) >= maxMenuRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("date range cannot exceed %d days", maxMenuRangeDays)
+	}
+
+	canManage, err := s.authorizer.HasPermission(role, authz.PermMenuManage, authz.ScopeAll)
+	if err != nil {
+		return nil, err
+	}
+	return s.menuRepo.FindByDateRange(startDate, endDate, !canManage)
+}
+
+func (s *menuService) PublishedMenus(date string) ([]models.Menu, error) {
+	return s.menuRepo.FindByDateRange(date, date, true)
+}
+
+func (s *menuService) SaveMenu(actorID string, input SaveMenuInput) (*models.Menu, error) {
+	if err := validateDate(input.Date); err != nil {
+		return nil, err
+	}
+	mealType, err := s.catalog.Validate(input.MealType)
+	if err != nil {
+		return nil, err
+	}
+	actorUUID, err := uuid.Parse(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	note := strings.TrimSpace(input.Note)
+	if utf8.RuneCountInString(note) > maxMenuNoteLength {
+		return nil, fmt.Errorf("note cannot exceed %d characters", maxMenuNoteLength)
+	}
+	dishes, err := buildDishes(input.Dishes)
+	if err != nil {
+		return nil, err
+	}
+
+	menu, err := s.menuRepo.FindByDateAndMeal(input.Date, string(mealType))
+	if err != nil {
+		return nil, err
+	}
+	if menu == nil {
+		menu = &models.Menu{MealType: mealType, CreatedBy: &actorUUID}
+	}
+	menu.Date = input.Date
+	menu.Note = nil
+	if note != "" {
+		menu.Note = &note
+	}
+	menu.Dishes = dishes
+	menu.UpdatedBy = &actorUUID
+	if input.Publish {
+		markPublished(menu)
+	}
+
+	if err := s.menuRepo.Save(menu); err != nil {
+		return nil, err
+	}
+	return menu, nil
+}
+
+func (s *menuService) PublishMenu(actorID, id string) (*models.Menu, error) {
+	menu, err := s.findMenu(id)
+	if err != nil {
+		return nil, err
+	}
+	if menu.IsPublished {
+		return nil, fmt.Errorf("menu is already published")
+	}
+	if len(menu.Dishes) == 0 {
+		return nil, fmt.Errorf("cannot publish a menu without dishes")
+	}
+
+	markPublished(menu)
+	return s.saveBy(actorID, menu)
+}
+
+func (s *menuService) UnpublishMenu(actorID, id string) (*models.Menu, error) {
+	menu, err := s.findMenu(id)
+	if err != nil {
+		return nil, err
+	}
+	if !menu.IsPublished {
+		return nil, fmt.Errorf("menu is not published")
+	}
+
+	menu.IsPublished = false
+	menu.PublishedAt = nil
+	return s.saveBy(actorID, menu)
+}
+
+func (s *menuService) DeleteMenu(id string) error {
+	menu, err := s.findMenu(id)
+	if err != nil {
+		return err
+	}
+	return s.menuRepo.Delete(menu.ID.String())
+}
+
+// CloneMenus copies a block of days' menus as drafts, keeping each menu's offset from the block start.
+// Menus already planned on a target date are kept unless Overwrite is set.
+func (s *menuService) CloneMenus(actorID string, input CloneMenusInput) (*MenuCloneResult, error) {
+	days := input.Days
+	if days == 0 {
+		days = 7
+	}
+	if days < 1 || days > maxMenuCloneDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", maxMenuCloneDays)
+	}
+
+	sourceStart, err := time.Parse("2006-01-02", input.SourceStart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source_start format, expected YYYY-MM-DD")
+	}
+	targetStart, err := time.Parse("2006-01-02", input.TargetStart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target_start format, expected YYYY-MM-DD")
+	}
+	offset := int(targetStart.Sub(sourceStart).Hours() / 24)
+	if offset > -days && offset < days {
+		return nil, fmt.Errorf("target range must not overlap the source range")
+	}
+
+	actorUUID, err := uuid.Parse(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	sourceEnd := sourceStart.AddDate(0, 0, days-1).Format("2006-01-02")
+	sources, err := s.menuRepo.FindByDateRange(input.SourceStart, sourceEnd, false)
+	if err != nil {
+		return nil, err
+	}
+	targetEnd := targetStart.AddDate(0, 0, days-1).Format("2006-01-02")
+	targets, err := s.menuRepo.FindByDateRange(input.TargetStart, targetEnd, false)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*models.Menu, len(targets))
+	for i := range targets {
+		existing[dateKey(targets[i].Date)+"|"+string(targets[i].MealType)] = &targets[i]
+	}
+
+	result := &MenuCloneResult{Created: []models.Menu{}, Skipped: []MenuCloneSkip{}}
+	for _, source := range sources {
+		sourceDay, _ := time.Parse("2006-01-02", dateKey(source.Date))
+		date := sourceDay.AddDate(0, 0, offset).Format("2006-01-02")
+
+		if len(source.Dishes) == 0 {
+			result.Skipped = append(result.Skipped, MenuCloneSkip{Date: date, MealType: source.MealType, Reason: "source menu has no dishes"})
+			continue
+		}
+
+		menu := existing[date+"|"+string(source.MealType)]
+		if menu != nil && !input.Overwrite {
+			result.Skipped = append(result.Skipped, MenuCloneSkip{Date: date, MealType: source.MealType, Reason: "a menu already exists"})
+			continue
+		}
+		if menu == nil {
+			menu = &models.Menu{MealType: source.MealType, CreatedBy: &actorUUID}
+		}
+
+		menu.Date = date
+		menu.Note = source.Note
+		menu.IsPublished = false
+		menu.PublishedAt = nil
+		menu.UpdatedBy = &actorUUID
+		menu.Dishes = make([]models.MenuDish, len(source.Dishes))
+		for i, dish := range source.Dishes {
+			menu.Dishes[i] = models.MenuDish{
+				Name:        dish.Name,
+				Description: dish.Description,
+				DietaryTags: dish.DietaryTags,
+				PhotoURL:    dish.PhotoURL,
+			}
+		}
+
+		if err := s.menuRepo.Save(menu); err != nil {
+			return nil, err
+		}
+		result.Created = append(result.Created, *menu)
+	}
+
+	return result, nil
+}
+
+func (s *menuService) findMenu(id string) (*models.Menu, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid menu ID")
+	}
+	menu, err := s.menuRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if menu == nil {
+		return nil, fmt.Errorf("menu not found")
+	}
+	return menu, nil
+}
+
+func (s *menuService) saveBy(actorID string, menu *models.Menu) (*models.Menu, error) {
+	actorUUID, err := uuid.Parse(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+	menu.UpdatedBy = &actorUUID
+	if err := s.menuRepo.Save(menu); err != nil {
+		return nil, err
+	}
+	return menu, nil
+}
+
+func markPublished(menu *models.Menu) {
+	if !menu.IsPublished {
+		now := time.Now()
+		menu.IsPublished = true
+		menu.PublishedAt = &now
+	}
+}
+
+// buildDishes validates dish input and converts it to models in the given order
+func buildDishes(inputs []DishInput) ([]models.MenuDish, error) {
+	if len(inputs) > maxDishesPerMenu {
+		return nil, fmt.Errorf("a menu cannot have more than %d dishes", maxDishesPerMenu)
+	}
+
+	dishes := make([]models.MenuDish, 0, len(inputs))
+	for _, input := range inputs {
+		name := strings.TrimSpace(input.Name)
+		if name == "" {
+			return nil, fmt.Errorf("dish name is required")
+		}
+		if utf8.RuneCountInString(name) > maxDishNameLength {
+			return nil, fmt.Errorf("dish name cannot exceed %d characters", maxDishNameLength)
+		}
+		dish := models.MenuDish{Name: name}
+
+		if description := strings.TrimSpace(input.Description); description != "" {
+			if utf8.RuneCountInString(description) > maxDishDescLength {
+				return nil, fmt.Errorf("description of %s cannot exceed %d characters", name, maxDishDescLength)
+			}
+			dish.Description = &description
+		}
+
+		tags := make([]string, 0, len(input.DietaryTags))
+		seen := make(map[models.DietaryRestriction]bool, len(input.DietaryTags))
+		for _, tag := range input.DietaryTags {
+			restriction := models.DietaryRestriction(strings.ToLower(strings.TrimSpace(tag)))
+			if !restriction.IsValid() {
+				return nil, fmt.Errorf("invalid dietary tag on %s: %s", name, tag)
+			}
+			if !seen[restriction] {
+				seen[restriction] = true
+				tags = append(tags, restriction.String())
+			}
+		}
+		dish.DietaryTags = strings.Join(tags, ",")
+
+		if photoURL := strings.TrimSpace(input.PhotoURL); photoURL != "" {
+			parsed, err := url.Parse(photoURL)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return nil, fmt.Errorf("photo URL of %s must be an http or https URL", name)
+			}
+			if len(photoURL) > maxPhotoURLLength {
+				return nil, fmt.Errorf("photo URL of %s cannot exceed %d characters", name, maxPhotoURLLength)
+			}
+			dish.PhotoURL = &photoURL
+		}
+
+		dishes = append(dishes, dish)
+	}
+	return dishes, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// maxNotificationsListed bounds how many notifications a listing returns
+const maxNotificationsListed = 100
+
+// NotificationService delivers and manages in-app notifications
+type NotificationService interface {
+	Notify(userID uuid.UUID, kind, title, message string) error
+	List(userID string, unreadOnly bool) ([]models.Notification, error)
+	MarkRead(userID, id string) error
+	MarkAllRead(userID string) error
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{notificationRepo: notificationRepo}
+}
+
+func (s *notificationService) Notify(userID uuid.UUID, kind, title, message string) error {
+	return s.notificationRepo.Create(&models.Notification{
+		UserID:  userID,
+		Kind:    kind,
+		Title:   title,
+		Message: message,
+	})
+}
+
+func (s *notificationService) List(userID string, unreadOnly bool) ([]models.Notification, error) {
+	return s.notificationRepo.FindByUser(userID, unreadOnly, maxNotificationsListed)
+}
+
+func (s *notificationService) MarkRead(userID, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("invalid notification ID")
+	}
+	found, err := s.notificationRepo.MarkRead(userID, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
+
+func (s *notificationService) MarkAllRead(userID string) error {
+	return s.notificationRepo.MarkAllRead(userID)
+}
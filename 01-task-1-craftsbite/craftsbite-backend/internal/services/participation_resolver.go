@@ -12,117 +12,251 @@ import (
 // ParticipationResolver defines the interface for resolving meal participation status
 type ParticipationResolver interface {
 	ResolveParticipation(userID, date, mealType string) (isParticipating bool, source string, Error error)
+	ResolveBatch(userIDs, dates, mealTypes []string) (ParticipationResolution, error)
+}
+
+// ParticipationKey identifies a single user, date and meal type combination
+type ParticipationKey struct {
+	UserID   string
+	Date     string
+	MealType string
+}
+
+// ResolvedParticipation is the resolved participation status for a ParticipationKey
+type ResolvedParticipation struct {
+	IsParticipating bool
+	Source          string
+}
+
+// ParticipationResolution holds the results of a batch resolution
+type ParticipationResolution map[ParticipationKey]ResolvedParticipation
+
+// Get returns the resolved participation for a user, date and meal type
+func (r ParticipationResolution) Get(userID, date, mealType string) (ResolvedParticipation, bool) {
+	res, ok := r[ParticipationKey{UserID: userID, Date: date, MealType: mealType}]
+	return res, ok
 }
 
 // participationResolver implements ParticipationResolver
 type participationResolver struct {
 	mealRepo       repository.MealRepository
-	scheduleRepo   repository.ScheduleRepository
+	scheduleRepo   ScheduleCalendar
 	bulkOptOutRepo repository.BulkOptOutRepository
+	ruleRepo       repository.ParticipationRuleRepository
 	userRepo       repository.UserRepository
+	catalog        MealCatalog
 	weekendDays    map[string]bool
 }
 
 // NewParticipationResolver creates a new participation resolver
 func NewParticipationResolver(
 	mealRepo repository.MealRepository,
-	scheduleRepo repository.ScheduleRepository,
+	scheduleRepo ScheduleCalendar,
 	bulkOptOutRepo repository.BulkOptOutRepository,
+	ruleRepo repository.ParticipationRuleRepository,
 	userRepo repository.UserRepository,
+	catalog MealCatalog,
 	cfg *config.Config,
 ) ParticipationResolver {
-	// Build weekend days map for quick lookup
-	weekendDays := make(map[string]bool)
-	for _, day := range cfg.Meal.WeekendDays {
-		weekendDays[strings.ToLower(strings.TrimSpace(day))] = true
-	}
-
 	return &participationResolver{
 		mealRepo:       mealRepo,
 		scheduleRepo:   scheduleRepo,
 		bulkOptOutRepo: bulkOptOutRepo,
+		ruleRepo:       ruleRepo,
 		userRepo:       userRepo,
-		weekendDays:    weekendDays,
+		catalog:        catalog,
+		weekendDays:    weekendDaySet(cfg.Meal.WeekendDays),
 	}
 }
 
 // ResolveParticipation resolves a user's participation status for a specific date and meal type
+func (r *participationResolver) ResolveParticipation(userID, date, mealType string) (bool, string, error) {
+	resolution, err := r.ResolveBatch([]string{userID}, []string{date}, []string{mealType})
+	if err != nil {
+		return false, "", err
+	}
+
+	res := resolution[ParticipationKey{UserID: userID, Date: date, MealType: mealType}]
+	return res.IsParticipating, res.Source, nil
+}
+
+// ResolveBatch resolves participation for every combination of the given users, dates and meal types.
+// All lookups are done with one query per data source instead of one per user/date/meal.
 // Priority order:
 // 0. Weekend Check
 // 1. Day Schedule
 // 2. Explicit Participation
 // 3. Bulk Opt-Out
-// 4. User Default
-// 5. System Default
-func (r *participationResolver) ResolveParticipation(userID, date, mealType string) (bool, string, error) {
-	// Priority 0: Check if date is a weekend
-	parsedDate, err := time.Parse("2006-01-02", date)
-	if err != nil {
-		return false, "", fmt.Errorf("invalid date format: %w", err)
+// 4. Recurring Rule
+// 5. User Default
+// 6. System Default
+func (r *participationResolver) ResolveBatch(userIDs, dates, mealTypes []string) (ParticipationResolution, error) {
+	resolution := make(ParticipationResolution, len(userIDs)*len(dates)*len(mealTypes))
+	if len(userIDs) == 0 || len(dates) == 0 || len(mealTypes) == 0 {
+		return resolution, nil
 	}
 
-	weekdayName := strings.ToLower(parsedDate.Weekday().String())
-	if r.weekendDays[weekdayName] {
-		// Check if there's a day schedule that overrides the weekend
-		schedule, err := r.scheduleRepo.FindByDate(date)
+	parsedDates := make(map[string]time.Time, len(dates))
+	startDate, endDate := "", ""
+	for _, date := range dates {
+		parsed, err := time.Parse("2006-01-02", date)
 		if err != nil {
-			return false, "", err
+			return nil, fmt.Errorf("invalid date format: %w", err)
 		}
-
-		// If there's a schedule with normal or celebration status, allow meals
-		if schedule != nil && (schedule.DayStatus == models.DayStatusNormal || schedule.DayStatus == models.DayStatusCelebration) {
-			// Continue to next priority checks
-		} else {
-			// Weekend with no override schedule
-			return false, "weekend", nil
+		parsedDates[date] = parsed
+		if startDate == "" || date < startDate {
+			startDate = date
+		}
+		if endDate == "" || date > endDate {
+			endDate = date
 		}
 	}
 
-	// Priority 1: Check day schedule
-	schedule, err := r.scheduleRepo.FindByDate(date)
+	schedules, err := r.scheduleRepo.FindByDateRange(startDate, endDate)
 	if err != nil {
-		return false, "", err
+		return nil, err
 	}
-
-	if schedule != nil {
-	    if schedule.DayStatus == models.DayStatusOfficeClosed || 
-	       (schedule.DayStatus == models.DayStatusGovtHoliday && schedule.AvailableMeals == nil) {
-	        return false, "day_schedule", nil
-	    }
+	schedulesByDate := make(map[string]*models.DaySchedule, len(schedules))
+	for i := range schedules {
+		schedulesByDate[dateKey(schedules[i].Date)] = &schedules[i]
 	}
 
-	// Priority 2: Check explicit participation record
-	participation, err := r.mealRepo.FindByUserDateMeal(userID, date, mealType)
+	participations, err := r.mealRepo.FindByUsersAndDates(userIDs, dates)
 	if err != nil {
-		return false, "", err
+		return nil, err
+	}
+	explicit := make(map[ParticipationKey]bool, len(participations))
+	for _, p := range participations {
+		explicit[ParticipationKey{UserID: p.UserID.String(), Date: dateKey(p.Date), MealType: string(p.MealType)}] = p.IsParticipating
 	}
 
-	if participation != nil {
-		return participation.IsParticipating, "explicit", nil
+	bulkOptOuts, err := r.bulkOptOutRepo.FindActiveByUsersAndDateRange(userIDs, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	optOutsByUser := make(map[string][]models.BulkOptOut)
+	for _, optOut := range bulkOptOuts {
+		uid := optOut.UserID.String()
+		optOutsByUser[uid] = append(optOutsByUser[uid], optOut)
 	}
 
-	// Priority 3: Check bulk opt-outs
-	bulkOptOuts, err := r.bulkOptOutRepo.FindActiveByUserAndDate(userID, date)
+	rules, err := r.ruleRepo.FindByUsersAndDateRange(userIDs, startDate, endDate)
 	if err != nil {
-		return false, "", err
+		return nil, err
+	}
+	rulesByUser := make(map[string][]models.ParticipationRule)
+	for _, rule := range rules {
+		uid := rule.UserID.String()
+		rulesByUser[uid] = append(rulesByUser[uid], rule)
 	}
 
-	for _, optOut := range bulkOptOuts {
-		if string(optOut.MealType) == mealType {
-			return false, "bulk_opt_out", nil
-		}
+	users, err := r.userRepo.FindByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defaultPreference := make(map[string]string, len(users))
+	for _, user := range users {
+		defaultPreference[user.ID.String()] = user.DefaultMealPreference
 	}
 
-	// Priority 4: Check user's default preference
-	user, err := r.userRepo.FindByID(userID)
+	// Meal types missing from the catalogue keep the opt-in system default
+	catalogued, err := r.catalog.List(true)
 	if err != nil {
-		return false, "", err
+		return nil, err
+	}
+	optOutByDefault := make(map[string]bool, len(catalogued))
+	for _, mealType := range catalogued {
+		optOutByDefault[string(mealType.Code)] = !mealType.DefaultParticipating
 	}
 
-	if user.DefaultMealPreference == "opt_out" {
-		return false, "user_default", nil
+	for _, date := range dates {
+		schedule := schedulesByDate[date]
+
+		// Priority 0: Weekend with no override schedule, or a schedule marking the day as weekend
+		weekdayName := strings.ToLower(parsedDates[date].Weekday().String())
+		isWeekend := (r.weekendDays[weekdayName] &&
+			!(schedule != nil && (schedule.DayStatus == models.DayStatusNormal || schedule.DayStatus == models.DayStatusCelebration))) ||
+			(schedule != nil && schedule.DayStatus == models.DayStatusWeekend)
+
+		// Priority 1: Day schedule closes the office
+		isClosed := schedule != nil &&
+			(schedule.DayStatus == models.DayStatusOfficeClosed ||
+				(schedule.DayStatus == models.DayStatusGovtHoliday && schedule.AvailableMeals == nil))
+
+		for _, userID := range userIDs {
+			for _, mealType := range mealTypes {
+				key := ParticipationKey{UserID: userID, Date: date, MealType: mealType}
+
+				switch {
+				case isWeekend:
+					resolution[key] = ResolvedParticipation{IsParticipating: false, Source: "weekend"}
+					continue
+				case isClosed:
+					resolution[key] = ResolvedParticipation{IsParticipating: false, Source: "day_schedule"}
+					continue
+				}
+
+				// Priority 2: Explicit participation record
+				if isParticipating, ok := explicit[key]; ok {
+					resolution[key] = ResolvedParticipation{IsParticipating: isParticipating, Source: "explicit"}
+					continue
+				}
+
+				// Priority 3: Bulk opt-outs
+				if hasBulkOptOut(optOutsByUser[userID], date, mealType) {
+					resolution[key] = ResolvedParticipation{IsParticipating: false, Source: "bulk_opt_out"}
+					continue
+				}
+
+				// Priority 4: Recurring weekly rule
+				if rule := matchingRule(rulesByUser[userID], date, weekdayName, mealType); rule != nil {
+					resolution[key] = ResolvedParticipation{IsParticipating: rule.IsParticipating, Source: "recurring_rule"}
+					continue
+				}
+
+				// Priority 5: User's default preference
+				preference, ok := defaultPreference[userID]
+				if !ok {
+					return nil, fmt.Errorf("user not found")
+				}
+				if preference == "opt_out" {
+					resolution[key] = ResolvedParticipation{IsParticipating: false, Source: "user_default"}
+					continue
+				}
+
+				// Priority 6: System default from the meal type catalogue (opt-in unless configured otherwise)
+				resolution[key] = ResolvedParticipation{IsParticipating: !optOutByDefault[mealType], Source: "system_default"}
+			}
+		}
 	}
 
-	// Priority 5: System default (opt-in)
-	return true, "system_default", nil
+	return resolution, nil
+}
+
+// hasBulkOptOut checks if any of the bulk opt-outs covers the given date and meal type
+func hasBulkOptOut(optOuts []models.BulkOptOut, date, mealType string) bool {
+	for _, optOut := range optOuts {
+		if string(optOut.MealType) == mealType && dateKey(optOut.StartDate) <= date && date <= dateKey(optOut.EndDate) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchingRule returns the recurring rule covering a date's weekday and meal type, or nil
+func matchingRule(rules []models.ParticipationRule, date, weekday, mealType string) *models.ParticipationRule {
+	for i := range rules {
+		rule := &rules[i]
+		if string(rule.MealType) != mealType || !rule.AppliesOn(weekday) {
+			continue
+		}
+		if rule.StartDate != nil && date < dateKey(*rule.StartDate) {
+			continue
+		}
+		if rule.EndDate != nil && date > dateKey(*rule.EndDate) {
+			continue
+		}
+		return rule
+	}
+	return nil
 }
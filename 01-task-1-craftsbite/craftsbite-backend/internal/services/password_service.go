@@ -0,0 +1,231 @@
+package services
+
+import (
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/mailer"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"craftsbite-backend/internal/utils"
+	"craftsbite-backend/pkg/logger"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	resetTokenBytes   = 32
+	minPasswordLength = 8
+	// passwordResetCooldown is how long after a reset email another one is not sent, so the
+	// endpoint cannot be used to flood someone's inbox
+	passwordResetCooldown = time.Minute
+)
+
+var (
+	// ErrInvalidResetToken is returned for a reset token that is unknown, used or expired
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+	// ErrIncorrectPassword is returned by ChangePassword when the current password is wrong
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+)
+
+// PasswordService recovers forgotten passwords by email and changes known ones. Both end
+// the user's other sessions.
+type PasswordService interface {
+	ForgotPassword(email string, client ClientInfo) error
+	ResetPassword(token, newPassword string, client ClientInfo) error
+	ChangePassword(userID, sessionID, currentPassword, newPassword string, client ClientInfo) error
+}
+
+type passwordService struct {
+	userRepo       repository.UserRepository
+	resetRepo      repository.PasswordResetRepository
+	auditRepo      repository.AuthAuditRepository
+	sessionService SessionService
+	mailer         mailer.Mailer
+	cfg            config.PasswordConfig
+	appURL         string
+}
+
+// NewPasswordService creates a new password service
+func NewPasswordService(
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetRepository,
+	auditRepo repository.AuthAuditRepository,
+	sessionService SessionService,
+	m mailer.Mailer,
+	cfg *config.Config,
+) PasswordService {
+	return &passwordService{
+		userRepo:       userRepo,
+		resetRepo:      resetRepo,
+		auditRepo:      auditRepo,
+		sessionService: sessionService,
+		mailer:         m,
+		cfg:            cfg.Password,
+		appURL:         cfg.Server.AppURL,
+	}
+}
+
+// ForgotPassword emails a single-use reset link to an active account. It succeeds whether
+// or not the email belongs to an account, so callers cannot tell which addresses exist.
+func (s *passwordService) ForgotPassword(email string, client ClientInfo) error {
+	user := s.findUser(email)
+	if user == nil || !user.Active {
+		return nil
+	}
+
+	now := time.Now()
+	latest, err := s.resetRepo.FindLatestByUserID(user.ID.String())
+	if err != nil {
+		return err
+	}
+	if latest != nil && now.Sub(latest.CreatedAt) < passwordResetCooldown {
+		return nil
+	}
+
+	token, err := utils.GenerateOpaqueToken(resetTokenBytes)
+	if err != nil {
+		return err
+	}
+	// Only the newest link works
+	if err := s.resetRepo.InvalidateForUser(user.ID.String(), now); err != nil {
+		return err
+	}
+	reset := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashOpaqueToken(token),
+		ExpiresAt: now.Add(s.cfg.ResetTokenTTL),
+	}
+	if err := s.resetRepo.Create(reset); err != nil {
+		return err
+	}
+
+	link := s.appURL + "/reset-password?token=" + url.QueryEscape(token)
+	err = s.mailer.Send(mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Reset your CraftsBite password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your CraftsBite account. "+
+			"To choose a new password, open:\n%s\n\nThe link can be used once and expires on %s. "+
+			"If you did not ask for this, you can ignore this email; your password has not changed.\n",
+			user.Name, link, reset.ExpiresAt.Format("2 Jan 2006 15:04 MST")),
+	})
+	if err != nil {
+		// Failing the request would reveal that the account exists
+		logger.Warn(fmt.Sprintf("Failed to email password reset link to user %s: %v", user.ID, err))
+	}
+
+	entry := newAuthAuditLog(models.AuthEventPasswordResetRequested, client)
+	entry.UserID = &user.ID
+	entry.Email = &user.Email
+	recordAuthEvent(s.auditRepo, entry, "")
+	return nil
+}
+
+// findUser looks an account up by email as typed, then in lower case, or returns nil
+func (s *passwordService) findUser(email string) *models.User {
+	email = strings.TrimSpace(email)
+	if user, err := s.userRepo.FindByEmail(email); err == nil {
+		return user
+	}
+	if user, err := s.userRepo.FindByEmail(normalizeEmail(email)); err == nil {
+		return user
+	}
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token, then invalidates the user's other
+// reset tokens and revokes all of their sessions
+func (s *passwordService) ResetPassword(token, newPassword string, client ClientInfo) error {
+	if err := validatePassword(newPassword); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	reset, err := s.resetRepo.FindByTokenHash(utils.HashOpaqueToken(token))
+	if err != nil {
+		return err
+	}
+	if reset == nil || !reset.IsValid(now) {
+		return ErrInvalidResetToken
+	}
+	user, err := s.userRepo.FindByID(reset.UserID.String())
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	if !user.Active {
+		return fmt.Errorf("user account is deactivated")
+	}
+
+	used, err := s.resetRepo.Use(reset.ID.String(), now)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	if err := s.setPassword(user, newPassword, ""); err != nil {
+		return err
+	}
+
+	entry := newAuthAuditLog(models.AuthEventPasswordReset, client)
+	entry.UserID = &user.ID
+	entry.Email = &user.Email
+	recordAuthEvent(s.auditRepo, entry, "")
+	return nil
+}
+
+// ChangePassword replaces a signed-in user's password after checking the current one. The
+// session making the change stays signed in; the user's other sessions are revoked.
+func (s *passwordService) ChangePassword(userID, sessionID, currentPassword, newPassword string, client ClientInfo) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !utils.CheckPasswordHash(currentPassword, user.Password) {
+		return ErrIncorrectPassword
+	}
+	if err := validatePassword(newPassword); err != nil {
+		return err
+	}
+	if newPassword == currentPassword {
+		return fmt.Errorf("new password must be different from the current password")
+	}
+
+	if err := s.setPassword(user, newPassword, sessionID); err != nil {
+		return err
+	}
+
+	entry := newAuthAuditLog(models.AuthEventPasswordChanged, client)
+	entry.UserID = &user.ID
+	entry.Email = &user.Email
+	recordAuthEvent(s.auditRepo, entry, "")
+	return nil
+}
+
+// setPassword stores a new password and ends every session but keepSessionID, along with any
+// outstanding reset links
+func (s *passwordService) setPassword(user *models.User, password, keepSessionID string) error {
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.Password = hashedPassword
+	if err := s.userRepo.Update(user); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.resetRepo.InvalidateForUser(user.ID.String(), time.Now()); err != nil {
+		return err
+	}
+	_, err = s.sessionService.RevokeUserSessions(user.ID.String(), models.SessionRevokePassword, keepSessionID)
+	return err
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return nil
+}
@@ -4,33 +4,61 @@ import (
 	"craftsbite-backend/internal/models"
 	"craftsbite-backend/internal/repository"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// maxAllergensLength matches the varchar(500) allergens column
+const maxAllergensLength = 500
+
 // UserPreferences represents user meal preferences
 type UserPreferences struct {
-	UserID                string `json:"user_id"`
-	DefaultMealPreference string `json:"default_meal_preference"`
+	UserID                string                      `json:"user_id"`
+	DefaultMealPreference string                      `json:"default_meal_preference"`
+	DietaryRestrictions   []models.DietaryRestriction `json:"dietary_restrictions"`
+	Allergens             string                      `json:"allergens"`
+	DietaryOptions        []models.DietaryRestriction `json:"dietary_options"`
+	RecurringRules        []models.ParticipationRule  `json:"recurring_rules"`
+}
+
+// ParticipationRuleInput represents a recurring weekly participation rule.
+// Weekdays are full English day names; the optional dates bound when the rule applies.
+type ParticipationRuleInput struct {
+	MealType        string   `json:"meal_type" binding:"required"`
+	Weekdays        []string `json:"weekdays" binding:"required"`
+	IsParticipating *bool    `json:"is_participating" binding:"required"`
+	StartDate       *string  `json:"start_date"`
+	EndDate         *string  `json:"end_date"`
 }
 
 // PreferenceService defines the interface for user preference management
 type PreferenceService interface {
 	GetPreferences(userID string) (*UserPreferences, error)
 	UpdateDefaultPreference(userID string, preference string) error
+	UpdateDietaryProfile(userID string, restrictions *[]string, allergens *string) error
+	ListRules(userID string) ([]models.ParticipationRule, error)
+	CreateRule(userID string, input ParticipationRuleInput) (*models.ParticipationRule, error)
+	UpdateRule(userID, ruleID string, input ParticipationRuleInput) (*models.ParticipationRule, error)
+	DeleteRule(userID, ruleID string) error
 }
 
 // preferenceService implements PreferenceService
 type preferenceService struct {
 	userRepo    repository.UserRepository
 	historyRepo repository.HistoryRepository
+	ruleRepo    repository.ParticipationRuleRepository
+	catalog     MealCatalog
 }
 
 // NewPreferenceService creates a new preference service
-func NewPreferenceService(userRepo repository.UserRepository, historyRepo repository.HistoryRepository) PreferenceService {
+func NewPreferenceService(userRepo repository.UserRepository, historyRepo repository.HistoryRepository, ruleRepo repository.ParticipationRuleRepository, catalog MealCatalog) PreferenceService {
 	return &preferenceService{
 		userRepo:    userRepo,
 		historyRepo: historyRepo,
+		ruleRepo:    ruleRepo,
+		catalog:     catalog,
 	}
 }
 
@@ -41,9 +69,18 @@ func (s *preferenceService) GetPreferences(userID string) (*UserPreferences, err
 		return nil, fmt.Errorf("failed to get user preferences: %w", err)
 	}
 
+	rules, err := s.ruleRepo.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+
 	return &UserPreferences{
 		UserID:                user.ID.String(),
 		DefaultMealPreference: user.DefaultMealPreference,
+		DietaryRestrictions:   parseDietaryRestrictions(user.DietaryRestrictions),
+		Allergens:             user.Allergens,
+		DietaryOptions:        models.DietaryRestrictions,
+		RecurringRules:        rules,
 	}, nil
 }
 
@@ -92,3 +129,222 @@ func (s *preferenceService) UpdateDefaultPreference(userID string, preference st
 
 	return nil
 }
+
+// UpdateDietaryProfile updates a user's dietary restrictions and free-text allergens.
+// A nil argument leaves that part of the profile unchanged; restrictions replaces the whole list.
+func (s *preferenceService) UpdateDietaryProfile(userID string, restrictions *[]string, allergens *string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if restrictions != nil {
+		seen := make(map[models.DietaryRestriction]bool, len(*restrictions))
+		normalized := make([]string, 0, len(*restrictions))
+		for _, r := range *restrictions {
+			restriction := models.DietaryRestriction(strings.ToLower(strings.TrimSpace(r)))
+			if !restriction.IsValid() {
+				return fmt.Errorf("invalid dietary restriction: %s", r)
+			}
+			if !seen[restriction] {
+				seen[restriction] = true
+				normalized = append(normalized, restriction.String())
+			}
+		}
+		user.DietaryRestrictions = strings.Join(normalized, ",")
+	}
+
+	if allergens != nil {
+		trimmed := strings.TrimSpace(*allergens)
+		if len(trimmed) > maxAllergensLength {
+			return fmt.Errorf("allergens cannot exceed %d characters", maxAllergensLength)
+		}
+		user.Allergens = trimmed
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return fmt.Errorf("failed to update dietary profile: %w", err)
+	}
+	return nil
+}
+
+// ListRules returns a user's recurring participation rules
+func (s *preferenceService) ListRules(userID string) ([]models.ParticipationRule, error) {
+	return s.ruleRepo.FindByUser(userID)
+}
+
+// CreateRule adds a recurring participation rule for a user
+func (s *preferenceService) CreateRule(userID string, input ParticipationRuleInput) (*models.ParticipationRule, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	rule := &models.ParticipationRule{UserID: userUUID}
+	if err := s.applyRuleInput(rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule replaces one of a user's recurring participation rules
+func (s *preferenceService) UpdateRule(userID, ruleID string, input ParticipationRuleInput) (*models.ParticipationRule, error) {
+	rule, err := s.findOwnRule(userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRuleInput(rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule removes one of a user's recurring participation rules
+func (s *preferenceService) DeleteRule(userID, ruleID string) error {
+	rule, err := s.findOwnRule(userID, ruleID)
+	if err != nil {
+		return err
+	}
+	return s.ruleRepo.Delete(rule.ID.String())
+}
+
+func (s *preferenceService) findOwnRule(userID, ruleID string) (*models.ParticipationRule, error) {
+	if _, err := uuid.Parse(ruleID); err != nil {
+		return nil, fmt.Errorf("invalid rule ID")
+	}
+	rule, err := s.ruleRepo.FindByID(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil || rule.UserID.String() != userID {
+		return nil, fmt.Errorf("participation rule not found")
+	}
+	return rule, nil
+}
+
+// applyRuleInput validates input onto rule, rejecting rules that would conflict with the
+// user's other rules for the same meal on a shared weekday within overlapping dates
+func (s *preferenceService) applyRuleInput(rule *models.ParticipationRule, input ParticipationRuleInput) error {
+	mealType, err := s.catalog.Validate(strings.ToLower(strings.TrimSpace(input.MealType)))
+	if err != nil {
+		return err
+	}
+
+	weekdays, err := normalizeWeekdays(input.Weekdays)
+	if err != nil {
+		return err
+	}
+
+	startDate := optionalDate(input.StartDate)
+	endDate := optionalDate(input.EndDate)
+	for _, date := range []*string{startDate, endDate} {
+		if date == nil {
+			continue
+		}
+		if err := validateDate(*date); err != nil {
+			return err
+		}
+	}
+	if startDate != nil && endDate != nil && *endDate < *startDate {
+		return fmt.Errorf("end date must not be before start date")
+	}
+
+	existing, err := s.ruleRepo.FindByUser(rule.UserID.String())
+	if err != nil {
+		return err
+	}
+	candidate := models.ParticipationRule{
+		MealType:  mealType,
+		Weekdays:  strings.Join(weekdays, ","),
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+	for _, other := range existing {
+		if other.ID == rule.ID || other.MealType != mealType || !rangesOverlap(candidate, other) {
+			continue
+		}
+		for _, day := range weekdays {
+			if other.AppliesOn(day) {
+				return fmt.Errorf("a %s rule already covers %s in this date range", mealType, day)
+			}
+		}
+	}
+
+	rule.MealType = mealType
+	rule.Weekdays = candidate.Weekdays
+	rule.IsParticipating = *input.IsParticipating
+	rule.StartDate = startDate
+	rule.EndDate = endDate
+	return nil
+}
+
+// normalizeWeekdays lowercases and de-duplicates weekday names, keeping Monday-first order
+func normalizeWeekdays(days []string) ([]string, error) {
+	if len(days) == 0 {
+		return nil, fmt.Errorf("at least one weekday is required")
+	}
+	selected := make(map[string]bool, len(days))
+	for _, day := range days {
+		name := strings.ToLower(strings.TrimSpace(day))
+		if !isWeekdayName(name) {
+			return nil, fmt.Errorf("invalid weekday: %s", day)
+		}
+		selected[name] = true
+	}
+
+	weekdays := make([]string, 0, len(selected))
+	for i := 1; i <= 7; i++ {
+		name := strings.ToLower(time.Weekday(i % 7).String())
+		if selected[name] {
+			weekdays = append(weekdays, name)
+		}
+	}
+	return weekdays, nil
+}
+
+func isWeekdayName(name string) bool {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.ToLower(day.String()) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// optionalDate trims an optional date, treating a blank value as absent
+func optionalDate(value *string) *string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	return &trimmed
+}
+
+// rangesOverlap reports whether two rules' optional date ranges share at least one day
+func rangesOverlap(a, b models.ParticipationRule) bool {
+	if a.EndDate != nil && b.StartDate != nil && dateKey(*a.EndDate) < dateKey(*b.StartDate) {
+		return false
+	}
+	if b.EndDate != nil && a.StartDate != nil && dateKey(*b.EndDate) < dateKey(*a.StartDate) {
+		return false
+	}
+	return true
+}
+
+// parseDietaryRestrictions parses the comma-separated restrictions stored on a user
+func parseDietaryRestrictions(value string) []models.DietaryRestriction {
+	restrictions := []models.DietaryRestriction{}
+	for _, part := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			restrictions = append(restrictions, models.DietaryRestriction(trimmed))
+		}
+	}
+	return restrictions
+}
@@ -0,0 +1,452 @@
+package services
+
+import (
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/mailer"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"craftsbite-backend/internal/utils"
+	"craftsbite-backend/pkg/logger"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// invitationTokenBytes is the entropy of an invitation token
+const invitationTokenBytes = 32
+
+var (
+	// ErrRegistrationClosed is returned by Register while registration is invite only
+	ErrRegistrationClosed = errors.New("registration is by invitation only")
+	// ErrEmailDomainNotAllowed is returned by Register for an email outside the allowed domains
+	ErrEmailDomainNotAllowed = errors.New("email domain is not allowed to register")
+)
+
+// CreateInvitationInput represents input for inviting someone
+type CreateInvitationInput struct {
+	Email  string `json:"email" binding:"required,email"`
+	Role   string `json:"role"`
+	TeamID string `json:"team_id"`
+}
+
+// AcceptInvitationInput represents the account details an invitee chooses
+type AcceptInvitationInput struct {
+	Token    string `json:"token" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// InvitationSent is an invitation with the link that accepts it. The link carries the
+// token, so it is only returned when the invitation is created or resent.
+type InvitationSent struct {
+	Invitation *models.Invitation `json:"invitation"`
+	AcceptURL  string             `json:"accept_url"`
+	EmailSent  bool               `json:"email_sent"`
+}
+
+// RegistrationService creates accounts, from admin invitations or, when registration is
+// open, by self-registration as an employee with an allowed email domain
+type RegistrationService interface {
+	Register(name, email, password string, client ClientInfo) (*models.User, error)
+	CreateInvitation(actorID string, input CreateInvitationInput, client ClientInfo) (*InvitationSent, error)
+	ListInvitations(status string) ([]models.Invitation, error)
+	ResendInvitation(id string) (*InvitationSent, error)
+	RevokeInvitation(actorID, id string, client ClientInfo) (*models.Invitation, error)
+	GetInvitation(token string) (*models.Invitation, error)
+	AcceptInvitation(input AcceptInvitationInput, client ClientInfo) (*models.User, error)
+}
+
+type registrationService struct {
+	invitationRepo  repository.InvitationRepository
+	userRepo        repository.UserRepository
+	roleRepo        repository.RoleRepository
+	teamRepo        repository.TeamRepository
+	teamHistoryRepo repository.TeamHistoryRepository
+	auditRepo       repository.AuthAuditRepository
+	userService     UserService
+	mailer          mailer.Mailer
+	cfg             config.RegistrationConfig
+	appURL          string
+}
+
+// NewRegistrationService creates a new registration service
+func NewRegistrationService(
+	invitationRepo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	teamRepo repository.TeamRepository,
+	teamHistoryRepo repository.TeamHistoryRepository,
+	auditRepo repository.AuthAuditRepository,
+	userService UserService,
+	m mailer.Mailer,
+	cfg *config.Config,
+) RegistrationService {
+	return &registrationService{
+		invitationRepo:  invitationRepo,
+		userRepo:        userRepo,
+		roleRepo:        roleRepo,
+		teamRepo:        teamRepo,
+		teamHistoryRepo: teamHistoryRepo,
+		auditRepo:       auditRepo,
+		userService:     userService,
+		mailer:          m,
+		cfg:             cfg.Registration,
+		appURL:          cfg.Server.AppURL,
+	}
+}
+
+// Register creates an employee account for someone signing up themselves. It fails with
+// ErrRegistrationClosed unless registration is open, and with ErrEmailDomainNotAllowed for
+// addresses outside the allowed domains.
+func (s *registrationService) Register(name, email, password string, client ClientInfo) (*models.User, error) {
+	if s.cfg.Mode != config.RegistrationOpen {
+		return nil, ErrRegistrationClosed
+	}
+	email = normalizeEmail(email)
+	if !s.domainAllowed(email) {
+		return nil, ErrEmailDomainNotAllowed
+	}
+
+	user, err := s.userService.CreateUser(CreateUserInput{
+		Email:                 email,
+		Name:                  strings.TrimSpace(name),
+		Password:              password,
+		Role:                  models.RoleEmployee,
+		DefaultMealPreference: "opt_in",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry := newAuthAuditLog(models.AuthEventUserRegistered, client)
+	entry.UserID = &user.ID
+	entry.Email = &user.Email
+	recordAuthEvent(s.auditRepo, entry, "")
+	return user, nil
+}
+
+func (s *registrationService) domainAllowed(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, allowed := range s.cfg.AllowedDomains {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateInvitation invites an email address to join with a role, employee by default, and
+// optionally a team, and emails the invitee a link to accept it
+func (s *registrationService) CreateInvitation(actorID string, input CreateInvitationInput, client ClientInfo) (*InvitationSent, error) {
+	actorUUID, err := uuid.Parse(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid actor ID")
+	}
+	now := time.Now()
+	email := normalizeEmail(input.Email)
+
+	if existing, _ := s.userRepo.FindByEmail(email); existing != nil {
+		return nil, fmt.Errorf("a user with this email already exists")
+	}
+	pending, err := s.invitationRepo.FindPendingByEmail(email, now)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, fmt.Errorf("a pending invitation already exists for this email, resend it instead")
+	}
+
+	role := models.Role(input.Role)
+	if role == "" {
+		role = models.RoleEmployee
+	}
+	definition, err := s.roleRepo.FindByName(role.String())
+	if err != nil {
+		return nil, err
+	}
+	if definition == nil {
+		return nil, fmt.Errorf("invalid role: %s", role)
+	}
+
+	invitation := &models.Invitation{
+		Email:     email,
+		Role:      role,
+		CreatedBy: &actorUUID,
+	}
+	if input.TeamID != "" {
+		team, err := s.activeTeam(input.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		invitation.TeamID = &team.ID
+	}
+
+	token, err := s.issueToken(invitation, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, err
+	}
+
+	entry := newAuthAuditLog(models.AuthEventInvitationCreated, client)
+	entry.Email = &invitation.Email
+	entry.ActorID = &actorUUID
+	recordAuthEvent(s.auditRepo, entry, fmt.Sprintf("invited as %s", invitation.Role))
+
+	// Reload for the team name used in the email
+	if created, err := s.invitationRepo.FindByID(invitation.ID.String()); err == nil && created != nil {
+		invitation = created
+	}
+	return s.send(invitation, token, now), nil
+}
+
+// ListInvitations returns invitations newest first, optionally only those with a status
+func (s *registrationService) ListInvitations(status string) ([]models.Invitation, error) {
+	switch status {
+	case "", models.InvitationPending, models.InvitationAccepted, models.InvitationRevoked, models.InvitationExpired:
+	default:
+		return nil, fmt.Errorf("status must be one of: pending, accepted, revoked, expired")
+	}
+
+	now := time.Now()
+	invitations, err := s.invitationRepo.FindAll(status, now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range invitations {
+		invitations[i].Status = invitations[i].StatusAt(now)
+	}
+	return invitations, nil
+}
+
+// ResendInvitation issues a new token for a pending or expired invitation, which extends its
+// expiry and invalidates the link sent before, and emails the invitee again
+func (s *registrationService) ResendInvitation(id string) (*InvitationSent, error) {
+	invitation, err := s.findInvitation(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	switch invitation.StatusAt(now) {
+	case models.InvitationAccepted:
+		return nil, fmt.Errorf("invitation has already been accepted")
+	case models.InvitationRevoked:
+		return nil, fmt.Errorf("invitation has been revoked")
+	case models.InvitationExpired:
+		pending, err := s.invitationRepo.FindPendingByEmail(invitation.Email, now)
+		if err != nil {
+			return nil, err
+		}
+		if pending != nil {
+			return nil, fmt.Errorf("a newer invitation is pending for this email")
+		}
+	}
+	if existing, _ := s.userRepo.FindByEmail(invitation.Email); existing != nil {
+		return nil, fmt.Errorf("a user with this email already exists")
+	}
+
+	token, err := s.issueToken(invitation, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invitationRepo.Update(invitation); err != nil {
+		return nil, err
+	}
+	return s.send(invitation, token, now), nil
+}
+
+// RevokeInvitation withdraws a pending invitation so its link no longer works
+func (s *registrationService) RevokeInvitation(actorID, id string, client ClientInfo) (*models.Invitation, error) {
+	actorUUID, err := uuid.Parse(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid actor ID")
+	}
+	invitation, err := s.findInvitation(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	switch invitation.StatusAt(now) {
+	case models.InvitationAccepted:
+		return nil, fmt.Errorf("invitation has already been accepted")
+	case models.InvitationRevoked:
+		return nil, fmt.Errorf("invitation has already been revoked")
+	}
+
+	invitation.RevokedAt = &now
+	if err := s.invitationRepo.Update(invitation); err != nil {
+		return nil, err
+	}
+	invitation.Status = invitation.StatusAt(now)
+
+	entry := newAuthAuditLog(models.AuthEventInvitationRevoked, client)
+	entry.Email = &invitation.Email
+	entry.ActorID = &actorUUID
+	recordAuthEvent(s.auditRepo, entry, "")
+	return invitation, nil
+}
+
+// GetInvitation returns the pending invitation a token belongs to, so the invitee can see
+// what they are accepting
+func (s *registrationService) GetInvitation(token string) (*models.Invitation, error) {
+	invitation, err := s.invitationRepo.FindByTokenHash(utils.HashOpaqueToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil {
+		return nil, fmt.Errorf("invalid invitation token")
+	}
+
+	invitation.Status = invitation.StatusAt(time.Now())
+	switch invitation.Status {
+	case models.InvitationAccepted:
+		return nil, fmt.Errorf("invitation has already been accepted")
+	case models.InvitationRevoked:
+		return nil, fmt.Errorf("invitation has been revoked")
+	case models.InvitationExpired:
+		return nil, fmt.Errorf("invitation has expired")
+	}
+	return invitation, nil
+}
+
+// AcceptInvitation creates the invitee's account with the invited role and adds it to the
+// invited team. The invitation can be accepted once.
+func (s *registrationService) AcceptInvitation(input AcceptInvitationInput, client ClientInfo) (*models.User, error) {
+	invitation, err := s.GetInvitation(input.Token)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if existing, _ := s.userRepo.FindByEmail(invitation.Email); existing != nil {
+		return nil, fmt.Errorf("a user with this email already exists")
+	}
+	if invitation.TeamID != nil {
+		if _, err := s.activeTeam(invitation.TeamID.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	hashedPassword, err := utils.HashPassword(input.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user := &models.User{
+		ID:                    uuid.New(),
+		Email:                 invitation.Email,
+		Name:                  name,
+		Password:              hashedPassword,
+		Role:                  invitation.Role,
+		Active:                true,
+		DefaultMealPreference: "opt_in",
+	}
+
+	now := time.Now()
+	accepted, err := s.invitationRepo.Accept(invitation, user, now)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, fmt.Errorf("invitation is no longer valid")
+	}
+
+	if invitation.TeamID != nil {
+		history := &models.TeamHistory{
+			TeamID:    *invitation.TeamID,
+			Action:    models.TeamActionMemberAdded,
+			ActorID:   invitation.CreatedBy,
+			SubjectID: &user.ID,
+		}
+		if err := s.teamHistoryRepo.Create(history); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to record team history for invited user %s: %v", user.ID, err))
+		}
+	}
+
+	entry := newAuthAuditLog(models.AuthEventInvitationAccepted, client)
+	entry.UserID = &user.ID
+	entry.Email = &user.Email
+	entry.ActorID = invitation.CreatedBy
+	recordAuthEvent(s.auditRepo, entry, fmt.Sprintf("joined as %s", user.Role))
+	return user, nil
+}
+
+func (s *registrationService) findInvitation(id string) (*models.Invitation, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid invitation ID")
+	}
+	invitation, err := s.invitationRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil {
+		return nil, fmt.Errorf("invitation not found")
+	}
+	return invitation, nil
+}
+
+func (s *registrationService) activeTeam(teamID string) (*models.Team, error) {
+	if _, err := uuid.Parse(teamID); err != nil {
+		return nil, fmt.Errorf("invalid team ID")
+	}
+	team, err := s.teamRepo.FindByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if !team.Active {
+		return nil, fmt.Errorf("team is deactivated")
+	}
+	return team, nil
+}
+
+// issueToken gives the invitation a new token and expiry and returns the token
+func (s *registrationService) issueToken(invitation *models.Invitation, now time.Time) (string, error) {
+	token, err := utils.GenerateOpaqueToken(invitationTokenBytes)
+	if err != nil {
+		return "", err
+	}
+	invitation.TokenHash = utils.HashOpaqueToken(token)
+	invitation.ExpiresAt = now.Add(s.cfg.InviteTTL)
+	return token, nil
+}
+
+// send emails the invitation link. A failed send is logged; the admin still gets the link
+// and can pass it on.
+func (s *registrationService) send(invitation *models.Invitation, token string, now time.Time) *InvitationSent {
+	invitation.Status = invitation.StatusAt(now)
+	sent := &InvitationSent{
+		Invitation: invitation,
+		AcceptURL:  s.appURL + "/accept-invite?token=" + url.QueryEscape(token),
+	}
+
+	var body strings.Builder
+	body.WriteString("You have been invited to CraftsBite")
+	if invitation.Team != nil {
+		fmt.Fprintf(&body, " to join the %s team", invitation.Team.Name)
+	}
+	fmt.Fprintf(&body, " as %s.\n\n", strings.ReplaceAll(invitation.Role.String(), "_", " "))
+	fmt.Fprintf(&body, "Choose your name and password to create your account:\n%s\n\n", sent.AcceptURL)
+	fmt.Fprintf(&body, "The link can be used once and expires on %s.\n", invitation.ExpiresAt.Format("2 Jan 2006 15:04 MST"))
+
+	err := s.mailer.Send(mailer.Message{
+		To:      []string{invitation.Email},
+		Subject: "Your invitation to CraftsBite",
+		Body:    body.String(),
+	})
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Failed to email invitation %s: %v", invitation.ID, err))
+		return sent
+	}
+	sent.EmailSent = true
+	return sent
+}
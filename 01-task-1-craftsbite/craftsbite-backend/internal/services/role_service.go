@@ -0,0 +1,192 @@
+package services
+
+import (
+	"craftsbite-backend/internal/authz"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// roleNamePattern restricts role names to lowercase identifiers such as "kitchen_staff"
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+// PermissionGrant is a permission granted to a role at a scope
+type PermissionGrant struct {
+	Permission authz.Permission `json:"permission" binding:"required"`
+	Scope      authz.Scope      `json:"scope" binding:"required"`
+}
+
+// CreateRoleInput represents input for defining a custom role
+type CreateRoleInput struct {
+	Name        string            `json:"name" binding:"required"`
+	Description string            `json:"description"`
+	Permissions []PermissionGrant `json:"permissions"`
+}
+
+// UpdateRoleInput represents input for updating a role. Permissions, when given, replace the existing grants.
+type UpdateRoleInput struct {
+	Description *string            `json:"description"`
+	Permissions *[]PermissionGrant `json:"permissions"`
+}
+
+// RoleService defines the interface for managing roles and their permissions
+type RoleService interface {
+	ListRoles() ([]models.RoleDefinition, error)
+	GetRole(name string) (*models.RoleDefinition, error)
+	CreateRole(input CreateRoleInput) (*models.RoleDefinition, error)
+	UpdateRole(name string, input UpdateRoleInput) (*models.RoleDefinition, error)
+	DeleteRole(name string) error
+	ListPermissions() []authz.PermissionInfo
+}
+
+type roleService struct {
+	roleRepo   repository.RoleRepository
+	authorizer authz.Authorizer
+}
+
+// NewRoleService creates a new role service
+func NewRoleService(roleRepo repository.RoleRepository, authorizer authz.Authorizer) RoleService {
+	return &roleService{
+		roleRepo:   roleRepo,
+		authorizer: authorizer,
+	}
+}
+
+// ListRoles returns every role with its permissions
+func (s *roleService) ListRoles() ([]models.RoleDefinition, error) {
+	return s.roleRepo.FindAll()
+}
+
+// GetRole returns a role with its permissions
+func (s *roleService) GetRole(name string) (*models.RoleDefinition, error) {
+	role, err := s.roleRepo.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, fmt.Errorf("role not found")
+	}
+	return role, nil
+}
+
+// CreateRole defines a custom role
+func (s *roleService) CreateRole(input CreateRoleInput) (*models.RoleDefinition, error) {
+	name := strings.ToLower(strings.TrimSpace(input.Name))
+	if !roleNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("role name must be 2-50 lowercase letters, digits or underscores, starting with a letter")
+	}
+
+	existing, err := s.roleRepo.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("role %s already exists", name)
+	}
+
+	permissions, err := toRolePermissions(name, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.RoleDefinition{
+		Name:        name,
+		Description: strings.TrimSpace(input.Description),
+		Permissions: permissions,
+	}
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+	s.authorizer.Invalidate()
+
+	return s.roleRepo.FindByName(name)
+}
+
+// UpdateRole changes a role's description and permissions.
+// The admin role cannot be edited so that administrators cannot lock themselves out.
+func (s *roleService) UpdateRole(name string, input UpdateRoleInput) (*models.RoleDefinition, error) {
+	role, err := s.GetRole(name)
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == models.RoleAdmin.String() {
+		return nil, fmt.Errorf("the admin role cannot be modified")
+	}
+
+	if input.Description != nil {
+		role.Description = strings.TrimSpace(*input.Description)
+	}
+	if input.Permissions != nil {
+		permissions, err := toRolePermissions(role.Name, *input.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		role.Permissions = permissions
+	}
+
+	if err := s.roleRepo.Update(role); err != nil {
+		return nil, err
+	}
+	s.authorizer.Invalidate()
+
+	return s.roleRepo.FindByName(name)
+}
+
+// DeleteRole removes a custom role that no user holds
+func (s *roleService) DeleteRole(name string) error {
+	role, err := s.GetRole(name)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return fmt.Errorf("system role %s cannot be deleted", role.Name)
+	}
+
+	count, err := s.roleRepo.CountUsers(role.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("role %s is assigned to %d user(s)", role.Name, count)
+	}
+
+	if err := s.roleRepo.Delete(role.Name); err != nil {
+		return err
+	}
+	s.authorizer.Invalidate()
+	return nil
+}
+
+// ListPermissions returns the catalog of permissions that can be granted
+func (s *roleService) ListPermissions() []authz.PermissionInfo {
+	return authz.Catalog
+}
+
+// toRolePermissions validates grants against the permission catalog
+func toRolePermissions(roleName string, grants []PermissionGrant) ([]models.RolePermission, error) {
+	seen := make(map[authz.Permission]bool, len(grants))
+	permissions := make([]models.RolePermission, 0, len(grants))
+
+	for _, grant := range grants {
+		info, ok := authz.LookupPermission(grant.Permission)
+		if !ok {
+			return nil, fmt.Errorf("unknown permission: %s", grant.Permission)
+		}
+		if !info.AllowsScope(grant.Scope) {
+			return nil, fmt.Errorf("permission %s cannot be granted at scope %q", grant.Permission, grant.Scope)
+		}
+		if seen[grant.Permission] {
+			return nil, fmt.Errorf("permission %s is granted more than once", grant.Permission)
+		}
+		seen[grant.Permission] = true
+
+		permissions = append(permissions, models.RolePermission{
+			RoleName:   roleName,
+			Permission: string(grant.Permission),
+			Scope:      string(grant.Scope),
+		})
+	}
+	return permissions, nil
+}
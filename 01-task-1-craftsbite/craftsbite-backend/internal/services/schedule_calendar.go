@@ -0,0 +1,254 @@
+package services
+
+import (
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/recurrence"
+	"craftsbite-backend/internal/repository"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxCalendarRangeDays bounds how many days EffectiveDays can list in one request
+const maxCalendarRangeDays = 366
+
+// Sources of an effective day
+const (
+	ScheduleSourceExplicit = "schedule"
+	ScheduleSourceRule     = "rule"
+	ScheduleSourceWeekend  = "weekend"
+	ScheduleSourceDefault  = "default"
+)
+
+// ScheduleCalendar resolves the effective schedule of a date: an explicit DaySchedule if one exists,
+// otherwise the highest-priority ScheduleRule matching the date. It has the read methods of
+// ScheduleRepository so services that only read schedules see recurring rules transparently.
+type ScheduleCalendar interface {
+	FindByDate(date string) (*models.DaySchedule, error)
+	FindByDateRange(startDate, endDate string) ([]models.DaySchedule, error)
+	// EffectiveDays describes every day in the range, including weekends and days without a schedule.
+	// Draft rules take part in the expansion as if they were stored; rules whose ID is in exclude are ignored.
+	EffectiveDays(startDate, endDate string, drafts []models.ScheduleRule, exclude ...uuid.UUID) ([]EffectiveDay, error)
+}
+
+// EffectiveDay is the schedule that applies to a single date and where it came from
+type EffectiveDay struct {
+	Date           string            `json:"date"`
+	DayStatus      models.DayStatus  `json:"day_status"`
+	AvailableMeals []models.MealType `json:"available_meals"`
+	Reason         *string           `json:"reason,omitempty"`
+	Source         string            `json:"source"`
+	RuleID         *uuid.UUID        `json:"rule_id,omitempty"`
+	RuleName       string            `json:"rule_name,omitempty"`
+}
+
+type scheduleCalendar struct {
+	scheduleRepo repository.ScheduleRepository
+	ruleRepo     repository.ScheduleRuleRepository
+	weekendDays  map[string]bool
+}
+
+// NewScheduleCalendar creates a schedule calendar over explicit schedules and recurring rules
+func NewScheduleCalendar(scheduleRepo repository.ScheduleRepository, ruleRepo repository.ScheduleRuleRepository, cfg *config.Config) ScheduleCalendar {
+	return &scheduleCalendar{
+		scheduleRepo: scheduleRepo,
+		ruleRepo:     ruleRepo,
+		weekendDays:  weekendDaySet(cfg.Meal.WeekendDays),
+	}
+}
+
+// FindByDate returns the effective schedule of a date, or nil if neither a schedule nor a rule applies
+func (c *scheduleCalendar) FindByDate(date string) (*models.DaySchedule, error) {
+	schedules, err := c.FindByDateRange(date, date)
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) == 0 {
+		return nil, nil
+	}
+	return &schedules[0], nil
+}
+
+// FindByDateRange returns the effective schedules within a date range, ordered by date.
+// Schedules expanded from a rule have a zero ID and RuleID set.
+func (c *scheduleCalendar) FindByDateRange(startDate, endDate string) ([]models.DaySchedule, error) {
+	explicit, ruled, err := c.expand(startDate, endDate, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]models.DaySchedule, 0, len(explicit)+len(ruled))
+	for _, schedule := range explicit {
+		schedules = append(schedules, *schedule)
+	}
+	for date, rule := range ruled {
+		schedules = append(schedules, scheduleFromRule(date, rule))
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return dateKey(schedules[i].Date) < dateKey(schedules[j].Date)
+	})
+	return schedules, nil
+}
+
+func (c *scheduleCalendar) EffectiveDays(startDate, endDate string, drafts []models.ScheduleRule, exclude ...uuid.UUID) ([]EffectiveDay, error) {
+	start, end, err := parseCalendarRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if end.Sub(start) >= maxCalendarRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("date range cannot exceed %d days", maxCalendarRangeDays)
+	}
+
+	excluded := make(map[uuid.UUID]bool, len(exclude))
+	for _, id := range exclude {
+		excluded[id] = true
+	}
+	explicit, ruled, err := c.expand(startDate, endDate, drafts, excluded)
+	if err != nil {
+		return nil, err
+	}
+
+	days := []EffectiveDay{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		effective := EffectiveDay{
+			Date:           date,
+			DayStatus:      models.DayStatusNormal,
+			AvailableMeals: []models.MealType{},
+			Source:         ScheduleSourceDefault,
+		}
+
+		if schedule, ok := explicit[date]; ok {
+			effective.DayStatus = schedule.DayStatus
+			effective.Reason = schedule.Reason
+			effective.Source = ScheduleSourceExplicit
+			if schedule.AvailableMeals != nil {
+				effective.AvailableMeals = parseMealTypes(*schedule.AvailableMeals)
+			}
+		} else if rule, ok := ruled[date]; ok {
+			ruleID := rule.ID
+			effective.DayStatus = rule.DayStatus
+			effective.Reason = rule.Reason
+			effective.Source = ScheduleSourceRule
+			effective.RuleID = &ruleID
+			effective.RuleName = rule.Name
+			if rule.AvailableMeals != nil {
+				effective.AvailableMeals = parseMealTypes(*rule.AvailableMeals)
+			}
+		} else if c.weekendDays[strings.ToLower(day.Weekday().String())] {
+			effective.DayStatus = models.DayStatusWeekend
+			effective.Source = ScheduleSourceWeekend
+		}
+
+		days = append(days, effective)
+	}
+	return days, nil
+}
+
+// expand loads explicit schedules in the range and, for dates without one, the winning rule
+func (c *scheduleCalendar) expand(startDate, endDate string, drafts []models.ScheduleRule, excluded map[uuid.UUID]bool) (map[string]*models.DaySchedule, map[string]*models.ScheduleRule, error) {
+	start, end, err := parseCalendarRange(startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schedules, err := c.scheduleRepo.FindByDateRange(startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	explicit := make(map[string]*models.DaySchedule, len(schedules))
+	for i := range schedules {
+		explicit[dateKey(schedules[i].Date)] = &schedules[i]
+	}
+
+	stored, err := c.ruleRepo.FindActiveInRange(startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Drafts go first so they win ties against stored rules of the same priority
+	rules := make([]models.ScheduleRule, 0, len(drafts)+len(stored))
+	rules = append(rules, drafts...)
+	for _, rule := range stored {
+		if !excluded[rule.ID] {
+			rules = append(rules, rule)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority > rules[j].Priority
+	})
+
+	ruled := make(map[string]*models.ScheduleRule)
+	for i := range rules {
+		occurrences, err := ruleOccurrences(&rules[i], start, end)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, day := range occurrences {
+			date := day.Format("2006-01-02")
+			if _, ok := explicit[date]; ok {
+				continue
+			}
+			if _, ok := ruled[date]; !ok {
+				ruled[date] = &rules[i]
+			}
+		}
+	}
+
+	return explicit, ruled, nil
+}
+
+// ruleOccurrences expands a rule within [from, to], clipped to the rule's own end date
+func ruleOccurrences(rule *models.ScheduleRule, from, to time.Time) ([]time.Time, error) {
+	parsed, err := recurrence.Parse(rule.RRule)
+	if err != nil {
+		return nil, fmt.Errorf("schedule rule %q: %w", rule.Name, err)
+	}
+	start, err := time.Parse("2006-01-02", dateKey(rule.StartDate))
+	if err != nil {
+		return nil, fmt.Errorf("schedule rule %q has an invalid start date", rule.Name)
+	}
+	if rule.EndDate != nil {
+		ruleEnd, err := time.Parse("2006-01-02", dateKey(*rule.EndDate))
+		if err != nil {
+			return nil, fmt.Errorf("schedule rule %q has an invalid end date", rule.Name)
+		}
+		if ruleEnd.Before(to) {
+			to = ruleEnd
+		}
+	}
+	return parsed.Between(start, from, to), nil
+}
+
+// scheduleFromRule builds the virtual DaySchedule a rule produces for a date
+func scheduleFromRule(date string, rule *models.ScheduleRule) models.DaySchedule {
+	ruleID := rule.ID
+	return models.DaySchedule{
+		Date:           date,
+		DayStatus:      rule.DayStatus,
+		Reason:         rule.Reason,
+		AvailableMeals: rule.AvailableMeals,
+		CreatedBy:      rule.CreatedBy,
+		RuleID:         &ruleID,
+	}
+}
+
+// parseCalendarRange validates a date range
+func parseCalendarRange(startDate, endDate string) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date format, expected YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date format, expected YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date must not be before start date")
+	}
+	return start, end, nil
+}
@@ -1,12 +1,17 @@
 package services
 
 import (
+	"craftsbite-backend/internal/ical"
 	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/recurrence"
 	"craftsbite-backend/internal/repository"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // ScheduleService defines the interface for day schedule business logic
@@ -16,8 +21,19 @@ type ScheduleService interface {
 	CreateSchedule(adminID string, input CreateScheduleInput) (*models.DaySchedule, error)
 	UpdateSchedule(id string, input UpdateScheduleInput) (*models.DaySchedule, error)
 	DeleteSchedule(id string) error
+
+	ListRules() ([]models.ScheduleRule, error)
+	CreateRule(adminID string, input ScheduleRuleInput) (*models.ScheduleRule, error)
+	UpdateRule(id string, input UpdateScheduleRuleInput) (*models.ScheduleRule, error)
+	DeleteRule(id string) error
+	GetEffectiveSchedule(startDate, endDate string) ([]EffectiveDay, error)
+	PreviewSchedule(input PreviewScheduleInput) (*SchedulePreview, error)
+	ImportHolidays(adminID string, calendar io.Reader, dryRun bool) (*HolidayImportResult, error)
 }
 
+// holidayRulePriority ranks rules imported from recurring holiday events above ordinary patterns
+const holidayRulePriority = 100
+
 // CreateScheduleInput represents input for creating a day schedule
 type CreateScheduleInput struct {
 	Date           string            `json:"date" binding:"required"`
@@ -33,29 +49,95 @@ type UpdateScheduleInput struct {
 	AvailableMeals *[]models.MealType `json:"available_meals"`
 }
 
+// ScheduleRuleInput represents input for creating a recurring schedule rule
+type ScheduleRuleInput struct {
+	Name           string            `json:"name" binding:"required"`
+	RRule          string            `json:"rrule" binding:"required"`
+	StartDate      string            `json:"start_date" binding:"required"`
+	EndDate        *string           `json:"end_date"`
+	DayStatus      models.DayStatus  `json:"day_status" binding:"required"`
+	Reason         string            `json:"reason"`
+	AvailableMeals []models.MealType `json:"available_meals"`
+	Priority       int               `json:"priority"`
+}
+
+// UpdateScheduleRuleInput represents input for updating a recurring schedule rule
+type UpdateScheduleRuleInput struct {
+	Name           *string            `json:"name"`
+	RRule          *string            `json:"rrule"`
+	StartDate      *string            `json:"start_date"`
+	EndDate        *string            `json:"end_date"`
+	ClearEndDate   bool               `json:"clear_end_date"`
+	DayStatus      *models.DayStatus  `json:"day_status"`
+	Reason         *string            `json:"reason"`
+	AvailableMeals *[]models.MealType `json:"available_meals"`
+	Priority       *int               `json:"priority"`
+	Active         *bool              `json:"active"`
+}
+
+// PreviewScheduleInput asks for the effective schedule of a range with a draft rule applied.
+// With RuleID set, the draft replaces that stored rule (an edit); RuleID without a draft previews deleting it.
+type PreviewScheduleInput struct {
+	StartDate string             `json:"start_date" binding:"required"`
+	EndDate   string             `json:"end_date" binding:"required"`
+	Rule      *ScheduleRuleInput `json:"rule"`
+	RuleID    *string            `json:"rule_id"`
+}
+
+// SchedulePreview is the effective schedule of a range with a draft applied, and the dates it changes
+type SchedulePreview struct {
+	StartDate    string         `json:"start_date"`
+	EndDate      string         `json:"end_date"`
+	Days         []EffectiveDay `json:"days"`
+	ChangedDates []string       `json:"changed_dates"`
+}
+
+// HolidayImportSkip is a holiday date that was not imported
+type HolidayImportSkip struct {
+	Date    string `json:"date"`
+	Summary string `json:"summary"`
+	Reason  string `json:"reason"`
+}
+
+// HolidayImportResult reports what an iCalendar import created (or would create, for a dry run)
+type HolidayImportResult struct {
+	DryRun    bool                  `json:"dry_run"`
+	Schedules []models.DaySchedule  `json:"schedules"`
+	Rules     []models.ScheduleRule `json:"rules"`
+	Skipped   []HolidayImportSkip   `json:"skipped"`
+}
+
 // scheduleService implements ScheduleService
 type scheduleService struct {
+	db           *gorm.DB
 	scheduleRepo repository.ScheduleRepository
+	ruleRepo     repository.ScheduleRuleRepository
+	calendar     ScheduleCalendar
+	catalog      MealCatalog
 }
 
 // NewScheduleService creates a new schedule service
-func NewScheduleService(scheduleRepo repository.ScheduleRepository) ScheduleService {
+func NewScheduleService(db *gorm.DB, scheduleRepo repository.ScheduleRepository, ruleRepo repository.ScheduleRuleRepository, calendar ScheduleCalendar, catalog MealCatalog) ScheduleService {
 	return &scheduleService{
+		db:           db,
 		scheduleRepo: scheduleRepo,
+		ruleRepo:     ruleRepo,
+		calendar:     calendar,
+		catalog:      catalog,
 	}
 }
 
-// GetSchedule gets a day schedule by date
+// GetSchedule gets the effective day schedule of a date, which may come from a recurring rule
 func (s *scheduleService) GetSchedule(date string) (*models.DaySchedule, error) {
 	// Validate date format
 	if _, err := time.Parse("2006-01-02", date); err != nil {
 		return nil, fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
 	}
 
-	return s.scheduleRepo.FindByDate(date)
+	return s.calendar.FindByDate(date)
 }
 
-// GetScheduleRange gets day schedules within a date range
+// GetScheduleRange gets the effective day schedules within a date range
 func (s *scheduleService) GetScheduleRange(startDate, endDate string) ([]models.DaySchedule, error) {
 	// Validate date formats
 	if _, err := time.Parse("2006-01-02", startDate); err != nil {
@@ -65,7 +147,7 @@ func (s *scheduleService) GetScheduleRange(startDate, endDate string) ([]models.
 		return nil, fmt.Errorf("invalid end date format, expected YYYY-MM-DD: %w", err)
 	}
 
-	return s.scheduleRepo.FindByDateRange(startDate, endDate)
+	return s.calendar.FindByDateRange(startDate, endDate)
 }
 
 // CreateSchedule creates a new day schedule
@@ -94,12 +176,8 @@ func (s *scheduleService) CreateSchedule(adminID string, input CreateScheduleInp
 		return nil, fmt.Errorf("invalid day status: %s", input.DayStatus)
 	}
 
-	if len(input.AvailableMeals) > 0 {
-		for _, meal := range input.AvailableMeals {
-		    if !models.MealType(meal).IsValid() {
-		        return nil, fmt.Errorf("invalid meal type: %s", meal)
-		    }
-		}
+	if err := s.validateMeals(input.AvailableMeals); err != nil {
+		return nil, err
 	}
 
 	// Convert meal types slice to comma-separated string
@@ -146,6 +224,9 @@ func (s *scheduleService) UpdateSchedule(id string, input UpdateScheduleInput) (
 		schedule.Reason = input.Reason
 	}
 	if input.AvailableMeals != nil {
+		if err := s.validateMeals(*input.AvailableMeals); err != nil {
+			return nil, err
+		}
 		mealsStr := serializeMealTypes(*input.AvailableMeals)
 		schedule.AvailableMeals = &mealsStr
 	}
@@ -161,3 +242,364 @@ func (s *scheduleService) UpdateSchedule(id string, input UpdateScheduleInput) (
 func (s *scheduleService) DeleteSchedule(id string) error {
 	return s.scheduleRepo.Delete(id)
 }
+
+// ListRules returns every recurring schedule rule, highest priority first
+func (s *scheduleService) ListRules() ([]models.ScheduleRule, error) {
+	return s.ruleRepo.FindAll()
+}
+
+// CreateRule creates a recurring schedule rule
+func (s *scheduleService) CreateRule(adminID string, input ScheduleRuleInput) (*models.ScheduleRule, error) {
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid admin ID: %w", err)
+	}
+
+	rule, err := s.buildScheduleRule(input)
+	if err != nil {
+		return nil, err
+	}
+	rule.CreatedBy = &adminUUID
+
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule updates a recurring schedule rule
+func (s *scheduleService) UpdateRule(id string, input UpdateScheduleRuleInput) (*models.ScheduleRule, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("schedule rule not found")
+	}
+	rule, err := s.ruleRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("schedule rule not found")
+	}
+
+	if input.Name != nil {
+		rule.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.RRule != nil {
+		rule.RRule = strings.TrimPrefix(strings.TrimSpace(*input.RRule), "RRULE:")
+	}
+	if input.StartDate != nil {
+		rule.StartDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		rule.EndDate = input.EndDate
+	}
+	if input.ClearEndDate {
+		rule.EndDate = nil
+	}
+	if input.DayStatus != nil {
+		rule.DayStatus = *input.DayStatus
+	}
+	if input.Reason != nil {
+		rule.Reason = input.Reason
+	}
+	if input.AvailableMeals != nil {
+		mealsStr := serializeMealTypes(*input.AvailableMeals)
+		rule.AvailableMeals = &mealsStr
+	}
+	if input.Priority != nil {
+		rule.Priority = *input.Priority
+	}
+	if input.Active != nil {
+		rule.Active = *input.Active
+	}
+
+	rule.StartDate = dateKey(rule.StartDate)
+	if rule.EndDate != nil {
+		endDate := dateKey(*rule.EndDate)
+		rule.EndDate = &endDate
+	}
+	if err := s.validateScheduleRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes a recurring schedule rule
+func (s *scheduleService) DeleteRule(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("schedule rule not found")
+	}
+	rule, err := s.ruleRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		return fmt.Errorf("schedule rule not found")
+	}
+	return s.ruleRepo.Delete(id)
+}
+
+// GetEffectiveSchedule lists every day in a range with the schedule that applies to it
+func (s *scheduleService) GetEffectiveSchedule(startDate, endDate string) ([]EffectiveDay, error) {
+	return s.calendar.EffectiveDays(startDate, endDate, nil)
+}
+
+// PreviewSchedule shows the effective schedule of a range as it would be with a draft rule saved
+func (s *scheduleService) PreviewSchedule(input PreviewScheduleInput) (*SchedulePreview, error) {
+	current, err := s.calendar.EffectiveDays(input.StartDate, input.EndDate, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var drafts []models.ScheduleRule
+	if input.Rule != nil {
+		draft, err := s.buildScheduleRule(*input.Rule)
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, *draft)
+	}
+
+	var exclude []uuid.UUID
+	if input.RuleID != nil {
+		ruleID, err := uuid.Parse(*input.RuleID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule ID")
+		}
+		if len(drafts) > 0 {
+			drafts[0].ID = ruleID
+		}
+		exclude = append(exclude, ruleID)
+	}
+
+	days, err := s.calendar.EffectiveDays(input.StartDate, input.EndDate, drafts, exclude...)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &SchedulePreview{
+		StartDate:    input.StartDate,
+		EndDate:      input.EndDate,
+		Days:         days,
+		ChangedDates: []string{},
+	}
+	for i := range days {
+		if !sameEffectiveDay(current[i], days[i]) {
+			preview.ChangedDates = append(preview.ChangedDates, days[i].Date)
+		}
+	}
+	return preview, nil
+}
+
+// ImportHolidays reads an iCalendar file and marks its events as government holidays.
+// Single events become day schedules; recurring events become schedule rules.
+// Dates that already have a schedule, and events already imported as rules, are skipped. A dry run
+// reports the result without saving; otherwise the whole calendar is saved in one transaction.
+func (s *scheduleService) ImportHolidays(adminID string, calendar io.Reader, dryRun bool) (*HolidayImportResult, error) {
+	adminUUID, err := uuid.Parse(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid admin ID: %w", err)
+	}
+
+	events, err := ical.Parse(calendar)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return s.importHolidays(s.scheduleRepo, s.ruleRepo, adminUUID, events, true)
+	}
+	var result *HolidayImportResult
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		result, err = s.importHolidays(repository.NewScheduleRepository(tx), repository.NewScheduleRuleRepository(tx), adminUUID, events, false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importHolidays turns calendar events into holiday rules and day schedules using the given repositories
+func (s *scheduleService) importHolidays(scheduleRepo repository.ScheduleRepository, ruleRepo repository.ScheduleRuleRepository, adminUUID uuid.UUID, events []ical.Event, dryRun bool) (*HolidayImportResult, error) {
+	result := &HolidayImportResult{
+		DryRun:    dryRun,
+		Schedules: []models.DaySchedule{},
+		Rules:     []models.ScheduleRule{},
+		Skipped:   []HolidayImportSkip{},
+	}
+	imported := make(map[string]bool)
+	importedUIDs := make(map[string]bool)
+
+	for _, event := range events {
+		summary := strings.TrimSpace(event.Summary)
+		if summary == "" {
+			summary = "Government holiday"
+		}
+		reason := summary
+
+		if event.RRule != "" {
+			if _, err := recurrence.Parse(event.RRule); err != nil {
+				result.Skipped = append(result.Skipped, HolidayImportSkip{
+					Date:    event.Start.Format("2006-01-02"),
+					Summary: summary,
+					Reason:  err.Error(),
+				})
+				continue
+			}
+			uid := strings.TrimSpace(event.UID)
+			if uid != "" {
+				if importedUIDs[uid] {
+					result.Skipped = append(result.Skipped, HolidayImportSkip{Date: event.Start.Format("2006-01-02"), Summary: summary, Reason: "duplicate event in calendar"})
+					continue
+				}
+				existing, err := ruleRepo.FindBySourceUID(uid)
+				if err != nil {
+					return nil, err
+				}
+				if existing != nil {
+					result.Skipped = append(result.Skipped, HolidayImportSkip{Date: event.Start.Format("2006-01-02"), Summary: summary, Reason: "rule already imported"})
+					continue
+				}
+				importedUIDs[uid] = true
+			}
+
+			rule := models.ScheduleRule{
+				ID:        uuid.New(),
+				Name:      summary,
+				RRule:     strings.TrimPrefix(event.RRule, "RRULE:"),
+				StartDate: event.Start.Format("2006-01-02"),
+				DayStatus: models.DayStatusGovtHoliday,
+				Reason:    &reason,
+				Priority:  holidayRulePriority,
+				Active:    true,
+				CreatedBy: &adminUUID,
+			}
+			if uid != "" {
+				rule.SourceUID = &uid
+			}
+			if !dryRun {
+				if err := ruleRepo.Create(&rule); err != nil {
+					return nil, err
+				}
+			}
+			result.Rules = append(result.Rules, rule)
+			continue
+		}
+
+		for _, day := range event.Days() {
+			date := day.Format("2006-01-02")
+			if imported[date] {
+				result.Skipped = append(result.Skipped, HolidayImportSkip{Date: date, Summary: summary, Reason: "duplicate date in calendar"})
+				continue
+			}
+
+			existing, err := scheduleRepo.FindByDate(date)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil {
+				result.Skipped = append(result.Skipped, HolidayImportSkip{Date: date, Summary: summary, Reason: "schedule already exists"})
+				continue
+			}
+
+			// No available meals: a holiday without meals closes the office
+			schedule := models.DaySchedule{
+				ID:        uuid.New(),
+				Date:      date,
+				DayStatus: models.DayStatusGovtHoliday,
+				Reason:    &reason,
+				CreatedBy: &adminUUID,
+			}
+			if !dryRun {
+				if err := scheduleRepo.Create(&schedule); err != nil {
+					return nil, err
+				}
+			}
+			imported[date] = true
+			result.Schedules = append(result.Schedules, schedule)
+		}
+	}
+
+	return result, nil
+}
+
+// buildScheduleRule validates rule input and converts it to a model
+func (s *scheduleService) buildScheduleRule(input ScheduleRuleInput) (*models.ScheduleRule, error) {
+	rule := &models.ScheduleRule{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(input.Name),
+		RRule:     strings.TrimPrefix(strings.TrimSpace(input.RRule), "RRULE:"),
+		StartDate: input.StartDate,
+		EndDate:   input.EndDate,
+		DayStatus: input.DayStatus,
+		Priority:  input.Priority,
+		Active:    true,
+	}
+	if input.Reason != "" {
+		reason := input.Reason
+		rule.Reason = &reason
+	}
+	if input.AvailableMeals != nil {
+		mealsStr := serializeMealTypes(input.AvailableMeals)
+		rule.AvailableMeals = &mealsStr
+	}
+
+	if err := s.validateScheduleRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// validateScheduleRule checks a rule's recurrence, dates, status and meals
+func (s *scheduleService) validateScheduleRule(rule *models.ScheduleRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("rule name is required")
+	}
+	if _, err := recurrence.Parse(rule.RRule); err != nil {
+		return fmt.Errorf("invalid rrule: %w", err)
+	}
+	if err := validateDate(rule.StartDate); err != nil {
+		return err
+	}
+	if rule.EndDate != nil {
+		if err := validateDate(*rule.EndDate); err != nil {
+			return err
+		}
+		if *rule.EndDate < rule.StartDate {
+			return fmt.Errorf("end date must not be before start date")
+		}
+	}
+	if !rule.DayStatus.IsValid() {
+		return fmt.Errorf("invalid day status: %s", rule.DayStatus)
+	}
+	if rule.AvailableMeals != nil {
+		return s.validateMeals(parseMealTypes(*rule.AvailableMeals))
+	}
+	return nil
+}
+
+// validateMeals checks that every meal is an active meal type in the catalogue
+func (s *scheduleService) validateMeals(meals []models.MealType) error {
+	for _, meal := range meals {
+		if _, err := s.catalog.Validate(string(meal)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameEffectiveDay reports whether two effective days would behave identically
+func sameEffectiveDay(a, b EffectiveDay) bool {
+	if a.DayStatus != b.DayStatus || serializeMealTypes(a.AvailableMeals) != serializeMealTypes(b.AvailableMeals) {
+		return false
+	}
+	if (a.Reason == nil) != (b.Reason == nil) || (a.Reason != nil && *a.Reason != *b.Reason) {
+		return false
+	}
+	return a.Source == b.Source && a.RuleName == b.RuleName
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"craftsbite-backend/internal/utils"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// refreshTokenBytes is the entropy of a refresh token
+const refreshTokenBytes = 32
+
+// ClientInfo describes the client a session was started from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// SessionTokens is an access token plus the refresh token of its session
+type SessionTokens struct {
+	SessionID        string
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// SessionInfo is a session as shown to its owner or an admin
+type SessionInfo struct {
+	models.Session
+	Current bool `json:"current"`
+}
+
+// SessionService defines the interface for login sessions and refresh token rotation
+type SessionService interface {
+	StartSession(user *models.User, client ClientInfo) (*SessionTokens, error)
+	Refresh(refreshToken string) (*SessionTokens, *models.User, error)
+	IsSessionActive(sessionID string) (bool, error)
+	ListSessions(userID, currentSessionID string) ([]SessionInfo, error)
+	RevokeOwnSession(userID, sessionID string) error
+	RevokeSession(sessionID string, reason models.SessionRevokeReason) error
+	RevokeUserSessions(userID string, reason models.SessionRevokeReason, exceptSessionID string) (int64, error)
+}
+
+type sessionService struct {
+	sessionRepo repository.SessionRepository
+	config      *config.Config
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(sessionRepo repository.SessionRepository, cfg *config.Config) SessionService {
+	return &sessionService{
+		sessionRepo: sessionRepo,
+		config:      cfg,
+	}
+}
+
+// StartSession creates a session for a user and issues its first token pair
+func (s *sessionService) StartSession(user *models.User, client ClientInfo) (*SessionTokens, error) {
+	refreshToken, err := utils.GenerateOpaqueToken(refreshTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashOpaqueToken(refreshToken),
+		UserAgent:        truncate(client.UserAgent, 512),
+		IPAddress:        truncate(client.IPAddress, 64),
+		ExpiresAt:        now.Add(s.config.JWT.RefreshExpiration),
+		LastUsedAt:       now,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session, refreshToken)
+}
+
+// Refresh rotates a refresh token and issues a new token pair for its session.
+// Presenting an already-rotated refresh token revokes the session, since it means the token leaked.
+func (s *sessionService) Refresh(refreshToken string) (*SessionTokens, *models.User, error) {
+	if refreshToken == "" {
+		return nil, nil, fmt.Errorf("refresh token is required")
+	}
+	hash := utils.HashOpaqueToken(refreshToken)
+
+	session, err := s.sessionRepo.FindByRefreshTokenHash(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session == nil {
+		reused, err := s.sessionRepo.FindByPreviousTokenHash(hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		if reused != nil {
+			if err := s.sessionRepo.Revoke(reused.ID.String(), models.SessionRevokeTokenReuse); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, fmt.Errorf("invalid refresh token")
+	}
+
+	if !session.IsActive() {
+		return nil, nil, fmt.Errorf("session has expired or been revoked")
+	}
+	if !session.User.Active {
+		return nil, nil, fmt.Errorf("user account is deactivated")
+	}
+
+	newToken, err := utils.GenerateOpaqueToken(refreshTokenBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	rotated, err := s.sessionRepo.Rotate(session.ID.String(), hash, utils.HashOpaqueToken(newToken))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rotated {
+		return nil, nil, fmt.Errorf("invalid refresh token")
+	}
+
+	tokens, err := s.issueTokens(&session.User, session, newToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, &session.User, nil
+}
+
+// IsSessionActive reports whether the session behind an access token is still valid
+func (s *sessionService) IsSessionActive(sessionID string) (bool, error) {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil {
+		return false, err
+	}
+	return session != nil && session.IsActive(), nil
+}
+
+// ListSessions returns the active sessions of a user, flagging the caller's own session
+func (s *sessionService) ListSessions(userID, currentSessionID string) ([]SessionInfo, error) {
+	sessions, err := s.sessionRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		infos = append(infos, SessionInfo{
+			Session: session,
+			Current: session.ID.String() == currentSessionID,
+		})
+	}
+	return infos, nil
+}
+
+// RevokeOwnSession revokes one of the caller's own sessions
+func (s *sessionService) RevokeOwnSession(userID, sessionID string) error {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return fmt.Errorf("session not found")
+	}
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID.String() != userID {
+		return fmt.Errorf("session not found")
+	}
+	return s.sessionRepo.Revoke(sessionID, models.SessionRevokeUser)
+}
+
+// RevokeSession revokes any session by ID
+func (s *sessionService) RevokeSession(sessionID string, reason models.SessionRevokeReason) error {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return fmt.Errorf("session not found")
+	}
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return fmt.Errorf("session not found")
+	}
+	return s.sessionRepo.Revoke(sessionID, reason)
+}
+
+// RevokeUserSessions revokes every active session of a user except exceptSessionID, if given
+func (s *sessionService) RevokeUserSessions(userID string, reason models.SessionRevokeReason, exceptSessionID string) (int64, error) {
+	return s.sessionRepo.RevokeAllByUserID(userID, reason, exceptSessionID)
+}
+
+// issueTokens signs an access token bound to the session
+func (s *sessionService) issueTokens(user *models.User, session *models.Session, refreshToken string) (*SessionTokens, error) {
+	accessExpiresAt := time.Now().Add(s.config.JWT.Expiration)
+	if accessExpiresAt.After(session.ExpiresAt) {
+		accessExpiresAt = session.ExpiresAt
+	}
+
+	accessToken, err := utils.GenerateToken(
+		user.ID.String(),
+		user.Email,
+		user.Role.String(),
+		session.ID.String(),
+		s.config.JWT.Secret,
+		time.Until(accessExpiresAt),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &SessionTokens{
+		SessionID:        session.ID.String(),
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// truncate caps a string at max bytes so client-supplied values fit their columns
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
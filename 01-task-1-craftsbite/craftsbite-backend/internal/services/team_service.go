@@ -0,0 +1,434 @@
+package services
+
+import (
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CreateTeamInput represents input for creating a team
+type CreateTeamInput struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	TeamLeadID  string   `json:"team_lead_id" binding:"required"`
+	MemberIDs   []string `json:"member_ids"`
+}
+
+// UpdateTeamInput represents input for updating a team's details
+type UpdateTeamInput struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// TeamMemberImportItem is the outcome of importing one member in a bulk import
+type TeamMemberImportItem struct {
+	Identifier string `json:"identifier"`
+	UserID     string `json:"user_id,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// TeamMemberImportResult summarises a bulk member import
+type TeamMemberImportResult struct {
+	Added   []TeamMemberImportItem `json:"added"`
+	Skipped []TeamMemberImportItem `json:"skipped"`
+}
+
+// TeamService defines the interface for team management
+type TeamService interface {
+	ListTeams(includeInactive bool) ([]models.Team, error)
+	GetTeam(id string) (*models.Team, error)
+	CreateTeam(actorID string, input CreateTeamInput) (*models.Team, error)
+	UpdateTeam(actorID, id string, input UpdateTeamInput) (*models.Team, error)
+	ChangeTeamLead(actorID, id, teamLeadID string) (*models.Team, error)
+	DeactivateTeam(actorID, id string) error
+	ReactivateTeam(actorID, id string) (*models.Team, error)
+	AddMember(actorID, teamID, userID string) (*models.Team, error)
+	RemoveMember(actorID, teamID, userID string) error
+	ImportMembers(actorID, teamID string, identifiers []string) (*TeamMemberImportResult, error)
+	GetTeamHistory(id string) ([]models.TeamHistory, error)
+}
+
+type teamService struct {
+	teamRepo        repository.TeamRepository
+	userRepo        repository.UserRepository
+	teamHistoryRepo repository.TeamHistoryRepository
+}
+
+// NewTeamService creates a new team service
+func NewTeamService(teamRepo repository.TeamRepository, userRepo repository.UserRepository, teamHistoryRepo repository.TeamHistoryRepository) TeamService {
+	return &teamService{
+		teamRepo:        teamRepo,
+		userRepo:        userRepo,
+		teamHistoryRepo: teamHistoryRepo,
+	}
+}
+
+// ListTeams returns active teams, or all teams when includeInactive is set
+func (s *teamService) ListTeams(includeInactive bool) ([]models.Team, error) {
+	if includeInactive {
+		return s.teamRepo.FindAllIncludingInactive()
+	}
+	return s.teamRepo.FindAllWithMembers()
+}
+
+// GetTeam returns a team with its lead and members
+func (s *teamService) GetTeam(id string) (*models.Team, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("team not found")
+	}
+	return s.teamRepo.FindByID(id)
+}
+
+// CreateTeam creates a team led by a team lead, optionally with initial members
+func (s *teamService) CreateTeam(actorID string, input CreateTeamInput) (*models.Team, error) {
+	name := strings.TrimSpace(input.Name)
+	if err := s.validateTeamName(name, ""); err != nil {
+		return nil, err
+	}
+
+	lead, err := s.validateTeamLead(input.TeamLeadID)
+	if err != nil {
+		return nil, err
+	}
+
+	team := &models.Team{
+		Name:        name,
+		Description: strings.TrimSpace(input.Description),
+		TeamLeadID:  lead.ID,
+		Active:      true,
+	}
+	if err := s.teamRepo.Create(team); err != nil {
+		return nil, err
+	}
+	teamID := team.ID.String()
+
+	if err := s.recordHistory(teamID, models.TeamActionCreated, actorID, &lead.ID, nil, &team.Name); err != nil {
+		return nil, err
+	}
+
+	for _, memberID := range input.MemberIDs {
+		if err := s.addMember(actorID, team, memberID); err != nil {
+			return nil, fmt.Errorf("team created but member %s could not be added: %w", memberID, err)
+		}
+	}
+
+	return s.teamRepo.FindByID(teamID)
+}
+
+// UpdateTeam updates a team's name and description
+func (s *teamService) UpdateTeam(actorID, id string, input UpdateTeamInput) (*models.Team, error) {
+	team, err := s.GetTeam(id)
+	if err != nil {
+		return nil, err
+	}
+
+	type change struct{ previous, next string }
+	var changes []change
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name != team.Name {
+			if err := s.validateTeamName(name, id); err != nil {
+				return nil, err
+			}
+			changes = append(changes, change{team.Name, name})
+			team.Name = name
+		}
+	}
+	if input.Description != nil {
+		description := strings.TrimSpace(*input.Description)
+		if description != team.Description {
+			changes = append(changes, change{team.Description, description})
+			team.Description = description
+		}
+	}
+	if len(changes) == 0 {
+		return team, nil
+	}
+
+	if err := s.teamRepo.Update(team); err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		if err := s.recordHistory(id, models.TeamActionUpdated, actorID, nil, &c.previous, &c.next); err != nil {
+			return nil, err
+		}
+	}
+	return s.teamRepo.FindByID(id)
+}
+
+// ChangeTeamLead reassigns the lead of a team to another user with the team_lead role
+func (s *teamService) ChangeTeamLead(actorID, id, teamLeadID string) (*models.Team, error) {
+	team, err := s.GetTeam(id)
+	if err != nil {
+		return nil, err
+	}
+	if !team.Active {
+		return nil, fmt.Errorf("team is deactivated")
+	}
+
+	lead, err := s.validateTeamLead(teamLeadID)
+	if err != nil {
+		return nil, err
+	}
+	if lead.ID == team.TeamLeadID {
+		return nil, fmt.Errorf("user is already the lead of this team")
+	}
+
+	previous := team.TeamLeadID.String()
+	next := lead.ID.String()
+	team.TeamLeadID = lead.ID
+	if err := s.teamRepo.Update(team); err != nil {
+		return nil, err
+	}
+	if err := s.recordHistory(id, models.TeamActionLeadChanged, actorID, &lead.ID, &previous, &next); err != nil {
+		return nil, err
+	}
+
+	return s.teamRepo.FindByID(id)
+}
+
+// DeactivateTeam soft-deletes a team; its members are kept so it can be reactivated
+func (s *teamService) DeactivateTeam(actorID, id string) error {
+	team, err := s.GetTeam(id)
+	if err != nil {
+		return err
+	}
+	if !team.Active {
+		return fmt.Errorf("team is already deactivated")
+	}
+
+	if err := s.teamRepo.Delete(id); err != nil {
+		return err
+	}
+	return s.recordHistory(id, models.TeamActionDeactivated, actorID, nil, nil, nil)
+}
+
+// ReactivateTeam restores a deactivated team
+func (s *teamService) ReactivateTeam(actorID, id string) (*models.Team, error) {
+	team, err := s.GetTeam(id)
+	if err != nil {
+		return nil, err
+	}
+	if team.Active {
+		return nil, fmt.Errorf("team is already active")
+	}
+	if err := s.validateTeamName(team.Name, id); err != nil {
+		return nil, err
+	}
+	if _, err := s.validateTeamLead(team.TeamLeadID.String()); err != nil {
+		return nil, fmt.Errorf("cannot reactivate team: %w", err)
+	}
+
+	team.Active = true
+	if err := s.teamRepo.Update(team); err != nil {
+		return nil, err
+	}
+	if err := s.recordHistory(id, models.TeamActionReactivated, actorID, nil, nil, nil); err != nil {
+		return nil, err
+	}
+
+	return s.teamRepo.FindByID(id)
+}
+
+// AddMember adds a single user to a team
+func (s *teamService) AddMember(actorID, teamID, userID string) (*models.Team, error) {
+	team, err := s.GetTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addMember(actorID, team, userID); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.FindByID(teamID)
+}
+
+// RemoveMember removes a user from a team
+func (s *teamService) RemoveMember(actorID, teamID, userID string) error {
+	if _, err := s.GetTeam(teamID); err != nil {
+		return err
+	}
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID")
+	}
+
+	isMember, err := s.teamRepo.IsTeamMember(teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return fmt.Errorf("user is not a member of this team")
+	}
+
+	if err := s.teamRepo.RemoveMember(teamID, userID); err != nil {
+		return err
+	}
+	return s.recordHistory(teamID, models.TeamActionMemberRemoved, actorID, &userUUID, nil, nil)
+}
+
+// ImportMembers adds many users to a team, identified by user ID or email.
+// Each identifier is processed independently; failures are reported, not fatal.
+func (s *teamService) ImportMembers(actorID, teamID string, identifiers []string) (*TeamMemberImportResult, error) {
+	team, err := s.GetTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if !team.Active {
+		return nil, fmt.Errorf("team is deactivated")
+	}
+
+	result := &TeamMemberImportResult{
+		Added:   []TeamMemberImportItem{},
+		Skipped: []TeamMemberImportItem{},
+	}
+	seen := make(map[string]bool, len(identifiers))
+
+	for _, raw := range identifiers {
+		identifier := strings.TrimSpace(raw)
+		if identifier == "" {
+			continue
+		}
+		if seen[strings.ToLower(identifier)] {
+			continue
+		}
+		seen[strings.ToLower(identifier)] = true
+
+		user, err := s.lookupUser(identifier)
+		if err != nil {
+			result.Skipped = append(result.Skipped, TeamMemberImportItem{Identifier: identifier, Reason: err.Error()})
+			continue
+		}
+
+		item := TeamMemberImportItem{Identifier: identifier, UserID: user.ID.String()}
+		if err := s.addMember(actorID, team, user.ID.String()); err != nil {
+			item.Reason = err.Error()
+			result.Skipped = append(result.Skipped, item)
+			continue
+		}
+		result.Added = append(result.Added, item)
+	}
+
+	return result, nil
+}
+
+// GetTeamHistory returns the audit history of a team, newest first
+func (s *teamService) GetTeamHistory(id string) ([]models.TeamHistory, error) {
+	if _, err := s.GetTeam(id); err != nil {
+		return nil, err
+	}
+	return s.teamHistoryRepo.FindByTeamID(id)
+}
+
+// addMember validates and adds a user to a team. A user can belong to one team only;
+// a membership in a deactivated team is released automatically.
+func (s *teamService) addMember(actorID string, team *models.Team, userID string) error {
+	if !team.Active {
+		return fmt.Errorf("team is deactivated")
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return fmt.Errorf("invalid user ID")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.Active {
+		return fmt.Errorf("user account is deactivated")
+	}
+
+	membership, err := s.teamRepo.FindMembershipByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if membership != nil {
+		if membership.TeamID == team.ID {
+			return fmt.Errorf("user is already a member of this team")
+		}
+		if membership.Team != nil && membership.Team.Active {
+			return fmt.Errorf("user already belongs to team %s", membership.Team.Name)
+		}
+
+		previousTeamID := membership.TeamID.String()
+		if err := s.teamRepo.RemoveMember(previousTeamID, userID); err != nil {
+			return err
+		}
+		if err := s.recordHistory(previousTeamID, models.TeamActionMemberRemoved, actorID, &user.ID, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	teamID := team.ID.String()
+	if err := s.teamRepo.AddMember(teamID, userID); err != nil {
+		return err
+	}
+	return s.recordHistory(teamID, models.TeamActionMemberAdded, actorID, &user.ID, nil, nil)
+}
+
+// validateTeamName checks a team name is present and not used by another active team
+func (s *teamService) validateTeamName(name, excludeTeamID string) error {
+	if name == "" {
+		return fmt.Errorf("team name is required")
+	}
+	existing, err := s.teamRepo.FindActiveByName(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID.String() != excludeTeamID {
+		return fmt.Errorf("an active team named %s already exists", existing.Name)
+	}
+	return nil
+}
+
+// validateTeamLead checks the user exists, is active and has the team_lead role
+func (s *teamService) validateTeamLead(userID string) (*models.User, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, fmt.Errorf("invalid team lead ID")
+	}
+	lead, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("team lead not found")
+	}
+	if !lead.Active {
+		return nil, fmt.Errorf("team lead account is deactivated")
+	}
+	if lead.Role != models.RoleTeamLead {
+		return nil, fmt.Errorf("user %s does not have the team_lead role", lead.Email)
+	}
+	return lead, nil
+}
+
+// lookupUser resolves a bulk import identifier, which is either a user ID or an email
+func (s *teamService) lookupUser(identifier string) (*models.User, error) {
+	if _, err := uuid.Parse(identifier); err == nil {
+		return s.userRepo.FindByID(identifier)
+	}
+	if strings.Contains(identifier, "@") {
+		return s.userRepo.FindByEmail(identifier)
+	}
+	return nil, fmt.Errorf("identifier must be a user ID or email")
+}
+
+func (s *teamService) recordHistory(teamID string, action models.TeamHistoryAction, actorID string, subjectID *uuid.UUID, previous, next *string) error {
+	teamUUID, err := uuid.Parse(teamID)
+	if err != nil {
+		return fmt.Errorf("invalid team ID")
+	}
+
+	history := &models.TeamHistory{
+		TeamID:        teamUUID,
+		Action:        action,
+		SubjectID:     subjectID,
+		PreviousValue: previous,
+		NewValue:      next,
+	}
+	if actorUUID, err := uuid.Parse(actorID); err == nil {
+		history.ActorID = &actorUUID
+	}
+
+	return s.teamHistoryRepo.Create(history)
+}
@@ -0,0 +1,515 @@
+package services
+
+import (
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"craftsbite-backend/internal/totp"
+	"craftsbite-backend/internal/utils"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	challengeTokenBytes = 32
+	// maxChallengeAttempts wrong codes use up a login challenge, so the password has to be
+	// entered again
+	maxChallengeAttempts = 5
+	// totpSkew accepts codes from one period either side, for clocks that drift
+	totpSkew          = 1
+	recoveryCodeCount = 10
+	// recoveryCodeLength characters of base32 give each recovery code 50 bits
+	recoveryCodeLength = 10
+)
+
+var (
+	// ErrInvalidTwoFactorCode is returned for a wrong, reused or expired TOTP or recovery code
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	// ErrInvalidLoginChallenge is returned for an unknown, expired or used-up login challenge
+	ErrInvalidLoginChallenge = errors.New("login challenge is invalid or has expired, please log in again")
+)
+
+// TwoFactorChallenge is returned by Login instead of a session when the password was right
+// but the user still has to enter a code, or to set up two-factor authentication first
+type TwoFactorChallenge struct {
+	Token         string    `json:"challenge_token"`
+	SetupRequired bool      `json:"setup_required"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// TwoFactorSetup is a new TOTP secret to add to an authenticator app, by scanning the
+// provisioning URI as a QR code or typing the secret
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorStatus describes a user's two-factor authentication
+type TwoFactorStatus struct {
+	Enabled           bool       `json:"enabled"`
+	Required          bool       `json:"required"`
+	EnabledAt         *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesLeft int64      `json:"recovery_codes_left"`
+}
+
+// TwoFactorService manages TOTP enrolment (RFC 6238) and recovery codes, and runs the second
+// login step. Roles listed in TWO_FACTOR_REQUIRED_ROLES cannot sign in without it.
+type TwoFactorService interface {
+	Challenge(user *models.User) (*TwoFactorChallenge, error)
+	SetupChallenge(challengeToken string) (*TwoFactorSetup, error)
+	CompleteChallenge(challengeToken, code string, client ClientInfo) (*models.User, []string, error)
+	GetStatus(userID string) (*TwoFactorStatus, error)
+	BeginSetup(userID string) (*TwoFactorSetup, error)
+	Enable(userID, code string, client ClientInfo) ([]string, error)
+	Disable(userID, password, code string, client ClientInfo) error
+	RegenerateRecoveryCodes(userID, code string, client ClientInfo) ([]string, error)
+	Reset(actorID, userID string, client ClientInfo) error
+}
+
+type twoFactorService struct {
+	twoFactorRepo   repository.TwoFactorRepository
+	userRepo        repository.UserRepository
+	auditRepo       repository.AuthAuditRepository
+	loginProtection LoginProtectionService
+	cfg             config.TwoFactorConfig
+	key             []byte
+}
+
+// NewTwoFactorService creates a new two-factor service
+func NewTwoFactorService(
+	twoFactorRepo repository.TwoFactorRepository,
+	userRepo repository.UserRepository,
+	auditRepo repository.AuthAuditRepository,
+	loginProtection LoginProtectionService,
+	cfg *config.Config,
+) TwoFactorService {
+	keySource := cfg.TwoFactor.EncryptionKey
+	if keySource == "" {
+		keySource = "two-factor:" + cfg.JWT.Secret
+	}
+	key := sha256.Sum256([]byte(keySource))
+
+	return &twoFactorService{
+		twoFactorRepo:   twoFactorRepo,
+		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		loginProtection: loginProtection,
+		cfg:             cfg.TwoFactor,
+		key:             key[:],
+	}
+}
+
+// Challenge starts the second login step for a user whose password was accepted. It returns
+// nil when the user has no two-factor authentication and their role does not require it.
+func (s *twoFactorService) Challenge(user *models.User) (*TwoFactorChallenge, error) {
+	twoFactor, err := s.twoFactorRepo.Find(user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	purpose := models.LoginChallengeTwoFactor
+	if !twoFactor.IsEnabled() {
+		if !s.required(user.Role) {
+			return nil, nil
+		}
+		purpose = models.LoginChallengeTwoFactorSetup
+	}
+
+	token, err := utils.GenerateOpaqueToken(challengeTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	challenge := &models.LoginChallenge{
+		UserID:    user.ID,
+		TokenHash: utils.HashOpaqueToken(token),
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(s.cfg.ChallengeTTL),
+	}
+	if err := s.twoFactorRepo.CreateChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorChallenge{
+		Token:         token,
+		SetupRequired: purpose == models.LoginChallengeTwoFactorSetup,
+		ExpiresAt:     challenge.ExpiresAt,
+	}, nil
+}
+
+// SetupChallenge issues a TOTP secret to a user who must enrol before their login completes
+func (s *twoFactorService) SetupChallenge(challengeToken string) (*TwoFactorSetup, error) {
+	challenge, err := s.findChallenge(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.Purpose != models.LoginChallengeTwoFactorSetup {
+		return nil, fmt.Errorf("two-factor authentication is already set up")
+	}
+
+	user, err := s.userRepo.FindByID(challenge.UserID.String())
+	if err != nil {
+		return nil, ErrInvalidLoginChallenge
+	}
+	return s.beginSetup(user)
+}
+
+// CompleteChallenge checks the code of the second login step: a TOTP or recovery code, or
+// for a setup challenge the first TOTP code, which enables two-factor authentication and
+// returns the new recovery codes. Wrong codes count as failed logins.
+func (s *twoFactorService) CompleteChallenge(challengeToken, code string, client ClientInfo) (*models.User, []string, error) {
+	challenge, err := s.findChallenge(challengeToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := s.userRepo.FindByID(challenge.UserID.String())
+	if err != nil {
+		return nil, nil, ErrInvalidLoginChallenge
+	}
+	if !user.Active {
+		return nil, nil, fmt.Errorf("user account is deactivated")
+	}
+	if err := s.loginProtection.CheckLogin(user.Email, client); err != nil {
+		return nil, nil, err
+	}
+
+	var recoveryCodes []string
+	if challenge.Purpose == models.LoginChallengeTwoFactorSetup {
+		recoveryCodes, err = s.enable(user, code, client)
+	} else {
+		err = s.verify(user, code, client)
+	}
+	if errors.Is(err, ErrInvalidTwoFactorCode) {
+		return nil, nil, s.challengeFailed(challenge, user, client)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.twoFactorRepo.DeleteChallenge(challenge.ID.String()); err != nil {
+		return nil, nil, err
+	}
+	// The password step left the account's failed logins in place until now
+	if err := s.loginProtection.RecordSuccess(user.Email); err != nil {
+		return nil, nil, err
+	}
+	return user, recoveryCodes, nil
+}
+
+func (s *twoFactorService) findChallenge(token string) (*models.LoginChallenge, error) {
+	challenge, err := s.twoFactorRepo.FindChallenge(utils.HashOpaqueToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil || !time.Now().Before(challenge.ExpiresAt) {
+		return nil, ErrInvalidLoginChallenge
+	}
+	return challenge, nil
+}
+
+// challengeFailed counts a wrong code against the challenge and the account, and uses the
+// challenge up after maxChallengeAttempts
+func (s *twoFactorService) challengeFailed(challenge *models.LoginChallenge, user *models.User, client ClientInfo) error {
+	if err := s.loginProtection.RecordFailure(user.Email, user, client); err != nil {
+		return err
+	}
+	attempts, err := s.twoFactorRepo.CountChallengeAttempt(challenge.ID.String())
+	if err != nil {
+		return err
+	}
+	if attempts >= maxChallengeAttempts {
+		if err := s.twoFactorRepo.DeleteChallenge(challenge.ID.String()); err != nil {
+			return err
+		}
+		return ErrInvalidLoginChallenge
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+// GetStatus returns whether a user has two-factor authentication and whether their role requires it
+func (s *twoFactorService) GetStatus(userID string) (*TwoFactorStatus, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor, err := s.twoFactorRepo.Find(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &TwoFactorStatus{Required: s.required(user.Role)}
+	if twoFactor.IsEnabled() {
+		status.Enabled = true
+		status.EnabledAt = twoFactor.EnabledAt
+		if status.RecoveryCodesLeft, err = s.twoFactorRepo.CountRecoveryCodes(userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// BeginSetup issues a new TOTP secret to a signed-in user. Two-factor authentication is
+// enabled once Enable confirms a code from it.
+func (s *twoFactorService) BeginSetup(userID string) (*TwoFactorSetup, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.beginSetup(user)
+}
+
+func (s *twoFactorService) beginSetup(user *models.User) (*TwoFactorSetup, error) {
+	existing, err := s.twoFactorRepo.Find(user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if existing.IsEnabled() {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := utils.SealSecret(secret, s.key)
+	if err != nil {
+		return nil, err
+	}
+	// A setup that was started before and never confirmed is replaced
+	if err := s.twoFactorRepo.Save(&models.UserTwoFactor{UserID: user.ID, Secret: sealed}); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.cfg.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Enable confirms a pending setup with a code from the authenticator app and returns the
+// recovery codes, which are only shown this once
+func (s *twoFactorService) Enable(userID, code string, client ClientInfo) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.enable(user, code, client)
+}
+
+func (s *twoFactorService) enable(user *models.User, code string, client ClientInfo) ([]string, error) {
+	twoFactor, err := s.twoFactorRepo.Find(user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, fmt.Errorf("start two-factor setup first")
+	}
+	if twoFactor.IsEnabled() {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	secret, err := utils.OpenSecret(twoFactor.Secret, s.key)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	step, ok := totp.Verify(secret, code, now, totpSkew)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	twoFactor.EnabledAt = &now
+	twoFactor.LastUsedStep = step
+	if err := s.twoFactorRepo.Save(twoFactor); err != nil {
+		return nil, err
+	}
+	recoveryCodes, err := s.newRecoveryCodes(user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	entry := newAuthAuditLog(models.AuthEventTwoFactorEnabled, client)
+	entry.UserID = &user.ID
+	entry.Email = &user.Email
+	recordAuthEvent(s.auditRepo, entry, "")
+	return recoveryCodes, nil
+}
+
+// Disable turns two-factor authentication off after checking the password and a code.
+// Users whose role requires it cannot turn it off.
+func (s *twoFactorService) Disable(userID, password, code string, client ClientInfo) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if s.required(user.Role) {
+		return fmt.Errorf("two-factor authentication is required for the %s role", user.Role)
+	}
+	if !utils.CheckPasswordHash(password, user.Password) {
+		return ErrIncorrectPassword
+	}
+	if err := s.verify(user, code, client); err != nil {
+		return err
+	}
+
+	if err := s.twoFactorRepo.Delete(userID); err != nil {
+		return err
+	}
+
+	entry := newAuthAuditLog(models.AuthEventTwoFactorDisabled, client)
+	entry.UserID = &user.ID
+	entry.Email = &user.Email
+	recordAuthEvent(s.auditRepo, entry, "")
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces a user's recovery codes after checking a code
+func (s *twoFactorService) RegenerateRecoveryCodes(userID, code string, client ClientInfo) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verify(user, code, client); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := s.newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := newAuthAuditLog(models.AuthEventRecoveryCodesRegenerated, client)
+	entry.UserID = &user.ID
+	entry.Email = &user.Email
+	recordAuthEvent(s.auditRepo, entry, "")
+	return recoveryCodes, nil
+}
+
+// Reset removes a user's two-factor authentication for an admin, when the user lost both
+// their authenticator and recovery codes. A role that requires it enrols again at next login.
+func (s *twoFactorService) Reset(actorID, userID string, client ClientInfo) error {
+	actorUUID, err := uuid.Parse(actorID)
+	if err != nil {
+		return fmt.Errorf("invalid actor ID")
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+	twoFactor, err := s.twoFactorRepo.Find(userID)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil {
+		return fmt.Errorf("user has not set up two-factor authentication")
+	}
+
+	if err := s.twoFactorRepo.Delete(userID); err != nil {
+		return err
+	}
+
+	entry := newAuthAuditLog(models.AuthEventTwoFactorReset, client)
+	entry.UserID = &user.ID
+	entry.Email = &user.Email
+	entry.ActorID = &actorUUID
+	recordAuthEvent(s.auditRepo, entry, "")
+	return nil
+}
+
+// verify checks a TOTP code, refusing one that was already used, or a recovery code, which
+// is used up
+func (s *twoFactorService) verify(user *models.User, code string, client ClientInfo) error {
+	userID := user.ID.String()
+	twoFactor, err := s.twoFactorRepo.Find(userID)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.IsEnabled() {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != totp.Digits {
+		return s.useRecoveryCode(user, code, client)
+	}
+
+	secret, err := utils.OpenSecret(twoFactor.Secret, s.key)
+	if err != nil {
+		return err
+	}
+	step, ok := totp.Verify(secret, code, time.Now(), totpSkew)
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	fresh, err := s.twoFactorRepo.UseStep(userID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (s *twoFactorService) useRecoveryCode(user *models.User, code string, client ClientInfo) error {
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) != recoveryCodeLength {
+		return ErrInvalidTwoFactorCode
+	}
+
+	used, err := s.twoFactorRepo.UseRecoveryCode(user.ID.String(), utils.HashOpaqueToken(normalized), time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+
+	left, _ := s.twoFactorRepo.CountRecoveryCodes(user.ID.String())
+	entry := newAuthAuditLog(models.AuthEventTwoFactorRecoveryCodeUsed, client)
+	entry.UserID = &user.ID
+	entry.Email = &user.Email
+	recordAuthEvent(s.auditRepo, entry, fmt.Sprintf("%d recovery codes left", left))
+	return nil
+}
+
+// newRecoveryCodes replaces a user's recovery codes and returns them formatted as xxxxx-xxxxx
+func (s *twoFactorService) newRecoveryCodes(userID string) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, 8)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(raw))[:recoveryCodeLength]
+		hashes[i] = utils.HashOpaqueToken(code)
+		codes[i] = code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode accepts recovery codes typed in any case, with or without the dash
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func (s *twoFactorService) required(role models.Role) bool {
+	for _, required := range s.cfg.RequiredRoles {
+		if required == role.String() {
+			return true
+		}
+	}
+	return false
+}
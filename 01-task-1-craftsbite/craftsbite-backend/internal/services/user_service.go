@@ -64,13 +64,15 @@ type UserService interface {
 
 // userService implements UserService
 type userService struct {
-	userRepo repository.UserRepository
-	teamRepo repository.TeamRepository
+	userRepo    repository.UserRepository
+	teamRepo    repository.TeamRepository
+	sessionRepo repository.SessionRepository
+	roleRepo    repository.RoleRepository
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo repository.UserRepository, teamRepo repository.TeamRepository) UserService {
-	return &userService{userRepo: userRepo, teamRepo: teamRepo}
+func NewUserService(userRepo repository.UserRepository, teamRepo repository.TeamRepository, sessionRepo repository.SessionRepository, roleRepo repository.RoleRepository) UserService {
+	return &userService{userRepo: userRepo, teamRepo: teamRepo, sessionRepo: sessionRepo, roleRepo: roleRepo}
 }
 
 // CreateUser creates a new user
@@ -81,6 +83,10 @@ func (s *userService) CreateUser(input CreateUserInput) (*models.User, error) {
 		return nil, fmt.Errorf("email already exists")
 	}
 
+	if err := s.validateRole(input.Role); err != nil {
+		return nil, err
+	}
+
 	// Hash password
 	hashedPassword, err := utils.HashPassword(input.Password)
 	if err != nil {
@@ -119,7 +125,7 @@ func (s *userService) GetUserByID(id string) (*models.User, error) {
 	return user, nil
 }
 
-// UpdateUser updates a user
+// UpdateUser updates a user. Setting a password revokes all of the user's sessions.
 func (s *userService) UpdateUser(id string, input UpdateUserInput) (*models.User, error) {
 	user, err := s.userRepo.FindByID(id)
 	if err != nil {
@@ -131,6 +137,9 @@ func (s *userService) UpdateUser(id string, input UpdateUserInput) (*models.User
 		user.Name = *input.Name
 	}
 	if input.Role != nil {
+		if err := s.validateRole(*input.Role); err != nil {
+			return nil, err
+		}
 		user.Role = *input.Role
 	}
 	if input.DefaultMealPreference != nil {
@@ -148,12 +157,35 @@ func (s *userService) UpdateUser(id string, input UpdateUserInput) (*models.User
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
+	// Sessions started with the old password must not outlive it
+	if input.Password != nil {
+		if _, err := s.sessionRepo.RevokeAllByUserID(id, models.SessionRevokePassword, ""); err != nil {
+			return nil, err
+		}
+	}
+
 	return user, nil
 }
 
-// DeactivateUser deactivates a user
+// DeactivateUser deactivates a user and revokes all of their sessions
 func (s *userService) DeactivateUser(id string) error {
-	return s.userRepo.Delete(id)
+	if err := s.userRepo.Delete(id); err != nil {
+		return err
+	}
+	_, err := s.sessionRepo.RevokeAllByUserID(id, models.SessionRevokeDeactivated, "")
+	return err
+}
+
+// validateRole checks that a role is defined in the roles table
+func (s *userService) validateRole(role models.Role) error {
+	definition, err := s.roleRepo.FindByName(role.String())
+	if err != nil {
+		return err
+	}
+	if definition == nil {
+		return fmt.Errorf("invalid role: %s", role)
+	}
+	return nil
 }
 
 // ListUsers lists all users with optional filters
@@ -163,16 +195,11 @@ func (s *userService) ListUsers(filters map[string]interface{}) ([]models.User,
 
 // GetMyTeamMembers returns all members of teams led by the given team lead
 func (s *userService) GetMyTeamMembers(teamLeadID string) (*TeamMembersResponse, error) {
-	// Verify the user exists and is a team lead
 	teamLead, err := s.userRepo.FindByID(teamLeadID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find team lead: %w", err)
 	}
 
-	if teamLead.Role != models.RoleTeamLead {
-		return nil, fmt.Errorf("user is not a team lead")
-	}
-
 	// Get all teams led by this user (members are preloaded)
 	teams, err := s.teamRepo.FindByTeamLeadID(teamLeadID)
 	if err != nil {
@@ -40,9 +40,69 @@ func serializeMealTypes(mealTypes []models.MealType) string {
 	return strings.Join(parts, ",")
 }
 
+// mealTypeKeys converts a slice of MealType to their string values
+func mealTypeKeys(mealTypes []models.MealType) []string {
+	keys := make([]string, len(mealTypes))
+	for i, mt := range mealTypes {
+		keys[i] = string(mt)
+	}
+	return keys
+}
+
 func validateDate(date string) error {
 	if _, err := time.Parse("2006-01-02", date); err != nil {
 		return fmt.Errorf("invalid date format, expected YYYY-MM-DD")
 	}
 	return nil
 }
+
+// maxReportRangeDays caps the number of days a single report may span
+const maxReportRangeDays = 31
+
+// reportDates lists the dates from startDate to endDate inclusive
+func reportDates(startDate, endDate string) ([]string, error) {
+	start, end, err := parseCalendarRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if end.Sub(start) >= maxReportRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("date range must not exceed %d days", maxReportRangeDays)
+	}
+
+	var dates []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d.Format("2006-01-02"))
+	}
+	return dates, nil
+}
+
+// dateKey normalizes a date column value to YYYY-MM-DD.
+// Postgres DATE columns scanned into strings may carry a time component.
+func dateKey(date string) string {
+	if len(date) > 10 {
+		return date[:10]
+	}
+	return date
+}
+
+// weekendDaySet builds a lookup of lowercase weekday names from the MEAL_WEEKEND_DAYS setting
+func weekendDaySet(days []string) map[string]bool {
+	weekendDays := make(map[string]bool, len(days))
+	for _, day := range days {
+		weekendDays[strings.ToLower(strings.TrimSpace(day))] = true
+	}
+	return weekendDays
+}
+
+// clockTimeOn returns the instant a wall-clock time such as "21:00" occurs on a date in a timezone
+func clockTimeOn(date time.Time, clock, timezone string) (time.Time, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timezone: %w", err)
+	}
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cutoff time format: %w", err)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), parsed.Hour(), parsed.Minute(), 0, 0, loc), nil
+}
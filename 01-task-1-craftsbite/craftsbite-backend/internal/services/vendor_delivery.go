@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/mailer"
+	"craftsbite-backend/internal/models"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// vendorSignatureHeader carries the HMAC-SHA256 of the webhook body, keyed with the vendor's secret
+const vendorSignatureHeader = "X-CraftsBite-Signature"
+
+// VendorOrderDeliverer sends an order document to its vendor
+type VendorOrderDeliverer interface {
+	Deliver(vendor *models.Vendor, order *models.VendorOrder) error
+}
+
+// VendorOrderWebhook is the JSON body posted to a webhook vendor
+type VendorOrderWebhook struct {
+	OrderID   string                   `json:"order_id"`
+	VendorID  string                   `json:"vendor_id"`
+	Date      string                   `json:"date"`
+	Revision  int                      `json:"revision"`
+	Amendment bool                     `json:"amendment"`
+	Subject   string                   `json:"subject"`
+	Document  string                   `json:"document"`
+	Lines     []models.VendorOrderLine `json:"lines"`
+}
+
+type vendorOrderDeliverer struct {
+	mailer mailer.Mailer
+	client *http.Client
+}
+
+// NewVendorOrderDeliverer creates a deliverer that emails orders or posts them to a webhook
+// depending on the vendor's delivery method
+func NewVendorOrderDeliverer(m mailer.Mailer, cfg *config.Config) VendorOrderDeliverer {
+	return &vendorOrderDeliverer{
+		mailer: m,
+		client: &http.Client{Timeout: cfg.Vendor.WebhookTimeout},
+	}
+}
+
+func (d *vendorOrderDeliverer) Deliver(vendor *models.Vendor, order *models.VendorOrder) error {
+	switch vendor.DeliveryMethod {
+	case models.VendorDeliveryEmail:
+		if vendor.Email == nil {
+			return fmt.Errorf("vendor has no email address")
+		}
+		return d.mailer.Send(mailer.Message{
+			To:      []string{*vendor.Email},
+			Subject: order.Subject,
+			Body:    order.Document,
+		})
+	case models.VendorDeliveryWebhook:
+		return d.post(vendor, order)
+	default:
+		return fmt.Errorf("unknown delivery method '%s'", vendor.DeliveryMethod)
+	}
+}
+
+func (d *vendorOrderDeliverer) post(vendor *models.Vendor, order *models.VendorOrder) error {
+	if vendor.WebhookURL == nil {
+		return fmt.Errorf("vendor has no webhook URL")
+	}
+
+	body, err := json.Marshal(VendorOrderWebhook{
+		OrderID:   order.ID.String(),
+		VendorID:  vendor.ID.String(),
+		Date:      dateKey(order.Date),
+		Revision:  order.Revision,
+		Amendment: order.Revision > 1,
+		Subject:   order.Subject,
+		Document:  order.Document,
+		Lines:     order.Lines,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook body: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, *vendor.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if vendor.WebhookSecret != nil && *vendor.WebhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(*vendor.WebhookSecret))
+		mac.Write(body)
+		req.Header.Set(vendorSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
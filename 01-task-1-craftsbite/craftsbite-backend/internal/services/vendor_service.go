@@ -0,0 +1,743 @@
+package services
+
+import (
+	"bytes"
+	"craftsbite-backend/internal/config"
+	"craftsbite-backend/internal/models"
+	"craftsbite-backend/internal/repository"
+	"craftsbite-backend/pkg/logger"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxVendorLeadDays     = 14
+	maxVendorOrderRange   = 62
+	maxVendorOrderSubject = 500
+)
+
+// defaultVendorSubjectTemplate and defaultVendorBodyTemplate render orders of vendors without templates
+const defaultVendorSubjectTemplate = `{{if .Amendment}}[Amendment {{.Revision}}] {{end}}Meal order for {{.Date}} - {{.Vendor}}`
+
+const defaultVendorBodyTemplate = `Hello {{.Vendor}},
+
+{{if .Amendment}}Our headcount has changed. This amended order replaces the previous one.{{else}}Please find our meal order below.{{end}}
+
+Date: {{.Weekday}}, {{.Date}}
+{{range .Lines}}
+{{.Label}}: {{.Total}} ({{.Participating}} staff, {{.Guests}} guests)
+{{- range .Dietary}}
+  - {{.Name}}: {{.Count}}
+{{- end}}
+{{- if .WithAllergens}}
+  - with allergen notes: {{.WithAllergens}}
+{{- end}}
+{{end}}
+Total meals: {{.Total}}
+
+Thank you,
+CraftsBite
+`
+
+// CreateVendorInput represents input for adding a vendor
+type CreateVendorInput struct {
+	Name            string            `json:"name" binding:"required"`
+	MealTypes       []models.MealType `json:"meal_types" binding:"required"`
+	DeliveryMethod  string            `json:"delivery_method" binding:"required"`
+	Email           *string           `json:"email"`
+	WebhookURL      *string           `json:"webhook_url"`
+	WebhookSecret   *string           `json:"webhook_secret"`
+	LeadDays        int               `json:"lead_days"`
+	OrderDeadline   string            `json:"order_deadline" binding:"required"`
+	SubjectTemplate *string           `json:"subject_template"`
+	BodyTemplate    *string           `json:"body_template"`
+}
+
+// UpdateVendorInput represents input for editing a vendor; omitted fields are left unchanged
+type UpdateVendorInput struct {
+	Name            *string            `json:"name"`
+	MealTypes       *[]models.MealType `json:"meal_types"`
+	DeliveryMethod  *string            `json:"delivery_method"`
+	Email           *string            `json:"email"`
+	WebhookURL      *string            `json:"webhook_url"`
+	WebhookSecret   *string            `json:"webhook_secret"`
+	LeadDays        *int               `json:"lead_days"`
+	OrderDeadline   *string            `json:"order_deadline"`
+	SubjectTemplate *string            `json:"subject_template"`
+	BodyTemplate    *string            `json:"body_template"`
+	IsActive        *bool              `json:"is_active"`
+}
+
+// GenerateVendorOrderInput represents input for drafting a vendor order ahead of its deadline
+type GenerateVendorOrderInput struct {
+	VendorID string `json:"vendor_id" binding:"required"`
+	Date     string `json:"date" binding:"required"`
+}
+
+// VendorOrderTemplateData is what vendor subject and body templates are rendered with
+type VendorOrderTemplateData struct {
+	Vendor    string
+	Date      string
+	Weekday   string
+	Revision  int
+	Amendment bool
+	Lines     []VendorOrderTemplateLine
+	Total     int
+}
+
+// VendorOrderTemplateLine is one meal of an order as seen by templates
+type VendorOrderTemplateLine struct {
+	MealType      string
+	Label         string
+	Participating int
+	Guests        int
+	Total         int
+	Dietary       []VendorOrderDietaryCount
+	WithAllergens int
+}
+
+// VendorOrderDietaryCount is the number of portions for a dietary restriction
+type VendorOrderDietaryCount struct {
+	Name  string
+	Count int
+}
+
+// VendorService defines the interface for vendors and the meal orders sent to them
+type VendorService interface {
+	ListVendors(includeInactive bool) ([]models.Vendor, error)
+	CreateVendor(input CreateVendorInput) (*models.Vendor, error)
+	UpdateVendor(id string, input UpdateVendorInput) (*models.Vendor, error)
+	DeactivateVendor(id string) error
+
+	ListOrders(startDate, endDate, vendorID string) ([]models.VendorOrder, error)
+	GetOrder(id string) (*models.VendorOrder, error)
+	// GenerateOrder creates or refreshes a vendor's draft order for a date without sending it
+	GenerateOrder(actorID string, input GenerateVendorOrderInput) (*models.VendorOrder, error)
+	// SendOrder delivers a draft, or an amendment that is still waiting to be delivered
+	SendOrder(id string) (*models.VendorOrder, error)
+	// AcknowledgeOrder records that the vendor confirmed the order they last received
+	AcknowledgeOrder(actorID, id string) (*models.VendorOrder, error)
+	// ProcessDueOrders sends the orders whose deadline has passed and amends sent orders whose
+	// headcount changed. It returns the number of deliveries made.
+	ProcessDueOrders(now time.Time) (int, error)
+}
+
+type vendorService struct {
+	vendorRepo       repository.VendorRepository
+	headcountService HeadcountService
+	catalog          MealCatalog
+	deliverer        VendorOrderDeliverer
+	timezone         string
+}
+
+// NewVendorService creates a new vendor service
+func NewVendorService(
+	vendorRepo repository.VendorRepository,
+	headcountService HeadcountService,
+	catalog MealCatalog,
+	deliverer VendorOrderDeliverer,
+	cfg *config.Config,
+) VendorService {
+	return &vendorService{
+		vendorRepo:       vendorRepo,
+		headcountService: headcountService,
+		catalog:          catalog,
+		deliverer:        deliverer,
+		timezone:         cfg.Meal.CutoffTimezone,
+	}
+}
+
+func (s *vendorService) ListVendors(includeInactive bool) ([]models.Vendor, error) {
+	return s.vendorRepo.FindAll(includeInactive)
+}
+
+func (s *vendorService) CreateVendor(input CreateVendorInput) (*models.Vendor, error) {
+	vendor := &models.Vendor{
+		Name:            strings.TrimSpace(input.Name),
+		DeliveryMethod:  input.DeliveryMethod,
+		Email:           trimmedOrNil(input.Email),
+		WebhookURL:      trimmedOrNil(input.WebhookURL),
+		WebhookSecret:   trimmedOrNil(input.WebhookSecret),
+		LeadDays:        input.LeadDays,
+		OrderDeadline:   input.OrderDeadline,
+		SubjectTemplate: trimmedOrNil(input.SubjectTemplate),
+		BodyTemplate:    trimmedOrNil(input.BodyTemplate),
+		IsActive:        true,
+	}
+	if err := s.setMealTypes(vendor, input.MealTypes); err != nil {
+		return nil, err
+	}
+	if err := s.validateVendor(vendor); err != nil {
+		return nil, err
+	}
+
+	if err := s.vendorRepo.Create(vendor); err != nil {
+		return nil, err
+	}
+	return vendor, nil
+}
+
+func (s *vendorService) UpdateVendor(id string, input UpdateVendorInput) (*models.Vendor, error) {
+	vendor, err := s.findVendor(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		vendor.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.MealTypes != nil {
+		if err := s.setMealTypes(vendor, *input.MealTypes); err != nil {
+			return nil, err
+		}
+	}
+	if input.DeliveryMethod != nil {
+		vendor.DeliveryMethod = *input.DeliveryMethod
+	}
+	if input.Email != nil {
+		vendor.Email = trimmedOrNil(input.Email)
+	}
+	if input.WebhookURL != nil {
+		vendor.WebhookURL = trimmedOrNil(input.WebhookURL)
+	}
+	if input.WebhookSecret != nil {
+		vendor.WebhookSecret = trimmedOrNil(input.WebhookSecret)
+	}
+	if input.LeadDays != nil {
+		vendor.LeadDays = *input.LeadDays
+	}
+	if input.OrderDeadline != nil {
+		vendor.OrderDeadline = *input.OrderDeadline
+	}
+	if input.SubjectTemplate != nil {
+		vendor.SubjectTemplate = trimmedOrNil(input.SubjectTemplate)
+	}
+	if input.BodyTemplate != nil {
+		vendor.BodyTemplate = trimmedOrNil(input.BodyTemplate)
+	}
+	if input.IsActive != nil {
+		vendor.IsActive = *input.IsActive
+	}
+	if err := s.validateVendor(vendor); err != nil {
+		return nil, err
+	}
+
+	if err := s.vendorRepo.Update(vendor); err != nil {
+		return nil, err
+	}
+	return vendor, nil
+}
+
+func (s *vendorService) DeactivateVendor(id string) error {
+	vendor, err := s.findVendor(id)
+	if err != nil {
+		return err
+	}
+	if !vendor.IsActive {
+		return fmt.Errorf("vendor is already inactive")
+	}
+
+	vendor.IsActive = false
+	return s.vendorRepo.Update(vendor)
+}
+
+func (s *vendorService) ListOrders(startDate, endDate, vendorID string) ([]models.VendorOrder, error) {
+	start, end, err := parseCalendarRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if end.Sub(start).Hours()/24 >= maxVendorOrderRange {
+		return nil, fmt.Errorf("date range cannot exceed %d days", maxVendorOrderRange)
+	}
+	if vendorID != "" {
+		if _, err := uuid.Parse(vendorID); err != nil {
+			return nil, fmt.Errorf("invalid vendor ID")
+		}
+	}
+
+	return s.vendorRepo.FindOrders(startDate, endDate, vendorID)
+}
+
+func (s *vendorService) GetOrder(id string) (*models.VendorOrder, error) {
+	return s.findOrder(id)
+}
+
+func (s *vendorService) GenerateOrder(actorID string, input GenerateVendorOrderInput) (*models.VendorOrder, error) {
+	if err := validateDate(input.Date); err != nil {
+		return nil, err
+	}
+	actorUUID, err := uuid.Parse(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+	vendor, err := s.findVendor(input.VendorID)
+	if err != nil {
+		return nil, err
+	}
+	if !vendor.IsActive {
+		return nil, fmt.Errorf("vendor is inactive")
+	}
+
+	order, err := s.vendorRepo.FindOrder(vendor.ID.String(), input.Date)
+	if err != nil {
+		return nil, err
+	}
+	if order != nil && order.DeliveredRevision > 0 {
+		return nil, fmt.Errorf("the order for %s was already sent; headcount changes are sent as amendments", input.Date)
+	}
+	if order == nil {
+		order = newVendorOrder(vendor, input.Date)
+		order.CreatedBy = &actorUUID
+	}
+
+	if _, err := s.refresh(vendor, order); err != nil {
+		return nil, err
+	}
+	if len(order.Lines) == 0 {
+		return nil, fmt.Errorf("%s has no meals from this vendor scheduled", input.Date)
+	}
+	if err := s.vendorRepo.SaveOrder(order); err != nil {
+		return nil, err
+	}
+
+	order.Vendor = vendor
+	return order, nil
+}
+
+func (s *vendorService) SendOrder(id string) (*models.VendorOrder, error) {
+	order, err := s.findOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.DeliveredRevision == order.Revision {
+		return nil, fmt.Errorf("the vendor already has the latest revision of this order")
+	}
+
+	vendor := order.Vendor
+	if _, err := s.refresh(vendor, order); err != nil {
+		return nil, err
+	}
+	if order.DeliveredRevision == 0 && len(order.Lines) == 0 {
+		return nil, fmt.Errorf("%s has no meals from this vendor scheduled", dateKey(order.Date))
+	}
+	if err := s.vendorRepo.SaveOrder(order); err != nil {
+		return nil, err
+	}
+
+	if err := s.deliver(vendor, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *vendorService) AcknowledgeOrder(actorID, id string) (*models.VendorOrder, error) {
+	actorUUID, err := uuid.Parse(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+	order, err := s.findOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	switch order.Status {
+	case models.VendorOrderStatusDraft:
+		return nil, fmt.Errorf("cannot acknowledge an order that has not been sent")
+	case models.VendorOrderStatusAcknowledged:
+		return nil, fmt.Errorf("order is already acknowledged")
+	}
+
+	now := time.Now()
+	order.Status = models.VendorOrderStatusAcknowledged
+	order.AcknowledgedAt = &now
+	order.AcknowledgedBy = &actorUUID
+	if err := s.vendorRepo.SaveOrder(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *vendorService) ProcessDueOrders(now time.Time) (int, error) {
+	loc, err := time.LoadLocation(s.timezone)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timezone: %w", err)
+	}
+	vendors, err := s.vendorRepo.FindAll(false)
+	if err != nil {
+		return 0, err
+	}
+
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	delivered := 0
+	for i := range vendors {
+		vendor := &vendors[i]
+		// Orders stay open for amendments from their deadline until the meal date has passed
+		for offset := 0; offset <= vendor.LeadDays; offset++ {
+			date := today.AddDate(0, 0, offset)
+			deadline, err := clockTimeOn(date.AddDate(0, 0, -vendor.LeadDays), vendor.OrderDeadline, s.timezone)
+			if err != nil {
+				return delivered, err
+			}
+			if now.Before(deadline) {
+				continue
+			}
+
+			sent, err := s.processOrder(vendor, date.Format("2006-01-02"))
+			if err != nil {
+				logger.Warn(fmt.Sprintf("Vendor order for %s on %s failed: %v", vendor.Name, date.Format("2006-01-02"), err))
+				continue
+			}
+			if sent {
+				delivered++
+			}
+		}
+	}
+
+	return delivered, nil
+}
+
+// processOrder brings a due order in line with the headcount and delivers it if the vendor
+// does not have the latest revision yet
+func (s *vendorService) processOrder(vendor *models.Vendor, date string) (bool, error) {
+	order, err := s.vendorRepo.FindOrder(vendor.ID.String(), date)
+	if err != nil {
+		return false, err
+	}
+	if order == nil {
+		order = newVendorOrder(vendor, date)
+	}
+
+	changed, err := s.refresh(vendor, order)
+	if err != nil {
+		return false, err
+	}
+	// Nothing to order, and nothing was ordered before
+	if order.DeliveredRevision == 0 && len(order.Lines) == 0 {
+		return false, nil
+	}
+	if changed {
+		if err := s.vendorRepo.SaveOrder(order); err != nil {
+			return false, err
+		}
+	}
+	if order.DeliveredRevision == order.Revision {
+		return false, nil
+	}
+
+	if err := s.deliver(vendor, order); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// refresh recomputes an order's lines from the live headcount and re-renders its document.
+// A change to an order the vendor already has starts a new revision; a revision that has not
+// been delivered yet is updated in place.
+func (s *vendorService) refresh(vendor *models.Vendor, order *models.VendorOrder) (bool, error) {
+	date := dateKey(order.Date)
+	lines, err := s.buildLines(vendor, date)
+	if err != nil {
+		return false, err
+	}
+	if order.ID != uuid.Nil && vendorOrderLinesEqual(order.Lines, lines) {
+		return false, nil
+	}
+
+	order.Lines = lines
+	if order.DeliveredRevision > 0 && order.DeliveredRevision == order.Revision {
+		order.Revision++
+	}
+
+	subject, document, err := s.render(vendor, order)
+	if err != nil {
+		return false, err
+	}
+	order.Subject = subject
+	order.Document = document
+	return true, nil
+}
+
+// deliver sends the current revision of an order. A failed delivery is recorded on the order
+// so it can be retried.
+func (s *vendorService) deliver(vendor *models.Vendor, order *models.VendorOrder) error {
+	if err := s.deliverer.Deliver(vendor, order); err != nil {
+		message := err.Error()
+		order.LastError = &message
+		if saveErr := s.vendorRepo.SaveOrder(order); saveErr != nil {
+			logger.Error(fmt.Sprintf("Failed to record delivery error of vendor order %s: %v", order.ID, saveErr))
+		}
+		return fmt.Errorf("failed to deliver order: %w", err)
+	}
+
+	now := time.Now()
+	if order.DeliveredRevision == 0 {
+		order.Status = models.VendorOrderStatusSent
+	} else {
+		order.Status = models.VendorOrderStatusAmended
+	}
+	order.DeliveredRevision = order.Revision
+	order.SentAt = &now
+	order.LastError = nil
+	return s.vendorRepo.SaveOrder(order)
+}
+
+// buildLines counts the participants, guests and dietary portions of the vendor's meals on a date
+func (s *vendorService) buildLines(vendor *models.Vendor, date string) ([]models.VendorOrderLine, error) {
+	summary, err := s.headcountService.GetHeadcountByDate(date)
+	if err != nil {
+		return nil, err
+	}
+	lines := []models.VendorOrderLine{}
+	if summary == nil {
+		return lines, nil
+	}
+
+	for _, mealType := range parseMealTypes(vendor.MealTypes) {
+		counts, ok := summary.Meals[string(mealType)]
+		if !ok {
+			continue
+		}
+		line := models.VendorOrderLine{
+			MealType:      mealType,
+			Participating: counts.Participating,
+			Guests:        counts.Guests,
+			Dietary:       make(map[models.DietaryRestriction]int),
+		}
+		if counts.Dietary != nil {
+			for restriction, count := range counts.Dietary.Restrictions {
+				if count > 0 {
+					line.Dietary[restriction] = count
+				}
+			}
+			line.WithAllergens = counts.Dietary.WithAllergens
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func (s *vendorService) render(vendor *models.Vendor, order *models.VendorOrder) (string, string, error) {
+	date := dateKey(order.Date)
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid order date: %w", err)
+	}
+
+	data := VendorOrderTemplateData{
+		Vendor:    vendor.Name,
+		Date:      date,
+		Weekday:   day.Weekday().String(),
+		Revision:  order.Revision,
+		Amendment: order.Revision > 1,
+	}
+	for _, line := range order.Lines {
+		label := string(line.MealType)
+		if def, err := s.catalog.Lookup(label); err == nil && def != nil {
+			label = def.Label
+		}
+		templateLine := VendorOrderTemplateLine{
+			MealType:      string(line.MealType),
+			Label:         label,
+			Participating: line.Participating,
+			Guests:        line.Guests,
+			Total:         line.Participating + line.Guests,
+			WithAllergens: line.WithAllergens,
+		}
+		for _, restriction := range models.DietaryRestrictions {
+			if count := line.Dietary[restriction]; count > 0 {
+				templateLine.Dietary = append(templateLine.Dietary, VendorOrderDietaryCount{
+					Name:  strings.ReplaceAll(restriction.String(), "_", " "),
+					Count: count,
+				})
+			}
+		}
+		data.Lines = append(data.Lines, templateLine)
+		data.Total += templateLine.Total
+	}
+
+	return renderVendorTemplates(vendor, data)
+}
+
+func (s *vendorService) setMealTypes(vendor *models.Vendor, mealTypes []models.MealType) error {
+	if len(mealTypes) == 0 {
+		return fmt.Errorf("at least one meal type is required")
+	}
+	for _, mealType := range mealTypes {
+		if _, err := s.catalog.Validate(string(mealType)); err != nil {
+			return err
+		}
+	}
+	vendor.MealTypes = serializeMealTypes(mealTypes)
+	return nil
+}
+
+func (s *vendorService) validateVendor(vendor *models.Vendor) error {
+	if vendor.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	switch vendor.DeliveryMethod {
+	case models.VendorDeliveryEmail:
+		if vendor.Email == nil {
+			return fmt.Errorf("email is required for email delivery")
+		}
+		if _, err := mail.ParseAddress(*vendor.Email); err != nil {
+			return fmt.Errorf("invalid email address")
+		}
+	case models.VendorDeliveryWebhook:
+		if vendor.WebhookURL == nil {
+			return fmt.Errorf("webhook_url is required for webhook delivery")
+		}
+		parsed, err := url.Parse(*vendor.WebhookURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("webhook_url must be an http or https URL")
+		}
+	default:
+		return fmt.Errorf("delivery_method must be one of: email, webhook")
+	}
+
+	if vendor.LeadDays < 0 || vendor.LeadDays > maxVendorLeadDays {
+		return fmt.Errorf("lead_days must be between 0 and %d", maxVendorLeadDays)
+	}
+	if _, err := time.Parse("15:04", vendor.OrderDeadline); err != nil {
+		return fmt.Errorf("order_deadline must be in HH:MM format")
+	}
+
+	// Render a sample order so template mistakes surface now rather than at the deadline
+	sample := VendorOrderTemplateData{
+		Vendor:   vendor.Name,
+		Date:     "2006-01-02",
+		Weekday:  "Monday",
+		Revision: 1,
+		Lines: []VendorOrderTemplateLine{{
+			MealType:      "lunch",
+			Label:         "Lunch",
+			Participating: 1,
+			Total:         1,
+			Dietary:       []VendorOrderDietaryCount{{Name: "vegetarian", Count: 1}},
+		}},
+		Total: 1,
+	}
+	if _, _, err := renderVendorTemplates(vendor, sample); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *vendorService) findVendor(id string) (*models.Vendor, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid vendor ID")
+	}
+	vendor, err := s.vendorRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if vendor == nil {
+		return nil, fmt.Errorf("vendor not found")
+	}
+	return vendor, nil
+}
+
+func (s *vendorService) findOrder(id string) (*models.VendorOrder, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid order ID")
+	}
+	order, err := s.vendorRepo.FindOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, fmt.Errorf("order not found")
+	}
+	return order, nil
+}
+
+func newVendorOrder(vendor *models.Vendor, date string) *models.VendorOrder {
+	return &models.VendorOrder{
+		VendorID: vendor.ID,
+		Date:     date,
+		Status:   models.VendorOrderStatusDraft,
+		Revision: 1,
+	}
+}
+
+// renderVendorTemplates renders the vendor's subject and body templates, falling back to the defaults
+func renderVendorTemplates(vendor *models.Vendor, data VendorOrderTemplateData) (string, string, error) {
+	subjectTemplate := defaultVendorSubjectTemplate
+	if vendor.SubjectTemplate != nil {
+		subjectTemplate = *vendor.SubjectTemplate
+	}
+	bodyTemplate := defaultVendorBodyTemplate
+	if vendor.BodyTemplate != nil {
+		bodyTemplate = *vendor.BodyTemplate
+	}
+
+	subject, err := executeVendorTemplate("subject", subjectTemplate, data)
+	if err != nil {
+		return "", "", err
+	}
+	subject = strings.Join(strings.Fields(subject), " ")
+	if len(subject) > maxVendorOrderSubject {
+		return "", "", fmt.Errorf("subject cannot exceed %d characters", maxVendorOrderSubject)
+	}
+	body, err := executeVendorTemplate("body", bodyTemplate, data)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+func executeVendorTemplate(name, text string, data VendorOrderTemplateData) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// vendorOrderLinesEqual reports whether two sets of order lines have the same quantities
+func vendorOrderLinesEqual(a, b []models.VendorOrderLine) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	byMeal := make(map[models.MealType]models.VendorOrderLine, len(a))
+	for _, line := range a {
+		byMeal[line.MealType] = line
+	}
+	for _, line := range b {
+		other, ok := byMeal[line.MealType]
+		if !ok || other.Participating != line.Participating || other.Guests != line.Guests ||
+			other.WithAllergens != line.WithAllergens || len(other.Dietary) != len(line.Dietary) {
+			return false
+		}
+		for restriction, count := range line.Dietary {
+			if other.Dietary[restriction] != count {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// trimmedOrNil returns nil for a missing or blank string
+func trimmedOrNil(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
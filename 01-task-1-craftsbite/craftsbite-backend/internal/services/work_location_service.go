@@ -1,10 +1,12 @@
 package services
 
 import (
+	"craftsbite-backend/internal/authz"
 	"craftsbite-backend/internal/config"
 	"craftsbite-backend/internal/models"
 	"craftsbite-backend/internal/repository"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -16,8 +18,12 @@ type WorkLocationService interface {
 	ListByDate(requesterID, date string) ([]WorkLocationResponse, error)
 	GetMonthlySummary(userID, yearMonth string) (*MonthlyWFHSummary, error)
 	GetTeamMonthlyReport(requesterID, yearMonth string) (*TeamMonthlyReport, error)
+	GetTeamMonthlyReportRange(requesterID, startMonth, endMonth string) ([]*TeamMonthlyReport, error)
 }
 
+// maxReportRangeMonths caps the number of months a WFH report may span
+const maxReportRangeMonths = 12
+
 type MonthlyWFHSummary struct {
     YearMonth  string `json:"year_month"`
     WFHDays    int64  `json:"wfh_days"`
@@ -35,6 +41,8 @@ type WorkLocationResponse struct {
 
 type MemberWFHSummary struct {
     UserID      string `json:"user_id"`
+    Name        string `json:"name"`
+    Email       string `json:"email"`
     WFHDays     int64  `json:"wfh_days"`
     IsOverLimit bool   `json:"is_over_limit"`
     ExtraDays   int64  `json:"extra_days"`
@@ -55,6 +63,7 @@ type workLocationService struct {
 	teamRepo    repository.TeamRepository
 	wfhPeriodRepo repository.WFHPeriodRepository
 	historyRepo repository.WorkLocationHistoryRepository
+	authorizer  authz.Authorizer
 	monthlyWFHAllowance int
 }
 
@@ -64,6 +73,7 @@ func NewWorkLocationService(
 	teamRepo repository.TeamRepository,
 	wfhPeriodRepo repository.WFHPeriodRepository,
 	historyRepo repository.WorkLocationHistoryRepository,
+	authorizer authz.Authorizer,
 	cfg *config.Config,
 ) WorkLocationService {
 	return &workLocationService{
@@ -72,6 +82,7 @@ func NewWorkLocationService(
 		teamRepo:            teamRepo,
 		wfhPeriodRepo:       wfhPeriodRepo,
 		historyRepo:         historyRepo,
+		authorizer:          authorizer,
 		monthlyWFHAllowance: cfg.WorkLocation.MonthlyWFHAllowance,
 	}
 }
@@ -179,14 +190,8 @@ func (s *workLocationService) SetLocationFor(requesterID, targetUserID, date, lo
 		return fmt.Errorf("requester not found")
 	}
 
-	if requester.Role == models.RoleTeamLead {
-		isMember, err := s.teamRepo.IsUserInAnyTeamLedBy(requesterID, targetUserID)
-		if err != nil {
-			return fmt.Errorf("failed to verify team membership: %w", err)
-		}
-		if !isMember {
-			return fmt.Errorf("you can only set work location for your own team members")
-		}
+	if err := s.authorizer.Authorize(requester.Role.String(), requesterID, authz.PermWorkLocationOverride, targetUserID); err != nil {
+		return err
 	}
 
 	targetUUID, err := uuid.Parse(targetUserID)
@@ -243,9 +248,15 @@ func (s *workLocationService) ListByDate(requesterID, date string) ([]WorkLocati
         return nil, fmt.Errorf("requester not found")
     }
 
+    scope, err := s.authorizer.ScopeFor(requester.Role.String(), authz.PermWorkLocationRead)
+    if err != nil {
+        return nil, err
+    }
+
     var wls []models.WorkLocation
 
-    if requester.Role == models.RoleTeamLead {
+    // Narrower than "all" means the requester only sees the teams they lead
+    if scope != authz.ScopeAll {
         teams, err := s.teamRepo.FindByTeamLeadID(requesterID)
         if err != nil {
             return nil, fmt.Errorf("failed to load teams: %w", err)
@@ -300,31 +311,83 @@ func (s *workLocationService) GetMonthlySummary(userID, yearMonth string) (*Mont
 }
 
 func (s *workLocationService) GetTeamMonthlyReport(requesterID, yearMonth string) (*TeamMonthlyReport, error) {
-    requester, err := s.userRepo.FindByID(requesterID)
+    users, err := s.reportUsers(requesterID)
     if err != nil {
-        return nil, fmt.Errorf("requester not found")
+        return nil, err
     }
 
-    var userIDs []string
+    return s.monthlyReport(yearMonth, users)
+}
 
-    if requester.Role == models.RoleTeamLead {
-        teams, err := s.teamRepo.FindByTeamLeadID(requesterID)
+// GetTeamMonthlyReportRange returns the WFH report of every month from startMonth to endMonth
+func (s *workLocationService) GetTeamMonthlyReportRange(requesterID, startMonth, endMonth string) ([]*TeamMonthlyReport, error) {
+    start, err := time.Parse("2006-01", startMonth)
+    if err != nil {
+        return nil, fmt.Errorf("invalid start month format, expected YYYY-MM")
+    }
+    end, err := time.Parse("2006-01", endMonth)
+    if err != nil {
+        return nil, fmt.Errorf("invalid end month format, expected YYYY-MM")
+    }
+    if end.Before(start) {
+        return nil, fmt.Errorf("end month must not be before start month")
+    }
+    if months := (end.Year()-start.Year())*12 + int(end.Month()-start.Month()) + 1; months > maxReportRangeMonths {
+        return nil, fmt.Errorf("month range must not exceed %d months", maxReportRangeMonths)
+    }
+
+    users, err := s.reportUsers(requesterID)
+    if err != nil {
+        return nil, err
+    }
+
+    var reports []*TeamMonthlyReport
+    for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+        report, err := s.monthlyReport(month.Format("2006-01"), users)
         if err != nil {
-            return nil, fmt.Errorf("failed to load teams: %w", err)
-        }
-        for _, team := range teams {
-            for _, member := range team.Members {
-                userIDs = append(userIDs, member.ID.String())
-            }
+            return nil, err
         }
-    } else {
+        reports = append(reports, report)
+    }
+    return reports, nil
+}
+
+// reportUsers returns the users the requester may report on: the members of the
+// teams they lead, or every active user when their scope is organization-wide
+func (s *workLocationService) reportUsers(requesterID string) ([]models.User, error) {
+    requester, err := s.userRepo.FindByID(requesterID)
+    if err != nil {
+        return nil, fmt.Errorf("requester not found")
+    }
+
+    scope, err := s.authorizer.ScopeFor(requester.Role.String(), authz.PermWorkLocationReport)
+    if err != nil {
+        return nil, err
+    }
+
+    if scope == authz.ScopeAll {
         users, err := s.userRepo.FindAll(map[string]interface{}{"active": true})
         if err != nil {
             return nil, fmt.Errorf("failed to load users: %w", err)
         }
-        for _, u := range users {
-            userIDs = append(userIDs, u.ID.String())
-        }
+        return users, nil
+    }
+
+    teams, err := s.teamRepo.FindByTeamLeadID(requesterID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to load teams: %w", err)
+    }
+    var users []models.User
+    for _, team := range teams {
+        users = append(users, team.Members...)
+    }
+    return users, nil
+}
+
+func (s *workLocationService) monthlyReport(yearMonth string, users []models.User) (*TeamMonthlyReport, error) {
+    userIDs := make([]string, len(users))
+    for i, u := range users {
+        userIDs[i] = u.ID.String()
     }
 
     counts, err := s.repo.GetMonthlyWFHCountsByUsers(yearMonth, userIDs)
@@ -339,7 +402,8 @@ func (s *workLocationService) GetTeamMonthlyReport(requesterID, yearMonth string
         Members:    make([]MemberWFHSummary, 0, len(userIDs)),
     }
 
-    for _, id := range userIDs {
+    for _, u := range users {
+        id := u.ID.String()
         wfhDays := counts[id]
         extra := wfhDays - int64(s.monthlyWFHAllowance)
         if extra < 0 {
@@ -347,6 +411,8 @@ func (s *workLocationService) GetTeamMonthlyReport(requesterID, yearMonth string
         }
         member := MemberWFHSummary{
             UserID:      id,
+            Name:        u.Name,
+            Email:       u.Email,
             WFHDays:     wfhDays,
             IsOverLimit: wfhDays > int64(s.monthlyWFHAllowance),
             ExtraDays:   extra,
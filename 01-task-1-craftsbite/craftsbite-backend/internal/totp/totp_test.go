@@ -0,0 +1,122 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 seed of RFC 6238 Appendix B, "12345678901234567890", in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeMatchesRFC6238Vectors(t *testing.T) {
+	// The RFC lists 8 digit codes; a 6 digit code is the same value modulo 10^6
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code at %d: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestVerifySkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+
+	tests := []struct {
+		name   string
+		delta  int64
+		skew   int
+		accept bool
+	}{
+		{"current step without skew", 0, 0, true},
+		{"previous step without skew", -1, 0, false},
+		{"next step without skew", 1, 0, false},
+		{"previous step within skew", -1, 1, true},
+		{"next step within skew", 1, 1, true},
+		{"two steps back beyond skew", -2, 1, false},
+		{"two steps ahead beyond skew", 2, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, current+tt.delta)
+			if err != nil {
+				t.Fatalf("Code: %v", err)
+			}
+			step, ok := Verify(rfcSecret, code, now, tt.skew)
+			if ok != tt.accept {
+				t.Fatalf("Verify = %v, want %v", ok, tt.accept)
+			}
+			if ok && step != current+tt.delta {
+				t.Errorf("Verify matched step %d, want %d", step, current+tt.delta)
+			}
+		})
+	}
+}
+
+func TestVerifyStepBoundary(t *testing.T) {
+	// 1111111109 and 1111111111 straddle the boundary between two steps
+	before := time.Unix(1111111109, 0)
+	after := time.Unix(1111111111, 0)
+	code, err := Code(rfcSecret, Step(before))
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+
+	if _, ok := Verify(rfcSecret, code, after, 0); ok {
+		t.Error("Verify accepted the previous step's code without skew")
+	}
+	if step, ok := Verify(rfcSecret, code, after, 1); !ok || step != Step(before) {
+		t.Errorf("Verify = (%d, %v), want (%d, true)", step, ok, Step(before))
+	}
+}
+
+func TestVerifyRejectsMalformedCodes(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	if _, ok := Verify(rfcSecret, "287 082", now, 0); !ok {
+		t.Error("Verify rejected a code with a space in it")
+	}
+	for _, code := range []string{"", "28708", "2870820", "94287082"} {
+		if _, ok := Verify(rfcSecret, code, now, 1); ok {
+			t.Errorf("Verify(%q) succeeded, want a rejection", code)
+		}
+	}
+	if _, ok := Verify("not base32!", "287082", now, 1); ok {
+		t.Error("Verify succeeded with an invalid secret")
+	}
+}
+
+func TestGenerateSecretRoundTrip(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("secret length = %d, want 32", len(secret))
+	}
+
+	now := time.Now()
+	code, err := Code(secret, Step(now))
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+	if _, ok := Verify(secret, code, now, 0); !ok {
+		t.Error("Verify rejected a code for a generated secret")
+	}
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCheckInToken = errors.New("invalid check-in token")
+	ErrExpiredCheckInToken = errors.New("check-in token has expired")
+)
+
+// GenerateCheckInToken returns a signed token identifying a user at a canteen kiosk until expiresAt.
+// It is signed with a key derived from secret, so it cannot be passed off as an access token.
+func GenerateCheckInToken(userID, secret string, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%s.%d", userID, expiresAt.Unix())
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + checkInSignature(payload, secret)
+}
+
+// ValidateCheckInToken verifies a check-in token and returns the user ID it was issued to
+func ValidateCheckInToken(token, secret string, now time.Time) (string, error) {
+	encoded, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return "", ErrInvalidCheckInToken
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidCheckInToken
+	}
+	payload := string(decoded)
+	if !hmac.Equal([]byte(signature), []byte(checkInSignature(payload, secret))) {
+		return "", ErrInvalidCheckInToken
+	}
+
+	userID, expiry, ok := strings.Cut(payload, ".")
+	if !ok {
+		return "", ErrInvalidCheckInToken
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", ErrInvalidCheckInToken
+	}
+	if now.Unix() > expiresAt {
+		return "", ErrExpiredCheckInToken
+	}
+	return userID, nil
+}
+
+func checkInSignature(payload, secret string) string {
+	mac := hmac.New(sha256.New, []byte("check-in:"+secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -9,18 +9,20 @@ import (
 
 // Claims represents JWT claims
 type Claims struct {
-	UserID string `json:"sub"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID    string `json:"sub"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken generates a JWT token for a user
-func GenerateToken(userID, email, role, secret string, expiration time.Duration) (string, error) {
+// GenerateToken generates a JWT access token for a user bound to a session
+func GenerateToken(userID, email, role, sessionID, secret string, expiration time.Duration) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS discord_links;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE discord_links (
+    id              UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    discord_user_id VARCHAR(32)  NOT NULL,
+    user_id         UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    linked_by       UUID         REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_discord_links_discord_user_id UNIQUE (discord_user_id),
+    CONSTRAINT uq_discord_links_user_id UNIQUE (user_id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS headcount_snapshot_users;
+DROP TABLE IF EXISTS headcount_snapshot_teams;
+DROP TABLE IF EXISTS headcount_snapshots;
\ No newline at end of file
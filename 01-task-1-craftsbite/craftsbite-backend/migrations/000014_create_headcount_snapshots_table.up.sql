@@ -0,0 +1,35 @@
+CREATE TABLE headcount_snapshots (
+    id                 UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    date               DATE         NOT NULL,
+    meal_type          VARCHAR(50)  NOT NULL,
+    day_status         VARCHAR(50)  NOT NULL,
+    total_active_users INTEGER      NOT NULL DEFAULT 0,
+    participating      INTEGER      NOT NULL DEFAULT 0,
+    opted_out          INTEGER      NOT NULL DEFAULT 0,
+    captured_by        UUID         REFERENCES users(id) ON DELETE SET NULL,
+    captured_at        TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_headcount_snapshot_date_meal UNIQUE (date, meal_type)
+);
+
+CREATE TABLE headcount_snapshot_teams (
+    id            UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id   UUID          NOT NULL REFERENCES headcount_snapshots(id) ON DELETE CASCADE,
+    team_id       UUID          NOT NULL,
+    team_name     VARCHAR(255)  NOT NULL,
+    total_members INTEGER       NOT NULL DEFAULT 0,
+    participating INTEGER       NOT NULL DEFAULT 0,
+    opted_out     INTEGER       NOT NULL DEFAULT 0
+);
+
+CREATE TABLE headcount_snapshot_users (
+    id               UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    snapshot_id      UUID          NOT NULL REFERENCES headcount_snapshots(id) ON DELETE CASCADE,
+    user_id          UUID          NOT NULL,
+    name             VARCHAR(255)  NOT NULL,
+    email            VARCHAR(255)  NOT NULL,
+    is_participating BOOLEAN       NOT NULL,
+    source           VARCHAR(50)   NOT NULL
+);
+
+CREATE INDEX idx_headcount_snapshot_teams_snapshot_id ON headcount_snapshot_teams(snapshot_id);
+CREATE INDEX idx_headcount_snapshot_users_snapshot_id ON headcount_snapshot_users(snapshot_id);
@@ -0,0 +1,17 @@
+CREATE TABLE sessions (
+    id                  UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id             UUID          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token_hash  VARCHAR(64)   NOT NULL,
+    previous_token_hash VARCHAR(64),
+    user_agent          VARCHAR(512)  NOT NULL DEFAULT '',
+    ip_address          VARCHAR(64)   NOT NULL DEFAULT '',
+    expires_at          TIMESTAMPTZ   NOT NULL,
+    last_used_at        TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    revoked_at          TIMESTAMPTZ,
+    revoked_reason      VARCHAR(50),
+    created_at          TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_sessions_refresh_token_hash UNIQUE (refresh_token_hash)
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_previous_token_hash ON sessions(previous_token_hash);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS team_history;
\ No newline at end of file
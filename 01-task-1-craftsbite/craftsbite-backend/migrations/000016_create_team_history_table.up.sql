@@ -0,0 +1,15 @@
+CREATE TABLE team_history (
+    id             UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id        UUID         NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    action         VARCHAR(30)  NOT NULL,
+    actor_id       UUID         REFERENCES users(id) ON DELETE SET NULL,
+    subject_id     UUID         REFERENCES users(id) ON DELETE SET NULL,
+    previous_value TEXT,
+    new_value      TEXT,
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_team_history_team_id ON team_history(team_id, created_at DESC);
+
+COMMENT ON TABLE team_history IS 'Audit trail of team management actions';
+COMMENT ON COLUMN team_history.subject_id IS 'User affected by the action (added/removed member, new lead)';
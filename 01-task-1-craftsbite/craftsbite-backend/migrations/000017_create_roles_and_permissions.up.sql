@@ -0,0 +1,78 @@
+CREATE TABLE roles (
+    name        VARCHAR(50)  PRIMARY KEY,
+    description TEXT,
+    is_system   BOOLEAN      NOT NULL DEFAULT false,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE role_permissions (
+    role_name  VARCHAR(50)   NOT NULL REFERENCES roles(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    permission VARCHAR(100)  NOT NULL,
+    scope      VARCHAR(20)   NOT NULL,
+    PRIMARY KEY (role_name, permission),
+    CONSTRAINT chk_role_permissions_scope CHECK (scope IN ('own', 'team', 'all'))
+);
+
+COMMENT ON TABLE roles IS 'Roles users can hold; system roles ship with the application';
+COMMENT ON TABLE role_permissions IS 'Permissions granted to each role and the scope (own, team, all) they apply to';
+
+INSERT INTO roles (name, description, is_system) VALUES
+    ('employee',  'Regular employee managing their own meals', true),
+    ('team_lead', 'Leads one or more teams and manages their members', true),
+    ('admin',     'Full administrative access', true),
+    ('logistics', 'Plans meals and orders from headcount', true);
+
+INSERT INTO role_permissions (role_name, permission, scope) VALUES
+    ('employee', 'user:read', 'own'),
+    ('employee', 'user:write', 'own'),
+    ('employee', 'team:read', 'own'),
+    ('employee', 'history:read', 'own'),
+
+    ('team_lead', 'user:read', 'own'),
+    ('team_lead', 'user:write', 'own'),
+    ('team_lead', 'team:read', 'team'),
+    ('team_lead', 'history:read', 'own'),
+    ('team_lead', 'participation:read', 'team'),
+    ('team_lead', 'participation:override', 'team'),
+    ('team_lead', 'bulk_optout:manage', 'team'),
+    ('team_lead', 'work_location:read', 'team'),
+    ('team_lead', 'work_location:report', 'team'),
+    ('team_lead', 'work_location:override', 'team'),
+
+    ('logistics', 'user:read', 'all'),
+    ('logistics', 'user:write', 'own'),
+    ('logistics', 'history:read', 'all'),
+    ('logistics', 'participation:read', 'all'),
+    ('logistics', 'schedule:write', 'all'),
+    ('logistics', 'headcount:read', 'all'),
+    ('logistics', 'headcount:snapshot', 'all'),
+    ('logistics', 'work_location:report', 'all'),
+    ('logistics', 'wfh_period:manage', 'all'),
+
+    ('admin', 'user:read', 'all'),
+    ('admin', 'user:write', 'all'),
+    ('admin', 'user:assign_role', 'all'),
+    ('admin', 'team:read', 'all'),
+    ('admin', 'team:manage', 'all'),
+    ('admin', 'participation:read', 'all'),
+    ('admin', 'participation:override', 'all'),
+    ('admin', 'bulk_optout:manage', 'all'),
+    ('admin', 'history:read', 'all'),
+    ('admin', 'schedule:write', 'all'),
+    ('admin', 'headcount:read', 'all'),
+    ('admin', 'headcount:snapshot', 'all'),
+    ('admin', 'work_location:read', 'all'),
+    ('admin', 'work_location:report', 'all'),
+    ('admin', 'work_location:override', 'all'),
+    ('admin', 'wfh_period:manage', 'all'),
+    ('admin', 'discord:manage', 'all'),
+    ('admin', 'session:manage', 'all'),
+    ('admin', 'role:manage', 'all');
+
+-- Every user must hold a defined role
+INSERT INTO roles (name, is_system)
+SELECT DISTINCT role, false FROM users
+WHERE role NOT IN (SELECT name FROM roles);
+
+ALTER TABLE users ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
@@ -0,0 +1,22 @@
+CREATE TABLE schedule_rules (
+    id              UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    name            VARCHAR(255) NOT NULL,
+    rrule           TEXT         NOT NULL,
+    start_date      DATE         NOT NULL,
+    end_date        DATE,
+    day_status      VARCHAR(50)  NOT NULL DEFAULT 'normal',
+    reason          TEXT,
+    available_meals TEXT,
+    priority        INTEGER      NOT NULL DEFAULT 0,
+    active          BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_by      UUID         REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_schedule_rules_dates CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+CREATE INDEX idx_schedule_rules_active_dates ON schedule_rules(active, start_date, end_date);
+
+COMMENT ON TABLE schedule_rules IS 'Recurring day schedules, expanded on read; explicit day_schedules rows take precedence';
+COMMENT ON COLUMN schedule_rules.rrule IS 'RFC 5545 recurrence rule anchored at start_date, e.g. FREQ=WEEKLY;BYDAY=TH';
+COMMENT ON COLUMN schedule_rules.priority IS 'When several rules match a date, the highest priority wins';
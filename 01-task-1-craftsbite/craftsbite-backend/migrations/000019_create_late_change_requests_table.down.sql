@@ -0,0 +1,2 @@
+DELETE FROM role_permissions WHERE permission = 'late_change:review';
+DROP TABLE IF EXISTS late_change_requests;
@@ -0,0 +1,27 @@
+CREATE TABLE late_change_requests (
+    id               UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id          UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date             DATE         NOT NULL,
+    meal_type        VARCHAR(50)  NOT NULL,
+    is_participating BOOLEAN      NOT NULL,
+    reason           VARCHAR(255) NOT NULL,
+    status           VARCHAR(20)  NOT NULL DEFAULT 'pending',
+    reviewed_by      UUID         REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at      TIMESTAMPTZ,
+    review_note      VARCHAR(255),
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_late_change_requests_status CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled', 'expired'))
+);
+
+CREATE INDEX idx_late_change_requests_user ON late_change_requests(user_id, created_at DESC);
+CREATE INDEX idx_late_change_requests_status ON late_change_requests(status, date);
+CREATE UNIQUE INDEX uq_late_change_requests_pending ON late_change_requests(user_id, date, meal_type) WHERE status = 'pending';
+
+COMMENT ON TABLE late_change_requests IS 'Requests to change meal participation after the cutoff, approved by a team lead or logistics';
+
+INSERT INTO role_permissions (role_name, permission, scope) VALUES
+    ('team_lead', 'late_change:review', 'team'),
+    ('logistics', 'late_change:review', 'all'),
+    ('admin', 'late_change:review', 'all')
+ON CONFLICT (role_name, permission) DO NOTHING;
@@ -0,0 +1,2 @@
+DELETE FROM role_permissions WHERE permission IN ('billing:read', 'billing:manage');
+DROP TABLE IF EXISTS meal_prices;
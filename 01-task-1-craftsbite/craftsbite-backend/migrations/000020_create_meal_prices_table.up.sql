@@ -0,0 +1,25 @@
+CREATE TABLE meal_prices (
+    id              UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    meal_type       VARCHAR(50)  NOT NULL,
+    unit_price      BIGINT       NOT NULL,
+    subsidy_percent INTEGER      NOT NULL DEFAULT 0,
+    effective_from  DATE         NOT NULL,
+    note            VARCHAR(255),
+    created_by      UUID         REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_meal_prices_meal_effective UNIQUE (meal_type, effective_from),
+    CONSTRAINT chk_meal_prices_unit_price CHECK (unit_price >= 0),
+    CONSTRAINT chk_meal_prices_subsidy CHECK (subsidy_percent BETWEEN 0 AND 100)
+);
+
+COMMENT ON TABLE meal_prices IS 'Effective-dated unit price of each meal type; a price applies from effective_from until the next entry';
+COMMENT ON COLUMN meal_prices.unit_price IS 'Price in minor currency units (see BILLING_CURRENCY)';
+COMMENT ON COLUMN meal_prices.subsidy_percent IS 'Share of the unit price paid by the company; the employee pays the rest';
+
+INSERT INTO role_permissions (role_name, permission, scope) VALUES
+    ('employee', 'billing:read', 'own'),
+    ('team_lead', 'billing:read', 'team'),
+    ('logistics', 'billing:read', 'all'),
+    ('admin', 'billing:read', 'all'),
+    ('admin', 'billing:manage', 'all')
+ON CONFLICT (role_name, permission) DO NOTHING;
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS allergens,
+    DROP COLUMN IF EXISTS dietary_restrictions;
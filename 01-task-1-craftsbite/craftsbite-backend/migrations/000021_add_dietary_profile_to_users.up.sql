@@ -0,0 +1,6 @@
+ALTER TABLE users
+    ADD COLUMN dietary_restrictions TEXT         NOT NULL DEFAULT '',
+    ADD COLUMN allergens            VARCHAR(500) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN users.dietary_restrictions IS 'Comma-separated dietary presets (vegetarian, vegan, halal, gluten_free, lactose_free, nut_allergy)';
+COMMENT ON COLUMN users.allergens IS 'Free-text allergens not covered by the presets';
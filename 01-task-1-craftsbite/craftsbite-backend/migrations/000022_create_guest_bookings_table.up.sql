@@ -0,0 +1,20 @@
+CREATE TABLE guest_bookings (
+    id            UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    host_user_id  UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date          DATE         NOT NULL,
+    meal_types    TEXT         NOT NULL,
+    guest_count   INTEGER      NOT NULL,
+    guest_names   TEXT,
+    dietary_notes VARCHAR(500),
+    purpose       VARCHAR(255),
+    is_active     BOOLEAN      NOT NULL DEFAULT true,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_guest_bookings_count CHECK (guest_count > 0)
+);
+
+CREATE INDEX idx_guest_bookings_date ON guest_bookings(date) WHERE is_active;
+CREATE INDEX idx_guest_bookings_host ON guest_bookings(host_user_id, date DESC);
+
+COMMENT ON TABLE guest_bookings IS 'Meals booked by an employee for visitors without a user account';
+COMMENT ON COLUMN guest_bookings.meal_types IS 'Comma-separated meal types the guests join';
@@ -0,0 +1,17 @@
+CREATE TABLE participation_rules (
+    id               UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id          UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    meal_type        VARCHAR(50)  NOT NULL,
+    weekdays         VARCHAR(100) NOT NULL,
+    is_participating BOOLEAN      NOT NULL,
+    start_date       DATE,
+    end_date         DATE,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_participation_rules_dates CHECK (start_date IS NULL OR end_date IS NULL OR end_date >= start_date)
+);
+
+CREATE INDEX idx_participation_rules_user ON participation_rules(user_id, meal_type);
+
+COMMENT ON TABLE participation_rules IS 'Weekly recurring participation per user and meal, resolved between bulk opt-outs and the user default';
+COMMENT ON COLUMN participation_rules.weekdays IS 'Comma-separated lowercase weekday names, e.g. monday,wednesday';
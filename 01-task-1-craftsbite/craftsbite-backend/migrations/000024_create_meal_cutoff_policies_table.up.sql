@@ -0,0 +1,23 @@
+CREATE TABLE meal_cutoff_policies (
+    id              UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    meal_type       VARCHAR(50)  NOT NULL,
+    day_schedule_id UUID         REFERENCES day_schedules(id) ON DELETE CASCADE,
+    days_before     INTEGER      NOT NULL,
+    cutoff_time     VARCHAR(5)   NOT NULL,
+    updated_by      UUID         REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_meal_cutoff_policies_days_before CHECK (days_before >= 0)
+);
+
+CREATE UNIQUE INDEX uq_meal_cutoff_policies_default ON meal_cutoff_policies(meal_type) WHERE day_schedule_id IS NULL;
+CREATE UNIQUE INDEX uq_meal_cutoff_policies_schedule ON meal_cutoff_policies(day_schedule_id, meal_type) WHERE day_schedule_id IS NOT NULL;
+
+COMMENT ON TABLE meal_cutoff_policies IS 'Change deadline per meal type; rows with a day_schedule_id override the default for that day';
+COMMENT ON COLUMN meal_cutoff_policies.days_before IS 'Days before the meal date on which the deadline falls (0 = same day)';
+COMMENT ON COLUMN meal_cutoff_policies.cutoff_time IS 'HH:MM in MEAL_CUTOFF_TIMEZONE';
+
+-- Meals without a policy keep the global MEAL_CUTOFF_TIME on the previous day
+INSERT INTO meal_cutoff_policies (meal_type, days_before, cutoff_time) VALUES
+    ('snacks', 0, '12:00'),
+    ('event_dinner', 2, '21:00');
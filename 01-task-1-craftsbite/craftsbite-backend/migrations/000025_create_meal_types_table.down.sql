@@ -0,0 +1,3 @@
+DELETE FROM role_permissions WHERE permission = 'meal_type:manage';
+
+DROP TABLE IF EXISTS meal_types;
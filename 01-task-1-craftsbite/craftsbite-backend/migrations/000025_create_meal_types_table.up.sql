@@ -0,0 +1,28 @@
+CREATE TABLE meal_types (
+    code                  VARCHAR(50)  PRIMARY KEY,
+    label                 VARCHAR(100) NOT NULL,
+    emoji                 VARCHAR(16)  NOT NULL DEFAULT '',
+    sort_order            INTEGER      NOT NULL DEFAULT 0,
+    default_available     BOOLEAN      NOT NULL DEFAULT false,
+    default_participating BOOLEAN      NOT NULL DEFAULT true,
+    is_active             BOOLEAN      NOT NULL DEFAULT true,
+    created_at            TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_meal_types_code CHECK (code ~ '^[a-z][a-z0-9_]*$')
+);
+
+COMMENT ON TABLE meal_types IS 'Catalogue of meal types; inactive types are kept so past records still render';
+COMMENT ON COLUMN meal_types.default_available IS 'Served on days whose schedule does not list available meals';
+COMMENT ON COLUMN meal_types.default_participating IS 'Participation when no override, rule or user preference applies';
+
+INSERT INTO meal_types (code, label, emoji, sort_order, default_available, default_participating) VALUES
+    ('lunch', 'Lunch', '🍽️', 10, true, true),
+    ('snacks', 'Snacks', '🍪', 20, true, true),
+    ('iftar', 'Iftar', '🌙', 30, false, true),
+    ('event_dinner', 'Event Dinner', '🍴', 40, false, true),
+    ('optional_dinner', 'Optional Dinner', '🥘', 50, false, true)
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_name, permission, scope) VALUES
+    ('admin', 'meal_type:manage', 'all')
+ON CONFLICT (role_name, permission) DO NOTHING;
@@ -0,0 +1,4 @@
+DELETE FROM role_permissions WHERE permission = 'menu:manage';
+
+DROP TABLE IF EXISTS menu_dishes;
+DROP TABLE IF EXISTS menus;
@@ -0,0 +1,34 @@
+CREATE TABLE menus (
+    id           UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    date         DATE         NOT NULL,
+    meal_type    VARCHAR(50)  NOT NULL,
+    note         VARCHAR(500),
+    is_published BOOLEAN      NOT NULL DEFAULT false,
+    published_at TIMESTAMPTZ,
+    created_by   UUID         REFERENCES users(id) ON DELETE SET NULL,
+    updated_by   UUID         REFERENCES users(id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_menus_date_meal UNIQUE (date, meal_type)
+);
+
+CREATE TABLE menu_dishes (
+    id           UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    menu_id      UUID          NOT NULL REFERENCES menus(id) ON DELETE CASCADE,
+    position     INTEGER       NOT NULL,
+    name         VARCHAR(255)  NOT NULL,
+    description  VARCHAR(1000),
+    dietary_tags TEXT          NOT NULL DEFAULT '',
+    photo_url    VARCHAR(2048),
+    created_at   TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_menu_dishes_menu ON menu_dishes(menu_id, position);
+
+COMMENT ON TABLE menus IS 'Menu of a meal on a date; employees only see published menus';
+COMMENT ON COLUMN menu_dishes.dietary_tags IS 'Comma-separated dietary restrictions the dish is suitable for';
+
+INSERT INTO role_permissions (role_name, permission, scope) VALUES
+    ('logistics', 'menu:manage', 'all'),
+    ('admin', 'menu:manage', 'all')
+ON CONFLICT (role_name, permission) DO NOTHING;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS meal_waitlist_entries;
+DROP TABLE IF EXISTS meal_capacities;
@@ -0,0 +1,26 @@
+CREATE TABLE meal_capacities (
+    id              UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    day_schedule_id UUID         NOT NULL REFERENCES day_schedules(id) ON DELETE CASCADE,
+    meal_type       VARCHAR(50)  NOT NULL,
+    capacity        INTEGER      NOT NULL,
+    updated_by      UUID         REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_meal_capacities_schedule_meal UNIQUE (day_schedule_id, meal_type),
+    CONSTRAINT chk_meal_capacities_capacity CHECK (capacity >= 0)
+);
+
+COMMENT ON TABLE meal_capacities IS 'Seat limit of a meal on a scheduled day; meals without a row are unlimited';
+
+CREATE TABLE meal_waitlist_entries (
+    id         UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date       DATE         NOT NULL,
+    meal_type  VARCHAR(50)  NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_meal_waitlist_user_date_meal UNIQUE (user_id, date, meal_type)
+);
+
+CREATE INDEX idx_meal_waitlist_date_meal ON meal_waitlist_entries(date, meal_type, created_at);
+
+COMMENT ON TABLE meal_waitlist_entries IS 'Opt-ins beyond a meal''s capacity, promoted in created_at order as seats free up';
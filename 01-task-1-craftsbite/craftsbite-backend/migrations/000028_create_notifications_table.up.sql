@@ -0,0 +1,13 @@
+CREATE TABLE notifications (
+    id         UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind       VARCHAR(50)   NOT NULL,
+    title      VARCHAR(255)  NOT NULL,
+    message    TEXT          NOT NULL,
+    read_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at DESC);
+
+COMMENT ON TABLE notifications IS 'In-app notifications shown to a user until read';
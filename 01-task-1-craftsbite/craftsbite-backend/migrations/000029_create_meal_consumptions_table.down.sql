@@ -0,0 +1,3 @@
+DELETE FROM role_permissions WHERE permission IN ('checkin:record', 'consumption:read');
+
+DROP TABLE IF EXISTS meal_consumptions;
@@ -0,0 +1,23 @@
+CREATE TABLE meal_consumptions (
+    id            UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id       UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date          DATE         NOT NULL,
+    meal_type     VARCHAR(50)  NOT NULL,
+    method        VARCHAR(20)  NOT NULL,
+    checked_in_by UUID         REFERENCES users(id) ON DELETE SET NULL,
+    checked_in_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_meal_consumptions_user_date_meal UNIQUE (user_id, date, meal_type),
+    CONSTRAINT chk_meal_consumptions_method CHECK (method IN ('qr', 'manual'))
+);
+
+CREATE INDEX idx_meal_consumptions_date ON meal_consumptions(date, meal_type);
+
+COMMENT ON TABLE meal_consumptions IS 'Meals a user was actually served, recorded by a canteen kiosk or staff';
+COMMENT ON COLUMN meal_consumptions.method IS 'qr: user showed their check-in token; manual: staff looked the user up';
+
+INSERT INTO role_permissions (role_name, permission, scope) VALUES
+    ('logistics', 'checkin:record', 'all'),
+    ('admin', 'checkin:record', 'all'),
+    ('logistics', 'consumption:read', 'all'),
+    ('admin', 'consumption:read', 'all')
+ON CONFLICT (role_name, permission) DO NOTHING;
@@ -0,0 +1,5 @@
+DELETE FROM role_permissions WHERE permission = 'vendor:manage';
+
+DROP TABLE IF EXISTS vendor_order_lines;
+DROP TABLE IF EXISTS vendor_orders;
+DROP TABLE IF EXISTS vendors;
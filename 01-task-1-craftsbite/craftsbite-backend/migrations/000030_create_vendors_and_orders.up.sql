@@ -0,0 +1,64 @@
+CREATE TABLE vendors (
+    id               UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    name             VARCHAR(255)  NOT NULL,
+    meal_types       TEXT          NOT NULL DEFAULT '',
+    delivery_method  VARCHAR(20)   NOT NULL,
+    email            VARCHAR(255),
+    webhook_url      VARCHAR(2048),
+    webhook_secret   VARCHAR(255),
+    lead_days        INTEGER       NOT NULL DEFAULT 1,
+    order_deadline   VARCHAR(5)    NOT NULL,
+    subject_template TEXT,
+    body_template    TEXT,
+    is_active        BOOLEAN       NOT NULL DEFAULT true,
+    created_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_vendors_delivery_method CHECK (delivery_method IN ('email', 'webhook')),
+    CONSTRAINT chk_vendors_lead_days CHECK (lead_days >= 0)
+);
+
+CREATE TABLE vendor_orders (
+    id                 UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    vendor_id          UUID          NOT NULL REFERENCES vendors(id) ON DELETE CASCADE,
+    date               DATE          NOT NULL,
+    status             VARCHAR(20)   NOT NULL DEFAULT 'draft',
+    revision           INTEGER       NOT NULL DEFAULT 1,
+    delivered_revision INTEGER       NOT NULL DEFAULT 0,
+    subject            VARCHAR(500)  NOT NULL,
+    document           TEXT          NOT NULL,
+    sent_at            TIMESTAMPTZ,
+    acknowledged_at    TIMESTAMPTZ,
+    acknowledged_by    UUID          REFERENCES users(id) ON DELETE SET NULL,
+    last_error         TEXT,
+    created_by         UUID          REFERENCES users(id) ON DELETE SET NULL,
+    created_at         TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_vendor_orders_vendor_date UNIQUE (vendor_id, date),
+    CONSTRAINT chk_vendor_orders_status CHECK (status IN ('draft', 'sent', 'acknowledged', 'amended'))
+);
+
+CREATE INDEX idx_vendor_orders_date ON vendor_orders(date);
+
+CREATE TABLE vendor_order_lines (
+    id             UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id       UUID         NOT NULL REFERENCES vendor_orders(id) ON DELETE CASCADE,
+    meal_type      VARCHAR(50)  NOT NULL,
+    participating  INTEGER      NOT NULL DEFAULT 0,
+    guests         INTEGER      NOT NULL DEFAULT 0,
+    dietary        JSONB        NOT NULL DEFAULT '{}',
+    with_allergens INTEGER      NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_vendor_order_lines_order ON vendor_order_lines(order_id);
+
+COMMENT ON TABLE vendors IS 'Caterers that receive meal orders generated from the headcount';
+COMMENT ON COLUMN vendors.meal_types IS 'Comma-separated meal types the vendor supplies';
+COMMENT ON COLUMN vendors.lead_days IS 'Days before the meal date the order is due';
+COMMENT ON COLUMN vendors.order_deadline IS 'HH:MM in the cutoff timezone on the due day when the order is generated and sent';
+COMMENT ON COLUMN vendor_orders.delivered_revision IS 'Last revision delivered to the vendor; lower than revision while a delivery is pending';
+COMMENT ON COLUMN vendor_order_lines.dietary IS 'Participants per dietary restriction';
+
+INSERT INTO role_permissions (role_name, permission, scope) VALUES
+    ('logistics', 'vendor:manage', 'all'),
+    ('admin', 'vendor:manage', 'all')
+ON CONFLICT (role_name, permission) DO NOTHING;
@@ -0,0 +1,4 @@
+DELETE FROM role_permissions WHERE permission = 'auth_security:manage';
+
+DROP TABLE IF EXISTS auth_audit_logs;
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,38 @@
+CREATE TABLE login_attempts (
+    id              UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    subject_type    VARCHAR(20)   NOT NULL,
+    subject         VARCHAR(255)  NOT NULL,
+    failures        INTEGER       NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ   NOT NULL,
+    locked_until    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_login_attempts_subject UNIQUE (subject_type, subject),
+    CONSTRAINT chk_login_attempts_subject_type CHECK (subject_type IN ('account', 'ip'))
+);
+
+CREATE INDEX idx_login_attempts_locked_until ON login_attempts(locked_until) WHERE locked_until IS NOT NULL;
+
+CREATE TABLE auth_audit_logs (
+    id          UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    event       VARCHAR(50)   NOT NULL,
+    user_id     UUID          REFERENCES users(id) ON DELETE SET NULL,
+    email       VARCHAR(255),
+    ip_address  VARCHAR(64),
+    user_agent  VARCHAR(512),
+    actor_id    UUID          REFERENCES users(id) ON DELETE SET NULL,
+    details     TEXT,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_auth_audit_logs_created_at ON auth_audit_logs(created_at);
+CREATE INDEX idx_auth_audit_logs_user ON auth_audit_logs(user_id);
+
+COMMENT ON TABLE login_attempts IS 'Consecutive failed logins per account (normalized email) and per client IP';
+COMMENT ON COLUMN login_attempts.locked_until IS 'Logins for the subject are refused until this time';
+COMMENT ON TABLE auth_audit_logs IS 'Security events such as lockouts and unlocks';
+COMMENT ON COLUMN auth_audit_logs.actor_id IS 'Admin who caused the event, if it was not the user';
+
+INSERT INTO role_permissions (role_name, permission, scope) VALUES
+    ('admin', 'auth_security:manage', 'all')
+ON CONFLICT (role_name, permission) DO NOTHING;
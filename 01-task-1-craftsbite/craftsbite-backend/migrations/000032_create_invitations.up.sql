@@ -0,0 +1,22 @@
+CREATE TABLE invitations (
+    id           UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    email        VARCHAR(255)  NOT NULL,
+    role         VARCHAR(50)   NOT NULL REFERENCES roles(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    team_id      UUID          REFERENCES teams(id) ON DELETE SET NULL,
+    token_hash   VARCHAR(64)   NOT NULL,
+    expires_at   TIMESTAMPTZ   NOT NULL,
+    accepted_at  TIMESTAMPTZ,
+    user_id      UUID          REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at   TIMESTAMPTZ,
+    created_by   UUID          REFERENCES users(id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_invitations_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX idx_invitations_email ON invitations(LOWER(email));
+CREATE INDEX idx_invitations_created_at ON invitations(created_at);
+
+COMMENT ON TABLE invitations IS 'Single-use invitations to create an account with a given role and team';
+COMMENT ON COLUMN invitations.token_hash IS 'SHA-256 of the invitation token; the token itself is only sent to the invitee';
+COMMENT ON COLUMN invitations.user_id IS 'Account created when the invitation was accepted';
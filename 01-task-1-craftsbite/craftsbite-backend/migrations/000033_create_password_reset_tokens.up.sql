@@ -0,0 +1,15 @@
+CREATE TABLE password_reset_tokens (
+    id          UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash  VARCHAR(64)   NOT NULL,
+    expires_at  TIMESTAMPTZ   NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_password_reset_tokens_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+
+COMMENT ON TABLE password_reset_tokens IS 'Single-use tokens emailed to users who forgot their password';
+COMMENT ON COLUMN password_reset_tokens.token_hash IS 'SHA-256 of the reset token; the token itself is only sent to the user';
+COMMENT ON COLUMN password_reset_tokens.used_at IS 'Set when the token is used or superseded; a token with used_at set is no longer valid';
@@ -0,0 +1,38 @@
+CREATE TABLE user_two_factors (
+    user_id         UUID          PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret          TEXT          NOT NULL,
+    enabled_at      TIMESTAMPTZ,
+    last_used_step  BIGINT        NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE two_factor_recovery_codes (
+    id          UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash   VARCHAR(64)   NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_two_factor_recovery_codes_code UNIQUE (user_id, code_hash)
+);
+
+CREATE TABLE login_challenges (
+    id          UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash  VARCHAR(64)   NOT NULL,
+    purpose     VARCHAR(30)   NOT NULL,
+    attempts    INTEGER       NOT NULL DEFAULT 0,
+    expires_at  TIMESTAMPTZ   NOT NULL,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_login_challenges_token_hash UNIQUE (token_hash),
+    CONSTRAINT chk_login_challenges_purpose CHECK (purpose IN ('two_factor', 'two_factor_setup'))
+);
+
+CREATE INDEX idx_login_challenges_user_id ON login_challenges(user_id);
+CREATE INDEX idx_login_challenges_expires_at ON login_challenges(expires_at);
+
+COMMENT ON TABLE user_two_factors IS 'TOTP enrolment per user; enabled_at is NULL while setup is unconfirmed';
+COMMENT ON COLUMN user_two_factors.secret IS 'TOTP secret encrypted with AES-256-GCM';
+COMMENT ON COLUMN user_two_factors.last_used_step IS 'Time step of the last accepted code; older or equal steps are refused so codes cannot be replayed';
+COMMENT ON TABLE two_factor_recovery_codes IS 'Single-use codes that stand in for a TOTP code when the authenticator is lost';
+COMMENT ON TABLE login_challenges IS 'Pending second login steps issued after a correct password';
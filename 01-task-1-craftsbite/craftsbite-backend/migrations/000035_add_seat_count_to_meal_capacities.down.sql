@@ -0,0 +1,3 @@
+ALTER TABLE meal_capacities
+    DROP COLUMN IF EXISTS seats_taken,
+    DROP COLUMN IF EXISTS seats_counted_at;
@@ -0,0 +1,5 @@
+ALTER TABLE meal_capacities
+    ADD COLUMN IF NOT EXISTS seats_taken INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS seats_counted_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN meal_capacities.seats_taken IS 'Seats taken as of seats_counted_at, kept in step by opt-ins and opt-outs made under the row lock';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_schedule_rules_source_uid;
+
+ALTER TABLE schedule_rules DROP COLUMN IF EXISTS source_uid;
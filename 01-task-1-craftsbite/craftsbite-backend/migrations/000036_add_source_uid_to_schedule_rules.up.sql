@@ -0,0 +1,5 @@
+ALTER TABLE schedule_rules ADD COLUMN IF NOT EXISTS source_uid TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_schedule_rules_source_uid ON schedule_rules(source_uid) WHERE source_uid IS NOT NULL;
+
+COMMENT ON COLUMN schedule_rules.source_uid IS 'UID of the iCalendar event the rule was imported from, so a re-import does not duplicate it';
@@ -0,0 +1,2 @@
+ALTER TABLE headcount_snapshot_teams DROP COLUMN IF EXISTS guests;
+ALTER TABLE headcount_snapshots DROP COLUMN IF EXISTS guests;
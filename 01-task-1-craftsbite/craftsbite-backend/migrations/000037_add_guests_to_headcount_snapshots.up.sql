@@ -0,0 +1,5 @@
+ALTER TABLE headcount_snapshots ADD COLUMN IF NOT EXISTS guests INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE headcount_snapshot_teams ADD COLUMN IF NOT EXISTS guests INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN headcount_snapshots.guests IS 'Guest meals booked at cutoff; snapshots taken before this column existed read 0';
+COMMENT ON COLUMN headcount_snapshot_teams.guests IS 'Guest meals booked at cutoff by hosts in the team';
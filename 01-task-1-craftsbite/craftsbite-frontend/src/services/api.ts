@@ -1,6 +1,6 @@
 // Axios API Instance Configuration
 
-import axios, { AxiosError } from 'axios';
+import axios, { AxiosError, type InternalAxiosRequestConfig } from 'axios';
 import type { ApiErrorResponse } from '../types';
 import { clearAuthData } from '../utils/storage';
 import { API_BASE_URL } from '../utils/constants';
@@ -30,12 +30,45 @@ const api = axios.create({
 //     }
 // );
 
+// Access tokens are short-lived; a 401 is retried once after rotating the refresh token.
+// Concurrent 401s share a single refresh request.
+let refreshPromise: Promise<void> | null = null;
+
+const refreshSession = (): Promise<void> => {
+    if (!refreshPromise) {
+        refreshPromise = api
+            .post('/auth/refresh')
+            .then(() => undefined)
+            .finally(() => {
+                refreshPromise = null;
+            });
+    }
+    return refreshPromise;
+};
+
+type RetryableRequestConfig = InternalAxiosRequestConfig & {
+    _retried?: boolean;
+};
+
 // Response interceptor - handle errors
 api.interceptors.response.use(
     (response) => {
         return response;
     },
-    (error: AxiosError<ApiErrorResponse>) => {
+    async (error: AxiosError<ApiErrorResponse>) => {
+        const original = error.config as RetryableRequestConfig | undefined;
+        const isAuthRequest = original?.url?.startsWith('/auth/login') || original?.url?.startsWith('/auth/refresh');
+
+        if (error.response?.status === 401 && original && !original._retried && !isAuthRequest) {
+            original._retried = true;
+            try {
+                await refreshSession();
+                return api(original);
+            } catch {
+                // Refresh failed; fall through to the logout handling below
+            }
+        }
+
         if (error.response?.status === 401) {
             clearAuthData();
 
@@ -7,10 +7,67 @@ import type {
   HeadcountDataArray,
   DetailedHeadcountData,
   AnnouncementResponse,
+  DayStatus,
 } from "../types";
 
 export type ForecastData = HeadcountData[];
 
+export type ForecastMethod = "statistical" | "rule_based";
+
+export interface MealForecast {
+  rule_based: number;
+  estimate: number;
+  low: number;
+  high: number;
+  method: ForecastMethod;
+  observations: number;
+}
+
+export interface DailyForecast {
+  date: string;
+  day_status: DayStatus;
+  confidence: number;
+  meals: Record<string, MealForecast>;
+  teams: {
+    team_id: string;
+    team_name: string;
+    meals: Record<string, MealForecast>;
+  }[];
+}
+
+export interface ForecastErrors {
+  samples: number;
+  mean_absolute_error: number;
+  mean_absolute_percentage_error: number;
+  bias: number;
+  coverage?: number;
+}
+
+export interface BacktestErrors {
+  statistical: ForecastErrors;
+  rule_based: ForecastErrors;
+}
+
+export interface ForecastBacktest {
+  start_date: string;
+  end_date: string;
+  horizon_days: number;
+  confidence: number;
+  overall: BacktestErrors;
+  meals: Record<string, BacktestErrors>;
+  weeks: (BacktestErrors & { week_start: string })[];
+  points: {
+    date: string;
+    meal_type: string;
+    actual: number;
+    rule_based: number;
+    estimate: number;
+    low: number;
+    high: number;
+    method: ForecastMethod;
+  }[];
+}
+
 /**
  * Get today's and tomorrow's headcount summary (Admin only)
  * GET /headcount/today
@@ -62,4 +119,23 @@ export async function getForecast(
         `/headcount/forecast?days=${days}`,
     );
     return response.data;
-}
\ No newline at end of file
+}
+
+export async function getStatisticalForecast(
+    days = 7,
+): Promise<ApiResponse<DailyForecast[]>> {
+    const response = await api.get<ApiResponse<DailyForecast[]>>(
+        `/headcount/forecast?days=${days}&mode=statistical`,
+    );
+    return response.data;
+}
+
+export async function getForecastBacktest(
+    weeks = 4,
+    horizon = 1,
+): Promise<ApiResponse<ForecastBacktest>> {
+    const response = await api.get<ApiResponse<ForecastBacktest>>(
+        `/headcount/forecast/backtest?weeks=${weeks}&horizon=${horizon}`,
+    );
+    return response.data;
+}
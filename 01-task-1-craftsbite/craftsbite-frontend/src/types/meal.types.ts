@@ -21,7 +21,9 @@ export type ParticipationAction =
   | "opted_in"
   | "opted_out"
   | "override_in"
-  | "override_out";
+  | "override_out"
+  | "waitlisted"
+  | "waitlist_promoted";
 
 export interface MealParticipation {
   id: string;
@@ -84,6 +86,7 @@ export type ParticipationSource =
   | "day_schedule"
   | "explicit"
   | "bulk_opt_out"
+  | "recurring_rule"
   | "user_default"
   | "system_default";
 
@@ -91,6 +94,9 @@ export interface TodayMealParticipation {
   meal_type: MealType;
   is_participating: boolean;
   source: ParticipationSource;
+  deadline: string;
+  waitlisted: boolean;
+  waitlist_position?: number;
 }
 
 export interface TodayMealsData {
@@ -98,6 +104,27 @@ export interface TodayMealsData {
   day_status: DayStatus;
   available_meals: MealType[];
   participations: TodayMealParticipation[];
+  menus: Menu[];
+}
+
+// --- Menus (matches GET /menus) ---
+export interface MenuDish {
+  id: string;
+  position: number;
+  name: string;
+  description?: string;
+  dietary_tags: string; // comma-separated
+  photo_url?: string;
+}
+
+export interface Menu {
+  id: string;
+  date: string;
+  meal_type: MealType;
+  note?: string;
+  is_published: boolean;
+  published_at?: string;
+  dishes: MenuDish[];
 }
 
 // --- Participation request (matches POST /meals/participation) ---